@@ -20,6 +20,7 @@ import (
 	"crypto/tls"
 	"crypto/x509"
 	"io/ioutil"
+	"os"
 
 	"github.com/gravitational/teleport/api/constants"
 	"github.com/gravitational/teleport/api/identityfile"
@@ -45,6 +46,27 @@ type Credentials interface {
 	SSHClientConfig() (*ssh.ClientConfig, error)
 }
 
+// IdentityFileEnvVar is the environment variable that can be set to a path
+// of an identity file for LoadDefault to pick up.
+const IdentityFileEnvVar = "TELEPORT_IDENTITY_FILE"
+
+// LoadDefault is used to automatically load client Credentials from the
+// environment. It returns a list of Credentials to try in order:
+//  - an identity file at the path set in the TELEPORT_IDENTITY_FILE
+//    environment variable, if the variable is set
+//  - the currently active tsh profile
+//
+// This makes it possible to write tools that work unchanged on an operator's
+// workstation (tsh profile) and in CI (exported identity file).
+func LoadDefault() []Credentials {
+	var creds []Credentials
+	if path := os.Getenv(IdentityFileEnvVar); path != "" {
+		creds = append(creds, LoadIdentityFile(path))
+	}
+	creds = append(creds, LoadProfile("", ""))
+	return creds
+}
+
 // LoadTLS is used to load Credentials directly from a *tls.Config.
 //
 // TLS creds can only be used to connect directly to a Teleport Auth server.
@@ -320,3 +320,21 @@ Na6B0YR7mdrrL+lyzymnOr6UOrT5nUWRAB1QeY7dhBNnsvoZwaS3VLSc1KCk
 
 	sshCACert = []byte("@cert-authority *.example.com ssh-rsa AAAAB3NzaC1yc2EAAAADAQABAAABAQDMIgxZpT5362npj0x6NQA76IB73bcK85K8cEyKURuHtFC83RjBzvzqtUz6X02+6ohVZiR2MdmsXkCLznzwEIZ0NtoxgnLTZLmduPLeAuYW2vIFpd0G17y6Yog9vxhQ0BLdlhU5Y3JYjRYjmQMfe1iD/RXWD6rEvgWlz+c3HMQR33JqkVIEFH34upfkC2RQG3TXjMe5t14l3yCTtyF5YGzN7+6z/4+/EDto/F3zVtSEp+k8XE/m0ddTGo7usa8ErAom31RwrgkNRmgJmPleDwEflybEsgGKApJXkfFxmG2wu20JoEt/CFjY3fIIa/5aqIGJPpMH4aEdLcj/iyNCog8D type=host")
 )
+
+func TestLoadDefault(t *testing.T) {
+	// Without the environment variable set, only profile credentials
+	// are returned.
+	os.Unsetenv(IdentityFileEnvVar)
+	creds := LoadDefault()
+	require.Len(t, creds, 1)
+	require.IsType(t, &profileCreds{}, creds[0])
+
+	// With the environment variable set, identity file credentials are
+	// tried before the profile.
+	os.Setenv(IdentityFileEnvVar, filepath.Join(t.TempDir(), "identity"))
+	defer os.Unsetenv(IdentityFileEnvVar)
+	creds = LoadDefault()
+	require.Len(t, creds, 2)
+	require.IsType(t, &identityCreds{}, creds[0])
+	require.IsType(t, &profileCreds{}, creds[1])
+}
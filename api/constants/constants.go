@@ -111,6 +111,15 @@ const (
 	// SecondFactorU2F means that only U2F is supported for 2FA and 2FA is
 	// required for all users.
 	SecondFactorU2F = SecondFactorType("u2f")
+	// TraceIDHeader is the HTTP header carrying the identifier that
+	// correlates a client request with server-side log entries across
+	// service boundaries.
+	TraceIDHeader = "X-Teleport-Trace-Id"
+
+	// SecondFactorPasswordless means that a hardware key tap (U2F) is the
+	// only factor: passwords are not used and a U2F authentication
+	// challenge can be requested without one.
+	SecondFactorPasswordless = SecondFactorType("passwordless")
 	// SecondFactorOn means that all 2FA protocols are supported and 2FA is
 	// required for all users.
 	SecondFactorOn = SecondFactorType("on")
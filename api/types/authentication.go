@@ -304,7 +304,7 @@ func (c *AuthPreferenceV2) CheckAndSetDefaults() error {
 	// make sure second factor makes sense
 	switch c.Spec.SecondFactor {
 	case constants.SecondFactorOff, constants.SecondFactorOTP:
-	case constants.SecondFactorU2F, constants.SecondFactorOn, constants.SecondFactorOptional:
+	case constants.SecondFactorU2F, constants.SecondFactorPasswordless, constants.SecondFactorOn, constants.SecondFactorOptional:
 		if c.Spec.U2F == nil {
 			return trace.BadParameter("missing required U2F configuration for second factor type %q", c.Spec.SecondFactor)
 		}
@@ -212,6 +212,21 @@ const (
 	// KindKubeService is a kubernetes service resource
 	KindKubeService = "kube_service"
 
+	// KindNotification is a user notification resource kind
+	KindNotification = "notification"
+
+	// KindLoginRule is a login rule resource kind, transforming SSO
+	// traits at login
+	KindLoginRule = "login_rule"
+
+	// KindKubePod is a kubernetes pod, used in role rules for
+	// resource-level RBAC in the kubernetes proxy
+	KindKubePod = "kube_pod"
+
+	// KindKubeNamespace is a kubernetes namespace, used in role rules for
+	// resource-level RBAC in the kubernetes proxy
+	KindKubeNamespace = "kube_namespace"
+
 	// KindMFADevice is an MFA device for a user.
 	KindMFADevice = "mfa_device"
 
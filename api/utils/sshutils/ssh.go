@@ -23,6 +23,7 @@ import (
 	"fmt"
 	"net"
 	"runtime"
+	"time"
 
 	"github.com/gravitational/teleport/api/constants"
 	"github.com/gravitational/teleport/api/defaults"
@@ -122,6 +123,11 @@ func AsAgentKeys(sshCert *ssh.Certificate, privKey []byte) ([]agent.AddedKey, er
 	// put a teleport identifier along with the teleport user into the comment field
 	comment := fmt.Sprintf("teleport:%v", sshCert.KeyId)
 
+	// Constrain the agent entries to the certificate lifetime: the agent
+	// drops the key when the certificate expires instead of keeping a
+	// stale credential around.
+	lifetime := agentKeyLifetime(sshCert)
+
 	// On Windows, return the certificate with the private key embedded.
 	if runtime.GOOS == constants.WindowsOS {
 		return []agent.AddedKey{
@@ -129,7 +135,7 @@ func AsAgentKeys(sshCert *ssh.Certificate, privKey []byte) ([]agent.AddedKey, er
 				PrivateKey:       privateKey,
 				Certificate:      sshCert,
 				Comment:          comment,
-				LifetimeSecs:     0,
+				LifetimeSecs:     lifetime,
 				ConfirmBeforeUse: false,
 			},
 		}, nil
@@ -152,19 +158,35 @@ func AsAgentKeys(sshCert *ssh.Certificate, privKey []byte) ([]agent.AddedKey, er
 			PrivateKey:       privateKey,
 			Certificate:      sshCert,
 			Comment:          comment,
-			LifetimeSecs:     0,
+			LifetimeSecs:     lifetime,
 			ConfirmBeforeUse: false,
 		},
 		{
 			PrivateKey:       privateKey,
 			Certificate:      nil,
 			Comment:          comment,
-			LifetimeSecs:     0,
+			LifetimeSecs:     lifetime,
 			ConfirmBeforeUse: false,
 		},
 	}, nil
 }
 
+// agentKeyLifetime converts the certificate expiry into an agent key
+// lifetime in seconds, zero (no constraint) when the certificate does
+// not expire. An already expired certificate gets the minimal nonzero
+// lifetime: zero would mean no constraint and leave the stale key in
+// the agent indefinitely.
+func agentKeyLifetime(sshCert *ssh.Certificate) uint32 {
+	if sshCert.ValidBefore == uint64(ssh.CertTimeInfinity) || sshCert.ValidBefore == 0 {
+		return 0
+	}
+	remaining := time.Until(time.Unix(int64(sshCert.ValidBefore), 0))
+	if remaining <= time.Second {
+		return 1
+	}
+	return uint32(remaining / time.Second)
+}
+
 // HostKeyCallback returns an ssh.HostKeyCallback that validates host
 // keys/certs against SSH CAs in the Key.
 //
@@ -319,6 +319,10 @@ const (
 	// storage
 	SchemeGCS = "gs"
 
+	// SchemeAZBlob is Azure Blob Storage file scheme, means upload or download
+	// to Azure Blob Storage like object storage
+	SchemeAZBlob = "azblob"
+
 	// GCSTestURI turns on GCS tests
 	GCSTestURI = "TEST_GCS_URI"
 
@@ -419,6 +423,19 @@ const (
 	// CertExtensionMFAVerified is used to mark certificates issued after an MFA
 	// check.
 	CertExtensionMFAVerified = "mfa-verified"
+	// TokenLabelSingleUse marks a provision token that is deleted after
+	// the first successful registration, set by "tctl tokens add
+	// --single-use"
+	TokenLabelSingleUse = "teleport.dev/single-use"
+
+	// CertExtensionAllowedResources lists the resource IDs ("kind/name",
+	// comma-separated) an approved resource access request limits the
+	// certificate to
+	CertExtensionAllowedResources = "teleport-allowed-resources"
+	// CertExtensionSessionID is used to bind a certificate to a specific
+	// session: single-use certificates issued for a session carry its ID
+	// so audit events and servers can correlate and scope them
+	CertExtensionSessionID = "session-id"
 	// CertExtensionClientIP is used to embed the IP of the client that created
 	// the certificate.
 	CertExtensionClientIP = "client-ip"
@@ -4,6 +4,7 @@ go 1.16
 
 require (
 	cloud.google.com/go v0.60.0
+	github.com/Azure/azure-storage-blob-go v0.10.0
 	cloud.google.com/go/firestore v1.2.0
 	cloud.google.com/go/storage v1.10.0
 	github.com/HdrHistogram/hdrhistogram-go v1.0.1
@@ -0,0 +1,185 @@
+/*
+Copyright 2021 Gravitational, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package auth
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/gravitational/teleport"
+	apidefaults "github.com/gravitational/teleport/api/defaults"
+	"github.com/gravitational/teleport/api/types"
+	"github.com/gravitational/teleport/lib/backend"
+
+	"github.com/gravitational/trace"
+)
+
+// NotificationTypeClusterAlert marks notifications generated by the
+// cluster health checks.
+const NotificationTypeClusterAlert = "cluster-alert"
+
+// clusterAlertsCheckPeriod is how often cluster health is evaluated.
+const clusterAlertsCheckPeriod = 5 * time.Minute
+
+// Stable alert IDs, one per condition: re-raising a condition refreshes
+// the same alert instead of stacking duplicates.
+const (
+	alertCARotationStuck = "ca-rotation-stuck"
+	alertBackendSlow     = "backend-slow"
+	alertVersionSkew     = "version-skew"
+)
+
+// caRotationStuckThreshold is how long a CA rotation may stay in
+// progress before it is considered stuck.
+const caRotationStuckThreshold = 24 * time.Hour
+
+// backendProbeThreshold is the backend read latency above which the
+// backend is considered degraded.
+const backendProbeThreshold = time.Second
+
+// checkClusterHealth evaluates cluster health and raises self-expiring
+// cluster alerts for degraded conditions. Alerts expire two check
+// periods after the condition was last observed, so a resolved condition
+// clears its alert without explicit bookkeeping.
+func (a *Server) checkClusterHealth(ctx context.Context) {
+	a.checkCARotations(ctx)
+	a.checkBackendLatency(ctx)
+	a.checkVersionSkew(ctx)
+}
+
+// raiseClusterAlert stores or refreshes a cluster alert.
+func (a *Server) raiseClusterAlert(ctx context.Context, id, title, message string) {
+	err := a.CreateNotification(ctx, Notification{
+		ID:      id,
+		Type:    NotificationTypeClusterAlert,
+		Title:   title,
+		Message: message,
+		Expires: a.clock.Now().UTC().Add(2 * clusterAlertsCheckPeriod),
+	})
+	if err != nil {
+		log.WithError(err).Warningf("Failed to raise cluster alert %v.", id)
+		return
+	}
+	log.Warningf("Cluster alert %v: %v", id, message)
+}
+
+// checkCARotations alerts when a certificate authority rotation has been
+// in progress longer than the stuck threshold.
+func (a *Server) checkCARotations(ctx context.Context) {
+	for _, caType := range []types.CertAuthType{types.HostCA, types.UserCA} {
+		cas, err := a.GetCertAuthorities(caType, false)
+		if err != nil {
+			log.WithError(err).Warning("Failed to load cert authorities for the health check.")
+			return
+		}
+		for _, ca := range cas {
+			rotation := ca.GetRotation()
+			if rotation.State != types.RotationStateInProgress {
+				continue
+			}
+			if a.clock.Now().Sub(rotation.Started) < caRotationStuckThreshold {
+				continue
+			}
+			a.raiseClusterAlert(ctx, alertCARotationStuck,
+				"CA rotation appears stuck",
+				fmt.Sprintf("Rotation of the %v CA for %v has been in phase %q since %v, complete or roll it back.",
+					caType, ca.GetClusterName(), rotation.Phase, rotation.Started.Format(time.RFC822)))
+			return
+		}
+	}
+}
+
+// checkBackendLatency alerts when a probe read against the cluster
+// backend is slower than the threshold.
+func (a *Server) checkBackendLatency(ctx context.Context) {
+	prefix := backend.Key(notificationsPrefix, "global")
+	started := time.Now()
+	_, err := a.bk.GetRange(ctx, prefix, backend.RangeEnd(prefix), 1)
+	elapsed := time.Since(started)
+	if err != nil {
+		a.raiseClusterAlert(ctx, alertBackendSlow,
+			"Backend is unhealthy",
+			fmt.Sprintf("A probe read against the cluster backend failed: %v.", err))
+		return
+	}
+	if elapsed > backendProbeThreshold {
+		a.raiseClusterAlert(ctx, alertBackendSlow,
+			"Backend latency is high",
+			fmt.Sprintf("A probe read against the cluster backend took %v, cluster operations may be degraded.", elapsed))
+	}
+}
+
+// checkVersionSkew alerts when agents run a major version more than one
+// behind the auth server.
+func (a *Server) checkVersionSkew(ctx context.Context) {
+	authMajor, err := majorVersion(teleport.Version)
+	if err != nil {
+		return
+	}
+	nodes, err := a.GetNodes(ctx, apidefaults.Namespace)
+	if err != nil {
+		log.WithError(err).Warning("Failed to load nodes for the health check.")
+		return
+	}
+	var outdated int
+	for _, node := range nodes {
+		nodeMajor, err := majorVersion(node.GetTeleportVersion())
+		if err != nil {
+			continue
+		}
+		if authMajor-nodeMajor > 1 {
+			outdated++
+		}
+	}
+	if outdated > 0 {
+		a.raiseClusterAlert(ctx, alertVersionSkew,
+			"Agents are too far behind",
+			fmt.Sprintf("%v agent(s) run a Teleport major version more than one behind the auth server (%v), upgrade them before the next cluster upgrade.",
+				outdated, teleport.Version))
+	}
+}
+
+// majorVersion parses the major component of a semver string.
+func majorVersion(version string) (int, error) {
+	return strconv.Atoi(strings.SplitN(version, ".", 2)[0])
+}
+
+// checkVersionSkewLimit rejects a joining agent whose major version is
+// more than the configured number of majors behind the auth server.
+// Agents predating the version field are let through.
+func (a *Server) checkVersionSkewLimit(req RegisterUsingTokenRequest) error {
+	if a.versionSkewLimit <= 0 || req.Version == "" {
+		return nil
+	}
+	agentMajor, err := majorVersion(req.Version)
+	if err != nil {
+		return nil
+	}
+	authMajor, err := majorVersion(teleport.Version)
+	if err != nil {
+		return nil
+	}
+	if authMajor-agentMajor > a.versionSkewLimit {
+		return trace.AccessDenied(
+			"node %q runs Teleport %v which is more than %v major version(s) behind the auth server (%v), upgrade the agent before joining",
+			req.NodeName, req.Version, a.versionSkewLimit, teleport.Version)
+	}
+	return nil
+}
@@ -31,6 +31,7 @@ import (
 
 	"github.com/gravitational/teleport/api/client/proto"
 	apidefaults "github.com/gravitational/teleport/api/defaults"
+	"github.com/gravitational/teleport/api/constants"
 	"github.com/gravitational/teleport/api/types"
 	apievents "github.com/gravitational/teleport/api/types/events"
 	"github.com/gravitational/teleport/lib/defaults"
@@ -107,6 +108,7 @@ func NewAPIServer(config *APIConfig) (http.Handler, error) {
 	// Generating certificates for user and host authorities
 	srv.POST("/:version/ca/host/certs", srv.withAuth(srv.generateHostCert))
 	srv.POST("/:version/ca/user/certs", srv.withAuth(srv.generateUserCert)) // DELETE IN: 4.2.0
+	srv.POST("/:version/ca/user/certs/batch", srv.withAuth(srv.generateUserCertsBatch))
 
 	// Operations on users
 	srv.GET("/:version/users", srv.withAuth(srv.getUsers))
@@ -127,6 +129,24 @@ func NewAPIServer(config *APIConfig) (http.Handler, error) {
 	srv.GET("/:version/users/:user/web/sessions/:sid", srv.withAuth(srv.getWebSession))
 	srv.DELETE("/:version/users/:user/web/sessions/:sid", srv.withAuth(srv.deleteWebSession))
 	srv.POST("/:version/web/password/token", srv.withRate(srv.withAuth(srv.changePasswordWithToken)))
+	srv.GET("/:version/resetpasswordtokens", srv.withAuth(srv.getResetPasswordTokens))
+	srv.POST("/:version/audit/sign", srv.withAuth(srv.signAuditDigest))
+	srv.GET("/:version/notifications", srv.withAuth(srv.getNotifications))
+	srv.POST("/:version/notifications", srv.withAuth(srv.createNotification))
+	srv.DELETE("/:version/notifications/:id", srv.withAuth(srv.ackNotification))
+	srv.POST("/:version/inventory/hello", srv.withAuth(srv.inventoryHello))
+	srv.GET("/:version/inventory/status", srv.withAuth(srv.inventoryStatus))
+	srv.POST("/:version/inventory/command", srv.withAuth(srv.sendInventoryCommand))
+	srv.POST("/:version/inventory/result", srv.withAuth(srv.postInventoryResult))
+	srv.GET("/:version/inventory/result/:id", srv.withAuth(srv.getInventoryResult))
+	srv.GET("/:version/revocations", srv.withAuth(srv.getRevocationList))
+	srv.POST("/:version/backup", srv.withAuth(srv.createBackup))
+	srv.POST("/:version/loginrules", srv.withAuth(srv.upsertLoginRule))
+	srv.POST("/:version/nodes/:name/labels", srv.withAuth(srv.updateManagedLabels))
+	srv.GET("/:version/usagereport", srv.withAuth(srv.getUsageReport))
+	srv.GET("/:version/licensestatus", srv.withAuth(srv.getLicenseStatus))
+	srv.GET("/:version/loginrules", srv.withAuth(srv.getLoginRules))
+	srv.DELETE("/:version/loginrules/:name", srv.withAuth(srv.deleteLoginRule))
 
 	// Servers and presence heartbeat
 	srv.POST("/:version/namespaces/:namespace/nodes", srv.withAuth(srv.upsertNode))
@@ -270,6 +290,11 @@ type HandlerWithAuthFunc func(auth ClientI, w http.ResponseWriter, r *http.Reque
 func (s *APIServer) withAuth(handler HandlerWithAuthFunc) httprouter.Handle {
 	const accessDeniedMsg = "auth API: access denied "
 	return httplib.MakeHandler(func(w http.ResponseWriter, r *http.Request, p httprouter.Params) (interface{}, error) {
+		// Log the client-supplied trace ID, if any, so requests can be
+		// correlated with client-side logs.
+		if traceID := r.Header.Get(constants.TraceIDHeader); traceID != "" {
+			log.WithField("trace_id", traceID).Debugf("%v %v", r.Method, r.URL.Path)
+		}
 		// HTTPS server expects auth context to be set by the auth middleware
 		authContext, err := s.Authorizer.Authorize(r.Context())
 		if err != nil {
@@ -904,6 +929,217 @@ func rawMessage(data []byte, err error) (interface{}, error) {
 	return &m, nil
 }
 
+func (s *APIServer) getResetPasswordTokens(auth ClientI, w http.ResponseWriter, r *http.Request, p httprouter.Params, version string) (interface{}, error) {
+	tokens, err := auth.GetResetPasswordTokens(r.Context())
+	if err != nil {
+		return nil, trace.Wrap(err)
+	}
+	out := make([]json.RawMessage, len(tokens))
+	for i, token := range tokens {
+		data, err := services.MarshalResetPasswordToken(token)
+		if err != nil {
+			return nil, trace.Wrap(err)
+		}
+		out[i] = data
+	}
+	return out, nil
+}
+
+// signAuditDigestReq is a request to sign an exported audit archive digest.
+type signAuditDigestReq struct {
+	// Digest is the SHA256 digest to sign.
+	Digest []byte `json:"digest"`
+}
+
+func (s *APIServer) signAuditDigest(auth ClientI, w http.ResponseWriter, r *http.Request, p httprouter.Params, version string) (interface{}, error) {
+	var req signAuditDigestReq
+	if err := httplib.ReadJSON(r, &req); err != nil {
+		return nil, trace.Wrap(err)
+	}
+	signature, err := auth.SignAuditDigest(r.Context(), req.Digest)
+	if err != nil {
+		return nil, trace.Wrap(err)
+	}
+	return signature, nil
+}
+
+type generateUserCertsBatchReq struct {
+	Requests []proto.UserCertsRequest `json:"requests"`
+}
+
+func (s *APIServer) generateUserCertsBatch(auth ClientI, w http.ResponseWriter, r *http.Request, p httprouter.Params, version string) (interface{}, error) {
+	var req generateUserCertsBatchReq
+	if err := httplib.ReadJSON(r, &req); err != nil {
+		return nil, trace.Wrap(err)
+	}
+	certs, err := auth.GenerateUserCertsBatch(r.Context(), req.Requests)
+	if err != nil {
+		return nil, trace.Wrap(err)
+	}
+	return certs, nil
+}
+
+func (s *APIServer) getNotifications(auth ClientI, w http.ResponseWriter, r *http.Request, p httprouter.Params, version string) (interface{}, error) {
+	notifications, err := auth.GetNotifications(r.Context())
+	if err != nil {
+		return nil, trace.Wrap(err)
+	}
+	return notifications, nil
+}
+
+func (s *APIServer) createNotification(auth ClientI, w http.ResponseWriter, r *http.Request, p httprouter.Params, version string) (interface{}, error) {
+	var notification Notification
+	if err := httplib.ReadJSON(r, &notification); err != nil {
+		return nil, trace.Wrap(err)
+	}
+	if err := auth.CreateNotification(r.Context(), notification); err != nil {
+		return nil, trace.Wrap(err)
+	}
+	return message("ok"), nil
+}
+
+func (s *APIServer) ackNotification(auth ClientI, w http.ResponseWriter, r *http.Request, p httprouter.Params, version string) (interface{}, error) {
+	if err := auth.AckNotification(r.Context(), p.ByName("id")); err != nil {
+		return nil, trace.Wrap(err)
+	}
+	return message("ok"), nil
+}
+
+func (s *APIServer) inventoryHello(auth ClientI, w http.ResponseWriter, r *http.Request, p httprouter.Params, version string) (interface{}, error) {
+	var hello InventoryHello
+	if err := httplib.ReadJSON(r, &hello); err != nil {
+		return nil, trace.Wrap(err)
+	}
+	commands, err := auth.InventoryHello(r.Context(), hello)
+	if err != nil {
+		return nil, trace.Wrap(err)
+	}
+	return commands, nil
+}
+
+func (s *APIServer) inventoryStatus(auth ClientI, w http.ResponseWriter, r *http.Request, p httprouter.Params, version string) (interface{}, error) {
+	statuses, err := auth.InventoryStatus(r.Context())
+	if err != nil {
+		return nil, trace.Wrap(err)
+	}
+	return statuses, nil
+}
+
+// sendInventoryCommandReq is a request to queue a command for an agent.
+type sendInventoryCommandReq struct {
+	// HostID identifies the agent.
+	HostID string `json:"host_id"`
+	// Command is the command to queue.
+	Command InventoryCommand `json:"command"`
+}
+
+func (s *APIServer) sendInventoryCommand(auth ClientI, w http.ResponseWriter, r *http.Request, p httprouter.Params, version string) (interface{}, error) {
+	var req sendInventoryCommandReq
+	if err := httplib.ReadJSON(r, &req); err != nil {
+		return nil, trace.Wrap(err)
+	}
+	if err := auth.SendInventoryCommand(r.Context(), req.HostID, req.Command); err != nil {
+		return nil, trace.Wrap(err)
+	}
+	return message("ok"), nil
+}
+
+func (s *APIServer) postInventoryResult(auth ClientI, w http.ResponseWriter, r *http.Request, p httprouter.Params, version string) (interface{}, error) {
+	var result InventoryResult
+	if err := httplib.ReadJSON(r, &result); err != nil {
+		return nil, trace.Wrap(err)
+	}
+	if err := auth.PostInventoryResult(r.Context(), result); err != nil {
+		return nil, trace.Wrap(err)
+	}
+	return message("ok"), nil
+}
+
+func (s *APIServer) getInventoryResult(auth ClientI, w http.ResponseWriter, r *http.Request, p httprouter.Params, version string) (interface{}, error) {
+	result, err := auth.GetInventoryResult(r.Context(), p.ByName("id"))
+	if err != nil {
+		return nil, trace.Wrap(err)
+	}
+	return result, nil
+}
+
+func (s *APIServer) createBackup(auth ClientI, w http.ResponseWriter, r *http.Request, p httprouter.Params, version string) (interface{}, error) {
+	result, err := auth.CreateBackup(r.Context())
+	if err != nil {
+		return nil, trace.Wrap(err)
+	}
+	return result, nil
+}
+
+func (s *APIServer) getRevocationList(auth ClientI, w http.ResponseWriter, r *http.Request, p httprouter.Params, version string) (interface{}, error) {
+	list, err := auth.GetRevocationList(r.Context())
+	if err != nil {
+		return nil, trace.Wrap(err)
+	}
+	return list, nil
+}
+
+func (s *APIServer) getLicenseStatus(auth ClientI, w http.ResponseWriter, r *http.Request, p httprouter.Params, version string) (interface{}, error) {
+	status, err := auth.GetLicenseStatus(r.Context())
+	if err != nil {
+		return nil, trace.Wrap(err)
+	}
+	return status, nil
+}
+
+func (s *APIServer) getUsageReport(auth ClientI, w http.ResponseWriter, r *http.Request, p httprouter.Params, version string) (interface{}, error) {
+	report, err := auth.GetUsageReport(r.Context())
+	if err != nil {
+		return nil, trace.Wrap(err)
+	}
+	return report, nil
+}
+
+// updateManagedLabelsReq is a request to patch a node's managed labels.
+type updateManagedLabelsReq struct {
+	// Set are labels to set or overwrite.
+	Set map[string]string `json:"set"`
+	// Remove are label keys to remove.
+	Remove []string `json:"remove"`
+}
+
+func (s *APIServer) updateManagedLabels(auth ClientI, w http.ResponseWriter, r *http.Request, p httprouter.Params, version string) (interface{}, error) {
+	var req updateManagedLabelsReq
+	if err := httplib.ReadJSON(r, &req); err != nil {
+		return nil, trace.Wrap(err)
+	}
+	if err := auth.UpdateManagedLabels(r.Context(), p.ByName("name"), req.Set, req.Remove); err != nil {
+		return nil, trace.Wrap(err)
+	}
+	return message("ok"), nil
+}
+
+func (s *APIServer) upsertLoginRule(auth ClientI, w http.ResponseWriter, r *http.Request, p httprouter.Params, version string) (interface{}, error) {
+	var rule LoginRule
+	if err := httplib.ReadJSON(r, &rule); err != nil {
+		return nil, trace.Wrap(err)
+	}
+	if err := auth.UpsertLoginRule(r.Context(), rule); err != nil {
+		return nil, trace.Wrap(err)
+	}
+	return message("ok"), nil
+}
+
+func (s *APIServer) getLoginRules(auth ClientI, w http.ResponseWriter, r *http.Request, p httprouter.Params, version string) (interface{}, error) {
+	rules, err := auth.GetLoginRules(r.Context())
+	if err != nil {
+		return nil, trace.Wrap(err)
+	}
+	return rules, nil
+}
+
+func (s *APIServer) deleteLoginRule(auth ClientI, w http.ResponseWriter, r *http.Request, p httprouter.Params, version string) (interface{}, error) {
+	if err := auth.DeleteLoginRule(r.Context(), p.ByName("name")); err != nil {
+		return nil, trace.Wrap(err)
+	}
+	return message("ok"), nil
+}
+
 func (s *APIServer) getUsers(auth ClientI, w http.ResponseWriter, r *http.Request, p httprouter.Params, version string) (interface{}, error) {
 	users, err := auth.GetUsers(false)
 	if err != nil {
@@ -1153,13 +1389,30 @@ func (s *APIServer) changePasswordWithToken(auth ClientI, w http.ResponseWriter,
 		return nil, trace.Wrap(err)
 	}
 
-	webSession, err := auth.ChangePasswordWithToken(r.Context(), req)
+	webSession, recoveryCodes, err := auth.ChangePasswordWithToken(r.Context(), req)
 	if err != nil {
 		log.Debugf("Failed to change user password with token: %v.", err)
 		return nil, trace.Wrap(err)
 	}
 
-	return rawMessage(services.MarshalWebSession(webSession, services.WithVersion(version)))
+	rawSession, err := services.MarshalWebSession(webSession, services.WithVersion(version))
+	if err != nil {
+		return nil, trace.Wrap(err)
+	}
+	return &changePasswordWithTokenResponse{
+		Session:       rawSession,
+		RecoveryCodes: recoveryCodes,
+	}, nil
+}
+
+// changePasswordWithTokenResponse is the wire response of the change
+// password with token call
+type changePasswordWithTokenResponse struct {
+	// Session is the marshaled web session
+	Session json.RawMessage `json:"session"`
+	// RecoveryCodes are one-time account recovery codes, handed out when
+	// the password is (re)set
+	RecoveryCodes []string `json:"recovery_codes,omitempty"`
 }
 
 // getU2FAppID returns the U2F AppID in the auth configuration
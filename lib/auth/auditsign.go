@@ -0,0 +1,103 @@
+/*
+Copyright 2021 Gravitational, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package auth
+
+import (
+	"context"
+	"crypto"
+	"crypto/rand"
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/pem"
+
+	"github.com/gravitational/teleport/api/types"
+	"github.com/gravitational/teleport/lib/utils"
+
+	"github.com/gravitational/trace"
+)
+
+// AuditSignature is a signature over an exported audit archive digest,
+// produced with the cluster host CA key so the export can be verified
+// offline.
+type AuditSignature struct {
+	// ClusterName is the name of the cluster that signed the digest.
+	ClusterName string `json:"cluster_name"`
+	// Signature is the PKCS#1 v1.5 signature over the digest.
+	Signature []byte `json:"signature"`
+	// PublicKey is the PEM encoded public key the signature verifies with.
+	PublicKey []byte `json:"public_key"`
+}
+
+// SignAuditDigest signs a SHA256 digest of an exported audit archive with
+// the cluster host CA key.
+func (a *Server) SignAuditDigest(ctx context.Context, digest []byte) (*AuditSignature, error) {
+	if len(digest) != sha256.Size {
+		return nil, trace.BadParameter("expected a SHA256 digest, got %v bytes", len(digest))
+	}
+	clusterName, err := a.GetClusterName()
+	if err != nil {
+		return nil, trace.Wrap(err)
+	}
+	ca, err := a.Trust.GetCertAuthority(types.CertAuthID{
+		Type:       types.HostCA,
+		DomainName: clusterName.GetClusterName(),
+	}, true)
+	if err != nil {
+		return nil, trace.Wrap(err)
+	}
+	signer, err := caRawSigner(ca)
+	if err != nil {
+		return nil, trace.Wrap(err)
+	}
+	signature, err := signer.Sign(rand.Reader, digest, crypto.SHA256)
+	if err != nil {
+		return nil, trace.Wrap(err)
+	}
+	return &AuditSignature{
+		ClusterName: clusterName.GetClusterName(),
+		Signature:   signature,
+		PublicKey:   publicKeyPEM(signer),
+	}, nil
+}
+
+// publicKeyPEM encodes a signer's public key as PKIX PEM, empty on
+// marshaling failure.
+func publicKeyPEM(signer crypto.Signer) []byte {
+	publicKey, err := x509.MarshalPKIXPublicKey(signer.Public())
+	if err != nil {
+		return nil
+	}
+	return pem.EncodeToMemory(&pem.Block{
+		Type:  "PUBLIC KEY",
+		Bytes: publicKey,
+	})
+}
+
+// caRawSigner returns a crypto signer backed by the CA's raw private key.
+func caRawSigner(ca types.CertAuthority) (crypto.Signer, error) {
+	for _, kp := range ca.GetActiveKeys().SSH {
+		if kp.PrivateKeyType != types.PrivateKeyType_RAW {
+			continue
+		}
+		signer, err := utils.ParsePrivateKey(kp.PrivateKey)
+		if err != nil {
+			return nil, trace.Wrap(err)
+		}
+		return signer, nil
+	}
+	return nil, trace.NotFound("no raw private key found in CA for %q", ca.GetClusterName())
+}
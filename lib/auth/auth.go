@@ -51,11 +51,13 @@ import (
 	"github.com/gravitational/teleport/lib/events"
 	kubeutils "github.com/gravitational/teleport/lib/kube/utils"
 	"github.com/gravitational/teleport/lib/limiter"
+	"github.com/gravitational/teleport/lib/pagerduty"
 	"github.com/gravitational/teleport/lib/services"
 	"github.com/gravitational/teleport/lib/services/local"
 	"github.com/gravitational/teleport/lib/session"
 	"github.com/gravitational/teleport/lib/sshca"
 	"github.com/gravitational/teleport/lib/sshutils"
+	"github.com/gravitational/teleport/lib/tickets"
 	"github.com/gravitational/teleport/lib/tlsca"
 	"github.com/gravitational/teleport/lib/utils"
 	"github.com/gravitational/teleport/lib/utils/interval"
@@ -130,18 +132,31 @@ func NewServer(cfg *InitConfig, opts ...ServerOption) (*Server, error) {
 
 	closeCtx, cancelFunc := context.WithCancel(context.TODO())
 	as := Server{
-		bk:              cfg.Backend,
-		limiter:         limiter,
-		Authority:       cfg.Authority,
-		AuthServiceName: cfg.AuthServiceName,
-		oidcClients:     make(map[string]*oidcClient),
-		samlProviders:   make(map[string]*samlProvider),
-		githubClients:   make(map[string]*githubClient),
-		caSigningAlg:    cfg.CASigningAlg,
-		cancelFunc:      cancelFunc,
-		closeCtx:        closeCtx,
-		emitter:         cfg.Emitter,
-		streamer:        cfg.Streamer,
+		bk:                        cfg.Backend,
+		maxLoginAttempts:          cfg.MaxLoginAttempts,
+		accountLockInterval:       cfg.AccountLockInterval,
+		passwordPolicy:            cfg.PasswordPolicy,
+		limiter:                   limiter,
+		accessRequestMaxDuration:  cfg.AccessRequestMaxDuration,
+		accessRequestUnassumedTTL: cfg.AccessRequestUnassumedTTL,
+		accessRequestRetention:    cfg.AccessRequestRetention,
+		versionSkewLimit:          cfg.VersionSkewLimit,
+		maxWebSessions:            cfg.MaxWebSessions,
+		recordingRetention:        cfg.RecordingRetention,
+		recordingRetentionDryRun:  cfg.RecordingRetentionDryRun,
+		recordsDir:                cfg.RecordsDir,
+		dataDir:                   cfg.DataDir,
+		usageReportingEndpoint:    cfg.UsageReportingEndpoint,
+		Authority:                 cfg.Authority,
+		AuthServiceName:           cfg.AuthServiceName,
+		oidcClients:               make(map[string]*oidcClient),
+		samlProviders:             make(map[string]*samlProvider),
+		githubClients:             make(map[string]*githubClient),
+		caSigningAlg:              cfg.CASigningAlg,
+		cancelFunc:                cancelFunc,
+		closeCtx:                  closeCtx,
+		emitter:                   cfg.Emitter,
+		streamer:                  cfg.Streamer,
 		Services: Services{
 			Trust:                cfg.Trust,
 			Presence:             cfg.Presence,
@@ -155,6 +170,22 @@ func NewServer(cfg *InitConfig, opts ...ServerOption) (*Server, error) {
 			Events:               cfg.Events,
 		},
 	}
+	as.license, err = loadLicenseCapabilities(cfg.LicenseFile)
+	if err != nil {
+		log.WithError(err).Warning("Failed to load the license file, running with community defaults.")
+	}
+	if cfg.PagerDuty.APIKey != "" {
+		as.pagerduty, err = pagerduty.NewClient(cfg.PagerDuty)
+		if err != nil {
+			return nil, trace.Wrap(err)
+		}
+	}
+	if cfg.Ticketing.System != "" {
+		as.tickets, err = tickets.NewClient(cfg.Ticketing)
+		if err != nil {
+			return nil, trace.Wrap(err)
+		}
+	}
 	for _, o := range opts {
 		o(&as)
 	}
@@ -242,8 +273,8 @@ var (
 // Server keeps the cluster together. It acts as a certificate authority (CA) for
 // a cluster and:
 //   - generates the keypair for the node it's running on
-//	 - invites other SSH nodes to a cluster, by issuing invite tokens
-//	 - adds other SSH nodes to a cluster, by checking their token and signing their keys
+//   - invites other SSH nodes to a cluster, by issuing invite tokens
+//   - adds other SSH nodes to a cluster, by checking their token and signing their keys
 //   - same for users and their sessions
 //   - checks public keys to see if they're signed by it (can be trusted or not)
 type Server struct {
@@ -254,6 +285,72 @@ type Server struct {
 	clock         clockwork.Clock
 	bk            backend.Backend
 
+	// maxLoginAttempts and accountLockInterval define the account lockout
+	// policy for failed local login attempts
+	maxLoginAttempts    int
+	accountLockInterval time.Duration
+
+	// passwordPolicy defines the requirements new local user passwords
+	// must satisfy
+	passwordPolicy services.PasswordPolicy
+
+	// pagerduty, when set, auto-approves access requests from users who
+	// are on call for the mapped PagerDuty schedules
+	pagerduty *pagerduty.Client
+
+	// tickets, when set, tracks access requests as Jira or ServiceNow
+	// tickets and syncs their status back
+	tickets *tickets.Client
+
+	// accessRequestMaxDuration, accessRequestUnassumedTTL and
+	// accessRequestRetention drive the access request lifecycle, zero
+	// disables the respective policy
+	accessRequestMaxDuration  time.Duration
+	accessRequestUnassumedTTL time.Duration
+	accessRequestRetention    time.Duration
+
+	// maxWebSessions caps concurrent web sessions per user, zero
+	// disables the limit
+	maxWebSessions int
+
+	// versionSkewLimit is how many major versions an agent may lag
+	// behind the auth server and still join, zero disables the check
+	versionSkewLimit int
+
+	// recordingRetention, recordingRetentionDryRun and recordsDir drive
+	// the session recording retention policy, zero retention disables it
+	recordingRetention       time.Duration
+	recordingRetentionDryRun bool
+	recordsDir               string
+
+	// dataDir is the auth server data directory, backend backups are
+	// written under it
+	dataDir string
+
+	// usageReportingEndpoint, when set, enables periodic submission of
+	// anonymized usage reports
+	usageReportingEndpoint string
+
+	// license holds the loaded capability file, nil for community
+	// defaults
+	license *licenseCapabilities
+
+	// userCASigners caches parsed user CA signers across certificate
+	// issuance requests
+	userCASigners signerCache
+
+	// inventoryMu guards inventoryAgents, the agents connected to the
+	// inventory control channel, and inventoryResults, their pending
+	// diagnostics answers
+	inventoryMu      sync.Mutex
+	inventoryAgents  map[string]*inventoryAgent
+	inventoryResults map[string]inventoryResult
+
+	// revocationMu guards revocationList, the cached signed revocation
+	// list served to polling nodes
+	revocationMu   sync.Mutex
+	revocationList *RevocationList
+
 	closeCtx   context.Context
 	cancelFunc context.CancelFunc
 
@@ -327,12 +424,42 @@ func (a *Server) runPeriodicOperations() {
 		Jitter:   utils.NewSeventhJitter(),
 	})
 	missedKeepAliveCount := 0
+	ticketSyncTicker := time.NewTicker(ticketSyncPeriod)
+	requestCleanupTicker := time.NewTicker(accessRequestCleanupPeriod)
+	clusterAlertsTicker := time.NewTicker(clusterAlertsCheckPeriod)
+	retentionTicker := time.NewTicker(recordingRetentionCheckPeriod)
+	usageTicker := time.NewTicker(usageReportPeriod)
+	licenseTicker := time.NewTicker(licenseCheckPeriod)
 	defer ticker.Stop()
 	defer heartbeatCheckTicker.Stop()
+	defer ticketSyncTicker.Stop()
+	defer requestCleanupTicker.Stop()
+	defer clusterAlertsTicker.Stop()
+	defer retentionTicker.Stop()
+	defer usageTicker.Stop()
+	defer licenseTicker.Stop()
 	for {
 		select {
 		case <-a.closeCtx.Done():
 			return
+		case <-ticketSyncTicker.C:
+			if a.tickets != nil {
+				a.syncAccessRequestTickets(ctx)
+			}
+		case <-requestCleanupTicker.C:
+			if a.accessRequestUnassumedTTL > 0 || a.accessRequestRetention > 0 {
+				a.cleanupAccessRequests(ctx)
+			}
+		case <-clusterAlertsTicker.C:
+			a.checkClusterHealth(ctx)
+		case <-retentionTicker.C:
+			a.pruneSessionRecordings()
+		case <-usageTicker.C:
+			if a.usageReportingEndpoint != "" {
+				a.submitUsageReport(ctx)
+			}
+		case <-licenseTicker.C:
+			a.checkLicenseLimits(ctx)
 		case <-ticker.C:
 			err := a.autoRotateCertAuthorities()
 			if err != nil {
@@ -814,17 +941,27 @@ func (a *Server) generateUserCert(req certRequest) (*certs, error) {
 	if err != nil {
 		return nil, trace.Wrap(err)
 	}
-	caSigner, err := sshSigner(ca)
+	// Parsing the CA key dominates issuance cost under load, reuse the
+	// parsed signer until the CA changes.
+	caSigner, err := a.userCASigners.sshSignerFor(ca)
 	if err != nil {
 		return nil, trace.Wrap(err)
 	}
 
+	var allowedResourceIDs []string
+	if len(req.activeRequests.AccessRequests) != 0 {
+		allowedResourceIDs, err = a.processActiveRequests(context.TODO(), req.activeRequests.AccessRequests)
+		if err != nil {
+			return nil, trace.Wrap(err)
+		}
+	}
 	params := services.UserCertParams{
 		CASigner:              caSigner,
 		CASigningAlg:          sshutils.GetSigningAlgName(ca),
 		PublicUserKey:         req.publicKey,
 		Username:              req.user.GetName(),
 		Impersonator:          req.impersonator,
+		SessionID:             req.appSessionID,
 		AllowedLogins:         allowedLogins,
 		TTL:                   sessionTTL,
 		Roles:                 req.checker.RoleNames(),
@@ -837,12 +974,8 @@ func (a *Server) generateUserCert(req certRequest) (*certs, error) {
 		ActiveRequests:        req.activeRequests,
 		MFAVerified:           req.mfaVerified,
 		ClientIP:              req.clientIP,
+		AllowedResourceIDs:    allowedResourceIDs,
 	}
-	sshCert, err := a.Authority.GenerateUserCert(params)
-	if err != nil {
-		return nil, trace.Wrap(err)
-	}
-
 	kubeGroups, kubeUsers, err := req.checker.CheckKubeGroupsAndUsers(sessionTTL, req.overrideRoleTTL)
 	// NotFound errors are acceptable - this user may have no k8s access
 	// granted and that shouldn't prevent us from issuing a TLS cert.
@@ -869,7 +1002,7 @@ func (a *Server) generateUserCert(req certRequest) (*certs, error) {
 	}
 
 	// generate TLS certificate
-	tlsAuthority, err := tlsca.FromAuthority(ca)
+	tlsAuthority, err := a.userCASigners.tlsAuthorityFor(ca)
 	if err != nil {
 		return nil, trace.Wrap(err)
 	}
@@ -912,21 +1045,54 @@ func (a *Server) generateUserCert(req certRequest) (*certs, error) {
 		Subject:   subject,
 		NotAfter:  a.clock.Now().UTC().Add(sessionTTL),
 	}
-	tlsCert, err := tlsAuthority.GenerateCertificate(certRequest)
-	if err != nil {
-		return nil, trace.Wrap(err)
+
+	// The SSH and TLS signatures are independent, produce them
+	// concurrently.
+	var wg sync.WaitGroup
+	var sshCert, tlsCert []byte
+	var sshErr, tlsErr error
+	wg.Add(2)
+	go func() {
+		defer wg.Done()
+		sshCert, sshErr = a.Authority.GenerateUserCert(params)
+	}()
+	go func() {
+		defer wg.Done()
+		tlsCert, tlsErr = tlsAuthority.GenerateCertificate(certRequest)
+	}()
+	wg.Wait()
+	if sshErr != nil {
+		return nil, trace.Wrap(sshErr)
+	}
+	if tlsErr != nil {
+		return nil, trace.Wrap(tlsErr)
 	}
 	return &certs{ssh: sshCert, tls: tlsCert}, nil
 }
 
+// loginLockoutPolicy returns the configured account lockout policy,
+// falling back to the defaults.
+func (a *Server) loginLockoutPolicy() (maxAttempts int, lockInterval time.Duration) {
+	maxAttempts = a.maxLoginAttempts
+	if maxAttempts == 0 {
+		maxAttempts = defaults.MaxLoginAttempts
+	}
+	lockInterval = a.accountLockInterval
+	if lockInterval == 0 {
+		lockInterval = defaults.AccountLockInterval
+	}
+	return maxAttempts, lockInterval
+}
+
 // WithUserLock executes function authenticateFn that performs user authentication
 // if authenticateFn returns non nil error, the login attempt will be logged in as failed.
 // The only exception to this rule is ConnectionProblemError, in case if it occurs
 // access will be denied, but login attempt will not be recorded
 // this is done to avoid potential user lockouts due to backend failures
-// In case if user exceeds defaults.MaxLoginAttempts
-// the user account will be locked for defaults.AccountLockInterval
+// In case if user exceeds the configured number of failed login attempts
+// the user account will be locked for the configured lock interval
 func (a *Server) WithUserLock(username string, authenticateFn func() error) error {
+	maxLoginAttempts, accountLockInterval := a.loginLockoutPolicy()
 	user, err := a.Identity.GetUser(username, false)
 	if err != nil {
 		if trace.IsNotFound(err) {
@@ -940,7 +1106,7 @@ func (a *Server) WithUserLock(username string, authenticateFn func() error) erro
 	status := user.GetStatus()
 	if status.IsLocked && status.LockExpires.After(a.clock.Now().UTC()) {
 		return trace.AccessDenied("%v exceeds %v failed login attempts, locked until %v",
-			user.GetName(), defaults.MaxLoginAttempts, apiutils.HumanTimeFormat(status.LockExpires))
+			user.GetName(), maxLoginAttempts, apiutils.HumanTimeFormat(status.LockExpires))
 	}
 	fnErr := authenticateFn()
 	if fnErr == nil {
@@ -968,13 +1134,13 @@ func (a *Server) WithUserLock(username string, authenticateFn func() error) erro
 		log.Error(trace.DebugReport(err))
 		return trace.Wrap(fnErr)
 	}
-	if !services.LastFailed(defaults.MaxLoginAttempts, loginAttempts) {
-		log.Debugf("%v user has less than %v failed login attempts", username, defaults.MaxLoginAttempts)
+	if !services.LastFailed(maxLoginAttempts, loginAttempts) {
+		log.Debugf("%v user has less than %v failed login attempts", username, maxLoginAttempts)
 		return trace.Wrap(fnErr)
 	}
-	lockUntil := a.clock.Now().UTC().Add(defaults.AccountLockInterval)
+	lockUntil := a.clock.Now().UTC().Add(accountLockInterval)
 	message := fmt.Sprintf("%v exceeds %v failed login attempts, locked until %v",
-		username, defaults.MaxLoginAttempts, apiutils.HumanTimeFormat(status.LockExpires))
+		username, maxLoginAttempts, apiutils.HumanTimeFormat(status.LockExpires))
 	log.Debug(message)
 	user.SetLocked(lockUntil, "user has exceeded maximum failed login attempts")
 	err = a.Identity.UpsertUser(user)
@@ -1024,12 +1190,20 @@ type MFAAuthenticateChallenge struct {
 func (a *Server) GetMFAAuthenticateChallenge(user string, password []byte) (*MFAAuthenticateChallenge, error) {
 	ctx := context.TODO()
 
-	err := a.WithUserLock(user, func() error {
-		return a.checkPasswordWOToken(user, password)
-	})
+	authPref, err := a.GetAuthPreference(ctx)
 	if err != nil {
 		return nil, trace.Wrap(err)
 	}
+	// In passwordless mode the hardware key tap is the only factor, so the
+	// challenge request is not gated on a password check.
+	if authPref.GetSecondFactor() != constants.SecondFactorPasswordless {
+		err := a.WithUserLock(user, func() error {
+			return a.checkPasswordWOToken(user, password)
+		})
+		if err != nil {
+			return nil, trace.Wrap(err)
+		}
+	}
 
 	protoChal, err := a.mfaAuthChallenge(ctx, user, a.Identity)
 	if err != nil {
@@ -1587,6 +1761,9 @@ type RegisterUsingTokenRequest struct {
 	// RemoteAddr is the remote address of the host requesting a host certificate.
 	// It is used to replace 0.0.0.0 in the list of additional principals.
 	RemoteAddr string `json:"remote_addr"`
+	// Version is the Teleport version of the joining agent, empty for
+	// agents predating the field.
+	Version string `json:"version,omitempty"`
 }
 
 // CheckAndSetDefaults checks for errors and sets defaults
@@ -1617,6 +1794,11 @@ func (a *Server) RegisterUsingToken(req RegisterUsingTokenRequest) (*PackedKeys,
 		return nil, trace.Wrap(err)
 	}
 
+	// reject agents running a version too far behind the auth server
+	if err := a.checkVersionSkewLimit(req); err != nil {
+		return nil, trace.Wrap(err)
+	}
+
 	// make sure the token is valid
 	roles, _, err := a.ValidateToken(req.Token)
 	if err != nil {
@@ -1646,6 +1828,17 @@ func (a *Server) RegisterUsingToken(req RegisterUsingTokenRequest) (*PackedKeys,
 		return nil, trace.Wrap(err)
 	}
 	log.Infof("Node %q [%v] has joined the cluster.", req.NodeName, req.HostID)
+
+	// Single-use tokens are consumed by the successful registration.
+	if tok, err := a.GetCache().GetToken(context.TODO(), req.Token); err == nil {
+		if tok.GetMetadata().Labels[teleport.TokenLabelSingleUse] != "" {
+			if err := a.DeleteToken(context.TODO(), req.Token); err != nil {
+				log.WithError(err).Warn("Failed to delete single-use token after registration.")
+			} else {
+				log.Infof("Single-use token consumed by %q [%v].", req.NodeName, req.HostID)
+			}
+		}
+	}
 	return keys, nil
 }
 
@@ -1756,6 +1949,12 @@ func (a *Server) NewWebSession(req types.NewWebSessionRequest) (types.WebSession
 		return nil, trace.Wrap(err)
 	}
 	bearerTokenTTL := utils.MinTTL(sessionTTL, BearerTokenTTL)
+	if idleTimeout := checker.AdjustClientIdleTimeout(0); idleTimeout != 0 {
+		// Per-user web session idle timeout: the UI renews the bearer
+		// token while the session is in active use, so capping the bearer
+		// token TTL expires idle browser sessions.
+		bearerTokenTTL = utils.MinTTL(bearerTokenTTL, idleTimeout)
+	}
 
 	startTime := a.clock.Now()
 	if !req.LoginTime.IsZero() {
@@ -1897,7 +2096,11 @@ func (a *Server) upsertRole(ctx context.Context, role types.Role) error {
 }
 
 func (a *Server) CreateAccessRequest(ctx context.Context, req types.AccessRequest) error {
-	err := services.ValidateAccessRequestForUser(a, req,
+	resourceIDs, err := a.translateResourceRequest(ctx, req)
+	if err != nil {
+		return trace.Wrap(err)
+	}
+	err = services.ValidateAccessRequestForUser(a, req,
 		// if request is in state pending, variable expansion must be applied
 		services.ExpandVars(req.GetState().IsPending()),
 	)
@@ -1917,6 +2120,16 @@ func (a *Server) CreateAccessRequest(ctx context.Context, req types.AccessReques
 	}
 	// By default, resource expiry should match access expiry.
 	req.SetExpiry(req.GetAccessExpiry())
+	// Record the requested resource IDs after validation, role-derived
+	// annotations are applied there and would overwrite them.
+	if len(resourceIDs) != 0 {
+		annotations := req.GetSystemAnnotations()
+		if annotations == nil {
+			annotations = map[string][]string{}
+		}
+		annotations[requestedResourcesAnnotation] = resourceIDs
+		req.SetSystemAnnotations(annotations)
+	}
 	// If the access-request is in a pending state, then the expiry of the underlying resource
 	// is capped to to PendingAccessDuration in order to limit orphaned access requests.
 	if req.GetState().IsPending() {
@@ -1948,6 +2161,8 @@ func (a *Server) CreateAccessRequest(ctx context.Context, req types.AccessReques
 	if err != nil {
 		log.WithError(err).Warn("Failed to emit access request create event.")
 	}
+	a.maybeStartPagerDutyAutoApproval(req)
+	a.maybeCreateAccessRequestTicket(req)
 	return nil
 }
 
@@ -1987,6 +2202,16 @@ func (a *Server) SetAccessRequestState(ctx context.Context, params types.AccessR
 	if err != nil {
 		log.WithError(err).Warn("Failed to emit access request update event.")
 	}
+	// Drop a notice into the requesting user's notification center.
+	notifyErr := a.CreateNotification(ctx, Notification{
+		User:    req.GetUser(),
+		Type:    NotificationTypeAccessRequest,
+		Title:   fmt.Sprintf("Access request %v", strings.ToLower(params.State.String())),
+		Message: fmt.Sprintf("Your access request %v was %v.", params.RequestID, strings.ToLower(params.State.String())),
+	})
+	if notifyErr != nil {
+		log.WithError(notifyErr).Warnf("Failed to create notification for access request %v.", params.RequestID)
+	}
 	return trace.Wrap(err)
 }
 
@@ -2058,6 +2283,9 @@ func (a *Server) GetAccessCapabilities(ctx context.Context, req types.AccessCapa
 // exceed the smallest allowable MaxSessionTTL value of the roles that it requests).
 func (a *Server) calculateMaxAccessTTL(ctx context.Context, req types.AccessRequest) (time.Duration, error) {
 	minTTL := defaults.MaxAccessDuration
+	if a.accessRequestMaxDuration > 0 && a.accessRequestMaxDuration < minTTL {
+		minTTL = a.accessRequestMaxDuration
+	}
 	for _, roleName := range req.GetRoles() {
 		role, err := a.GetRole(ctx, roleName)
 		if err != nil {
@@ -2402,7 +2630,7 @@ func (a *Server) mfaAuthChallenge(ctx context.Context, user string, u2fStorage u
 	switch apref.GetSecondFactor() {
 	case constants.SecondFactorOTP:
 		enableTOTP, enableU2F = true, false
-	case constants.SecondFactorU2F:
+	case constants.SecondFactorU2F, constants.SecondFactorPasswordless:
 		enableTOTP, enableU2F = false, true
 	default:
 		// Other AuthPreference types don't restrict us to a single MFA type,
@@ -2523,6 +2751,9 @@ func (a *Server) upsertWebSession(ctx context.Context, user string, session type
 	if err := a.WebSessions().Upsert(ctx, session); err != nil {
 		return trace.Wrap(err)
 	}
+	if err := a.indexWebSession(ctx, session); err != nil {
+		log.WithError(err).Warning("Failed to index the web session.")
+	}
 	token, err := types.NewWebToken(session.GetBearerTokenExpiryTime(), types.WebTokenSpecV3{
 		User:  session.GetUser(),
 		Token: session.GetBearerToken(),
@@ -19,6 +19,7 @@ package auth
 import (
 	"context"
 	"net/url"
+	"sync"
 	"time"
 
 	"github.com/gravitational/teleport"
@@ -82,6 +83,16 @@ func (a *ServerWithRoles) action(namespace string, resource string, action strin
 	return a.context.Checker.CheckAccessToRule(&services.Context{User: a.context.User}, namespace, resource, action, cfg.quiet)
 }
 
+// actionForSession checks access to an SSH session, letting rules with
+// where clauses reference the session. Sessions are readable through the
+// implicit rules, so conditions are expressed as deny rules, e.g.
+// deny where !contains(session.participants, user.metadata.name).
+func (a *ServerWithRoles) actionForSession(namespace string, verb string, s *session.Session) error {
+	return a.context.Checker.CheckAccessToRule(
+		&services.Context{User: a.context.User, Session: s},
+		namespace, types.KindSSHSession, verb, true)
+}
+
 // currentUserAction is a special checker that allows certain actions for users
 // even if they are not admins, e.g. update their own passwords,
 // or generate certificates, otherwise it will require admin privileges
@@ -174,18 +185,34 @@ func (a *ServerWithRoles) AuthenticateSSHUser(req AuthenticateSSHRequest) (*SSHL
 }
 
 func (a *ServerWithRoles) GetSessions(namespace string) ([]session.Session, error) {
-	if err := a.action(namespace, types.KindSSHSession, types.VerbList); err != nil {
+	sessions, err := a.sessions.GetSessions(namespace)
+	if err != nil {
 		return nil, trace.Wrap(err)
 	}
-
-	return a.sessions.GetSessions(namespace)
+	// Access is checked per session rather than up front: rules with
+	// where clauses can restrict visibility to individual sessions, e.g.
+	// to sessions the user participates in, and must see the session to
+	// evaluate. Plain rules behave as before.
+	filtered := make([]session.Session, 0, len(sessions))
+	for i := range sessions {
+		if err := a.actionForSession(namespace, types.VerbList, &sessions[i]); err == nil {
+			filtered = append(filtered, sessions[i])
+		}
+	}
+	return filtered, nil
 }
 
 func (a *ServerWithRoles) GetSession(namespace string, id session.ID) (*session.Session, error) {
-	if err := a.action(namespace, types.KindSSHSession, types.VerbRead); err != nil {
+	s, err := a.sessions.GetSession(namespace, id)
+	if err != nil {
 		return nil, trace.Wrap(err)
 	}
-	return a.sessions.GetSession(namespace, id)
+	// Checked against the fetched session so where clauses can evaluate,
+	// plain rules behave as before.
+	if err := a.actionForSession(namespace, types.VerbRead, s); err != nil {
+		return nil, trace.Wrap(err)
+	}
+	return s, nil
 }
 
 func (a *ServerWithRoles) CreateSession(s session.Session) error {
@@ -1361,6 +1388,49 @@ func (a *ServerWithRoles) GenerateUserCerts(ctx context.Context, req proto.UserC
 	return a.generateUserCerts(ctx, req)
 }
 
+// maxUserCertsBatchSize caps the number of certificates a single batch
+// issuance request may ask for.
+const maxUserCertsBatchSize = 64
+
+// userCertsBatchParallelism bounds how many certificates of a batch are
+// signed concurrently, so one large batch cannot monopolize the auth
+// server.
+const userCertsBatchParallelism = 8
+
+// GenerateUserCertsBatch issues one certificate per request, signing up
+// to userCertsBatchParallelism of them concurrently. Bots that need
+// certificates for many role combinations use this instead of issuing
+// sequential GenerateUserCerts calls. Results are returned in request
+// order; any failed entry fails the whole batch.
+func (a *ServerWithRoles) GenerateUserCertsBatch(ctx context.Context, reqs []proto.UserCertsRequest) ([]*proto.Certs, error) {
+	if len(reqs) == 0 {
+		return nil, trace.BadParameter("batch certificate request requires at least one entry")
+	}
+	if len(reqs) > maxUserCertsBatchSize {
+		return nil, trace.BadParameter("batch certificate request exceeds the limit of %v entries", maxUserCertsBatchSize)
+	}
+	results := make([]*proto.Certs, len(reqs))
+	errors := make([]error, len(reqs))
+	semaphore := make(chan struct{}, userCertsBatchParallelism)
+	var wg sync.WaitGroup
+	for i := range reqs {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			semaphore <- struct{}{}
+			defer func() { <-semaphore }()
+			results[i], errors[i] = a.generateUserCerts(ctx, reqs[i])
+		}(i)
+	}
+	wg.Wait()
+	for i, err := range errors {
+		if err != nil {
+			return nil, trace.Wrap(err, "batch entry %v", i)
+		}
+	}
+	return results, nil
+}
+
 func (a *ServerWithRoles) generateUserCerts(ctx context.Context, req proto.UserCertsRequest, opts ...certRequestOption) (*proto.Certs, error) {
 	var err error
 	var roles []string
@@ -1501,12 +1571,21 @@ func (a *ServerWithRoles) generateUserCerts(ctx context.Context, req proto.UserC
 		if err != nil {
 			log.Warning(err)
 			err := trace.AccessDenied("user %q has requested to generate certs for %q.", a.context.User.GetName(), roles)
+			// Record who attempted the impersonation, so failed attempts can
+			// be traced back to the service account that issued them.
+			userMetadata := apievents.UserMetadata{
+				User: a.context.User.GetName(),
+			}
+			if a.context.Identity != nil {
+				userMetadata.Impersonator = a.context.Identity.GetIdentity().Impersonator
+			}
 			if err := a.authServer.emitter.EmitAuditEvent(a.CloseContext(), &apievents.UserLogin{
 				Metadata: apievents.Metadata{
 					Type: events.UserLoginEvent,
 					Code: events.UserLocalLoginFailureCode,
 				},
-				Method: events.LoginMethodClientCert,
+				UserMetadata: userMetadata,
+				Method:       events.LoginMethodClientCert,
 				Status: apievents.Status{
 					Success:     false,
 					Error:       trace.Unwrap(err).Error(),
@@ -1590,6 +1669,15 @@ func (a *ServerWithRoles) CreateResetPasswordToken(ctx context.Context, req Crea
 	return a.authServer.CreateResetPasswordToken(ctx, req)
 }
 
+// GetResetPasswordTokens returns all pending user invite and password
+// reset tokens.
+func (a *ServerWithRoles) GetResetPasswordTokens(ctx context.Context) ([]types.ResetPasswordToken, error) {
+	if err := a.action(apidefaults.Namespace, types.KindResetPasswordToken, types.VerbList); err != nil {
+		return nil, trace.Wrap(err)
+	}
+	return a.authServer.GetResetPasswordTokens(ctx)
+}
+
 func (a *ServerWithRoles) GetResetPasswordToken(ctx context.Context, tokenID string) (types.ResetPasswordToken, error) {
 	// tokens are their own authz mechanism, no need to double check
 	return a.authServer.GetResetPasswordToken(ctx, tokenID)
@@ -1600,7 +1688,7 @@ func (a *ServerWithRoles) RotateResetPasswordTokenSecrets(ctx context.Context, t
 	return a.authServer.RotateResetPasswordTokenSecrets(ctx, tokenID)
 }
 
-func (a *ServerWithRoles) ChangePasswordWithToken(ctx context.Context, req ChangePasswordWithTokenRequest) (types.WebSession, error) {
+func (a *ServerWithRoles) ChangePasswordWithToken(ctx context.Context, req ChangePasswordWithTokenRequest) (types.WebSession, []string, error) {
 	// Token is it's own authentication, no need to double check.
 	return a.authServer.ChangePasswordWithToken(ctx, req)
 }
@@ -2279,7 +2367,12 @@ func (a *ServerWithRoles) SetAuthPreference(ctx context.Context, newAuthPref typ
 		}
 	}
 
-	return a.authServer.SetAuthPreference(ctx, newAuthPref)
+	if err := a.authServer.SetAuthPreference(ctx, newAuthPref); err != nil {
+		return trace.Wrap(err)
+	}
+	log.WithField("user", a.context.User.GetName()).Infof(
+		"Cluster auth preference updated: %v.", configResourceDiff(storedAuthPref, newAuthPref))
+	return nil
 }
 
 // ResetAuthPreference resets cluster auth preference to defaults.
@@ -2343,7 +2436,12 @@ func (a *ServerWithRoles) SetClusterNetworkingConfig(ctx context.Context, newNet
 		}
 	}
 
-	return a.authServer.SetClusterNetworkingConfig(ctx, newNetConfig)
+	if err := a.authServer.SetClusterNetworkingConfig(ctx, newNetConfig); err != nil {
+		return trace.Wrap(err)
+	}
+	log.WithField("user", a.context.User.GetName()).Infof(
+		"Cluster networking configuration updated: %v.", configResourceDiff(storedNetConfig, newNetConfig))
+	return nil
 }
 
 // ResetClusterNetworkingConfig resets cluster networking configuration to defaults.
@@ -2384,7 +2482,16 @@ func (a *ServerWithRoles) SetSessionRecordingConfig(ctx context.Context, recConf
 	if err := a.action(apidefaults.Namespace, types.KindSessionRecordingConfig, types.VerbUpdate); err != nil {
 		return trace.Wrap(err)
 	}
-	return a.authServer.SetSessionRecordingConfig(ctx, recConfig)
+	storedRecConfig, err := a.authServer.GetSessionRecordingConfig(ctx)
+	if err != nil {
+		return trace.Wrap(err)
+	}
+	if err := a.authServer.SetSessionRecordingConfig(ctx, recConfig); err != nil {
+		return trace.Wrap(err)
+	}
+	log.WithField("user", a.context.User.GetName()).Infof(
+		"Session recording configuration updated: %v.", configResourceDiff(storedRecConfig, recConfig))
+	return nil
 }
 
 // ResetSessionRecordingConfig resets session recording configuration to defaults.
@@ -3104,6 +3211,187 @@ func (a *ServerWithRoles) IsMFARequired(ctx context.Context, req *proto.IsMFAReq
 	return a.authServer.isMFARequired(ctx, a.context.Checker, req)
 }
 
+// SignAuditDigest signs an exported audit archive digest with the cluster
+// host CA key. It requires the same permissions as searching the audit log.
+func (a *ServerWithRoles) SignAuditDigest(ctx context.Context, digest []byte) (*AuditSignature, error) {
+	if err := a.action(apidefaults.Namespace, types.KindEvent, types.VerbList); err != nil {
+		return nil, trace.Wrap(err)
+	}
+	return a.authServer.SignAuditDigest(ctx, digest)
+}
+
+// CreateBackup snapshots the backend on the auth server. Requires
+// update access to auth servers, an administrative operation.
+func (a *ServerWithRoles) CreateBackup(ctx context.Context) (*BackupResult, error) {
+	if err := a.action(apidefaults.Namespace, types.KindAuthServer, types.VerbUpdate); err != nil {
+		return nil, trace.Wrap(err)
+	}
+	return a.authServer.CreateBackup(ctx)
+}
+
+// GetLicenseStatus reports the effective license state.
+func (a *ServerWithRoles) GetLicenseStatus(ctx context.Context) (*LicenseStatus, error) {
+	if err := a.action(apidefaults.Namespace, types.KindNode, types.VerbList); err != nil {
+		return nil, trace.Wrap(err)
+	}
+	return a.authServer.GetLicenseStatus(ctx)
+}
+
+// GetUsageReport builds the anonymized usage report.
+func (a *ServerWithRoles) GetUsageReport(ctx context.Context) (*UsageReport, error) {
+	if err := a.action(apidefaults.Namespace, types.KindEvent, types.VerbList); err != nil {
+		return nil, trace.Wrap(err)
+	}
+	return a.authServer.GetUsageReport(ctx)
+}
+
+// UpdateManagedLabels sets and removes centrally managed labels on a
+// node.
+func (a *ServerWithRoles) UpdateManagedLabels(ctx context.Context, nodeName string, set map[string]string, remove []string) error {
+	if err := a.action(apidefaults.Namespace, types.KindNode, types.VerbUpdate); err != nil {
+		return trace.Wrap(err)
+	}
+	return a.authServer.UpdateManagedLabels(ctx, nodeName, set, remove)
+}
+
+// UpsertLoginRule stores a login rule.
+func (a *ServerWithRoles) UpsertLoginRule(ctx context.Context, rule LoginRule) error {
+	if err := a.action(apidefaults.Namespace, types.KindLoginRule, types.VerbCreate); err != nil {
+		return trace.Wrap(err)
+	}
+	if err := a.action(apidefaults.Namespace, types.KindLoginRule, types.VerbUpdate); err != nil {
+		return trace.Wrap(err)
+	}
+	return a.authServer.UpsertLoginRule(ctx, rule)
+}
+
+// GetLoginRules returns all login rules.
+func (a *ServerWithRoles) GetLoginRules(ctx context.Context) ([]LoginRule, error) {
+	if err := a.action(apidefaults.Namespace, types.KindLoginRule, types.VerbList); err != nil {
+		return nil, trace.Wrap(err)
+	}
+	return a.authServer.GetLoginRules(ctx)
+}
+
+// DeleteLoginRule removes a login rule.
+func (a *ServerWithRoles) DeleteLoginRule(ctx context.Context, name string) error {
+	if err := a.action(apidefaults.Namespace, types.KindLoginRule, types.VerbDelete); err != nil {
+		return trace.Wrap(err)
+	}
+	return a.authServer.DeleteLoginRule(ctx, name)
+}
+
+// GetRevocationList returns the signed revocation list. Any
+// authenticated cluster identity may read it, nodes poll it frequently.
+func (a *ServerWithRoles) GetRevocationList(ctx context.Context) (*RevocationList, error) {
+	return a.authServer.GetRevocationList(ctx)
+}
+
+// inventoryHostID verifies the caller holds a builtin host role and
+// returns the host ID bound into its certificate, so agents can only
+// speak for themselves on the inventory control channel.
+func (a *ServerWithRoles) inventoryHostID() (string, error) {
+	hostRoles := []types.SystemRole{
+		types.RoleNode, types.RoleProxy, types.RoleApp,
+		types.RoleDatabase, types.RoleKube,
+	}
+	var isHost bool
+	for _, role := range hostRoles {
+		if a.hasBuiltinRole(string(role)) {
+			isHost = true
+			break
+		}
+	}
+	if !isHost {
+		return "", trace.AccessDenied("[10] access denied")
+	}
+	clusterName, err := a.GetDomainName()
+	if err != nil {
+		return "", trace.Wrap(err)
+	}
+	hostID, err := ExtractHostID(a.context.User.GetName(), clusterName)
+	if err != nil {
+		return "", trace.AccessDenied("[10] access denied")
+	}
+	return hostID, nil
+}
+
+// InventoryHello registers the calling agent on the inventory control
+// channel. Only builtin host identities may register, and only under
+// the host ID bound into their own certificate: reusing another host's
+// ID would let the caller race the real agent for queued commands.
+func (a *ServerWithRoles) InventoryHello(ctx context.Context, hello InventoryHello) ([]InventoryCommand, error) {
+	hostID, err := a.inventoryHostID()
+	if err != nil {
+		return nil, trace.Wrap(err)
+	}
+	if hello.HostID != hostID {
+		return nil, trace.AccessDenied("inventory hello host ID %q does not match the caller's host identity", hello.HostID)
+	}
+	return a.authServer.InventoryHello(ctx, hello)
+}
+
+// InventoryStatus lists the agents connected to the control channel.
+// Gated on audit event access: the implicit rules grant node reads to
+// every user, which must not expose the control channel.
+func (a *ServerWithRoles) InventoryStatus(ctx context.Context) ([]InventoryAgentStatus, error) {
+	if err := a.action(apidefaults.Namespace, types.KindEvent, types.VerbList); err != nil {
+		return nil, trace.Wrap(err)
+	}
+	return a.authServer.InventoryStatus(ctx)
+}
+
+// SendInventoryCommand queues a command for a connected agent.
+func (a *ServerWithRoles) SendInventoryCommand(ctx context.Context, hostID string, command InventoryCommand) error {
+	if err := a.action(apidefaults.Namespace, types.KindNode, types.VerbUpdate); err != nil {
+		return trace.Wrap(err)
+	}
+	return a.authServer.SendInventoryCommand(ctx, hostID, command)
+}
+
+// PostInventoryResult stores the calling agent's answer to a diagnostics
+// command. Only builtin host identities may post, and only results
+// attributed to their own host ID.
+func (a *ServerWithRoles) PostInventoryResult(ctx context.Context, result InventoryResult) error {
+	hostID, err := a.inventoryHostID()
+	if err != nil {
+		return trace.Wrap(err)
+	}
+	if result.HostID != hostID {
+		return trace.AccessDenied("inventory result host ID %q does not match the caller's host identity", result.HostID)
+	}
+	return a.authServer.PostInventoryResult(ctx, result)
+}
+
+// GetInventoryResult returns a stored diagnostics result. Gated on
+// audit event access like InventoryStatus: results hold collected agent
+// logs and profiles.
+func (a *ServerWithRoles) GetInventoryResult(ctx context.Context, id string) (*InventoryResult, error) {
+	if err := a.action(apidefaults.Namespace, types.KindEvent, types.VerbList); err != nil {
+		return nil, trace.Wrap(err)
+	}
+	return a.authServer.GetInventoryResult(ctx, id)
+}
+
+// CreateNotification stores a user or cluster-wide notification. Only
+// users allowed to create notification resources may post them.
+func (a *ServerWithRoles) CreateNotification(ctx context.Context, notification Notification) error {
+	if err := a.action(apidefaults.Namespace, types.KindNotification, types.VerbCreate); err != nil {
+		return trace.Wrap(err)
+	}
+	return a.authServer.CreateNotification(ctx, notification)
+}
+
+// GetNotifications returns the calling user's notifications.
+func (a *ServerWithRoles) GetNotifications(ctx context.Context) ([]Notification, error) {
+	return a.authServer.GetNotifications(ctx, a.context.User.GetName())
+}
+
+// AckNotification dismisses one of the calling user's notifications.
+func (a *ServerWithRoles) AckNotification(ctx context.Context, id string) error {
+	return a.authServer.AckNotification(ctx, a.context.User.GetName(), id)
+}
+
 // SearchEvents allows searching audit events with pagination support.
 func (a *ServerWithRoles) SearchEvents(fromUTC, toUTC time.Time, namespace string, eventTypes []string, limit int, order types.EventOrder, startKey string) (events []apievents.AuditEvent, lastKey string, err error) {
 	if err := a.action(apidefaults.Namespace, types.KindEvent, types.VerbList); err != nil {
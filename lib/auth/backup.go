@@ -0,0 +1,65 @@
+/*
+Copyright 2021 Gravitational, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package auth
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/gravitational/teleport/lib/backend"
+
+	"github.com/gravitational/trace"
+)
+
+// backupsDir is the directory inside the auth server data directory
+// online backend backups are written to.
+const backupsDir = "backups"
+
+// BackupResult describes a completed online backup.
+type BackupResult struct {
+	// Path is where the snapshot was written on the auth server.
+	Path string `json:"path"`
+	// CreatedAt is when the snapshot completed.
+	CreatedAt time.Time `json:"created_at"`
+}
+
+// CreateBackup snapshots the backend into the data directory without
+// stopping the auth server. Only backends supporting online backups
+// (currently sqlite) can be snapshotted; others return NotImplemented.
+func (a *Server) CreateBackup(ctx context.Context) (*BackupResult, error) {
+	bk, ok := a.bk.(backend.OnlineBackup)
+	if !ok {
+		return nil, trace.NotImplemented("backend %T does not support online backups", a.bk)
+	}
+	if a.dataDir == "" {
+		return nil, trace.BadParameter("auth server has no data directory configured for backups")
+	}
+	dir := filepath.Join(a.dataDir, backupsDir)
+	if err := os.MkdirAll(dir, 0700); err != nil {
+		return nil, trace.ConvertSystemError(err)
+	}
+	now := a.clock.Now().UTC()
+	path := filepath.Join(dir, fmt.Sprintf("backup-%v.sqlite", now.Format("2006-01-02T15-04-05.000000000")))
+	if err := bk.Backup(ctx, path); err != nil {
+		return nil, trace.Wrap(err)
+	}
+	log.Infof("Created online backend backup at %v.", path)
+	return &BackupResult{Path: path, CreatedAt: now}, nil
+}
@@ -49,6 +49,7 @@ import (
 	"github.com/gravitational/roundtrip"
 	"github.com/gravitational/trace"
 	"github.com/gravitational/trace/trail"
+	"github.com/pborman/uuid"
 )
 
 const (
@@ -179,7 +180,7 @@ func NewHTTPClient(cfg client.Config, tls *tls.Config, params ...roundtrip.Clien
 
 	clientParams := append(
 		[]roundtrip.ClientParam{
-			roundtrip.HTTPClient(&http.Client{Transport: transport}),
+			roundtrip.HTTPClient(&http.Client{Transport: tracingRoundTripper{inner: transport}}),
 			roundtrip.SanitizerEnabled(true),
 		},
 		params...,
@@ -1146,13 +1147,22 @@ func (c *Client) GetSignupU2FRegisterRequest(token string) (*u2f.RegisterChallen
 	return &u2fRegReq, nil
 }
 
-// ChangePasswordWithToken changes user password with ResetPasswordToken
-func (c *Client) ChangePasswordWithToken(ctx context.Context, req ChangePasswordWithTokenRequest) (types.WebSession, error) {
+// ChangePasswordWithToken changes user password with ResetPasswordToken,
+// it returns the new web session and the account recovery codes
+func (c *Client) ChangePasswordWithToken(ctx context.Context, req ChangePasswordWithTokenRequest) (types.WebSession, []string, error) {
 	out, err := c.PostJSON(c.Endpoint("web", "password", "token"), req)
 	if err != nil {
-		return nil, trace.Wrap(err)
+		return nil, nil, trace.Wrap(err)
 	}
-	return services.UnmarshalWebSession(out.Bytes())
+	var resp changePasswordWithTokenResponse
+	if err := json.Unmarshal(out.Bytes(), &resp); err != nil {
+		return nil, nil, trace.Wrap(err)
+	}
+	sess, err := services.UnmarshalWebSession(resp.Session)
+	if err != nil {
+		return nil, nil, trace.Wrap(err)
+	}
+	return sess, resp.RecoveryCodes, nil
 }
 
 // CreateOIDCAuthRequest creates OIDCAuthRequest
@@ -1910,12 +1920,17 @@ type IdentityService interface {
 	// CreateResetPasswordToken creates a new user reset token
 	CreateResetPasswordToken(ctx context.Context, req CreateResetPasswordTokenRequest) (types.ResetPasswordToken, error)
 
-	// ChangePasswordWithToken changes password with token
-	ChangePasswordWithToken(ctx context.Context, req ChangePasswordWithTokenRequest) (types.WebSession, error)
+	// ChangePasswordWithToken changes password with token and returns the
+	// new web session along with the account recovery codes
+	ChangePasswordWithToken(ctx context.Context, req ChangePasswordWithTokenRequest) (types.WebSession, []string, error)
 
 	// GetResetPasswordToken returns token
 	GetResetPasswordToken(ctx context.Context, username string) (types.ResetPasswordToken, error)
 
+	// GetResetPasswordTokens returns all pending user invite and password
+	// reset tokens
+	GetResetPasswordTokens(ctx context.Context) ([]types.ResetPasswordToken, error)
+
 	// RotateResetPasswordTokenSecrets rotates token secrets for a given tokenID
 	RotateResetPasswordTokenSecrets(ctx context.Context, tokenID string) (types.ResetPasswordTokenSecrets, error)
 
@@ -2038,4 +2053,299 @@ type ClientI interface {
 
 	// ResetSessionRecordingConfig resets session recording configuration to defaults.
 	ResetSessionRecordingConfig(ctx context.Context) error
+
+	// SignAuditDigest signs an exported audit archive digest with the
+	// cluster host CA key.
+	SignAuditDigest(ctx context.Context, digest []byte) (*AuditSignature, error)
+
+	// GenerateUserCertsBatch issues one certificate per request, signing
+	// them concurrently on the auth server.
+	GenerateUserCertsBatch(ctx context.Context, reqs []proto.UserCertsRequest) ([]*proto.Certs, error)
+
+	// CreateNotification stores a user or cluster-wide notification.
+	CreateNotification(ctx context.Context, notification Notification) error
+
+	// GetNotifications returns the calling user's notifications.
+	GetNotifications(ctx context.Context) ([]Notification, error)
+
+	// AckNotification dismisses one of the calling user's notifications.
+	AckNotification(ctx context.Context, id string) error
+
+	// InventoryHello registers the calling agent on the inventory
+	// control channel and returns any pending commands.
+	InventoryHello(ctx context.Context, hello InventoryHello) ([]InventoryCommand, error)
+
+	// InventoryStatus lists the agents connected to the control channel.
+	InventoryStatus(ctx context.Context) ([]InventoryAgentStatus, error)
+
+	// SendInventoryCommand queues a command for a connected agent.
+	SendInventoryCommand(ctx context.Context, hostID string, command InventoryCommand) error
+
+	// PostInventoryResult stores the calling agent's answer to a
+	// diagnostics command.
+	PostInventoryResult(ctx context.Context, result InventoryResult) error
+
+	// GetInventoryResult returns a stored diagnostics result.
+	GetInventoryResult(ctx context.Context, id string) (*InventoryResult, error)
+
+	// GetRevocationList returns the signed revocation list.
+	GetRevocationList(ctx context.Context) (*RevocationList, error)
+
+	// CreateBackup snapshots the auth server backend without downtime.
+	CreateBackup(ctx context.Context) (*BackupResult, error)
+
+	// UpsertLoginRule stores a login rule.
+	UpsertLoginRule(ctx context.Context, rule LoginRule) error
+
+	// GetLoginRules returns all login rules.
+	GetLoginRules(ctx context.Context) ([]LoginRule, error)
+
+	// DeleteLoginRule removes a login rule.
+	DeleteLoginRule(ctx context.Context, name string) error
+
+	// UpdateManagedLabels sets and removes centrally managed labels on a
+	// node.
+	UpdateManagedLabels(ctx context.Context, nodeName string, set map[string]string, remove []string) error
+
+	// GetUsageReport builds the anonymized usage report.
+	GetUsageReport(ctx context.Context) (*UsageReport, error)
+
+	// GetLicenseStatus reports the effective license state.
+	GetLicenseStatus(ctx context.Context) (*LicenseStatus, error)
+}
+
+// GetResetPasswordTokens returns all pending user invite and password
+// reset tokens.
+func (c *Client) GetResetPasswordTokens(ctx context.Context) ([]types.ResetPasswordToken, error) {
+	out, err := c.Get(c.Endpoint("resetpasswordtokens"), url.Values{})
+	if err != nil {
+		return nil, trace.Wrap(err)
+	}
+	var items []json.RawMessage
+	if err := json.Unmarshal(out.Bytes(), &items); err != nil {
+		return nil, trace.Wrap(err)
+	}
+	tokens := make([]types.ResetPasswordToken, len(items))
+	for i, raw := range items {
+		token, err := services.UnmarshalResetPasswordToken(raw)
+		if err != nil {
+			return nil, trace.Wrap(err)
+		}
+		tokens[i] = token
+	}
+	return tokens, nil
+}
+
+// SignAuditDigest signs an exported audit archive digest with the cluster
+// host CA key.
+func (c *Client) SignAuditDigest(ctx context.Context, digest []byte) (*AuditSignature, error) {
+	out, err := c.PostJSON(c.Endpoint("audit", "sign"), signAuditDigestReq{Digest: digest})
+	if err != nil {
+		return nil, trace.Wrap(err)
+	}
+	var signature AuditSignature
+	if err := json.Unmarshal(out.Bytes(), &signature); err != nil {
+		return nil, trace.Wrap(err)
+	}
+	return &signature, nil
+}
+
+// GenerateUserCertsBatch issues one certificate per request, signing
+// them concurrently on the auth server.
+func (c *Client) GenerateUserCertsBatch(ctx context.Context, reqs []proto.UserCertsRequest) ([]*proto.Certs, error) {
+	out, err := c.PostJSON(c.Endpoint("ca", "user", "certs", "batch"), generateUserCertsBatchReq{Requests: reqs})
+	if err != nil {
+		return nil, trace.Wrap(err)
+	}
+	var certs []*proto.Certs
+	if err := json.Unmarshal(out.Bytes(), &certs); err != nil {
+		return nil, trace.Wrap(err)
+	}
+	return certs, nil
+}
+
+// CreateNotification stores a user or cluster-wide notification.
+func (c *Client) CreateNotification(ctx context.Context, notification Notification) error {
+	_, err := c.PostJSON(c.Endpoint("notifications"), notification)
+	return trace.Wrap(err)
+}
+
+// GetNotifications returns the calling user's notifications.
+func (c *Client) GetNotifications(ctx context.Context) ([]Notification, error) {
+	out, err := c.Get(c.Endpoint("notifications"), url.Values{})
+	if err != nil {
+		return nil, trace.Wrap(err)
+	}
+	var notifications []Notification
+	if err := json.Unmarshal(out.Bytes(), &notifications); err != nil {
+		return nil, trace.Wrap(err)
+	}
+	return notifications, nil
+}
+
+// AckNotification dismisses one of the calling user's notifications.
+func (c *Client) AckNotification(ctx context.Context, id string) error {
+	_, err := c.Delete(c.Endpoint("notifications", id))
+	return trace.Wrap(err)
+}
+
+// InventoryHello registers the calling agent on the inventory control
+// channel and returns any pending commands.
+func (c *Client) InventoryHello(ctx context.Context, hello InventoryHello) ([]InventoryCommand, error) {
+	out, err := c.PostJSON(c.Endpoint("inventory", "hello"), hello)
+	if err != nil {
+		return nil, trace.Wrap(err)
+	}
+	var commands []InventoryCommand
+	if err := json.Unmarshal(out.Bytes(), &commands); err != nil {
+		return nil, trace.Wrap(err)
+	}
+	return commands, nil
+}
+
+// InventoryStatus lists the agents connected to the control channel.
+func (c *Client) InventoryStatus(ctx context.Context) ([]InventoryAgentStatus, error) {
+	out, err := c.Get(c.Endpoint("inventory", "status"), url.Values{})
+	if err != nil {
+		return nil, trace.Wrap(err)
+	}
+	var statuses []InventoryAgentStatus
+	if err := json.Unmarshal(out.Bytes(), &statuses); err != nil {
+		return nil, trace.Wrap(err)
+	}
+	return statuses, nil
+}
+
+// SendInventoryCommand queues a command for a connected agent.
+func (c *Client) SendInventoryCommand(ctx context.Context, hostID string, command InventoryCommand) error {
+	_, err := c.PostJSON(c.Endpoint("inventory", "command"), sendInventoryCommandReq{
+		HostID:  hostID,
+		Command: command,
+	})
+	return trace.Wrap(err)
+}
+
+// PostInventoryResult stores the calling agent's answer to a diagnostics
+// command.
+func (c *Client) PostInventoryResult(ctx context.Context, result InventoryResult) error {
+	_, err := c.PostJSON(c.Endpoint("inventory", "result"), result)
+	return trace.Wrap(err)
+}
+
+// GetInventoryResult returns a stored diagnostics result.
+func (c *Client) GetInventoryResult(ctx context.Context, id string) (*InventoryResult, error) {
+	out, err := c.Get(c.Endpoint("inventory", "result", id), url.Values{})
+	if err != nil {
+		return nil, trace.Wrap(err)
+	}
+	var result InventoryResult
+	if err := json.Unmarshal(out.Bytes(), &result); err != nil {
+		return nil, trace.Wrap(err)
+	}
+	return &result, nil
+}
+
+// CreateBackup snapshots the auth server backend without downtime.
+func (c *Client) CreateBackup(ctx context.Context) (*BackupResult, error) {
+	out, err := c.PostJSON(c.Endpoint("backup"), nil)
+	if err != nil {
+		return nil, trace.Wrap(err)
+	}
+	var result BackupResult
+	if err := json.Unmarshal(out.Bytes(), &result); err != nil {
+		return nil, trace.Wrap(err)
+	}
+	return &result, nil
+}
+
+// GetRevocationList returns the signed revocation list.
+func (c *Client) GetRevocationList(ctx context.Context) (*RevocationList, error) {
+	out, err := c.Get(c.Endpoint("revocations"), url.Values{})
+	if err != nil {
+		return nil, trace.Wrap(err)
+	}
+	var list RevocationList
+	if err := json.Unmarshal(out.Bytes(), &list); err != nil {
+		return nil, trace.Wrap(err)
+	}
+	return &list, nil
+}
+
+// UpsertLoginRule stores a login rule.
+func (c *Client) UpsertLoginRule(ctx context.Context, rule LoginRule) error {
+	_, err := c.PostJSON(c.Endpoint("loginrules"), rule)
+	return trace.Wrap(err)
+}
+
+// GetLoginRules returns all login rules.
+func (c *Client) GetLoginRules(ctx context.Context) ([]LoginRule, error) {
+	out, err := c.Get(c.Endpoint("loginrules"), url.Values{})
+	if err != nil {
+		return nil, trace.Wrap(err)
+	}
+	var rules []LoginRule
+	if err := json.Unmarshal(out.Bytes(), &rules); err != nil {
+		return nil, trace.Wrap(err)
+	}
+	return rules, nil
+}
+
+// DeleteLoginRule removes a login rule.
+func (c *Client) DeleteLoginRule(ctx context.Context, name string) error {
+	_, err := c.Delete(c.Endpoint("loginrules", name))
+	return trace.Wrap(err)
+}
+
+// UpdateManagedLabels sets and removes centrally managed labels on a
+// node.
+func (c *Client) UpdateManagedLabels(ctx context.Context, nodeName string, set map[string]string, remove []string) error {
+	_, err := c.PostJSON(c.Endpoint("nodes", nodeName, "labels"), updateManagedLabelsReq{
+		Set:    set,
+		Remove: remove,
+	})
+	return trace.Wrap(err)
+}
+
+// GetUsageReport builds the anonymized usage report.
+func (c *Client) GetUsageReport(ctx context.Context) (*UsageReport, error) {
+	out, err := c.Get(c.Endpoint("usagereport"), url.Values{})
+	if err != nil {
+		return nil, trace.Wrap(err)
+	}
+	var report UsageReport
+	if err := json.Unmarshal(out.Bytes(), &report); err != nil {
+		return nil, trace.Wrap(err)
+	}
+	return &report, nil
+}
+
+// GetLicenseStatus reports the effective license state.
+func (c *Client) GetLicenseStatus(ctx context.Context) (*LicenseStatus, error) {
+	out, err := c.Get(c.Endpoint("licensestatus"), url.Values{})
+	if err != nil {
+		return nil, trace.Wrap(err)
+	}
+	var status LicenseStatus
+	if err := json.Unmarshal(out.Bytes(), &status); err != nil {
+		return nil, trace.Wrap(err)
+	}
+	return &status, nil
+}
+
+// tracingRoundTripper stamps outgoing requests with a unique trace ID so
+// client requests can be correlated with server-side log entries across
+// service boundaries.
+type tracingRoundTripper struct {
+	inner http.RoundTripper
+}
+
+// RoundTrip implements http.RoundTripper. The request is cloned before
+// the header is added, round trippers are not allowed to modify the
+// original request.
+func (t tracingRoundTripper) RoundTrip(req *http.Request) (*http.Response, error) {
+	if req.Header.Get(constants.TraceIDHeader) == "" {
+		req = req.Clone(req.Context())
+		req.Header.Set(constants.TraceIDHeader, uuid.New())
+	}
+	return t.inner.RoundTrip(req)
 }
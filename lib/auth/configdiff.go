@@ -0,0 +1,86 @@
+/*
+Copyright 2021 Gravitational, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package auth
+
+import (
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// configResourceDiff returns a human readable list of top-level fields
+// that differ between two versions of a configuration resource, used to
+// record what changed when cluster configuration is updated.
+func configResourceDiff(old, new interface{}) string {
+	oldFields, err := resourceFields(old)
+	if err != nil {
+		return "unable to compute diff"
+	}
+	newFields, err := resourceFields(new)
+	if err != nil {
+		return "unable to compute diff"
+	}
+
+	var changes []string
+	for field, newValue := range newFields {
+		oldValue, ok := oldFields[field]
+		if !ok {
+			changes = append(changes, fmt.Sprintf("%v: added %v", field, newValue))
+			continue
+		}
+		if oldValue != newValue {
+			changes = append(changes, fmt.Sprintf("%v: %v -> %v", field, oldValue, newValue))
+		}
+	}
+	for field, oldValue := range oldFields {
+		if _, ok := newFields[field]; !ok {
+			changes = append(changes, fmt.Sprintf("%v: removed %v", field, oldValue))
+		}
+	}
+	if len(changes) == 0 {
+		return "no changes"
+	}
+	sort.Strings(changes)
+	return strings.Join(changes, ", ")
+}
+
+// resourceFields flattens the resource spec into comparable top-level
+// field representations.
+func resourceFields(resource interface{}) (map[string]string, error) {
+	data, err := json.Marshal(resource)
+	if err != nil {
+		return nil, err
+	}
+	var raw map[string]json.RawMessage
+	if err := json.Unmarshal(data, &raw); err != nil {
+		return nil, err
+	}
+	spec, ok := raw["spec"]
+	if ok {
+		// Compare the spec fields, metadata churn (ids, revisions) is not
+		// interesting for configuration change records.
+		if err := json.Unmarshal(spec, &raw); err != nil {
+			return nil, err
+		}
+	}
+	fields := make(map[string]string, len(raw))
+	for field, value := range raw {
+		fields[field] = string(value)
+	}
+	return fields, nil
+}
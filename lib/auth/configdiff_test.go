@@ -0,0 +1,40 @@
+/*
+Copyright 2021 Gravitational, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package auth
+
+import (
+	"testing"
+
+	"github.com/gravitational/teleport/api/types"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestConfigResourceDiff(t *testing.T) {
+	t.Parallel()
+
+	old := types.DefaultClusterNetworkingConfig()
+	updated := types.DefaultClusterNetworkingConfig()
+	updated.SetKeepAliveCountMax(10)
+
+	diff := configResourceDiff(old, updated)
+	require.Contains(t, diff, "keep_alive_count_max")
+	require.Contains(t, diff, "10")
+
+	// Identical resources report no changes.
+	require.Equal(t, "no changes", configResourceDiff(old, old))
+}
@@ -359,6 +359,7 @@ func (a *Server) calculateGithubUser(connector types.GithubConnector, claims *ty
 		teleport.TraitKubeGroups: p.kubeGroups,
 		teleport.TraitKubeUsers:  p.kubeUsers,
 	}
+	p.traits = a.applyLoginRulesToTraits(context.TODO(), p.traits)
 
 	// Pick smaller for role: session TTL from role or requested TTL.
 	roles, err := services.FetchRoles(p.roles, a.Access, p.traits)
@@ -1803,7 +1803,7 @@ func (g *GRPCServer) DeleteMFADevice(stream proto.AuthService_DeleteMFADeviceSer
 			if _, ok := d.Device.(*types.MFADevice_Totp); ok && numTOTPDevs == 1 {
 				return trace.BadParameter("cannot delete the last OTP device for this user; add a replacement device first to avoid getting locked out")
 			}
-		case constants.SecondFactorU2F:
+		case constants.SecondFactorU2F, constants.SecondFactorPasswordless:
 			if _, ok := d.Device.(*types.MFADevice_U2F); ok && numU2FDevs == 1 {
 				return trace.BadParameter("cannot delete the last U2F device for this user; add a replacement device first to avoid getting locked out")
 			}
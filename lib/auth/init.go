@@ -38,10 +38,12 @@ import (
 	"github.com/gravitational/teleport/lib/defaults"
 	"github.com/gravitational/teleport/lib/events"
 	"github.com/gravitational/teleport/lib/modules"
+	"github.com/gravitational/teleport/lib/pagerduty"
 	"github.com/gravitational/teleport/lib/services"
 	"github.com/gravitational/teleport/lib/services/local"
 	"github.com/gravitational/teleport/lib/sshca"
 	"github.com/gravitational/teleport/lib/sshutils"
+	"github.com/gravitational/teleport/lib/tickets"
 	"github.com/gravitational/teleport/lib/tlsca"
 	"github.com/gravitational/teleport/lib/utils"
 
@@ -59,6 +61,64 @@ type InitConfig struct {
 	// Backend is auth backend to use
 	Backend backend.Backend
 
+	// MaxWebSessions caps concurrent web sessions per user, zero
+	// disables the limit
+	MaxWebSessions int
+
+	// MaxLoginAttempts is the number of consecutive failed local login
+	// attempts after which an account is locked, zero means use the
+	// default
+	MaxLoginAttempts int
+
+	// AccountLockInterval is how long an account stays locked after too
+	// many failed login attempts, zero means use the default
+	AccountLockInterval time.Duration
+
+	// PasswordPolicy is the password requirements policy for local users
+	PasswordPolicy services.PasswordPolicy
+
+	// PagerDuty configures auto-approval of access requests from on-call
+	// users, enabled when the API key is set
+	PagerDuty pagerduty.Config
+
+	// Ticketing configures tracking access requests as Jira or ServiceNow
+	// tickets, enabled when the system is set
+	Ticketing tickets.Config
+
+	// AccessRequestMaxDuration caps how long requested access can last,
+	// zero means no cluster-wide cap
+	AccessRequestMaxDuration time.Duration
+
+	// AccessRequestUnassumedTTL is how long an approval stays valid
+	// without being assumed, zero disables the expiry
+	AccessRequestUnassumedTTL time.Duration
+
+	// AccessRequestRetention is how long resolved requests are kept
+	// before being pruned, zero disables pruning
+	AccessRequestRetention time.Duration
+
+	// VersionSkewLimit is how many major versions an agent may lag
+	// behind the auth server and still join, zero disables the check
+	VersionSkewLimit int
+
+	// RecordingRetention is how long session recordings are kept before
+	// being pruned, zero disables pruning
+	RecordingRetention time.Duration
+
+	// RecordingRetentionDryRun reports what would be pruned instead of
+	// deleting
+	RecordingRetentionDryRun bool
+
+	// RecordsDir is the directory local session recordings are stored in
+	RecordsDir string
+
+	// UsageReportingEndpoint, when set, enables periodic submission of
+	// anonymized usage reports to the endpoint
+	UsageReportingEndpoint string
+
+	// LicenseFile is the path to the capability file, optional
+	LicenseFile string
+
 	// Authority is key generator that we use
 	Authority sshca.Authority
 
@@ -0,0 +1,246 @@
+/*
+Copyright 2021 Gravitational, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package auth
+
+import (
+	"context"
+	"sort"
+	"time"
+
+	"github.com/gravitational/trace"
+)
+
+// Inventory control commands the auth server can send to agents.
+const (
+	// InventoryCommandPing asks the agent to report in immediately.
+	InventoryCommandPing = "ping"
+	// InventoryCommandUpgrade hints that the agent should be upgraded.
+	InventoryCommandUpgrade = "upgrade"
+	// InventoryCommandShutdown asks the agent to shut down gracefully.
+	InventoryCommandShutdown = "shutdown"
+)
+
+const (
+	// inventoryHoldInterval is how long a hello call is held open waiting
+	// for commands before returning empty, forming a long-poll control
+	// channel. It must stay below the HTTP client's response header
+	// timeout (apidefaults.DefaultDialTimeout) or idle polls error out
+	// client-side.
+	inventoryHoldInterval = 25 * time.Second
+	// inventoryCommandBacklog bounds the commands queued per agent.
+	inventoryCommandBacklog = 16
+	// inventoryStaleAfter is how long after the last hello an agent is
+	// still considered connected.
+	inventoryStaleAfter = 3 * time.Minute
+)
+
+// InventoryHello is the agent's side of the control channel: sent on
+// connect and on every poll, carrying identity, version and health.
+type InventoryHello struct {
+	// HostID is the unique host ID of the agent.
+	HostID string `json:"host_id"`
+	// NodeName is the agent's node name.
+	NodeName string `json:"node_name"`
+	// Version is the agent's Teleport version.
+	Version string `json:"version"`
+	// Services lists the services the agent runs, e.g. node or proxy.
+	Services []string `json:"services"`
+	// Health is the agent's self-reported health state.
+	Health string `json:"health"`
+}
+
+// InventoryCommand is a server-initiated command delivered over the
+// control channel.
+type InventoryCommand struct {
+	// Name is one of the InventoryCommand constants.
+	Name string `json:"name"`
+	// ID correlates diagnostics commands with their results, unused for
+	// fire-and-forget commands.
+	ID string `json:"id,omitempty"`
+}
+
+// InventoryAgentStatus describes one agent connected to the control
+// channel.
+type InventoryAgentStatus struct {
+	// InventoryHello is the last hello received from the agent.
+	InventoryHello
+	// LastSeen is when the agent last reported in.
+	LastSeen time.Time `json:"last_seen"`
+}
+
+// inventoryAgent tracks one connected agent and its pending commands.
+type inventoryAgent struct {
+	hello    InventoryHello
+	lastSeen time.Time
+	commands chan InventoryCommand
+}
+
+// InventoryHello registers the agent on the control channel and holds
+// the call open until a command arrives or the hold interval passes.
+func (a *Server) InventoryHello(ctx context.Context, hello InventoryHello) ([]InventoryCommand, error) {
+	if hello.HostID == "" {
+		return nil, trace.BadParameter("missing host ID in inventory hello")
+	}
+	a.inventoryMu.Lock()
+	if a.inventoryAgents == nil {
+		a.inventoryAgents = make(map[string]*inventoryAgent)
+	}
+	agent, ok := a.inventoryAgents[hello.HostID]
+	if !ok {
+		agent = &inventoryAgent{commands: make(chan InventoryCommand, inventoryCommandBacklog)}
+		a.inventoryAgents[hello.HostID] = agent
+		log.Infof("Agent %v (%v) connected to the inventory control channel.", hello.NodeName, hello.HostID)
+	}
+	agent.hello = hello
+	agent.lastSeen = a.clock.Now().UTC()
+	a.inventoryMu.Unlock()
+
+	// Wait for the first command, then drain whatever else is queued.
+	var commands []InventoryCommand
+	select {
+	case command := <-agent.commands:
+		commands = append(commands, command)
+	case <-time.After(inventoryHoldInterval):
+		return nil, nil
+	case <-ctx.Done():
+		return nil, nil
+	}
+	for {
+		select {
+		case command := <-agent.commands:
+			commands = append(commands, command)
+		default:
+			return commands, nil
+		}
+	}
+}
+
+// InventoryStatus lists the agents connected to the control channel.
+func (a *Server) InventoryStatus(ctx context.Context) ([]InventoryAgentStatus, error) {
+	a.inventoryMu.Lock()
+	defer a.inventoryMu.Unlock()
+	cutoff := a.clock.Now().UTC().Add(-inventoryStaleAfter)
+	var statuses []InventoryAgentStatus
+	for hostID, agent := range a.inventoryAgents {
+		if agent.lastSeen.Before(cutoff) {
+			delete(a.inventoryAgents, hostID)
+			continue
+		}
+		statuses = append(statuses, InventoryAgentStatus{
+			InventoryHello: agent.hello,
+			LastSeen:       agent.lastSeen,
+		})
+	}
+	sort.Slice(statuses, func(i, j int) bool {
+		return statuses[i].NodeName < statuses[j].NodeName
+	})
+	return statuses, nil
+}
+
+// SendInventoryCommand queues a command for a connected agent.
+func (a *Server) SendInventoryCommand(ctx context.Context, hostID string, command InventoryCommand) error {
+	switch command.Name {
+	case InventoryCommandPing, InventoryCommandUpgrade, InventoryCommandShutdown,
+		InventoryCommandCollectLogs, InventoryCommandCollectProfile:
+	default:
+		return trace.BadParameter("unsupported inventory command %q", command.Name)
+	}
+	a.inventoryMu.Lock()
+	agent, ok := a.inventoryAgents[hostID]
+	a.inventoryMu.Unlock()
+	if !ok {
+		return trace.NotFound("agent %q is not connected to the inventory control channel", hostID)
+	}
+	select {
+	case agent.commands <- command:
+		return nil
+	default:
+		return trace.LimitExceeded("agent %q has too many pending commands", hostID)
+	}
+}
+
+// Commands collecting diagnostics from agents, delivered like any other
+// inventory command and answered through PostInventoryResult.
+const (
+	// InventoryCommandCollectLogs asks the agent for its recent logs.
+	InventoryCommandCollectLogs = "collect-logs"
+	// InventoryCommandCollectProfile asks the agent for a pprof dump.
+	InventoryCommandCollectProfile = "collect-profile"
+)
+
+// inventoryResultTTL is how long a collected result waits to be picked
+// up before it is dropped.
+const inventoryResultTTL = 5 * time.Minute
+
+// inventoryResultLimit caps how many unclaimed results are kept, so a
+// flood of posts cannot grow the auth server's memory unboundedly.
+const inventoryResultLimit = 256
+
+// InventoryResult is an agent's answer to a diagnostics command.
+type InventoryResult struct {
+	// HostID identifies the agent that produced the result.
+	HostID string `json:"host_id"`
+	// ID matches the ID of the command the result answers.
+	ID string `json:"id"`
+	// Data is the collected payload.
+	Data []byte `json:"data"`
+	// Error is set when the agent could not collect the data.
+	Error string `json:"error,omitempty"`
+}
+
+// inventoryResult is a stored result with its arrival time.
+type inventoryResult struct {
+	result  InventoryResult
+	arrived time.Time
+}
+
+// PostInventoryResult stores an agent's answer to a diagnostics command
+// until the requester picks it up.
+func (a *Server) PostInventoryResult(ctx context.Context, result InventoryResult) error {
+	if result.ID == "" {
+		return trace.BadParameter("missing result ID")
+	}
+	a.inventoryMu.Lock()
+	defer a.inventoryMu.Unlock()
+	if a.inventoryResults == nil {
+		a.inventoryResults = make(map[string]inventoryResult)
+	}
+	now := a.clock.Now().UTC()
+	for id, stored := range a.inventoryResults {
+		if now.Sub(stored.arrived) > inventoryResultTTL {
+			delete(a.inventoryResults, id)
+		}
+	}
+	if len(a.inventoryResults) >= inventoryResultLimit {
+		return trace.LimitExceeded("too many unclaimed inventory results")
+	}
+	a.inventoryResults[result.ID] = inventoryResult{result: result, arrived: now}
+	return nil
+}
+
+// GetInventoryResult returns and removes a stored diagnostics result,
+// NotFound while the agent has not answered yet.
+func (a *Server) GetInventoryResult(ctx context.Context, id string) (*InventoryResult, error) {
+	a.inventoryMu.Lock()
+	defer a.inventoryMu.Unlock()
+	stored, ok := a.inventoryResults[id]
+	if !ok {
+		return nil, trace.NotFound("no result for request %q yet", id)
+	}
+	delete(a.inventoryResults, id)
+	return &stored.result, nil
+}
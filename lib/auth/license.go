@@ -0,0 +1,130 @@
+/*
+Copyright 2021 Gravitational, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package auth
+
+import (
+	"context"
+	"encoding/json"
+	"io/ioutil"
+	"os"
+	"time"
+
+	apidefaults "github.com/gravitational/teleport/api/defaults"
+
+	"github.com/gravitational/trace"
+)
+
+// licenseCheckPeriod is how often license limits are evaluated.
+const licenseCheckPeriod = time.Hour
+
+// Stable alert IDs for license conditions, raised through the cluster
+// alert machinery so limits degrade to warnings instead of outages.
+const (
+	alertLicenseExpired   = "license-expired"
+	alertLicenseNodeLimit = "license-node-limit"
+)
+
+// LicenseStatus describes the capability file the cluster runs with.
+// Clusters without one run unrestricted community defaults.
+type LicenseStatus struct {
+	// Present is true when a capability file was loaded.
+	Present bool `json:"present"`
+	// Features lists the licensed feature names.
+	Features []string `json:"features,omitempty"`
+	// MaxNodes caps the number of registered nodes, zero means no cap.
+	MaxNodes int `json:"max_nodes,omitempty"`
+	// Expires is when the license lapses, zero means never.
+	Expires time.Time `json:"expires,omitempty"`
+	// NodeCount is the current number of registered nodes.
+	NodeCount int `json:"node_count"`
+	// Valid is false when the license has lapsed or a limit is
+	// exceeded. The cluster keeps running, conditions are surfaced as
+	// cluster alerts.
+	Valid bool `json:"valid"`
+}
+
+// licenseCapabilities is the on-disk capability file format.
+type licenseCapabilities struct {
+	Features []string  `json:"features"`
+	MaxNodes int       `json:"max_nodes"`
+	Expires  time.Time `json:"expires"`
+}
+
+// loadLicenseCapabilities reads the capability file, returning nil when
+// none exists: the cluster runs with community defaults.
+func loadLicenseCapabilities(path string) (*licenseCapabilities, error) {
+	if path == "" {
+		return nil, nil
+	}
+	data, err := ioutil.ReadFile(path)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, nil
+		}
+		return nil, trace.ConvertSystemError(err)
+	}
+	var capabilities licenseCapabilities
+	if err := json.Unmarshal(data, &capabilities); err != nil {
+		return nil, trace.BadParameter("failed to parse license file %v: %v", path, err)
+	}
+	return &capabilities, nil
+}
+
+// GetLicenseStatus reports the effective license state for
+// "tctl license status".
+func (a *Server) GetLicenseStatus(ctx context.Context) (*LicenseStatus, error) {
+	status := &LicenseStatus{Valid: true}
+	nodes, err := a.GetNodes(ctx, apidefaults.Namespace)
+	if err == nil {
+		status.NodeCount = len(nodes)
+	}
+	if a.license == nil {
+		return status, nil
+	}
+	status.Present = true
+	status.Features = a.license.Features
+	status.MaxNodes = a.license.MaxNodes
+	status.Expires = a.license.Expires
+	if !a.license.Expires.IsZero() && a.clock.Now().After(a.license.Expires) {
+		status.Valid = false
+	}
+	if a.license.MaxNodes > 0 && status.NodeCount > a.license.MaxNodes {
+		status.Valid = false
+	}
+	return status, nil
+}
+
+// checkLicenseLimits raises cluster alerts for lapsed licenses and
+// exceeded limits. The cluster keeps serving, operators get warned.
+func (a *Server) checkLicenseLimits(ctx context.Context) {
+	if a.license == nil {
+		return
+	}
+	if !a.license.Expires.IsZero() && a.clock.Now().After(a.license.Expires) {
+		a.raiseClusterAlert(ctx, alertLicenseExpired,
+			"License expired",
+			"The cluster license has expired, renew it to stay within your agreement. The cluster keeps running.")
+	}
+	if a.license.MaxNodes > 0 {
+		nodes, err := a.GetNodes(ctx, apidefaults.Namespace)
+		if err == nil && len(nodes) > a.license.MaxNodes {
+			a.raiseClusterAlert(ctx, alertLicenseNodeLimit,
+				"License node limit exceeded",
+				"The cluster has more registered nodes than the license allows, new nodes keep joining but the overage is recorded.")
+		}
+	}
+}
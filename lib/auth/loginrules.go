@@ -0,0 +1,200 @@
+/*
+Copyright 2021 Gravitational, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package auth
+
+import (
+	"context"
+	"encoding/json"
+	"sort"
+	"strings"
+
+	"github.com/gravitational/teleport/api/types/wrappers"
+	"github.com/gravitational/teleport/lib/backend"
+
+	"github.com/gravitational/trace"
+)
+
+// loginRulesPrefix is the backend key prefix login rules are stored
+// under.
+const loginRulesPrefix = "loginrules"
+
+// LoginRule transforms the traits produced by an SSO login before roles
+// are mapped, so connectors do not have to duplicate mapping logic.
+type LoginRule struct {
+	// Name identifies the rule.
+	Name string `json:"name" yaml:"name"`
+	// Priority orders rule application, lower runs first.
+	Priority int `json:"priority" yaml:"priority"`
+	// TraitsMap computes the output traits: each trait is the
+	// deduplicated union of its expressions. Supported expressions:
+	// "external.<trait>" copies the incoming trait, lowercase(...) and
+	// uppercase(...) transform it, and double-quoted strings are
+	// literals. Traits not listed are dropped.
+	TraitsMap map[string][]string `json:"traits_map" yaml:"traits_map"`
+}
+
+// CheckAndSetDefaults validates the rule.
+func (r *LoginRule) CheckAndSetDefaults() error {
+	if r.Name == "" {
+		return trace.BadParameter("login rule requires a name")
+	}
+	if len(r.TraitsMap) == 0 {
+		return trace.BadParameter("login rule %q requires a traits_map", r.Name)
+	}
+	for trait, exprs := range r.TraitsMap {
+		for _, expr := range exprs {
+			if _, err := evalLoginRuleExpr(expr, nil); err != nil {
+				return trace.BadParameter("login rule %q, trait %q: %v", r.Name, trait, err)
+			}
+		}
+	}
+	return nil
+}
+
+// UpsertLoginRule stores a login rule.
+func (a *Server) UpsertLoginRule(ctx context.Context, rule LoginRule) error {
+	if err := rule.CheckAndSetDefaults(); err != nil {
+		return trace.Wrap(err)
+	}
+	value, err := json.Marshal(rule)
+	if err != nil {
+		return trace.Wrap(err)
+	}
+	_, err = a.bk.Put(ctx, backend.Item{
+		Key:   backend.Key(loginRulesPrefix, rule.Name),
+		Value: value,
+	})
+	return trace.Wrap(err)
+}
+
+// GetLoginRules returns all login rules ordered by priority.
+func (a *Server) GetLoginRules(ctx context.Context) ([]LoginRule, error) {
+	prefix := backend.Key(loginRulesPrefix)
+	result, err := a.bk.GetRange(ctx, prefix, backend.RangeEnd(prefix), backend.NoLimit)
+	if err != nil {
+		return nil, trace.Wrap(err)
+	}
+	var rules []LoginRule
+	for _, item := range result.Items {
+		var rule LoginRule
+		if err := json.Unmarshal(item.Value, &rule); err != nil {
+			log.WithError(err).Warningf("Skipping corrupted login rule %v.", string(item.Key))
+			continue
+		}
+		rules = append(rules, rule)
+	}
+	sort.Slice(rules, func(i, j int) bool {
+		if rules[i].Priority != rules[j].Priority {
+			return rules[i].Priority < rules[j].Priority
+		}
+		return rules[i].Name < rules[j].Name
+	})
+	return rules, nil
+}
+
+// DeleteLoginRule removes a login rule.
+func (a *Server) DeleteLoginRule(ctx context.Context, name string) error {
+	return trace.Wrap(a.bk.Delete(ctx, backend.Key(loginRulesPrefix, name)))
+}
+
+// ApplyLoginRules runs the stored login rules over the traits produced
+// by an SSO login, returning the transformed traits. Without rules the
+// traits pass through unchanged.
+func (a *Server) ApplyLoginRules(ctx context.Context, traits map[string][]string) (map[string][]string, error) {
+	rules, err := a.GetLoginRules(ctx)
+	if err != nil {
+		return nil, trace.Wrap(err)
+	}
+	for _, rule := range rules {
+		traits = applyLoginRule(rule, traits)
+	}
+	return traits, nil
+}
+
+// applyLoginRule computes the output traits of a single rule.
+func applyLoginRule(rule LoginRule, traits map[string][]string) map[string][]string {
+	out := make(map[string][]string, len(rule.TraitsMap))
+	for trait, exprs := range rule.TraitsMap {
+		seen := make(map[string]bool)
+		var values []string
+		for _, expr := range exprs {
+			results, err := evalLoginRuleExpr(expr, traits)
+			if err != nil {
+				// Validated at store time, an error here means the rule
+				// predates a syntax change, skip the expression.
+				log.WithError(err).Warningf("Skipping login rule %q expression.", rule.Name)
+				continue
+			}
+			for _, value := range results {
+				if value == "" || seen[value] {
+					continue
+				}
+				seen[value] = true
+				values = append(values, value)
+			}
+		}
+		out[trait] = values
+	}
+	return out
+}
+
+// evalLoginRuleExpr evaluates a single login rule expression against the
+// incoming traits. A nil traits map validates syntax only.
+func evalLoginRuleExpr(expr string, traits map[string][]string) ([]string, error) {
+	expr = strings.TrimSpace(expr)
+	switch {
+	case strings.HasPrefix(expr, `"`) && strings.HasSuffix(expr, `"`) && len(expr) >= 2:
+		return []string{strings.Trim(expr, `"`)}, nil
+	case strings.HasPrefix(expr, "lowercase(") && strings.HasSuffix(expr, ")"):
+		inner, err := evalLoginRuleExpr(expr[len("lowercase("):len(expr)-1], traits)
+		if err != nil {
+			return nil, trace.Wrap(err)
+		}
+		for i := range inner {
+			inner[i] = strings.ToLower(inner[i])
+		}
+		return inner, nil
+	case strings.HasPrefix(expr, "uppercase(") && strings.HasSuffix(expr, ")"):
+		inner, err := evalLoginRuleExpr(expr[len("uppercase("):len(expr)-1], traits)
+		if err != nil {
+			return nil, trace.Wrap(err)
+		}
+		for i := range inner {
+			inner[i] = strings.ToUpper(inner[i])
+		}
+		return inner, nil
+	case strings.HasPrefix(expr, "external."):
+		name := strings.TrimPrefix(expr, "external.")
+		if name == "" {
+			return nil, trace.BadParameter("missing trait name in %q", expr)
+		}
+		return traits[name], nil
+	}
+	return nil, trace.BadParameter("unsupported expression %q, expected external.<trait>, lowercase(...), uppercase(...) or a quoted literal", expr)
+}
+
+// applyLoginRulesToTraits is a convenience wrapper used by the SSO
+// callbacks, logging instead of failing the login when rules cannot be
+// loaded.
+func (a *Server) applyLoginRulesToTraits(ctx context.Context, traits wrappers.Traits) wrappers.Traits {
+	transformed, err := a.ApplyLoginRules(ctx, traits)
+	if err != nil {
+		log.WithError(err).Warning("Failed to apply login rules, using connector traits unchanged.")
+		return traits
+	}
+	return transformed
+}
@@ -0,0 +1,53 @@
+/*
+Copyright 2021 Gravitational, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package auth
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+// TestApplyLoginRule checks login rule expression evaluation and trait
+// transformation.
+func TestApplyLoginRule(t *testing.T) {
+	rule := LoginRule{
+		Name: "normalize",
+		TraitsMap: map[string][]string{
+			"logins": {"lowercase(external.username)", `"ubuntu"`},
+			"groups": {"external.groups"},
+		},
+	}
+	require.NoError(t, rule.CheckAndSetDefaults())
+
+	out := applyLoginRule(rule, map[string][]string{
+		"username": {"Alice", "ALICE"},
+		"groups":   {"devs", "devs", "ops"},
+		"shoe":     {"42"},
+	})
+	require.Equal(t, []string{"alice", "ubuntu"}, out["logins"])
+	require.Equal(t, []string{"devs", "ops"}, out["groups"])
+	// Unmapped traits are dropped.
+	require.NotContains(t, out, "shoe")
+
+	// Invalid expressions are rejected at validation time.
+	bad := LoginRule{
+		Name:      "bad",
+		TraitsMap: map[string][]string{"logins": {"rot13(external.username)"}},
+	}
+	require.Error(t, bad.CheckAndSetDefaults())
+}
@@ -0,0 +1,143 @@
+/*
+Copyright 2021 Gravitational, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package auth
+
+import (
+	"context"
+	"encoding/json"
+
+	"github.com/gravitational/teleport/api/types"
+	"github.com/gravitational/teleport/lib/backend"
+
+	"github.com/gravitational/trace"
+)
+
+// managedLabelsPrefix is the backend key prefix the centrally managed
+// node labels are stored under, keyed by node name.
+const managedLabelsPrefix = "managedlabels"
+
+// GetManagedLabels returns the centrally managed labels of a node,
+// empty when none are set.
+func (a *Server) GetManagedLabels(ctx context.Context, nodeName string) (map[string]string, error) {
+	item, err := a.bk.Get(ctx, backend.Key(managedLabelsPrefix, nodeName))
+	if err != nil {
+		if trace.IsNotFound(err) {
+			return map[string]string{}, nil
+		}
+		return nil, trace.Wrap(err)
+	}
+	labels := map[string]string{}
+	if err := json.Unmarshal(item.Value, &labels); err != nil {
+		return nil, trace.Wrap(err)
+	}
+	return labels, nil
+}
+
+// UpdateManagedLabels sets and removes centrally managed labels on a
+// node. Managed labels survive agent heartbeats (which re-announce the
+// agent's own configuration) and take precedence over agent-configured
+// static labels, so inventory hygiene does not require touching agent
+// configs.
+func (a *Server) UpdateManagedLabels(ctx context.Context, nodeName string, set map[string]string, remove []string) error {
+	for key := range set {
+		if !types.IsValidLabelKey(key) {
+			return trace.BadParameter("invalid label key %q", key)
+		}
+	}
+	labels, err := a.GetManagedLabels(ctx, nodeName)
+	if err != nil {
+		return trace.Wrap(err)
+	}
+	for key, value := range set {
+		labels[key] = value
+	}
+	for _, key := range remove {
+		delete(labels, key)
+	}
+	value, err := json.Marshal(labels)
+	if err != nil {
+		return trace.Wrap(err)
+	}
+	if _, err := a.bk.Put(ctx, backend.Item{
+		Key:   backend.Key(managedLabelsPrefix, nodeName),
+		Value: value,
+	}); err != nil {
+		return trace.Wrap(err)
+	}
+
+	// Patch the currently registered node right away instead of waiting
+	// for its next heartbeat.
+	nodes, err := a.GetNodes(ctx, types.ProcessNamespace(""))
+	if err != nil {
+		return trace.Wrap(err)
+	}
+	for _, node := range nodes {
+		if node.GetName() != nodeName && node.GetHostname() != nodeName {
+			continue
+		}
+		mergeManagedLabels(node, labels)
+		if _, err := a.Presence.UpsertNode(ctx, node); err != nil {
+			return trace.Wrap(err)
+		}
+		return nil
+	}
+	return nil
+}
+
+// UpsertNode merges the node's centrally managed labels before storing
+// the heartbeat, so they survive agents re-announcing their own label
+// configuration.
+func (a *Server) UpsertNode(ctx context.Context, server types.Server) (*types.KeepAlive, error) {
+	labels, err := a.GetManagedLabels(ctx, server.GetName())
+	if err != nil {
+		log.WithError(err).Warningf("Failed to load managed labels for node %v.", server.GetName())
+	} else if len(labels) > 0 {
+		mergeManagedLabels(server, labels)
+	}
+	return a.Presence.UpsertNode(ctx, server)
+}
+
+// mergeManagedLabels applies managed labels on top of the node's static
+// labels: managed values win over agent-configured ones.
+func mergeManagedLabels(server types.Server, managed map[string]string) {
+	node, ok := server.(*types.ServerV2)
+	if !ok {
+		return
+	}
+	if node.Metadata.Labels == nil {
+		node.Metadata.Labels = map[string]string{}
+	}
+	for key, value := range managed {
+		node.Metadata.Labels[key] = value
+	}
+}
+
+// UpsertNodes merges managed labels into every node of a bulk upsert,
+// mirroring UpsertNode.
+func (a *Server) UpsertNodes(namespace string, servers []types.Server) error {
+	for _, server := range servers {
+		labels, err := a.GetManagedLabels(context.TODO(), server.GetName())
+		if err != nil {
+			log.WithError(err).Warningf("Failed to load managed labels for node %v.", server.GetName())
+			continue
+		}
+		if len(labels) > 0 {
+			mergeManagedLabels(server, labels)
+		}
+	}
+	return a.Presence.UpsertNodes(namespace, servers)
+}
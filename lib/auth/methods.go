@@ -84,8 +84,9 @@ type SessionCreds struct {
 	ID string `json:"id"`
 }
 
-// AuthenticateUser authenticates user based on the request type
-func (s *Server) AuthenticateUser(req AuthenticateUserRequest) error {
+// AuthenticateUser authenticates user based on the request type.
+// Returns the MFA device used to authenticate, if any.
+func (s *Server) AuthenticateUser(req AuthenticateUserRequest) (*types.MFADevice, error) {
 	mfaDev, err := s.authenticateUser(context.TODO(), req)
 	event := &apievents.UserLogin{
 		Metadata: apievents.Metadata{
@@ -112,7 +113,7 @@ func (s *Server) AuthenticateUser(req AuthenticateUserRequest) error {
 	if err := s.emitter.EmitAuditEvent(s.closeCtx, event); err != nil {
 		log.WithError(err).Warn("Failed to emit login event.")
 	}
-	return err
+	return mfaDev, err
 }
 
 func (s *Server) authenticateUser(ctx context.Context, req AuthenticateUserRequest) (*types.MFADevice, error) {
@@ -149,6 +150,11 @@ func (s *Server) authenticateUser(ctx context.Context, req AuthenticateUserReque
 		}
 		return mfaDev, nil
 	case req.OTP != nil:
+		// In passwordless mode the hardware key tap is the only accepted
+		// factor, passwords and OTP tokens are rejected.
+		if authPreference.GetSecondFactor() == constants.SecondFactorPasswordless {
+			return nil, trace.AccessDenied("passwords are disabled, authenticate with a hardware key")
+		}
 		var mfaDev *types.MFADevice
 		err := s.WithUserLock(req.Username, func() error {
 			res, err := s.checkPassword(req.Username, req.OTP.Password, req.OTP.Token)
@@ -235,7 +241,7 @@ func (s *Server) AuthenticateWebUser(req AuthenticateUserRequest) (types.WebSess
 		return session, nil
 	}
 
-	if err := s.AuthenticateUser(req); err != nil {
+	if _, err := s.AuthenticateUser(req); err != nil {
 		return nil, trace.Wrap(err)
 	}
 
@@ -356,7 +362,8 @@ func (s *Server) AuthenticateSSHUser(req AuthenticateSSHRequest) (*SSHLoginRespo
 		return nil, trace.Wrap(err)
 	}
 
-	if err := s.AuthenticateUser(req.AuthenticateUserRequest); err != nil {
+	mfaDev, err := s.AuthenticateUser(req.AuthenticateUserRequest)
+	if err != nil {
 		return nil, trace.Wrap(err)
 	}
 
@@ -383,9 +390,18 @@ func (s *Server) AuthenticateSSHUser(req AuthenticateSSHRequest) (*SSHLoginRespo
 		authority,
 	}
 
+	// Certificate TTL laddering: logins verified with a hardware key tap
+	// may keep the full role-configured session TTL, other logins get a
+	// certificate capped at the unverified-session TTL when the roles
+	// configure one. Note that this does not mark the certificate as MFA
+	// verified: per-session MFA checks are stricter than login MFA and
+	// issue their own short-lived certificates.
+	hardwareKeyTap := mfaDev != nil && mfaDev.GetU2F() != nil
+	ttl := checker.AdjustMFAVerifiedSessionTTL(req.TTL, hardwareKeyTap)
+
 	certs, err := s.generateUserCert(certRequest{
 		user:              user,
-		ttl:               req.TTL,
+		ttl:               ttl,
 		publicKey:         req.PublicKey,
 		compatibility:     req.CompatibilityMode,
 		checker:           checker,
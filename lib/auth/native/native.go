@@ -18,11 +18,16 @@ package native
 
 import (
 	"context"
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/ed25519"
+	"crypto/elliptic"
 	"crypto/rand"
 	"crypto/rsa"
 	"crypto/x509"
 	"encoding/pem"
 	"fmt"
+	"strings"
 	"time"
 
 	"golang.org/x/crypto/ssh"
@@ -65,6 +70,10 @@ type Keygen struct {
 
 	// clock is used to control time.
 	clock clockwork.Clock
+
+	// algorithmSuite selects the key type for generated keys, RSA when
+	// empty.
+	algorithmSuite string
 }
 
 // KeygenOption is a functional optional argument for key generator
@@ -77,6 +86,14 @@ func SetClock(clock clockwork.Clock) KeygenOption {
 	}
 }
 
+// WithAlgorithmSuite selects the signature algorithm suite used for
+// newly generated keys.
+func WithAlgorithmSuite(suite string) KeygenOption {
+	return func(k *Keygen) {
+		k.algorithmSuite = suite
+	}
+}
+
 // PrecomputeKeys sets up a number of private keys to pre-compute
 // in background, 0 disables the process
 func PrecomputeKeys(count int) KeygenOption {
@@ -120,14 +137,14 @@ func (k *Keygen) GetNewKeyPairFromPool() ([]byte, []byte, error) {
 	case key := <-k.keysCh:
 		return key.privPem, key.pubBytes, nil
 	default:
-		return GenerateKeyPair("")
+		return GenerateKeyPairWithAlgorithm(k.algorithmSuite)
 	}
 }
 
 // precomputeKeys continues loops forever trying to compute cache key pairs.
 func (k *Keygen) precomputeKeys() {
 	for {
-		privPem, pubBytes, err := GenerateKeyPair("")
+		privPem, pubBytes, err := GenerateKeyPairWithAlgorithm(k.algorithmSuite)
 		if err != nil {
 			log.Errorf("Unable to generate key pair: %v.", err)
 			continue
@@ -147,33 +164,101 @@ func (k *Keygen) precomputeKeys() {
 	}
 }
 
+// Signature algorithm suites selecting the key types generated for
+// users and hosts. RSA remains the default for compatibility with old
+// OpenSSH peers.
+const (
+	// SuiteRSA2048 generates RSA 2048 keys.
+	SuiteRSA2048 = "rsa2048"
+	// SuiteECDSAP256 generates ECDSA P-256 keys.
+	SuiteECDSAP256 = "ecdsa-p256"
+	// SuiteEd25519 generates Ed25519 keys. Requires OpenSSH 6.5 or
+	// newer on all peers.
+	SuiteEd25519 = "ed25519"
+)
+
 // GenerateKeyPair returns fresh priv/pub keypair, takes about 300ms to
 // execute.
 func GenerateKeyPair(passphrase string) ([]byte, []byte, error) {
-	priv, err := rsa.GenerateKey(rand.Reader, teleport.RSAKeySize)
-	if err != nil {
-		return nil, nil, err
+	return GenerateKeyPairWithAlgorithm(SuiteRSA2048)
+}
+
+// ValidateAlgorithmSuite checks that the suite name is supported.
+func ValidateAlgorithmSuite(suite string) error {
+	switch suite {
+	case "", SuiteRSA2048, SuiteECDSAP256, SuiteEd25519:
+		return nil
 	}
-	privDer := x509.MarshalPKCS1PrivateKey(priv)
-	privBlock := pem.Block{
-		Type:    "RSA PRIVATE KEY",
-		Headers: nil,
-		Bytes:   privDer,
+	return trace.BadParameter("unsupported signature algorithm suite %q, expected %q, %q or %q",
+		suite, SuiteRSA2048, SuiteECDSAP256, SuiteEd25519)
+}
+
+// GenerateKeyPairWithAlgorithm returns a fresh priv/pub keypair of the
+// given signature algorithm suite.
+func GenerateKeyPairWithAlgorithm(suite string) ([]byte, []byte, error) {
+	var signer crypto.Signer
+	var privPem []byte
+	switch suite {
+	case "", SuiteRSA2048:
+		priv, err := rsa.GenerateKey(rand.Reader, teleport.RSAKeySize)
+		if err != nil {
+			return nil, nil, err
+		}
+		privPem = pem.EncodeToMemory(&pem.Block{
+			Type:  "RSA PRIVATE KEY",
+			Bytes: x509.MarshalPKCS1PrivateKey(priv),
+		})
+		signer = priv
+	case SuiteECDSAP256:
+		priv, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+		if err != nil {
+			return nil, nil, err
+		}
+		privDer, err := x509.MarshalECPrivateKey(priv)
+		if err != nil {
+			return nil, nil, err
+		}
+		privPem = pem.EncodeToMemory(&pem.Block{
+			Type:  "EC PRIVATE KEY",
+			Bytes: privDer,
+		})
+		signer = priv
+	case SuiteEd25519:
+		_, priv, err := ed25519.GenerateKey(rand.Reader)
+		if err != nil {
+			return nil, nil, err
+		}
+		privDer, err := x509.MarshalPKCS8PrivateKey(priv)
+		if err != nil {
+			return nil, nil, err
+		}
+		privPem = pem.EncodeToMemory(&pem.Block{
+			Type:  "PRIVATE KEY",
+			Bytes: privDer,
+		})
+		signer = priv
+	default:
+		return nil, nil, trace.BadParameter("unsupported signature algorithm suite %q, expected %q, %q or %q",
+			suite, SuiteRSA2048, SuiteECDSAP256, SuiteEd25519)
 	}
-	privPem := pem.EncodeToMemory(&privBlock)
 
-	pub, err := ssh.NewPublicKey(&priv.PublicKey)
+	pub, err := ssh.NewPublicKey(signer.Public())
 	if err != nil {
 		return nil, nil, err
 	}
-	pubBytes := ssh.MarshalAuthorizedKey(pub)
-	return privPem, pubBytes, nil
+	return privPem, ssh.MarshalAuthorizedKey(pub), nil
 }
 
 // GenerateKeyPair returns fresh priv/pub keypair, takes about 300ms to
 // execute.
 func (k *Keygen) GenerateKeyPair(passphrase string) ([]byte, []byte, error) {
-	return GenerateKeyPair(passphrase)
+	return GenerateKeyPairWithAlgorithm(k.algorithmSuite)
+}
+
+// SetAlgorithmSuite selects the signature algorithm suite used for
+// newly generated keys.
+func (k *Keygen) SetAlgorithmSuite(suite string) {
+	k.algorithmSuite = suite
 }
 
 // GenerateHostCert generates a host certificate with the passed in parameters.
@@ -276,6 +361,12 @@ func (k *Keygen) GenerateUserCertWithoutValidation(c services.UserCertParams) ([
 	if c.PermitPortForwarding {
 		cert.Permissions.Extensions[teleport.CertExtensionPermitPortForwarding] = ""
 	}
+	if c.SessionID != "" {
+		cert.Permissions.Extensions[teleport.CertExtensionSessionID] = c.SessionID
+	}
+	if len(c.AllowedResourceIDs) != 0 {
+		cert.Permissions.Extensions[teleport.CertExtensionAllowedResources] = strings.Join(c.AllowedResourceIDs, ",")
+	}
 	if c.MFAVerified != "" {
 		cert.Permissions.Extensions[teleport.CertExtensionMFAVerified] = c.MFAVerified
 	}
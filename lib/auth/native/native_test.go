@@ -246,3 +246,55 @@ func (s *NativeSuite) TestUserCertCompatibility(c *check.C) {
 		c.Assert(ok, check.Equals, tt.outHasRoles, comment)
 	}
 }
+
+// TestSessionIDExtension makes sure that the session ID is embedded into
+// session-bound user certificates and left out otherwise.
+func (s *NativeSuite) TestSessionIDExtension(c *check.C) {
+	priv, pub, err := s.suite.A.GenerateKeyPair("")
+	c.Assert(err, check.IsNil)
+
+	caSigner, err := ssh.ParsePrivateKey(priv)
+	c.Assert(err, check.IsNil)
+
+	params := services.UserCertParams{
+		CASigner:      caSigner,
+		CASigningAlg:  defaults.CASignatureAlgorithm,
+		PublicUserKey: pub,
+		Username:      "user",
+		AllowedLogins: []string{"centos"},
+		TTL:           time.Minute,
+		Roles:         []string{"foo"},
+		SessionID:     "00000000-1111-2222-3333-444444444444",
+	}
+	userCertificateBytes, err := s.suite.A.GenerateUserCert(params)
+	c.Assert(err, check.IsNil)
+	userCertificate, err := sshutils.ParseCertificate(userCertificateBytes)
+	c.Assert(err, check.IsNil)
+	c.Assert(userCertificate.Extensions[teleport.CertExtensionSessionID], check.Equals, params.SessionID)
+
+	// A certificate without a session ID does not carry the extension.
+	params.SessionID = ""
+	userCertificateBytes, err = s.suite.A.GenerateUserCert(params)
+	c.Assert(err, check.IsNil)
+	userCertificate, err = sshutils.ParseCertificate(userCertificateBytes)
+	c.Assert(err, check.IsNil)
+	_, ok := userCertificate.Extensions[teleport.CertExtensionSessionID]
+	c.Assert(ok, check.Equals, false)
+}
+
+// TestGenerateKeyPairWithAlgorithm checks key generation across the
+// supported signature algorithm suites.
+func (s *NativeSuite) TestGenerateKeyPairWithAlgorithm(c *check.C) {
+	for _, suite := range []string{SuiteRSA2048, SuiteECDSAP256, SuiteEd25519} {
+		priv, pub, err := GenerateKeyPairWithAlgorithm(suite)
+		c.Assert(err, check.IsNil, check.Commentf("suite %v", suite))
+		signer, err := ssh.ParsePrivateKey(priv)
+		c.Assert(err, check.IsNil, check.Commentf("suite %v", suite))
+		parsed, _, _, _, err := ssh.ParseAuthorizedKey(pub)
+		c.Assert(err, check.IsNil, check.Commentf("suite %v", suite))
+		c.Assert(signer.PublicKey().Type(), check.Equals, parsed.Type())
+	}
+
+	_, _, err := GenerateKeyPairWithAlgorithm("dsa")
+	c.Assert(err, check.NotNil)
+}
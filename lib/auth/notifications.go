@@ -0,0 +1,182 @@
+/*
+Copyright 2021 Gravitational, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package auth
+
+import (
+	"context"
+	"encoding/json"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/gravitational/teleport/lib/backend"
+
+	"github.com/gravitational/trace"
+	"github.com/pborman/uuid"
+)
+
+// Notification kinds surfaced in the notification center.
+const (
+	// NotificationTypeAnnouncement is a cluster announcement from an
+	// admin.
+	NotificationTypeAnnouncement = "announcement"
+	// NotificationTypeAccessRequest reports an access request decision.
+	NotificationTypeAccessRequest = "access-request"
+)
+
+// notificationsPrefix is the backend key prefix notifications are stored
+// under: notifications/users/<user>/<id> for per-user notices,
+// notifications/global/<id> for cluster announcements and
+// notifications/acks/<user>/<id> for acknowledged announcements.
+const notificationsPrefix = "notifications"
+
+// defaultNotificationTTL bounds how long a notification is kept when the
+// creator does not set an expiry.
+const defaultNotificationTTL = 30 * 24 * time.Hour
+
+// Notification is a per-user or cluster-wide notice shown by the web UI
+// and tsh.
+type Notification struct {
+	// ID identifies the notification.
+	ID string `json:"id"`
+	// User is the recipient, empty for cluster-wide announcements.
+	User string `json:"user,omitempty"`
+	// Type is one of the NotificationType constants.
+	Type string `json:"type"`
+	// Title is a short headline.
+	Title string `json:"title"`
+	// Message is the notification body.
+	Message string `json:"message"`
+	// Created is when the notification was created.
+	Created time.Time `json:"created"`
+	// Expires is when the notification disappears.
+	Expires time.Time `json:"expires"`
+}
+
+// CheckAndSetDefaults validates the notification and fills in the ID,
+// creation time and expiry.
+func (n *Notification) CheckAndSetDefaults(now time.Time) error {
+	if n.Title == "" && n.Message == "" {
+		return trace.BadParameter("notification requires a title or a message")
+	}
+	if n.Type == "" {
+		n.Type = NotificationTypeAnnouncement
+	}
+	if n.ID == "" {
+		n.ID = uuid.New()
+	}
+	if n.Created.IsZero() {
+		n.Created = now
+	}
+	if n.Expires.IsZero() {
+		n.Expires = now.Add(defaultNotificationTTL)
+	}
+	return nil
+}
+
+// CreateNotification stores a notification for a user, or for the whole
+// cluster when the user is empty.
+func (a *Server) CreateNotification(ctx context.Context, notification Notification) error {
+	if err := notification.CheckAndSetDefaults(a.clock.Now().UTC()); err != nil {
+		return trace.Wrap(err)
+	}
+	value, err := json.Marshal(notification)
+	if err != nil {
+		return trace.Wrap(err)
+	}
+	key := backend.Key(notificationsPrefix, "global", notification.ID)
+	if notification.User != "" {
+		key = backend.Key(notificationsPrefix, "users", notification.User, notification.ID)
+	}
+	_, err = a.bk.Put(ctx, backend.Item{
+		Key:     key,
+		Value:   value,
+		Expires: notification.Expires,
+	})
+	return trace.Wrap(err)
+}
+
+// GetNotifications returns the user's notifications together with the
+// cluster announcements the user has not acknowledged yet, newest first.
+func (a *Server) GetNotifications(ctx context.Context, user string) ([]Notification, error) {
+	acked := map[string]bool{}
+	ackResult, err := a.bk.GetRange(ctx,
+		backend.Key(notificationsPrefix, "acks", user),
+		backend.RangeEnd(backend.Key(notificationsPrefix, "acks", user)),
+		backend.NoLimit)
+	if err != nil {
+		return nil, trace.Wrap(err)
+	}
+	for _, item := range ackResult.Items {
+		key := string(item.Key)
+		acked[key[strings.LastIndex(key, string(backend.Separator))+1:]] = true
+	}
+
+	var notifications []Notification
+	for _, prefix := range [][]byte{
+		backend.Key(notificationsPrefix, "users", user),
+		backend.Key(notificationsPrefix, "global"),
+	} {
+		result, err := a.bk.GetRange(ctx, prefix, backend.RangeEnd(prefix), backend.NoLimit)
+		if err != nil {
+			return nil, trace.Wrap(err)
+		}
+		for _, item := range result.Items {
+			var notification Notification
+			if err := json.Unmarshal(item.Value, &notification); err != nil {
+				log.WithError(err).Warningf("Skipping corrupted notification %v.", string(item.Key))
+				continue
+			}
+			if notification.User == "" && acked[notification.ID] {
+				continue
+			}
+			notifications = append(notifications, notification)
+		}
+	}
+	sort.Slice(notifications, func(i, j int) bool {
+		return notifications[i].Created.After(notifications[j].Created)
+	})
+	return notifications, nil
+}
+
+// AckNotification dismisses a notification for the user: per-user notices
+// are deleted, cluster announcements are remembered as acknowledged.
+func (a *Server) AckNotification(ctx context.Context, user, id string) error {
+	err := a.bk.Delete(ctx, backend.Key(notificationsPrefix, "users", user, id))
+	if err == nil {
+		return nil
+	}
+	if !trace.IsNotFound(err) {
+		return trace.Wrap(err)
+	}
+	// Not a per-user notification: record an acknowledgment tombstone for
+	// the announcement, expiring when the announcement itself would.
+	item, err := a.bk.Get(ctx, backend.Key(notificationsPrefix, "global", id))
+	if err != nil {
+		return trace.Wrap(err)
+	}
+	var notification Notification
+	if err := json.Unmarshal(item.Value, &notification); err != nil {
+		return trace.Wrap(err)
+	}
+	_, err = a.bk.Put(ctx, backend.Item{
+		Key:     backend.Key(notificationsPrefix, "acks", user, id),
+		Value:   []byte("acked"),
+		Expires: notification.Expires,
+	})
+	return trace.Wrap(err)
+}
@@ -439,6 +439,7 @@ func (a *Server) calculateOIDCUser(connector types.OIDCConnector, claims jose.Cl
 	}
 
 	p.traits = services.OIDCClaimsToTraits(claims)
+	p.traits = a.applyLoginRulesToTraits(context.TODO(), p.traits)
 
 	var warnings []string
 	warnings, p.roles = services.TraitsToRoles(connector.GetTraitMappings(), p.traits)
@@ -455,6 +456,11 @@ func (a *Server) calculateOIDCUser(connector types.OIDCConnector, claims jose.Cl
 		return nil, trace.Wrap(err)
 	}
 	roleTTL := roles.AdjustSessionTTL(apidefaults.MaxCertDuration)
+	// Connectors with an ACR value delegate MFA to the identity provider:
+	// the ACR claim has been validated by the time we get here, so the
+	// login counts as MFA-verified for certificate TTL laddering. Logins
+	// through connectors without ACR get the short TTL rung.
+	roleTTL = roles.AdjustMFAVerifiedSessionTTL(roleTTL, connector.GetACR() != "")
 	p.sessionTTL = utils.MinTTL(roleTTL, request.CertTTL)
 
 	return &p, nil
@@ -0,0 +1,101 @@
+/*
+Copyright 2021 Gravitational, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package auth
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/gravitational/teleport/api/types"
+	apiutils "github.com/gravitational/teleport/api/utils"
+)
+
+// pagerDutyApprovalTimeout bounds the PagerDuty API calls made for a
+// single auto-approval decision.
+const pagerDutyApprovalTimeout = 30 * time.Second
+
+// Annotation keys recorded on auto-approved access requests.
+const (
+	// pagerDutyScheduleAnnotation lists the schedules that matched.
+	pagerDutyScheduleAnnotation = "pagerduty_schedule"
+	// pagerDutyShiftAnnotation lists the matching on-call shifts.
+	pagerDutyShiftAnnotation = "pagerduty_shift"
+)
+
+// tryPagerDutyAutoApproval approves the access request if the requesting
+// user is currently on call for the PagerDuty schedule mapped to every
+// requested role. It is called in a goroutine after the request is
+// created, any failure leaves the request pending for a human reviewer.
+func (a *Server) tryPagerDutyAutoApproval(req types.AccessRequest) {
+	ctx, cancel := context.WithTimeout(a.closeCtx, pagerDutyApprovalTimeout)
+	defer cancel()
+	// Attribute the approval to the integration in the audit trail.
+	ctx = apiutils.WithDelegator(ctx, "pagerduty")
+
+	if len(req.GetRoles()) == 0 {
+		return
+	}
+	annotations := map[string][]string{}
+	for _, role := range req.GetRoles() {
+		scheduleID, ok := a.pagerduty.Schedule(role)
+		if !ok {
+			// All requested roles must be covered by the schedule map.
+			return
+		}
+		oncalls, err := a.pagerduty.OnCalls(ctx, scheduleID)
+		if err != nil {
+			log.WithError(err).Warningf("Failed to check PagerDuty on-calls for access request %v.", req.GetName())
+			return
+		}
+		var onCall bool
+		for _, shift := range oncalls {
+			if shift.UserEmail != req.GetUser() {
+				continue
+			}
+			onCall = true
+			annotations[pagerDutyScheduleAnnotation] = append(annotations[pagerDutyScheduleAnnotation], shift.ScheduleID)
+			annotations[pagerDutyShiftAnnotation] = append(annotations[pagerDutyShiftAnnotation],
+				fmt.Sprintf("%v/%v %v - %v", shift.ScheduleSummary, shift.UserEmail, shift.Start, shift.End))
+			break
+		}
+		if !onCall {
+			return
+		}
+	}
+
+	err := a.SetAccessRequestState(ctx, types.AccessRequestUpdate{
+		RequestID:   req.GetName(),
+		State:       types.RequestState_APPROVED,
+		Reason:      "auto-approved: requesting user is on call in PagerDuty",
+		Annotations: annotations,
+	})
+	if err != nil {
+		log.WithError(err).Warningf("Failed to auto-approve access request %v.", req.GetName())
+		return
+	}
+	log.Infof("Auto-approved access request %v for on-call user %v.", req.GetName(), req.GetUser())
+}
+
+// maybeStartPagerDutyAutoApproval kicks off the auto-approval check for
+// newly created pending requests when the integration is configured.
+func (a *Server) maybeStartPagerDutyAutoApproval(req types.AccessRequest) {
+	if a.pagerduty == nil || !req.GetState().IsPending() {
+		return
+	}
+	go a.tryPagerDutyAutoApproval(req)
+}
@@ -37,19 +37,26 @@ type ChangePasswordWithTokenRequest struct {
 	U2FRegisterResponse *u2f.RegisterChallengeResponse `json:"u2f_register_response,omitempty"`
 }
 
-// ChangePasswordWithToken changes password with token
-func (s *Server) ChangePasswordWithToken(ctx context.Context, req ChangePasswordWithTokenRequest) (types.WebSession, error) {
+// ChangePasswordWithToken changes password with token and returns a fresh
+// set of one-time account recovery codes. Codes are rotated on every
+// token-based password (re)set, invalidating previously issued ones.
+func (s *Server) ChangePasswordWithToken(ctx context.Context, req ChangePasswordWithTokenRequest) (types.WebSession, []string, error) {
 	user, err := s.changePasswordWithToken(ctx, req)
 	if err != nil {
-		return nil, trace.Wrap(err)
+		return nil, nil, trace.Wrap(err)
+	}
+
+	recoveryCodes, err := s.generateRecoveryCodes(ctx, user.GetName())
+	if err != nil {
+		return nil, nil, trace.Wrap(err)
 	}
 
 	sess, err := s.createUserWebSession(ctx, user)
 	if err != nil {
-		return nil, trace.Wrap(err)
+		return nil, nil, trace.Wrap(err)
 	}
 
-	return sess, nil
+	return sess, recoveryCodes, nil
 }
 
 // ResetPassword securely generates a new random password and assigns it to user.
@@ -78,7 +85,7 @@ func (s *Server) ResetPassword(username string) (string, error) {
 func (s *Server) ChangePassword(req services.ChangePasswordReq) error {
 	ctx := context.TODO()
 	// validate new password
-	if err := services.VerifyPassword(req.NewPassword); err != nil {
+	if err := s.passwordPolicy.Check(req.NewPassword); err != nil {
 		return trace.Wrap(err)
 
 	}
@@ -97,7 +104,7 @@ func (s *Server) ChangePassword(req services.ChangePasswordReq) error {
 		case constants.SecondFactorOTP:
 			_, err := s.checkPassword(userID, req.OldPassword, req.SecondFactorToken)
 			return trace.Wrap(err)
-		case constants.SecondFactorU2F:
+		case constants.SecondFactorU2F, constants.SecondFactorPasswordless:
 			if req.U2FSignResponse == nil {
 				return trace.AccessDenied("missing U2F sign response")
 			}
@@ -166,6 +173,9 @@ func (s *Server) ChangePassword(req services.ChangePasswordReq) error {
 func (s *Server) checkPasswordWOToken(user string, password []byte) error {
 	const errMsg = "invalid username or password"
 
+	// Note: only the relaxed sanity check applies here. The configured
+	// password policy governs setting new passwords, applying it at login
+	// time would lock out users whose passwords predate a policy change.
 	err := services.VerifyPassword(password)
 	if err != nil {
 		return trace.BadParameter(errMsg)
@@ -358,7 +368,7 @@ func (s *Server) changePasswordWithToken(ctx context.Context, req ChangePassword
 		return nil, trace.AccessDenied(noLocalAuth)
 	}
 
-	err = services.VerifyPassword(req.Password)
+	err = s.passwordPolicy.Check(req.Password)
 	if err != nil {
 		return nil, trace.Wrap(err)
 	}
@@ -413,7 +423,7 @@ func (s *Server) changeUserSecondFactor(req ChangePasswordWithTokenRequest, toke
 		return nil
 	}
 	if req.SecondFactorToken != "" {
-		if secondFactor == constants.SecondFactorU2F {
+		if secondFactor == constants.SecondFactorU2F || secondFactor == constants.SecondFactorPasswordless {
 			return trace.BadParameter("user %q sent an OTP token during password reset but cluster only allows U2F for second factor", username)
 		}
 		secrets, err := s.Identity.GetResetPasswordTokenSecrets(ctx, req.TokenID)
@@ -0,0 +1,109 @@
+/*
+Copyright 2021 Gravitational, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package auth
+
+import (
+	"context"
+	"encoding/json"
+
+	"github.com/gravitational/teleport/lib/backend"
+	"github.com/gravitational/teleport/lib/utils"
+
+	"github.com/gravitational/trace"
+	"golang.org/x/crypto/bcrypt"
+)
+
+const (
+	// numRecoveryCodes is the number of one-time recovery codes handed out
+	// when an account is set up
+	numRecoveryCodes = 8
+	// recoveryCodeRandomBytes is the amount of randomness in each code,
+	// the codes are hex encoded so they are twice as many characters
+	recoveryCodeRandomBytes = 8
+	// recoveryCodePrefix makes the codes recognizable, so password
+	// managers and support staff can tell what they are looking at
+	recoveryCodePrefix = "tele-"
+	// recoveryCodesKey is the backend key component under which the bcrypt
+	// hashes of a user's recovery codes are stored
+	recoveryCodesKey = "recoverycodes"
+)
+
+// generateRecoveryCodes creates a fresh set of one-time recovery codes for
+// the user, stores their bcrypt hashes and returns the plaintext codes.
+// Any previously issued codes are invalidated.
+func (s *Server) generateRecoveryCodes(ctx context.Context, username string) ([]string, error) {
+	codes := make([]string, 0, numRecoveryCodes)
+	hashes := make([][]byte, 0, numRecoveryCodes)
+	for i := 0; i < numRecoveryCodes; i++ {
+		random, err := utils.CryptoRandomHex(recoveryCodeRandomBytes)
+		if err != nil {
+			return nil, trace.Wrap(err)
+		}
+		code := recoveryCodePrefix + random
+		hash, err := bcrypt.GenerateFromPassword([]byte(code), bcrypt.DefaultCost)
+		if err != nil {
+			return nil, trace.Wrap(err)
+		}
+		codes = append(codes, code)
+		hashes = append(hashes, hash)
+	}
+
+	value, err := json.Marshal(hashes)
+	if err != nil {
+		return nil, trace.Wrap(err)
+	}
+	_, err = s.bk.Put(ctx, backend.Item{
+		Key:   backend.Key(recoveryCodesKey, username),
+		Value: value,
+	})
+	if err != nil {
+		return nil, trace.Wrap(err)
+	}
+	return codes, nil
+}
+
+// VerifyRecoveryCode checks the supplied recovery code for the user and
+// consumes it on success, so each code can only be used once.
+func (s *Server) VerifyRecoveryCode(ctx context.Context, username, code string) error {
+	item, err := s.bk.Get(ctx, backend.Key(recoveryCodesKey, username))
+	if err != nil {
+		// Obscure whether the user has recovery codes at all.
+		log.Debugf("Failed to load recovery codes for %q: %v.", username, err)
+		return trace.AccessDenied("invalid recovery code")
+	}
+	var hashes [][]byte
+	if err := json.Unmarshal(item.Value, &hashes); err != nil {
+		return trace.Wrap(err)
+	}
+	for i, hash := range hashes {
+		if bcrypt.CompareHashAndPassword(hash, []byte(code)) != nil {
+			continue
+		}
+		// Burn the used code.
+		hashes = append(hashes[:i], hashes[i+1:]...)
+		value, err := json.Marshal(hashes)
+		if err != nil {
+			return trace.Wrap(err)
+		}
+		item.Value = value
+		if _, err := s.bk.Put(ctx, *item); err != nil {
+			return trace.Wrap(err)
+		}
+		return nil
+	}
+	return trace.AccessDenied("invalid recovery code")
+}
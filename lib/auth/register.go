@@ -183,6 +183,7 @@ func registerThroughProxy(token string, params RegisterParams) (*Identity, error
 			DNSNames:             params.DNSNames,
 			PublicTLSKey:         params.PublicTLSKey,
 			PublicSSHKey:         params.PublicSSHKey,
+			Version:              teleport.Version,
 		})
 	if err != nil {
 		return nil, trace.Unwrap(err)
@@ -221,6 +222,7 @@ func registerThroughAuth(token string, params RegisterParams) (*Identity, error)
 		DNSNames:             params.DNSNames,
 		PublicTLSKey:         params.PublicTLSKey,
 		PublicSSHKey:         params.PublicSSHKey,
+		Version:              teleport.Version,
 	})
 	if err != nil {
 		return nil, trace.Wrap(err)
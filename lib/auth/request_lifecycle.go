@@ -0,0 +1,135 @@
+/*
+Copyright 2021 Gravitational, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package auth
+
+import (
+	"context"
+	"time"
+
+	"github.com/gravitational/teleport/api/types"
+	apiutils "github.com/gravitational/teleport/api/utils"
+
+	"github.com/gravitational/trace"
+)
+
+// accessRequestCleanupPeriod is how often the request lifecycle job runs.
+const accessRequestCleanupPeriod = 10 * time.Minute
+
+// Annotation keys driving the access request lifecycle.
+const (
+	// AssumeStartTimeAnnotation holds an RFC3339 timestamp before which
+	// an approved request cannot be assumed, set by the approver.
+	AssumeStartTimeAnnotation = "assume_start_time"
+	// assumedAtAnnotation records when the request was first used to
+	// issue a certificate.
+	assumedAtAnnotation = "assumed_at"
+)
+
+// processActiveRequests inspects the access requests a certificate is
+// being issued for: it rejects requests whose assume start time has not
+// arrived yet, records the first assumption, and collects the resource
+// IDs the certificate must be scoped to.
+func (a *Server) processActiveRequests(ctx context.Context, requestIDs []string) ([]string, error) {
+	var resourceIDs []string
+	scoped := true
+	now := a.clock.Now().UTC()
+	for _, requestID := range requestIDs {
+		req, err := a.getAccessRequest(ctx, requestID)
+		if err != nil {
+			return nil, trace.Wrap(err)
+		}
+		annotations := req.GetSystemAnnotations()
+		if start := annotationTime(annotations, AssumeStartTimeAnnotation); !start.IsZero() && now.Before(start) {
+			return nil, trace.AccessDenied("access request %v cannot be assumed before %v",
+				requestID, start.Format(time.RFC3339))
+		}
+		if annotationTime(annotations, assumedAtAnnotation).IsZero() {
+			if annotations == nil {
+				annotations = map[string][]string{}
+			}
+			annotations[assumedAtAnnotation] = []string{now.Format(time.RFC3339)}
+			req.SetSystemAnnotations(annotations)
+			if err := a.DynamicAccessExt.UpsertAccessRequest(ctx, req); err != nil {
+				log.WithError(err).Warningf("Failed to mark access request %v as assumed.", requestID)
+			}
+		}
+		// The certificate is only scoped when every active request is a
+		// resource request, a plain role request keeps its full reach.
+		ids := annotations[requestedResourcesAnnotation]
+		if len(ids) == 0 {
+			scoped = false
+			continue
+		}
+		resourceIDs = append(resourceIDs, ids...)
+	}
+	if !scoped {
+		return nil, nil
+	}
+	return resourceIDs, nil
+}
+
+// cleanupAccessRequests expires approvals that were never assumed and
+// prunes resolved requests past the retention period.
+func (a *Server) cleanupAccessRequests(ctx context.Context) {
+	reqs, err := a.GetAccessRequests(ctx, types.AccessRequestFilter{})
+	if err != nil {
+		log.WithError(err).Warning("Failed to list access requests for lifecycle cleanup.")
+		return
+	}
+	now := a.clock.Now().UTC()
+	for _, req := range reqs {
+		annotations := req.GetSystemAnnotations()
+		switch {
+		case a.accessRequestUnassumedTTL > 0 &&
+			req.GetState().IsApproved() &&
+			annotationTime(annotations, assumedAtAnnotation).IsZero() &&
+			now.After(req.GetCreationTime().Add(a.accessRequestUnassumedTTL)):
+			err := a.SetAccessRequestState(apiutils.WithDelegator(ctx, "lifecycle"), types.AccessRequestUpdate{
+				RequestID: req.GetName(),
+				State:     types.RequestState_DENIED,
+				Reason:    "approval expired before being assumed",
+			})
+			if err != nil {
+				log.WithError(err).Warningf("Failed to expire unassumed access request %v.", req.GetName())
+				continue
+			}
+			log.Infof("Expired unassumed approval of access request %v.", req.GetName())
+		case a.accessRequestRetention > 0 &&
+			!req.GetState().IsPending() &&
+			now.After(req.GetCreationTime().Add(a.accessRequestRetention)):
+			if err := a.DynamicAccessExt.DeleteAccessRequest(ctx, req.GetName()); err != nil {
+				log.WithError(err).Warningf("Failed to prune access request %v.", req.GetName())
+				continue
+			}
+			log.Infof("Pruned resolved access request %v past the retention period.", req.GetName())
+		}
+	}
+}
+
+// annotationTime parses an RFC3339 timestamp stored in a system
+// annotation, returning the zero time when absent or malformed.
+func annotationTime(annotations map[string][]string, key string) time.Time {
+	values := annotations[key]
+	if len(values) == 0 {
+		return time.Time{}
+	}
+	t, err := time.Parse(time.RFC3339, values[0])
+	if err != nil {
+		return time.Time{}
+	}
+	return t
+}
@@ -0,0 +1,102 @@
+/*
+Copyright 2021 Gravitational, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package auth
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/gravitational/teleport"
+	apidefaults "github.com/gravitational/teleport/api/defaults"
+	"github.com/gravitational/teleport/api/types"
+	apiutils "github.com/gravitational/teleport/api/utils"
+	"github.com/gravitational/teleport/lib/services"
+
+	"github.com/gravitational/trace"
+)
+
+// requestedResourcesAnnotation stores the resource IDs a translated
+// resource access request asked for. The IDs end up in the issued
+// certificate and are enforced at the resource.
+const requestedResourcesAnnotation = "requested_resources"
+
+// resourceRequestRolePrefix prefixes roles synthesized for resource
+// access requests. Users must be allowed to request roles matching this
+// prefix (e.g. allow rule "resource-access-*") for resource requests to
+// pass validation.
+const resourceRequestRolePrefix = "resource-access-"
+
+// translateResourceRequest rewrites "resource:kind/name" entries in the
+// request's role list into a synthesized role scoped by the certificate's
+// allowed resource list. It returns the requested resource IDs, empty
+// when the request only names plain roles.
+func (a *Server) translateResourceRequest(ctx context.Context, req types.AccessRequest) ([]string, error) {
+	resourceIDs, remaining, err := services.ParseResourceRequestIDs(req.GetRoles())
+	if err != nil {
+		return nil, trace.Wrap(err)
+	}
+	if len(resourceIDs) == 0 {
+		return nil, nil
+	}
+	if len(remaining) != 0 {
+		return nil, trace.BadParameter("cannot mix role and resource requests, request the roles separately")
+	}
+
+	// The synthesized role only opens the resource kinds that were
+	// actually requested, the issued certificate is scoped down to the
+	// requested resource IDs and enforced where the connection lands. The
+	// role outlives the longest certificate the request could produce.
+	wildcard := types.Labels{types.Wildcard: apiutils.Strings{types.Wildcard}}
+	allow := types.RoleConditions{
+		Namespaces: []string{apidefaults.Namespace},
+	}
+	for _, resourceID := range resourceIDs {
+		switch strings.SplitN(resourceID, "/", 2)[0] {
+		case "node":
+			allow.Logins = []string{teleport.TraitInternalLoginsVariable}
+			allow.NodeLabels = wildcard
+		case "db":
+			allow.DatabaseLabels = wildcard
+		case "kube":
+			allow.KubernetesLabels = wildcard
+		}
+	}
+	expires := a.clock.Now().UTC().Add(apidefaults.MaxCertDuration)
+	role := &types.RoleV4{
+		Kind:    types.KindRole,
+		Version: types.V3,
+		Metadata: types.Metadata{
+			Name:        resourceRequestRolePrefix + req.GetName(),
+			Namespace:   apidefaults.Namespace,
+			Description: fmt.Sprintf("Synthesized for access request %v, certificate scoped to %v", req.GetName(), resourceIDs),
+			Expires:     &expires,
+		},
+		Spec: types.RoleSpecV4{
+			Allow: allow,
+		},
+	}
+	if err := role.CheckAndSetDefaults(); err != nil {
+		return nil, trace.Wrap(err)
+	}
+	if err := a.UpsertRole(ctx, role); err != nil {
+		return nil, trace.Wrap(err)
+	}
+	req.SetRoles([]string{role.GetName()})
+	return resourceIDs, nil
+}
+
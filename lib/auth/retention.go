@@ -0,0 +1,83 @@
+/*
+Copyright 2021 Gravitational, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package auth
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+)
+
+// recordingRetentionCheckPeriod is how often the retention policy is
+// evaluated.
+const recordingRetentionCheckPeriod = time.Hour
+
+// pruneSessionRecordings deletes (or, in dry-run mode, reports) session
+// recordings in the local records directory older than the retention
+// period. Every pruned recording is logged with its session ID so the
+// operation leaves a trail.
+func (a *Server) pruneSessionRecordings() {
+	if a.recordingRetention <= 0 || a.recordsDir == "" {
+		return
+	}
+	cutoff := a.clock.Now().Add(-a.recordingRetention)
+	entries, err := os.ReadDir(a.recordsDir)
+	if err != nil {
+		log.WithError(err).Warning("Failed to scan the session recordings directory for retention.")
+		return
+	}
+	var pruned, kept int
+	for _, entry := range entries {
+		if entry.IsDir() || !strings.HasSuffix(entry.Name(), ".tar") {
+			continue
+		}
+		info, err := entry.Info()
+		if err != nil {
+			continue
+		}
+		if !info.ModTime().Before(cutoff) {
+			kept++
+			continue
+		}
+		path := filepath.Join(a.recordsDir, entry.Name())
+		if a.recordingRetentionDryRun {
+			log.Infof("Retention dry-run: would prune session recording %v (age %v).",
+				entry.Name(), a.clock.Now().Sub(info.ModTime()).Round(time.Hour))
+			pruned++
+			continue
+		}
+		if err := os.Remove(path); err != nil {
+			log.WithError(err).Warningf("Failed to prune session recording %v.", entry.Name())
+			continue
+		}
+		// Remove the tamper-evidence chain along with the recording.
+		if err := os.Remove(path + ".chain"); err != nil && !os.IsNotExist(err) {
+			log.WithError(err).Warningf("Failed to prune recording chain of %v.", entry.Name())
+		}
+		log.Infof("Pruned session recording %v past the retention period of %v.",
+			entry.Name(), a.recordingRetention)
+		pruned++
+	}
+	if pruned > 0 {
+		action := "pruned"
+		if a.recordingRetentionDryRun {
+			action = "would prune"
+		}
+		log.Infof("Session recording retention: %v %v recording(s), kept %v.", action, pruned, kept)
+	}
+}
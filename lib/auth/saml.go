@@ -163,6 +163,7 @@ func (a *Server) calculateSAMLUser(connector types.SAMLConnector, assertionInfo
 	}
 
 	p.traits = services.SAMLAssertionsToTraits(assertionInfo)
+	p.traits = a.applyLoginRulesToTraits(context.TODO(), p.traits)
 
 	var warnings []string
 	warnings, p.roles = services.TraitsToRoles(connector.GetTraitMappings(), p.traits)
@@ -18,10 +18,14 @@ package auth
 
 import (
 	"context"
+	"sort"
 	"time"
 
 	"github.com/gravitational/teleport"
 	"github.com/gravitational/teleport/api/types"
+	apievents "github.com/gravitational/teleport/api/types/events"
+	"github.com/gravitational/teleport/lib/backend"
+	"github.com/gravitational/teleport/lib/events"
 	"github.com/gravitational/teleport/lib/jwt"
 	"github.com/gravitational/teleport/lib/modules"
 	"github.com/gravitational/teleport/lib/services"
@@ -34,6 +38,22 @@ import (
 	"github.com/pborman/uuid"
 )
 
+// webSessionIndexPrefix is the backend key prefix of the per-user web
+// session index, so the session limit can be enforced without listing
+// every web session in the cluster.
+const webSessionIndexPrefix = "websessionindex"
+
+// indexWebSession records the session in the per-user index. Index
+// entries share the session expiry, so they age out with the session.
+func (s *Server) indexWebSession(ctx context.Context, session types.WebSession) error {
+	_, err := s.bk.Put(ctx, backend.Item{
+		Key:     backend.Key(webSessionIndexPrefix, session.GetUser(), session.GetName()),
+		Value:   []byte(session.GetName()),
+		Expires: session.GetExpiryTime(),
+	})
+	return trace.Wrap(err)
+}
+
 // CreateAppSession creates and inserts a services.WebSession into the
 // backend with the identity of the caller used to generate the certificate.
 // The certificate is used for all access requests, which is where access
@@ -195,6 +215,10 @@ func (s *Server) createWebSession(ctx context.Context, req types.NewWebSessionRe
 		return nil, trace.Wrap(err)
 	}
 
+	if err := s.enforceWebSessionLimit(ctx, req); err != nil {
+		return nil, trace.Wrap(err)
+	}
+
 	err = s.upsertWebSession(ctx, req.User, session)
 	if err != nil {
 		return nil, trace.Wrap(err)
@@ -203,6 +227,89 @@ func (s *Server) createWebSession(ctx context.Context, req types.NewWebSessionRe
 	return session, nil
 }
 
+// enforceWebSessionLimit enforces the concurrent web session limit for
+// the user, as configured by the auth_service.max_web_sessions option.
+// The oldest sessions are evicted to make room, so a user who lost
+// track of a browser session does not get locked out; each eviction is
+// audited as a rejected session.
+func (s *Server) enforceWebSessionLimit(ctx context.Context, req types.NewWebSessionRequest) error {
+	limit := s.maxWebSessions
+	if limit == 0 {
+		return nil
+	}
+
+	userSessions, err := s.userWebSessions(ctx, req.User)
+	if err != nil {
+		return trace.Wrap(err)
+	}
+	// The new session is about to be added, remove the oldest sessions
+	// that would exceed the limit.
+	excess := len(userSessions) - limit + 1
+	if excess <= 0 {
+		return nil
+	}
+	sort.Slice(userSessions, func(i, j int) bool {
+		return userSessions[i].GetLoginTime().Before(userSessions[j].GetLoginTime())
+	})
+	for _, session := range userSessions[:excess] {
+		err := s.Identity.WebSessions().Delete(ctx, types.DeleteWebSessionRequest{
+			User:      req.User,
+			SessionID: session.GetName(),
+		})
+		if err != nil && !trace.IsNotFound(err) {
+			return trace.Wrap(err)
+		}
+		if err := s.bk.Delete(ctx, backend.Key(webSessionIndexPrefix, req.User, session.GetName())); err != nil && !trace.IsNotFound(err) {
+			log.WithError(err).Warning("Failed to remove the evicted web session from the index.")
+		}
+		if err := s.emitter.EmitAuditEvent(ctx, &apievents.SessionReject{
+			Metadata: apievents.Metadata{
+				Type: events.SessionRejectedEvent,
+				Code: events.SessionRejectedCode,
+			},
+			UserMetadata: apievents.UserMetadata{
+				User: req.User,
+			},
+			Reason:  "max_web_sessions limit reached, evicting the oldest web session",
+			Maximum: int64(limit),
+		}); err != nil {
+			log.WithError(err).Warning("Failed to emit the web session eviction audit event.")
+		}
+	}
+	return nil
+}
+
+// userWebSessions returns the user's current web sessions via the
+// per-user index, pruning index entries whose session is gone.
+func (s *Server) userWebSessions(ctx context.Context, user string) ([]types.WebSession, error) {
+	prefix := backend.Key(webSessionIndexPrefix, user)
+	result, err := s.bk.GetRange(ctx, prefix, backend.RangeEnd(prefix), backend.NoLimit)
+	if err != nil {
+		return nil, trace.Wrap(err)
+	}
+	var sessions []types.WebSession
+	for _, item := range result.Items {
+		sessionID := string(item.Value)
+		session, err := s.Identity.WebSessions().Get(ctx, types.GetWebSessionRequest{
+			User:      user,
+			SessionID: sessionID,
+		})
+		if err != nil {
+			if trace.IsNotFound(err) {
+				// The session was deleted without going through the
+				// index, drop the stale entry.
+				if err := s.bk.Delete(ctx, item.Key); err != nil && !trace.IsNotFound(err) {
+					log.WithError(err).Warning("Failed to prune a stale web session index entry.")
+				}
+				continue
+			}
+			return nil, trace.Wrap(err)
+		}
+		sessions = append(sessions, session)
+	}
+	return sessions, nil
+}
+
 func (s *Server) createSessionCert(user types.User, sessionTTL time.Duration, publicKey []byte, compatibility, routeToCluster, kubernetesCluster string) ([]byte, []byte, error) {
 	// It's safe to extract the roles and traits directly from services.User
 	// because this occurs during the user creation process and services.User
@@ -0,0 +1,120 @@
+/*
+Copyright 2021 Gravitational, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package auth
+
+import (
+	"fmt"
+	"sync"
+
+	"github.com/gravitational/teleport/api/types"
+	"github.com/gravitational/teleport/lib/tlsca"
+
+	"github.com/gravitational/trace"
+	"golang.org/x/crypto/ssh"
+)
+
+// signerCache caches parsed CA signing keys per certificate authority:
+// parsing an RSA private key costs more than signing with it, and
+// certificate issuance parses the same user CA key on every request.
+// Entries are keyed by the CA revision and rotation state, so updating
+// or rotating the CA invalidates the cache on the next issuance.
+type signerCache struct {
+	mu      sync.RWMutex
+	entries map[string]*cachedSigners
+}
+
+// cachedSigners pins the parsed signers to the CA revision they were
+// parsed from.
+type cachedSigners struct {
+	version      string
+	sshSigner    ssh.Signer
+	tlsAuthority *tlsca.CertAuthority
+}
+
+// sshSignerFor returns a parsed SSH signer for the CA, reusing the
+// cached one while the CA is unchanged.
+func (c *signerCache) sshSignerFor(ca types.CertAuthority) (ssh.Signer, error) {
+	entry := c.get(ca)
+	c.mu.RLock()
+	signer := entry.sshSigner
+	c.mu.RUnlock()
+	if signer != nil {
+		return signer, nil
+	}
+	parsed, err := sshSigner(ca)
+	if err != nil {
+		return nil, trace.Wrap(err)
+	}
+	c.mu.Lock()
+	entry.sshSigner = parsed
+	c.mu.Unlock()
+	return parsed, nil
+}
+
+// tlsAuthorityFor returns a parsed TLS certificate authority for the CA,
+// reusing the cached one while the CA is unchanged.
+func (c *signerCache) tlsAuthorityFor(ca types.CertAuthority) (*tlsca.CertAuthority, error) {
+	entry := c.get(ca)
+	c.mu.RLock()
+	tlsAuthority := entry.tlsAuthority
+	c.mu.RUnlock()
+	if tlsAuthority != nil {
+		return tlsAuthority, nil
+	}
+	tlsAuthority, err := tlsca.FromAuthority(ca)
+	if err != nil {
+		return nil, trace.Wrap(err)
+	}
+	c.mu.Lock()
+	entry.tlsAuthority = tlsAuthority
+	c.mu.Unlock()
+	return tlsAuthority, nil
+}
+
+// get returns the cache entry matching the CA's current revision,
+// replacing a stale one.
+func (c *signerCache) get(ca types.CertAuthority) *cachedSigners {
+	key := string(ca.GetType()) + "/" + ca.GetName()
+	version := versionKey(ca)
+
+	c.mu.RLock()
+	entry, ok := c.entries[key]
+	c.mu.RUnlock()
+	if ok && entry.version == version {
+		return entry
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.entries == nil {
+		c.entries = make(map[string]*cachedSigners)
+	}
+	if entry, ok := c.entries[key]; ok && entry.version == version {
+		return entry
+	}
+	entry = &cachedSigners{version: version}
+	c.entries[key] = entry
+	return entry
+}
+
+// versionKey derives the cache invalidation key from the CA revision and
+// rotation state, so mid-rotation phase changes never serve a stale
+// signer even when the resource ID lags.
+func versionKey(ca types.CertAuthority) string {
+	rotation := ca.GetRotation()
+	return fmt.Sprintf("%v/%v/%v", ca.GetResourceID(), rotation.State, rotation.Phase)
+}
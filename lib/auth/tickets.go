@@ -0,0 +1,155 @@
+/*
+Copyright 2021 Gravitational, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package auth
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/gravitational/teleport/api/types"
+	apiutils "github.com/gravitational/teleport/api/utils"
+	"github.com/gravitational/teleport/lib/tickets"
+
+	"github.com/gravitational/trace"
+)
+
+const (
+	// ticketCreateTimeout bounds the API call opening a ticket.
+	ticketCreateTimeout = 30 * time.Second
+	// ticketSyncPeriod is how often pending requests are reconciled with
+	// their ticket status.
+	ticketSyncPeriod = time.Minute
+)
+
+// System annotation keys linking an access request to its ticket.
+const (
+	// ticketIDAnnotation holds the ticket identifier.
+	ticketIDAnnotation = "ticket_id"
+	// ticketSystemAnnotation holds the ticketing system the ticket lives
+	// in.
+	ticketSystemAnnotation = "ticket_system"
+)
+
+// maybeCreateAccessRequestTicket opens a ticket for newly created pending
+// requests when the ticketing integration is configured.
+func (a *Server) maybeCreateAccessRequestTicket(req types.AccessRequest) {
+	if a.tickets == nil || !req.GetState().IsPending() {
+		return
+	}
+	go a.createAccessRequestTicket(req)
+}
+
+// createAccessRequestTicket opens the ticket and links it to the request
+// through system annotations. Failures are logged and leave the request
+// without a ticket, the periodic sync does not touch unlinked requests.
+func (a *Server) createAccessRequestTicket(req types.AccessRequest) {
+	ctx, cancel := context.WithTimeout(a.closeCtx, ticketCreateTimeout)
+	defer cancel()
+
+	summary := fmt.Sprintf("Teleport access request %v from %v", req.GetName(), req.GetUser())
+	description := fmt.Sprintf("User %v requests roles %v.", req.GetUser(), strings.Join(req.GetRoles(), ", "))
+	if reason := req.GetRequestReason(); reason != "" {
+		description += fmt.Sprintf(" Reason: %v", reason)
+	}
+	ticketID, err := a.tickets.CreateTicket(ctx, summary, description)
+	if err != nil {
+		log.WithError(err).Warningf("Failed to create %v ticket for access request %v.", a.tickets.System(), req.GetName())
+		return
+	}
+
+	// Re-fetch the request and annotate the fresh copy: the request may
+	// have been reviewed while the ticket was being created, writing the
+	// stale copy back would revert that decision.
+	current, err := a.getAccessRequest(ctx, req.GetName())
+	if err != nil {
+		log.WithError(err).Warningf("Failed to link ticket %v to access request %v.", ticketID, req.GetName())
+		return
+	}
+	annotations := current.GetSystemAnnotations()
+	if annotations == nil {
+		annotations = map[string][]string{}
+	}
+	annotations[ticketIDAnnotation] = []string{ticketID}
+	annotations[ticketSystemAnnotation] = []string{a.tickets.System()}
+	current.SetSystemAnnotations(annotations)
+	if err := a.DynamicAccessExt.UpsertAccessRequest(ctx, current); err != nil {
+		log.WithError(err).Warningf("Failed to link ticket %v to access request %v.", ticketID, req.GetName())
+		return
+	}
+	log.Infof("Created %v ticket %v for access request %v.", a.tickets.System(), ticketID, req.GetName())
+}
+
+// getAccessRequest fetches a single access request by name.
+func (a *Server) getAccessRequest(ctx context.Context, name string) (types.AccessRequest, error) {
+	reqs, err := a.GetAccessRequests(ctx, types.AccessRequestFilter{ID: name})
+	if err != nil {
+		return nil, trace.Wrap(err)
+	}
+	if len(reqs) == 0 {
+		return nil, trace.NotFound("access request %q not found", name)
+	}
+	return reqs[0], nil
+}
+
+// syncAccessRequestTickets reconciles pending access requests with the
+// status of their tickets: an approved ticket approves the request, a
+// rejected ticket denies it.
+func (a *Server) syncAccessRequestTickets(ctx context.Context) {
+	reqs, err := a.GetAccessRequests(ctx, types.AccessRequestFilter{})
+	if err != nil {
+		log.WithError(err).Warning("Failed to list access requests for ticket sync.")
+		return
+	}
+	for _, req := range reqs {
+		if !req.GetState().IsPending() {
+			continue
+		}
+		annotations := req.GetSystemAnnotations()
+		ids := annotations[ticketIDAnnotation]
+		if len(ids) == 0 {
+			continue
+		}
+		ticketID := ids[0]
+		status, err := a.tickets.TicketStatus(ctx, ticketID)
+		if err != nil {
+			log.WithError(err).Warningf("Failed to check ticket %v for access request %v.", ticketID, req.GetName())
+			continue
+		}
+		var state types.RequestState
+		switch status {
+		case tickets.StatusApproved:
+			state = types.RequestState_APPROVED
+		case tickets.StatusDenied:
+			state = types.RequestState_DENIED
+		default:
+			continue
+		}
+		err = a.SetAccessRequestState(apiutils.WithDelegator(ctx, a.tickets.System()), types.AccessRequestUpdate{
+			RequestID:   req.GetName(),
+			State:       state,
+			Reason:      fmt.Sprintf("ticket %v was %v", ticketID, status),
+			Annotations: map[string][]string{ticketIDAnnotation: {ticketID}},
+		})
+		if err != nil {
+			log.WithError(err).Warningf("Failed to update access request %v from ticket %v.", req.GetName(), ticketID)
+			continue
+		}
+		log.Infof("Access request %v %v from %v ticket %v.", req.GetName(), status, a.tickets.System(), ticketID)
+	}
+}
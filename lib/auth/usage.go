@@ -0,0 +1,134 @@
+/*
+Copyright 2021 Gravitational, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package auth
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"net/http"
+	"time"
+
+	"github.com/gravitational/teleport"
+	apidefaults "github.com/gravitational/teleport/api/defaults"
+	"github.com/gravitational/teleport/api/types"
+	"github.com/gravitational/teleport/lib/events"
+
+	"github.com/gravitational/trace"
+)
+
+// usageReportPeriod is how often an enabled reporter submits a report.
+const usageReportPeriod = 6 * time.Hour
+
+// usageReportWindow is the event window a report covers.
+const usageReportWindow = 24 * time.Hour
+
+// UsageReport is an anonymized snapshot of cluster usage. It contains
+// only counters and a salted hash of the cluster name, never names,
+// addresses or user identifiers.
+type UsageReport struct {
+	// ClusterID is a hash of the cluster name.
+	ClusterID string `json:"cluster_id"`
+	// Version is the auth server's Teleport version.
+	Version string `json:"version"`
+	// GeneratedAt is when the report was produced.
+	GeneratedAt time.Time `json:"generated_at"`
+	// Nodes, Users, Roles, Apps and Databases are resource counts.
+	Nodes     int `json:"nodes"`
+	Users     int `json:"users"`
+	Roles     int `json:"roles"`
+	Apps      int `json:"apps"`
+	Databases int `json:"databases"`
+	// LoginsLast24h counts user logins within the report window.
+	LoginsLast24h int `json:"logins_last_24h"`
+	// SessionsLast24h counts session starts within the report window.
+	SessionsLast24h int `json:"sessions_last_24h"`
+}
+
+// GetUsageReport builds the usage report operators can inspect with
+// "tctl usage report", the same payload an enabled reporter submits.
+func (a *Server) GetUsageReport(ctx context.Context) (*UsageReport, error) {
+	clusterName, err := a.GetClusterName()
+	if err != nil {
+		return nil, trace.Wrap(err)
+	}
+	hash := sha256.Sum256([]byte("teleport-usage-" + clusterName.GetClusterName()))
+
+	report := &UsageReport{
+		ClusterID:   hex.EncodeToString(hash[:8]),
+		Version:     teleport.Version,
+		GeneratedAt: a.clock.Now().UTC(),
+	}
+	if nodes, err := a.GetNodes(ctx, apidefaults.Namespace); err == nil {
+		report.Nodes = len(nodes)
+	}
+	if users, err := a.GetUsers(false); err == nil {
+		report.Users = len(users)
+	}
+	if roles, err := a.GetRoles(ctx); err == nil {
+		report.Roles = len(roles)
+	}
+	if apps, err := a.GetAppServers(ctx, apidefaults.Namespace); err == nil {
+		report.Apps = len(apps)
+	}
+	if databases, err := a.GetDatabaseServers(ctx, apidefaults.Namespace); err == nil {
+		report.Databases = len(databases)
+	}
+
+	to := a.clock.Now().UTC()
+	from := to.Add(-usageReportWindow)
+	logins, _, err := a.SearchEvents(from, to, apidefaults.Namespace,
+		[]string{events.UserLoginEvent}, 5000, types.EventOrderDescending, "")
+	if err == nil {
+		report.LoginsLast24h = len(logins)
+	}
+	sessions, _, err := a.SearchEvents(from, to, apidefaults.Namespace,
+		[]string{events.SessionStartEvent}, 5000, types.EventOrderDescending, "")
+	if err == nil {
+		report.SessionsLast24h = len(sessions)
+	}
+	return report, nil
+}
+
+// submitUsageReport posts the report to the configured endpoint.
+func (a *Server) submitUsageReport(ctx context.Context) {
+	report, err := a.GetUsageReport(ctx)
+	if err != nil {
+		log.WithError(err).Debug("Failed to build the usage report.")
+		return
+	}
+	payload, err := json.Marshal(report)
+	if err != nil {
+		return
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, a.usageReportingEndpoint, bytes.NewReader(payload))
+	if err != nil {
+		log.WithError(err).Debug("Failed to build the usage report request.")
+		return
+	}
+	req.Header.Set("Content-Type", "application/json")
+	client := &http.Client{Timeout: 30 * time.Second}
+	resp, err := client.Do(req)
+	if err != nil {
+		log.WithError(err).Debug("Failed to submit the usage report.")
+		return
+	}
+	resp.Body.Close()
+	log.Debugf("Submitted anonymized usage report to %v.", a.usageReportingEndpoint)
+}
@@ -90,6 +90,14 @@ type Backend interface {
 	Migrate(context.Context) error
 }
 
+// OnlineBackup is implemented by backends that can snapshot a live
+// database to a file without stopping writes, currently the sqlite
+// backend.
+type OnlineBackup interface {
+	// Backup writes a consistent snapshot of the backend to destPath.
+	Backup(ctx context.Context, destPath string) error
+}
+
 // Batch implements some batch methods
 // that are not mandatory for all interfaces,
 // only the ones used in bulk operations.
@@ -164,6 +164,15 @@ type Config struct {
 	// MaxClientMsgSizeBytes optionally specifies the size limit on client send message size.
 	// See https://github.com/etcd-io/etcd/blob/221f0cc107cb3497eeb20fb241e1bcafca2e9115/clientv3/config.go#L49
 	MaxClientMsgSizeBytes int `json:"etcd_max_client_msg_size_bytes,omitempty"`
+	// KeepAliveTime is the time after which the client pings the server to
+	// see if the transport is alive, set to 0 to disable keepalives
+	KeepAliveTime time.Duration `json:"keep_alive_time,omitempty"`
+	// KeepAliveTimeout is the time the client waits for a keepalive probe
+	// response before closing the connection
+	KeepAliveTimeout time.Duration `json:"keep_alive_timeout,omitempty"`
+	// AutoSyncInterval is the interval to update endpoints with the latest
+	// cluster members, set to 0 to disable auto sync
+	AutoSyncInterval time.Duration `json:"auto_sync_interval,omitempty"`
 }
 
 // GetName returns the name of etcd backend as it appears in 'storage/type' section
@@ -307,19 +316,16 @@ func (b *EtcdBackend) reconnect(ctx context.Context) error {
 	tlsConfig := utils.TLSConfig(nil)
 
 	if b.cfg.TLSCertFile != "" {
-		clientCertPEM, err := ioutil.ReadFile(b.cfg.TLSCertFile)
-		if err != nil {
-			return trace.ConvertSystemError(err)
+		// Make sure the certificate and key pair parses before the first
+		// handshake needs it.
+		if _, err := loadClientCertificate(b.cfg); err != nil {
+			return trace.Wrap(err)
 		}
-		clientKeyPEM, err := ioutil.ReadFile(b.cfg.TLSKeyFile)
-		if err != nil {
-			return trace.ConvertSystemError(err)
+		// Reload the client certificate from disk on every handshake, so
+		// rotated client certificates are picked up without a restart.
+		tlsConfig.GetClientCertificate = func(*tls.CertificateRequestInfo) (*tls.Certificate, error) {
+			return loadClientCertificate(b.cfg)
 		}
-		tlsCert, err := tls.X509KeyPair(clientCertPEM, clientKeyPEM)
-		if err != nil {
-			return trace.BadParameter("failed to parse private key: %v", err)
-		}
-		tlsConfig.Certificates = []tls.Certificate{tlsCert}
 	}
 
 	if b.cfg.TLSCAFile != "" {
@@ -340,13 +346,16 @@ func (b *EtcdBackend) reconnect(ctx context.Context) error {
 	}
 
 	clt, err := clientv3.New(clientv3.Config{
-		Endpoints:          b.nodes,
-		TLS:                tlsConfig,
-		DialTimeout:        b.cfg.DialTimeout,
-		DialOptions:        []grpc.DialOption{grpc.WithBlock()},
-		Username:           b.cfg.Username,
-		Password:           b.cfg.Password,
-		MaxCallSendMsgSize: b.cfg.MaxClientMsgSizeBytes,
+		Endpoints:            b.nodes,
+		TLS:                  tlsConfig,
+		DialTimeout:          b.cfg.DialTimeout,
+		DialOptions:          []grpc.DialOption{grpc.WithBlock()},
+		Username:             b.cfg.Username,
+		Password:             b.cfg.Password,
+		MaxCallSendMsgSize:   b.cfg.MaxClientMsgSizeBytes,
+		DialKeepAliveTime:    b.cfg.KeepAliveTime,
+		DialKeepAliveTimeout: b.cfg.KeepAliveTimeout,
+		AutoSyncInterval:     b.cfg.AutoSyncInterval,
 	})
 	if err != nil {
 		return trace.Wrap(err)
@@ -355,6 +364,25 @@ func (b *EtcdBackend) reconnect(ctx context.Context) error {
 	return nil
 }
 
+// loadClientCertificate reads and parses the client certificate and key
+// pair from disk, it is called on every TLS handshake to pick up rotated
+// client credentials.
+func loadClientCertificate(cfg *Config) (*tls.Certificate, error) {
+	clientCertPEM, err := ioutil.ReadFile(cfg.TLSCertFile)
+	if err != nil {
+		return nil, trace.ConvertSystemError(err)
+	}
+	clientKeyPEM, err := ioutil.ReadFile(cfg.TLSKeyFile)
+	if err != nil {
+		return nil, trace.ConvertSystemError(err)
+	}
+	tlsCert, err := tls.X509KeyPair(clientCertPEM, clientKeyPEM)
+	if err != nil {
+		return nil, trace.BadParameter("failed to parse private key: %v", err)
+	}
+	return &tlsCert, nil
+}
+
 func (b *EtcdBackend) asyncWatch() {
 	err := b.watchEvents()
 	b.Debugf("Watch exited: %v.", err)
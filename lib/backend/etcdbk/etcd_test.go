@@ -19,13 +19,16 @@ package etcdbk
 import (
 	"context"
 	"encoding/base64"
+	"io/ioutil"
 	"os"
+	"path/filepath"
 	"strings"
 	"testing"
 	"time"
 
 	"github.com/gravitational/teleport/lib/backend"
 	"github.com/gravitational/teleport/lib/backend/test"
+	"github.com/gravitational/teleport/lib/fixtures"
 	"github.com/gravitational/teleport/lib/utils"
 
 	"github.com/gravitational/trace"
@@ -238,3 +241,21 @@ func (r fakeClock) Advance(d time.Duration) {
 type fakeClock struct {
 	clockwork.FakeClock
 }
+
+func TestLoadClientCertificate(t *testing.T) {
+	dir := t.TempDir()
+	certFile := filepath.Join(dir, "cert.pem")
+	keyFile := filepath.Join(dir, "key.pem")
+	require.NoError(t, ioutil.WriteFile(certFile, fixtures.LocalhostCert, 0600))
+	require.NoError(t, ioutil.WriteFile(keyFile, fixtures.LocalhostKey, 0600))
+
+	cfg := &Config{TLSCertFile: certFile, TLSKeyFile: keyFile}
+	cert, err := loadClientCertificate(cfg)
+	require.NoError(t, err)
+	require.NotNil(t, cert)
+
+	// A rotated (rewritten) certificate is picked up on the next load.
+	require.NoError(t, ioutil.WriteFile(certFile, []byte("garbage"), 0600))
+	_, err = loadClientCertificate(cfg)
+	require.Error(t, err)
+}
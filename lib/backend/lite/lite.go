@@ -48,6 +48,15 @@ const (
 	slowTransactionThreshold             = time.Second
 	syncOFF                              = "OFF"
 	busyTimeout                          = 10000
+
+	// backupStepPages is the number of pages copied per backup step,
+	// the database lock is released between steps to let writers proceed
+	backupStepPages = 1024
+
+	// journalModeWAL is the write-ahead log journal mode, it allows
+	// concurrent readers while a writer is active and is more resilient
+	// against crashes than the rollback journal
+	journalModeWAL = "WAL"
 )
 
 // GetName is a part of backend API and it returns SQLite backend type
@@ -81,6 +90,10 @@ type Config struct {
 	Sync string `json:"sync,omitempty"`
 	// BusyTimeout sets busy timeout in milliseconds
 	BusyTimeout int `json:"busy_timeout,omitempty"`
+	// Journal sets the journal_mode pragma, defaults to WAL. Set to
+	// "DELETE" for filesystems that do not support shared memory files,
+	// e.g. some network filesystems.
+	Journal string `json:"journal,omitempty"`
 	// Memory turns memory mode of the database
 	Memory bool `json:"memory"`
 	// MemoryName sets the name of the database,
@@ -113,6 +126,9 @@ func (cfg *Config) CheckAndSetDefaults() error {
 	if cfg.BusyTimeout == 0 {
 		cfg.BusyTimeout = busyTimeout
 	}
+	if cfg.Journal == "" {
+		cfg.Journal = journalModeWAL
+	}
 	if cfg.MemoryName == "" {
 		cfg.MemoryName = defaultDBFile
 	}
@@ -143,7 +159,7 @@ func NewWithConfig(ctx context.Context, cfg Config) (*Backend, error) {
 			return nil, trace.ConvertSystemError(err)
 		}
 		fullPath := filepath.Join(cfg.Path, defaultDBFile)
-		connectorURL = fmt.Sprintf("file:%v?_busy_timeout=%v&_sync=%v", fullPath, cfg.BusyTimeout, cfg.Sync)
+		connectorURL = fmt.Sprintf("file:%v?_busy_timeout=%v&_sync=%v&_journal_mode=%v", fullPath, cfg.BusyTimeout, cfg.Sync, cfg.Journal)
 	} else {
 		connectorURL = fmt.Sprintf("file:%v?mode=memory", cfg.MemoryName)
 	}
@@ -215,7 +231,12 @@ func (l *Backend) showPragmas() error {
 		if err := row.Scan(&timeoutValue); err != nil {
 			return trace.Wrap(err)
 		}
-		l.Debugf("Synchronous: %v, busy timeout: %v", syncValue, timeoutValue)
+		var journalValue string
+		row = tx.QueryRowContext(l.ctx, "PRAGMA journal_mode;")
+		if err := row.Scan(&journalValue); err != nil {
+			return trace.Wrap(err)
+		}
+		l.Debugf("Synchronous: %v, busy timeout: %v, journal mode: %v", syncValue, timeoutValue, journalValue)
 		return nil
 	})
 }
@@ -771,6 +792,70 @@ func (l *Backend) DeleteRange(ctx context.Context, startKey, endKey []byte) erro
 	})
 }
 
+// Backup creates an online backup of the database at the destination path
+// using the SQLite backup API. It is safe to call on a live database: the
+// backup is taken in steps without holding the database lock for the whole
+// duration.
+func (l *Backend) Backup(ctx context.Context, destPath string) error {
+	if l.Memory {
+		return trace.BadParameter("backup is not supported for in-memory databases")
+	}
+	destDB, err := sql.Open(BackendName, fmt.Sprintf("file:%v?_busy_timeout=%v", destPath, l.BusyTimeout))
+	if err != nil {
+		return trace.Wrap(err)
+	}
+	defer destDB.Close()
+
+	srcConn, err := l.db.Conn(ctx)
+	if err != nil {
+		return trace.Wrap(err)
+	}
+	defer srcConn.Close()
+	destConn, err := destDB.Conn(ctx)
+	if err != nil {
+		return trace.Wrap(err)
+	}
+	defer destConn.Close()
+
+	start := time.Now()
+	err = destConn.Raw(func(destRaw interface{}) error {
+		return srcConn.Raw(func(srcRaw interface{}) error {
+			src, ok := srcRaw.(*sqlite3.SQLiteConn)
+			if !ok {
+				return trace.BadParameter("expected *sqlite3.SQLiteConn, got %T", srcRaw)
+			}
+			dest, ok := destRaw.(*sqlite3.SQLiteConn)
+			if !ok {
+				return trace.BadParameter("expected *sqlite3.SQLiteConn, got %T", destRaw)
+			}
+			backup, err := dest.Backup("main", src, "main")
+			if err != nil {
+				return trace.Wrap(err)
+			}
+			for {
+				done, err := backup.Step(backupStepPages)
+				if err != nil {
+					backup.Close()
+					return trace.Wrap(err)
+				}
+				if done {
+					break
+				}
+				if err := ctx.Err(); err != nil {
+					backup.Close()
+					return trace.Wrap(err)
+				}
+			}
+			return trace.Wrap(backup.Finish())
+		})
+	})
+	if err != nil {
+		return trace.Wrap(err)
+	}
+	l.Debugf("Backed up database to %v in %v.", destPath, time.Since(start))
+	return nil
+}
+
 // NewWatcher returns a new event watcher
 func (l *Backend) NewWatcher(ctx context.Context, watch backend.Watch) (backend.Watcher, error) {
 	if l.EventsOff {
@@ -19,6 +19,7 @@ package lite
 import (
 	"context"
 	"os"
+	"path/filepath"
 	"testing"
 	"time"
 
@@ -28,6 +29,7 @@ import (
 	"github.com/gravitational/teleport/lib/utils"
 
 	"github.com/jonboulle/clockwork"
+	"github.com/stretchr/testify/require"
 	"gopkg.in/check.v1"
 )
 
@@ -155,3 +157,47 @@ func (s *LiteSuite) TestImport(c *check.C) {
 	c.Assert(err, check.IsNil)
 	c.Assert(imported, check.Equals, true)
 }
+
+func TestBackup(t *testing.T) {
+	ctx := context.Background()
+	bk, err := NewWithConfig(ctx, Config{
+		Path:             t.TempDir(),
+		PollStreamPeriod: 300 * time.Millisecond,
+	})
+	require.NoError(t, err)
+	defer bk.Close()
+
+	item := backend.Item{Key: []byte("/backup/key"), Value: []byte("value")}
+	_, err = bk.Put(ctx, item)
+	require.NoError(t, err)
+
+	// Take an online backup while the source database remains open.
+	destDir := t.TempDir()
+	require.NoError(t, bk.Backup(ctx, filepath.Join(destDir, defaultDBFile)))
+
+	// The backup is a complete database that can be opened as a backend.
+	restored, err := NewWithConfig(ctx, Config{
+		Path:             destDir,
+		PollStreamPeriod: 300 * time.Millisecond,
+	})
+	require.NoError(t, err)
+	defer restored.Close()
+
+	out, err := restored.Get(ctx, item.Key)
+	require.NoError(t, err)
+	require.Equal(t, item.Value, out.Value)
+}
+
+func TestJournalModeWAL(t *testing.T) {
+	ctx := context.Background()
+	bk, err := NewWithConfig(ctx, Config{
+		Path:             t.TempDir(),
+		PollStreamPeriod: 300 * time.Millisecond,
+	})
+	require.NoError(t, err)
+	defer bk.Close()
+
+	var mode string
+	require.NoError(t, bk.db.QueryRowContext(ctx, "PRAGMA journal_mode;").Scan(&mode))
+	require.Equal(t, "wal", mode)
+}
@@ -116,6 +116,7 @@ func (s *Reporter) GetRange(ctx context.Context, startKey []byte, endKey []byte,
 	if err != nil {
 		batchReadRequestsFailed.WithLabelValues(s.Component).Inc()
 	}
+	s.reportSlowOperation("GetRange", startKey, start)
 	s.trackRequest(types.OpGet, startKey, endKey)
 	return res, err
 }
@@ -129,6 +130,7 @@ func (s *Reporter) Create(ctx context.Context, i Item) (*Lease, error) {
 	if err != nil {
 		writeRequestsFailed.WithLabelValues(s.Component).Inc()
 	}
+	s.reportSlowOperation("Create", i.Key, start)
 	s.trackRequest(types.OpPut, i.Key, nil)
 	return lease, err
 }
@@ -143,6 +145,7 @@ func (s *Reporter) Put(ctx context.Context, i Item) (*Lease, error) {
 	if err != nil {
 		writeRequestsFailed.WithLabelValues(s.Component).Inc()
 	}
+	s.reportSlowOperation("Put", i.Key, start)
 	s.trackRequest(types.OpPut, i.Key, nil)
 	return lease, err
 }
@@ -156,6 +159,7 @@ func (s *Reporter) Update(ctx context.Context, i Item) (*Lease, error) {
 	if err != nil {
 		writeRequestsFailed.WithLabelValues(s.Component).Inc()
 	}
+	s.reportSlowOperation("Update", i.Key, start)
 	s.trackRequest(types.OpPut, i.Key, nil)
 	return lease, err
 }
@@ -163,12 +167,13 @@ func (s *Reporter) Update(ctx context.Context, i Item) (*Lease, error) {
 // Get returns a single item or not found error
 func (s *Reporter) Get(ctx context.Context, key []byte) (*Item, error) {
 	start := s.Clock().Now()
+	item, err := s.Backend.Get(ctx, key)
 	readLatencies.WithLabelValues(s.Component).Observe(time.Since(start).Seconds())
 	readRequests.WithLabelValues(s.Component).Inc()
-	item, err := s.Backend.Get(ctx, key)
 	if err != nil && !trace.IsNotFound(err) {
 		readRequestsFailed.WithLabelValues(s.Component).Inc()
 	}
+	s.reportSlowOperation("Get", key, start)
 	s.trackRequest(types.OpGet, key, nil)
 	return item, err
 }
@@ -183,6 +188,7 @@ func (s *Reporter) CompareAndSwap(ctx context.Context, expected Item, replaceWit
 	if err != nil && !trace.IsNotFound(err) && !trace.IsCompareFailed(err) {
 		writeRequestsFailed.WithLabelValues(s.Component).Inc()
 	}
+	s.reportSlowOperation("CompareAndSwap", expected.Key, start)
 	s.trackRequest(types.OpPut, expected.Key, nil)
 	return lease, err
 }
@@ -196,6 +202,7 @@ func (s *Reporter) Delete(ctx context.Context, key []byte) error {
 	if err != nil && !trace.IsNotFound(err) {
 		writeRequestsFailed.WithLabelValues(s.Component).Inc()
 	}
+	s.reportSlowOperation("Delete", key, start)
 	s.trackRequest(types.OpDelete, key, nil)
 	return err
 }
@@ -209,6 +216,7 @@ func (s *Reporter) DeleteRange(ctx context.Context, startKey []byte, endKey []by
 	if err != nil && !trace.IsNotFound(err) {
 		batchWriteRequestsFailed.WithLabelValues(s.Component).Inc()
 	}
+	s.reportSlowOperation("DeleteRange", startKey, start)
 	s.trackRequest(types.OpDelete, startKey, endKey)
 	return err
 }
@@ -225,6 +233,7 @@ func (s *Reporter) KeepAlive(ctx context.Context, lease Lease, expires time.Time
 	if err != nil && !trace.IsNotFound(err) {
 		writeRequestsFailed.WithLabelValues(s.Component).Inc()
 	}
+	s.reportSlowOperation("KeepAlive", lease.Key, start)
 	s.trackRequest(types.OpPut, lease.Key, nil)
 	return err
 }
@@ -263,6 +272,26 @@ type topRequestsCacheKey struct {
 	isRange   string
 }
 
+// slowOperationThreshold is the duration after which a backend operation
+// is logged as slow.
+const slowOperationThreshold = time.Second
+
+// reportSlowOperation logs backend operations that took longer than the
+// slow operation threshold, and counts them in the slow requests metric.
+func (s *Reporter) reportSlowOperation(op string, key []byte, start time.Time) {
+	elapsed := s.Clock().Now().Sub(start)
+	if elapsed <= slowOperationThreshold {
+		return
+	}
+	slowRequests.WithLabelValues(s.Component).Inc()
+	log.WithFields(log.Fields{
+		"component": s.Component,
+		"op":        op,
+		"key":       string(key),
+		"duration":  elapsed,
+	}).Warn("Slow backend operation.")
+}
+
 // trackRequests tracks top requests, endKey is supplied for ranges
 func (s *Reporter) trackRequest(opType types.OpType, key []byte, endKey []byte) {
 	if len(key) == 0 {
@@ -466,10 +495,28 @@ var (
 		[]string{teleport.ComponentLabel},
 	)
 
+	slowRequests = prometheus.NewCounterVec(
+		prometheus.CounterOpts{
+			Name: "backend_slow_requests_total",
+			Help: "Number of backend operations that exceeded the slow operation threshold",
+		},
+		[]string{teleport.ComponentLabel},
+	)
+
 	prometheusCollectors = []prometheus.Collector{
 		watchers, watcherQueues, requests, writeRequests,
 		writeRequestsFailed, batchWriteRequests, batchWriteRequestsFailed, readRequests,
 		readRequestsFailed, batchReadRequests, batchReadRequestsFailed, writeLatencies,
-		batchWriteLatencies, batchReadLatencies, readLatencies,
+		batchWriteLatencies, batchReadLatencies, readLatencies, slowRequests,
 	}
 )
+
+// Backup delegates an online backup to the wrapped backend when it
+// supports one.
+func (s *Reporter) Backup(ctx context.Context, destPath string) error {
+	b, ok := s.Backend.(OnlineBackup)
+	if !ok {
+		return trace.NotImplemented("backend %T does not support online backups", s.Backend)
+	}
+	return b.Backup(ctx, destPath)
+}
@@ -166,3 +166,13 @@ func (s *Sanitizer) CloseWatchers() {
 
 // Migrate runs the necessary data migrations for this backend.
 func (s *Sanitizer) Migrate(ctx context.Context) error { return s.backend.Migrate(ctx) }
+
+// Backup delegates an online backup to the wrapped backend when it
+// supports one.
+func (s *Sanitizer) Backup(ctx context.Context, destPath string) error {
+	b, ok := s.backend.(OnlineBackup)
+	if !ok {
+		return trace.NotImplemented("backend %T does not support online backups", s.backend)
+	}
+	return b.Backup(ctx, destPath)
+}
@@ -43,6 +43,7 @@ import (
 
 	"golang.org/x/crypto/ssh"
 	"golang.org/x/crypto/ssh/agent"
+	"golang.org/x/net/websocket"
 	"golang.org/x/term"
 
 	"github.com/gravitational/teleport"
@@ -205,6 +206,11 @@ type Config struct {
 	// InsecureSkipVerify is an option to skip HTTPS cert check
 	InsecureSkipVerify bool
 
+	// WebsocketTransport tunnels the SSH connection to the proxy over a
+	// websocket on the HTTPS port, for clients behind restrictive
+	// firewalls that only allow HTTPS traffic.
+	WebsocketTransport bool
+
 	// SkipLocalAuth tells the client to use AuthMethods parameter for authentication and NOT
 	// use its own SSH agent or ask user for passwords. This is used by external programs linking
 	// against Teleport client and obtaining credentials from elsewhere.
@@ -263,6 +269,14 @@ type Config struct {
 	// that uses local cache to validate hosts
 	HostKeyCallback ssh.HostKeyCallback
 
+	// HostCheckMode selects between trust-on-first-use and strict host
+	// validation, TOFU if not set.
+	HostCheckMode string
+
+	// SecurityProfile selects a preset of SSH algorithms (default,
+	// strict or legacy) used for outbound SSH connections.
+	SecurityProfile string
+
 	// KeyDir defines where temporary session keys will be stored.
 	// if empty, they'll go to ~/.tsh
 	KeysDir string
@@ -1084,6 +1098,9 @@ func NewClient(c *Config) (tc *TeleportClient, err error) {
 		if err != nil {
 			return nil, trace.Wrap(err)
 		}
+		if err := tc.localAgent.SetHostCheckMode(c.HostCheckMode); err != nil {
+			return nil, trace.Wrap(err)
+		}
 		if tc.HostKeyCallback == nil {
 			tc.HostKeyCallback = tc.localAgent.CheckHostSignature
 		}
@@ -2033,14 +2050,23 @@ func (tc *TeleportClient) connectToProxy(ctx context.Context) (*ProxyClient, err
 		return nil, trace.BadParameter("no SSH auth methods loaded, are you logged in?")
 	}
 
+	clientCiphers, clientKEX, clientMACs, err := defaults.SecurityProfileAlgorithms(tc.SecurityProfile)
+	if err != nil {
+		return nil, trace.Wrap(err)
+	}
 	sshConfig := &ssh.ClientConfig{
+		Config: ssh.Config{
+			Ciphers:      clientCiphers,
+			KeyExchanges: clientKEX,
+			MACs:         clientMACs,
+		},
 		User:            tc.getProxySSHPrincipal(),
 		HostKeyCallback: hostKeyCallback,
 		Auth:            authMethods,
 	}
 	log.Infof("Connecting proxy=%v login=%q", sshProxyAddr, sshConfig.User)
 
-	sshClient, err := ssh.Dial("tcp", sshProxyAddr, sshConfig)
+	sshClient, err := tc.dialSSHProxy(sshProxyAddr, sshConfig)
 	if err != nil {
 		log.WithError(err).Warnf("Failed to authenticate with proxy %v.", sshProxyAddr)
 		return nil, trace.Wrap(err, "failed to authenticate with proxy %v", sshProxyAddr)
@@ -2060,6 +2086,40 @@ func (tc *TeleportClient) connectToProxy(ctx context.Context) (*ProxyClient, err
 	}, nil
 }
 
+// dialSSHProxy opens the SSH connection to the proxy, either directly or,
+// when the websocket transport is enabled, tunneled over a websocket on
+// the proxy HTTPS port.
+func (tc *TeleportClient) dialSSHProxy(sshProxyAddr string, sshConfig *ssh.ClientConfig) (*ssh.Client, error) {
+	if !tc.WebsocketTransport {
+		client, err := ssh.Dial("tcp", sshProxyAddr, sshConfig)
+		return client, trace.Wrap(err)
+	}
+
+	log.Debugf("Tunneling SSH connection over websocket via %v.", tc.WebProxyAddr)
+	wsConfig, err := websocket.NewConfig(
+		fmt.Sprintf("wss://%v/webapi/ssh/transport", tc.WebProxyAddr),
+		fmt.Sprintf("https://%v", tc.WebProxyAddr),
+	)
+	if err != nil {
+		return nil, trace.Wrap(err)
+	}
+	tlsConfig := utils.TLSConfig(nil)
+	tlsConfig.InsecureSkipVerify = tc.InsecureSkipVerify
+	wsConfig.TlsConfig = tlsConfig
+	conn, err := websocket.DialConfig(wsConfig)
+	if err != nil {
+		return nil, trace.Wrap(err)
+	}
+	conn.PayloadType = websocket.BinaryFrame
+
+	c, chans, reqs, err := ssh.NewClientConn(conn, sshProxyAddr, sshConfig)
+	if err != nil {
+		conn.Close()
+		return nil, trace.Wrap(err)
+	}
+	return ssh.NewClient(c, chans, reqs), nil
+}
+
 func (tc *TeleportClient) rootClusterName() (string, error) {
 	if tc.localAgent == nil {
 		return "", trace.NotFound("cannot load root cluster name without local agent")
@@ -2585,6 +2645,11 @@ func (tc *TeleportClient) localLogin(ctx context.Context, secondFactor constants
 		if err != nil {
 			return nil, trace.Wrap(err)
 		}
+	case constants.SecondFactorPasswordless:
+		response, err = tc.passwordlessLocalLogin(ctx, pub)
+		if err != nil {
+			return nil, trace.Wrap(err)
+		}
 	default:
 		return nil, trace.BadParameter("unsupported second factor type: %q", secondFactor)
 	}
@@ -2691,6 +2756,25 @@ func (tc *TeleportClient) ssoLogin(ctx context.Context, connectorID string, pub
 }
 
 // mfaLocalLogin asks for a password and performs the challenge-response authentication
+// passwordlessLocalLogin asks the user for a hardware key tap only, no
+// password is collected or sent.
+func (tc *TeleportClient) passwordlessLocalLogin(ctx context.Context, pub []byte) (*auth.SSHLoginResponse, error) {
+	response, err := SSHAgentMFALogin(ctx, SSHLoginMFA{
+		SSHLogin: SSHLogin{
+			ProxyAddr:         tc.WebProxyAddr,
+			PubKey:            pub,
+			TTL:               tc.KeyTTL,
+			Insecure:          tc.InsecureSkipVerify,
+			Pool:              loopbackPool(tc.WebProxyAddr),
+			Compatibility:     tc.CertificateFormat,
+			RouteToCluster:    tc.SiteName,
+			KubernetesCluster: tc.KubernetesCluster,
+		},
+		User: tc.Config.Username,
+	})
+	return response, trace.Wrap(err)
+}
+
 func (tc *TeleportClient) mfaLocalLogin(ctx context.Context, pub []byte) (*auth.SSHLoginResponse, error) {
 	password, err := tc.AskPassword()
 	if err != nil {
@@ -3105,3 +3189,55 @@ func playSession(sessionEvents []events.EventFields, stream []byte) error {
 		return trace.Wrap(err)
 	}
 }
+
+// certRenewalRetryInterval is how long the renewal loop waits after a
+// failed reissue attempt.
+const certRenewalRetryInterval = time.Minute
+
+// StartCertRenewal runs a background loop that re-issues the user
+// certificate before it expires and reloads it into the local agent, so
+// long-lived operations like port forwards survive the original TTL.
+// The loop stops when the context is canceled.
+func (tc *TeleportClient) StartCertRenewal(ctx context.Context) {
+	if tc.localAgent == nil {
+		return
+	}
+	go func() {
+		for {
+			key, err := tc.localAgent.GetKey(tc.SiteName, WithSSHCerts{})
+			if err != nil {
+				log.WithError(err).Debug("Certificate renewal: no key in the local agent.")
+				return
+			}
+			expires, err := key.CertValidBefore()
+			if err != nil {
+				log.WithError(err).Debug("Certificate renewal: failed to read certificate expiry.")
+				return
+			}
+			// Renew when 80% of the remaining validity has elapsed.
+			wait := time.Until(expires) * 4 / 5
+			if wait < 0 {
+				wait = 0
+			}
+			select {
+			case <-ctx.Done():
+				return
+			case <-time.After(wait):
+			}
+
+			err = tc.ReissueUserCerts(ctx, CertCacheKeep, ReissueParams{
+				RouteToCluster: tc.SiteName,
+			})
+			if err != nil {
+				log.WithError(err).Warning("Failed to renew the user certificate, retrying. Sessions may fail when the current certificate expires.")
+				select {
+				case <-ctx.Done():
+					return
+				case <-time.After(certRenewalRetryInterval):
+				}
+				continue
+			}
+			log.Info("Renewed the user certificate before expiry.")
+		}
+	}()
+}
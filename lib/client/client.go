@@ -1019,6 +1019,11 @@ func (proxy *ProxyClient) ConnectToNode(ctx context.Context, nodeAddress NodeAdd
 	// terminal sizes when the remote PTY size has changed.
 	go nc.handleGlobalRequests(ctx, reqs)
 
+	// Send periodic keep-alive requests so connections survive NAT and
+	// firewall idle timeouts across short network blips, and so dead
+	// connections are detected instead of hanging forever.
+	go nc.startKeepAlives(ctx, apidefaults.KeepAliveInterval, apidefaults.KeepAliveCountMax)
+
 	return nc, nil
 }
 
@@ -1091,9 +1096,61 @@ func (proxy *ProxyClient) PortForwardToNode(ctx context.Context, nodeAddress Nod
 	// terminal sizes when the remote PTY size has changed.
 	go nc.handleGlobalRequests(ctx, reqs)
 
+	// Send periodic keep-alive requests so connections survive NAT and
+	// firewall idle timeouts across short network blips, and so dead
+	// connections are detected instead of hanging forever.
+	go nc.startKeepAlives(ctx, apidefaults.KeepAliveInterval, apidefaults.KeepAliveCountMax)
+
 	return nc, nil
 }
 
+// startKeepAlives sends keep-alive requests to the server at the given
+// interval so connections survive NAT and firewall idle timeouts. After
+// maxCount consecutive unanswered requests the interruption is logged,
+// but the connection is left open: short blips are for TCP to recover
+// from, and tearing the session down here would kill sessions that
+// would otherwise survive.
+func (c *NodeClient) startKeepAlives(ctx context.Context, interval time.Duration, maxCount int) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	var missed int
+	for {
+		select {
+		case <-ticker.C:
+			// The request is sent in a goroutine with a reply deadline:
+			// on a connection that silently drops packets SendRequest
+			// would otherwise block until the kernel TCP timeout.
+			replied := make(chan error, 1)
+			go func() {
+				_, _, err := c.Client.SendRequest(teleport.KeepAliveReqType, true, nil)
+				replied <- err
+			}()
+			var err error
+			select {
+			case err = <-replied:
+			case <-time.After(interval):
+				err = trace.ConnectionProblem(nil, "timed out waiting for keep-alive reply")
+			case <-ctx.Done():
+				return
+			}
+			if err != nil {
+				missed++
+				if missed == maxCount {
+					log.Warnf("Missed %v keep-alive replies, connection to node may be interrupted.", missed)
+				}
+				continue
+			}
+			if missed >= maxCount {
+				log.Info("Connection to node recovered.")
+			}
+			missed = 0
+		case <-ctx.Done():
+			return
+		}
+	}
+}
+
 func (c *NodeClient) handleGlobalRequests(ctx context.Context, requestCh <-chan *ssh.Request) {
 	for {
 		select {
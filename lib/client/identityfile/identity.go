@@ -19,6 +19,7 @@ package identityfile
 
 import (
 	"context"
+	"encoding/pem"
 	"fmt"
 	"io/ioutil"
 	"os"
@@ -61,12 +62,16 @@ const (
 	// configuring a MongoDB database for mutual TLS authentication.
 	FormatMongo Format = "mongodb"
 
+	// FormatWindows produces the TLS certificate in DER format, which is
+	// what Windows tooling (certutil, schannel-based clients) expects.
+	FormatWindows Format = "windows"
+
 	// DefaultFormat is what Teleport uses by default
 	DefaultFormat = FormatFile
 )
 
 // KnownFormats is a list of all above formats.
-var KnownFormats = []Format{FormatFile, FormatOpenSSH, FormatTLS, FormatKubernetes, FormatDatabase, FormatMongo}
+var KnownFormats = []Format{FormatFile, FormatOpenSSH, FormatTLS, FormatKubernetes, FormatDatabase, FormatMongo, FormatWindows}
 
 // WriteConfig holds the necessary information to write an identity file.
 type WriteConfig struct {
@@ -200,6 +205,23 @@ func Write(cfg WriteConfig) (filesWritten []string, err error) {
 			return nil, trace.Wrap(err)
 		}
 
+	// FormatWindows writes the user's TLS certificate, converted from PEM
+	// to DER encoding, into a single .der file.
+	case FormatWindows:
+		certPath := cfg.OutputPath + ".der"
+		filesWritten = append(filesWritten, certPath)
+		if err := checkOverwrite(cfg.OverwriteDestination, filesWritten...); err != nil {
+			return nil, trace.Wrap(err)
+		}
+
+		block, _ := pem.Decode(cfg.Key.TLSCert)
+		if block == nil {
+			return nil, trace.BadParameter("failed to decode the user TLS certificate")
+		}
+		if err := ioutil.WriteFile(certPath, block.Bytes, identityfile.FilePermissions); err != nil {
+			return nil, trace.Wrap(err)
+		}
+
 	case FormatKubernetes:
 		filesWritten = append(filesWritten, cfg.OutputPath)
 		if err := checkOverwrite(cfg.OverwriteDestination, filesWritten...); err != nil {
@@ -1,6 +1,7 @@
 package identityfile
 
 import (
+	"crypto/x509"
 	"io/ioutil"
 	"path/filepath"
 	"testing"
@@ -8,6 +9,7 @@ import (
 	"github.com/gravitational/teleport/api/utils/keypaths"
 	"github.com/gravitational/teleport/lib/auth"
 	"github.com/gravitational/teleport/lib/client"
+	"github.com/gravitational/teleport/lib/fixtures"
 	"github.com/gravitational/teleport/lib/kube/kubeconfig"
 	"github.com/stretchr/testify/require"
 )
@@ -52,6 +54,20 @@ func TestWrite(t *testing.T) {
 	require.NoError(t, err)
 	require.Equal(t, string(out), "priv\ncert\ntls-cert\nca-cert\n")
 
+	// test DER certificate creation for Windows tooling:
+	key.TLSCert = []byte(fixtures.TLSCACertPEM)
+	cfg.OutputPath = filepath.Join(outputDir, "windows")
+	cfg.Format = FormatWindows
+	_, err = Write(cfg)
+	require.NoError(t, err)
+
+	// cert is a valid DER-encoded certificate:
+	out, err = ioutil.ReadFile(cfg.OutputPath + ".der")
+	require.NoError(t, err)
+	_, err = x509.ParseCertificate(out)
+	require.NoError(t, err)
+	key.TLSCert = []byte("tls-cert")
+
 	// Test kubeconfig creation.
 	cfg.OutputPath = filepath.Join(outputDir, "kubeconfig")
 	cfg.Format = FormatKubernetes
@@ -283,6 +283,27 @@ func (k *Key) AsAgentKeys() ([]agent.AddedKey, error) {
 	return sshutils.AsAgentKeys(cert, k.Priv)
 }
 
+// TLSCertificate returns the key's TLS certificate paired with its
+// private key, ready to present in a TLS handshake.
+func (k *Key) TLSCertificate() (tls.Certificate, error) {
+	cert, err := tls.X509KeyPair(k.TLSCert, k.Priv)
+	if err != nil {
+		return tls.Certificate{}, trace.Wrap(err)
+	}
+	return cert, nil
+}
+
+// TLSIdentity parses the Teleport identity encoded in the key's TLS
+// certificate.
+func (k *Key) TLSIdentity() (*tlsca.Identity, error) {
+	cert, err := k.TeleportTLSCertificate()
+	if err != nil {
+		return nil, trace.Wrap(err)
+	}
+	identity, err := tlsca.FromSubject(cert.Subject, cert.NotAfter)
+	return identity, trace.Wrap(err)
+}
+
 // TeleportTLSCertificate returns the parsed x509 certificate for
 // authentication against Teleport APIs.
 func (k *Key) TeleportTLSCertificate() (*x509.Certificate, error) {
@@ -39,6 +39,16 @@ import (
 	"github.com/sirupsen/logrus"
 )
 
+// Host validation modes for LocalKeyAgent.
+const (
+	// HostCheckModeTOFU prompts the user for unknown host keys and
+	// remembers accepted ones (trust on first use).
+	HostCheckModeTOFU = "tofu"
+	// HostCheckModeStrict rejects hosts that do not present a
+	// certificate with matching principals signed by a known CA.
+	HostCheckModeStrict = "strict"
+)
+
 // LocalKeyAgent holds Teleport certificates for a user connected to a cluster.
 type LocalKeyAgent struct {
 	// log holds the structured logger.
@@ -57,6 +67,10 @@ type LocalKeyAgent struct {
 	// manually (via keyboard input) refused connecting to.
 	noHosts map[string]bool
 
+	// hostCheckMode selects between trust-on-first-use and strict host
+	// validation.
+	hostCheckMode string
+
 	// function which asks a user to trust host/key combination (during host auth)
 	hostPromptFunc func(host string, k ssh.PublicKey) error
 
@@ -125,11 +139,12 @@ func NewLocalAgent(keystore LocalKeyStore, proxyHost, username string, keysOptio
 		log: logrus.WithFields(logrus.Fields{
 			trace.Component: teleport.ComponentKeyAgent,
 		}),
-		Agent:     agent.NewKeyring(),
-		keyStore:  keystore,
-		noHosts:   make(map[string]bool),
-		username:  username,
-		proxyHost: proxyHost,
+		Agent:         agent.NewKeyring(),
+		keyStore:      keystore,
+		noHosts:       make(map[string]bool),
+		username:      username,
+		proxyHost:     proxyHost,
+		hostCheckMode: HostCheckModeTOFU,
 	}
 
 	if shouldAddKeysToAgent(keysOption) {
@@ -146,6 +161,20 @@ the --add-keys-to-agent=yes flag.`)
 	return a, nil
 }
 
+// SetHostCheckMode switches between TOFU and strict host validation.
+func (a *LocalKeyAgent) SetHostCheckMode(mode string) error {
+	switch mode {
+	case "", HostCheckModeTOFU:
+		a.hostCheckMode = HostCheckModeTOFU
+	case HostCheckModeStrict:
+		a.hostCheckMode = HostCheckModeStrict
+	default:
+		return trace.BadParameter("unsupported host check mode %q, expected %q or %q",
+			mode, HostCheckModeStrict, HostCheckModeTOFU)
+	}
+	return nil
+}
+
 // UpdateProxyHost changes the proxy host that the local agent operates on.
 func (a *LocalKeyAgent) UpdateProxyHost(proxyHost string) {
 	a.proxyHost = proxyHost
@@ -314,6 +343,9 @@ func (a *LocalKeyAgent) UserRefusedHosts() bool {
 
 // CheckHostSignature checks if the given host key was signed by a Teleport
 // certificate authority (CA) or a host certificate the user has seen before.
+// In strict mode the host must present a certificate whose principals match
+// the dialed address, and unknown plain keys are rejected instead of
+// prompting.
 func (a *LocalKeyAgent) CheckHostSignature(addr string, remote net.Addr, key ssh.PublicKey) error {
 	certChecker := utils.CertChecker{
 		CertChecker: ssh.CertChecker{
@@ -322,6 +354,11 @@ func (a *LocalKeyAgent) CheckHostSignature(addr string, remote net.Addr, key ssh
 		},
 		FIPS: isFIPS(),
 	}
+	if a.hostCheckMode == HostCheckModeStrict {
+		if err := a.checkHostPrincipals(addr, key); err != nil {
+			return trace.Wrap(err)
+		}
+	}
 	err := certChecker.CheckHostKey(addr, remote, key)
 	if err != nil {
 		a.log.Debugf("Host validation failed: %v.", err)
@@ -331,6 +368,28 @@ func (a *LocalKeyAgent) CheckHostSignature(addr string, remote net.Addr, key ssh
 	return nil
 }
 
+// checkHostPrincipals enforces that the host presented a certificate
+// whose principals cover the dialed address.
+func (a *LocalKeyAgent) checkHostPrincipals(addr string, key ssh.PublicKey) error {
+	cert, ok := key.(*ssh.Certificate)
+	if !ok {
+		return trace.AccessDenied(
+			"strict host checking: %v presented a plain host key instead of a certificate", addr)
+	}
+	host := addr
+	if h, _, err := net.SplitHostPort(addr); err == nil {
+		host = h
+	}
+	for _, principal := range cert.ValidPrincipals {
+		if principal == host {
+			return nil
+		}
+	}
+	return trace.AccessDenied(
+		"strict host checking: host certificate of %v is valid for %v but not for the dialed host %q",
+		addr, strings.Join(cert.ValidPrincipals, ", "), host)
+}
+
 // checkHostCertificate validates a host certificate. First checks the
 // ~/.tsh/known_hosts cache and if not found, prompts the user to accept
 // or reject.
@@ -367,6 +426,13 @@ func (a *LocalKeyAgent) checkHostKey(addr string, remote net.Addr, key ssh.Publi
 		return nil
 	}
 
+	// In strict mode unknown keys are rejected outright, trust on first
+	// use is disabled.
+	if a.hostCheckMode == HostCheckModeStrict {
+		return trace.AccessDenied(
+			"strict host checking: %v presented an unknown host key and trust-on-first-use is disabled", addr)
+	}
+
 	// If this key was not seen before, prompt the user with a fingerprint.
 	if a.hostPromptFunc != nil {
 		err = a.hostPromptFunc(addr, key)
@@ -41,14 +41,19 @@ import (
 	"github.com/gravitational/teleport/api/types"
 	apiutils "github.com/gravitational/teleport/api/utils"
 	"github.com/gravitational/teleport/lib"
+	"github.com/gravitational/teleport/lib/auth/native"
 	"github.com/gravitational/teleport/lib/backend"
 	"github.com/gravitational/teleport/lib/backend/lite"
 	"github.com/gravitational/teleport/lib/client"
 	"github.com/gravitational/teleport/lib/defaults"
+	"github.com/gravitational/teleport/lib/events"
 	"github.com/gravitational/teleport/lib/limiter"
+	"github.com/gravitational/teleport/lib/pagerduty"
 	"github.com/gravitational/teleport/lib/pam"
 	"github.com/gravitational/teleport/lib/service"
 	"github.com/gravitational/teleport/lib/services"
+	"github.com/gravitational/teleport/lib/srv"
+	"github.com/gravitational/teleport/lib/tickets"
 	"github.com/gravitational/teleport/lib/utils"
 
 	log "github.com/sirupsen/logrus"
@@ -58,6 +63,9 @@ import (
 // CommandLineFlags stores command line flag values, it's a much simplified subset
 // of Teleport configuration (which is fully expressed via YAML config file)
 type CommandLineFlags struct {
+	// LowMemory trims caches, buffers and key precomputation for
+	// resource-constrained agents.
+	LowMemory bool
 	// --name flag
 	NodeName string
 	// --auth-server flag
@@ -274,6 +282,12 @@ func ApplyFileConfig(fc *FileConfig, cfg *service.Config) error {
 		return trace.Wrap(err)
 	}
 	cfg.Log = logger
+	cfg.LogOutput = fc.Logger.Output
+
+	if fc.TunnelConnections < 0 {
+		return trace.BadParameter("tunnel_connections must not be negative")
+	}
+	cfg.TunnelConnectionCount = fc.TunnelConnections
 
 	// Apply logging configuration for the global logger instance
 	// DELETE this when global logger instance is no longer in use.
@@ -297,6 +311,14 @@ func ApplyFileConfig(fc *FileConfig, cfg *service.Config) error {
 		}
 		cfg.CipherSuites = cipherSuites
 	}
+	profileCiphers, profileKEX, profileMACs, err := defaults.SecurityProfileAlgorithms(fc.SecurityProfile)
+	if err != nil {
+		return trace.Wrap(err)
+	}
+	cfg.Ciphers = profileCiphers
+	cfg.KEXAlgorithms = profileKEX
+	cfg.MACAlgorithms = profileMACs
+	// Explicit algorithm lists override the profile preset.
 	if fc.Ciphers != nil {
 		cfg.Ciphers = fc.Ciphers
 	}
@@ -306,6 +328,7 @@ func ApplyFileConfig(fc *FileConfig, cfg *service.Config) error {
 	if fc.MACAlgorithms != nil {
 		cfg.MACAlgorithms = fc.MACAlgorithms
 	}
+	defaults.WarnOnWeakAlgorithms(cfg.Ciphers, cfg.KEXAlgorithms, cfg.MACAlgorithms)
 	if fc.CASignatureAlgorithm != nil {
 		cfg.CASignatureAlgorithm = fc.CASignatureAlgorithm
 	}
@@ -386,6 +409,19 @@ func ApplyFileConfig(fc *FileConfig, cfg *service.Config) error {
 	return nil
 }
 
+// readBannerOption resolves an inline banner value or a banner file,
+// the file taking precedence.
+func readBannerOption(inline, file string) (string, error) {
+	if file == "" {
+		return inline, nil
+	}
+	data, err := ioutil.ReadFile(file)
+	if err != nil {
+		return "", trace.Wrap(err, "failed to read banner file")
+	}
+	return string(data), nil
+}
+
 func applyLogConfig(loggerConfig Log, logger *log.Logger) error {
 	switch loggerConfig.Output {
 	case "":
@@ -422,6 +458,24 @@ func applyLogConfig(loggerConfig Log, logger *log.Logger) error {
 		return trace.BadParameter("unsupported logger severity: %q", loggerConfig.Severity)
 	}
 
+	componentLevels, err := parseComponentLevels(loggerConfig.ComponentLevels)
+	if err != nil {
+		return trace.Wrap(err)
+	}
+	// baseLevel is the configured severity for components without an
+	// override, captured before the global level is raised below.
+	baseLevel := logger.GetLevel()
+	if len(componentLevels) != 0 {
+		// The global level has to be at least as verbose as the most
+		// verbose component, entries above their component's level are
+		// filtered out by the formatter below.
+		for _, level := range componentLevels {
+			if level > logger.GetLevel() {
+				logger.SetLevel(level)
+			}
+		}
+	}
+
 	switch strings.ToLower(loggerConfig.Format.Output) {
 	case "":
 		fallthrough // not set. defaults to 'text'
@@ -435,7 +489,7 @@ func applyLogConfig(loggerConfig Log, logger *log.Logger) error {
 			return trace.Wrap(err)
 		}
 
-		logger.SetFormatter(formatter)
+		logger.SetFormatter(newComponentLevelFormatter(formatter, componentLevels, baseLevel))
 	case "json":
 		formatter := &jsonFormatter{
 			extraFields: loggerConfig.Format.ExtraFields,
@@ -445,7 +499,7 @@ func applyLogConfig(loggerConfig Log, logger *log.Logger) error {
 			return trace.Wrap(err)
 		}
 
-		logger.SetFormatter(formatter)
+		logger.SetFormatter(newComponentLevelFormatter(formatter, componentLevels, baseLevel))
 	default:
 		return trace.BadParameter("unsupported log output format : %q", loggerConfig.Format.Output)
 	}
@@ -466,6 +520,111 @@ func applyAuthConfig(fc *FileConfig, cfg *service.Config) error {
 	if err != nil {
 		return trace.Wrap(err)
 	}
+	if fc.Auth.LoginLockout != nil {
+		if fc.Auth.LoginLockout.MaxAttempts < 0 {
+			return trace.BadParameter("login_lockout.max_attempts must not be negative")
+		}
+		if fc.Auth.LoginLockout.LockDuration.Value() < 0 {
+			return trace.BadParameter("login_lockout.lock_duration must not be negative")
+		}
+		cfg.Auth.MaxLoginAttempts = fc.Auth.LoginLockout.MaxAttempts
+		cfg.Auth.AccountLockInterval = fc.Auth.LoginLockout.LockDuration.Value()
+	}
+	if fc.Auth.PasswordPolicy != nil {
+		if fc.Auth.PasswordPolicy.MinLength < 0 {
+			return trace.BadParameter("password_policy.min_length must not be negative")
+		}
+		cfg.Auth.PasswordPolicy = services.PasswordPolicy{
+			MinLength:        fc.Auth.PasswordPolicy.MinLength,
+			RequireUppercase: fc.Auth.PasswordPolicy.RequireUppercase,
+			RequireDigits:    fc.Auth.PasswordPolicy.RequireDigits,
+			RequireSymbols:   fc.Auth.PasswordPolicy.RequireSymbols,
+		}
+	}
+	for _, hook := range fc.Auth.Webhooks {
+		webhook := events.WebhookConfig{
+			URL:    hook.URL,
+			Events: hook.Events,
+			Secret: hook.Secret,
+		}
+		if err := webhook.CheckAndSetDefaults(); err != nil {
+			return trace.Wrap(err)
+		}
+		cfg.Auth.Webhooks = append(cfg.Auth.Webhooks, webhook)
+	}
+	for _, notification := range fc.Auth.AccessRequestNotifications {
+		target := events.AccessNotifyTarget{
+			Type:        notification.Type,
+			WebhookURL:  notification.WebhookURL,
+			RequestsURL: notification.RequestsURL,
+		}
+		if err := target.CheckAndSetDefaults(); err != nil {
+			return trace.Wrap(err)
+		}
+		cfg.Auth.AccessRequestNotifications = append(cfg.Auth.AccessRequestNotifications, target)
+	}
+	if fc.Auth.PagerDuty != nil {
+		cfg.Auth.PagerDuty = pagerduty.Config{
+			APIEndpoint: fc.Auth.PagerDuty.APIEndpoint,
+			APIKey:      fc.Auth.PagerDuty.APIKey,
+			ScheduleMap: fc.Auth.PagerDuty.ScheduleMap,
+		}
+		if err := cfg.Auth.PagerDuty.CheckAndSetDefaults(); err != nil {
+			return trace.Wrap(err)
+		}
+	}
+	if fc.Auth.Ticketing != nil {
+		cfg.Auth.Ticketing = tickets.Config{
+			System:   fc.Auth.Ticketing.System,
+			URL:      fc.Auth.Ticketing.URL,
+			Username: fc.Auth.Ticketing.Username,
+			APIToken: fc.Auth.Ticketing.APIToken,
+			Project:  fc.Auth.Ticketing.Project,
+		}
+		if err := cfg.Auth.Ticketing.CheckAndSetDefaults(); err != nil {
+			return trace.Wrap(err)
+		}
+	}
+	if fc.Auth.AccessRequests != nil {
+		for name, value := range map[string]types.Duration{
+			"max_duration":           fc.Auth.AccessRequests.MaxDuration,
+			"unassumed_approval_ttl": fc.Auth.AccessRequests.UnassumedApprovalTTL,
+			"retention_period":       fc.Auth.AccessRequests.RetentionPeriod,
+		} {
+			if value.Value() < 0 {
+				return trace.BadParameter("access_requests.%v must not be negative", name)
+			}
+		}
+		cfg.Auth.AccessRequestMaxDuration = fc.Auth.AccessRequests.MaxDuration.Value()
+		cfg.Auth.AccessRequestUnassumedTTL = fc.Auth.AccessRequests.UnassumedApprovalTTL.Value()
+		cfg.Auth.AccessRequestRetention = fc.Auth.AccessRequests.RetentionPeriod.Value()
+	}
+	if fc.Auth.VersionSkewLimit < 0 {
+		return trace.BadParameter("version_skew_limit must not be negative")
+	}
+	cfg.Auth.VersionSkewLimit = fc.Auth.VersionSkewLimit
+	if fc.Auth.MaxWebSessions < 0 {
+		return trace.BadParameter("max_web_sessions must not be negative")
+	}
+	cfg.Auth.MaxWebSessions = fc.Auth.MaxWebSessions
+	if err := native.ValidateAlgorithmSuite(fc.Auth.SignatureAlgorithmSuite); err != nil {
+		return trace.Wrap(err)
+	}
+	cfg.SignatureAlgorithmSuite = fc.Auth.SignatureAlgorithmSuite
+	if fc.Auth.SessionRecordingRetention != nil {
+		if fc.Auth.SessionRecordingRetention.Period.Value() <= 0 {
+			return trace.BadParameter("session_recording_retention.period must be positive")
+		}
+		cfg.Auth.RecordingRetention = fc.Auth.SessionRecordingRetention.Period.Value()
+		cfg.Auth.RecordingRetentionDryRun = fc.Auth.SessionRecordingRetention.DryRun
+	}
+	if fc.Auth.UsageReporting != nil && fc.Auth.UsageReporting.Enabled {
+		u, err := url.Parse(fc.Auth.UsageReporting.Endpoint)
+		if err != nil || (u.Scheme != "http" && u.Scheme != "https") {
+			return trace.BadParameter("usage_reporting.endpoint %q must be an http(s) URL", fc.Auth.UsageReporting.Endpoint)
+		}
+		cfg.Auth.UsageReportingEndpoint = fc.Auth.UsageReporting.Endpoint
+	}
 	if fc.Auth.ListenAddress != "" {
 		addr, err := utils.ParseHostPortAddr(fc.Auth.ListenAddress, int(defaults.AuthListenPort))
 		if err != nil {
@@ -568,6 +727,7 @@ func applyAuthConfig(fc *FileConfig, cfg *service.Config) error {
 
 // applyProxyConfig applies file configuration for the "proxy_service" section.
 func applyProxyConfig(fc *FileConfig, cfg *service.Config) error {
+	cfg.Proxy.SecurityHeaders = fc.Proxy.SecurityHeaders
 	var err error
 
 	cfg.Proxy.EnableProxyProtocol, err = utils.ParseOnOff("proxy_protocol", fc.Proxy.ProxyProtocol, true)
@@ -760,6 +920,60 @@ func applyProxyConfig(fc *FileConfig, cfg *service.Config) error {
 	return nil
 }
 
+// parseComponentLevels parses the per-component severity overrides.
+func parseComponentLevels(components map[string]string) (map[string]log.Level, error) {
+	if len(components) == 0 {
+		return nil, nil
+	}
+	levels := make(map[string]log.Level, len(components))
+	for component, severity := range components {
+		level, err := log.ParseLevel(severity)
+		if err != nil {
+			return nil, trace.BadParameter("unsupported severity %q for component %q", severity, component)
+		}
+		levels[component] = level
+	}
+	return levels, nil
+}
+
+// componentLevelFormatter filters log entries whose component is
+// configured with a less verbose level than the entry. It wraps the
+// actual output formatter since logrus applies a single level globally.
+type componentLevelFormatter struct {
+	inner        log.Formatter
+	levels       map[string]log.Level
+	defaultLevel log.Level
+}
+
+// newComponentLevelFormatter returns the inner formatter unchanged when no
+// component overrides are configured.
+func newComponentLevelFormatter(inner log.Formatter, levels map[string]log.Level, defaultLevel log.Level) log.Formatter {
+	if len(levels) == 0 {
+		return inner
+	}
+	return &componentLevelFormatter{inner: inner, levels: levels, defaultLevel: defaultLevel}
+}
+
+// Format implements logrus.Formatter.
+func (f *componentLevelFormatter) Format(entry *log.Entry) ([]byte, error) {
+	level := f.defaultLevel
+	if component, ok := entry.Data[trace.Component].(string); ok {
+		// Components are hierarchical, e.g. "proxy:agent" matches the
+		// "proxy" override.
+		for name, override := range f.levels {
+			if component == name || strings.HasPrefix(component, name+":") {
+				level = override
+				break
+			}
+		}
+	}
+	if entry.Level > level {
+		// Suppressed: returning no bytes emits nothing.
+		return nil, nil
+	}
+	return f.inner.Format(entry)
+}
+
 // applySSHConfig applies file configuration for the "ssh_service" section.
 func applySSHConfig(fc *FileConfig, cfg *service.Config) (err error) {
 	if fc.SSH.ListenAddress != "" {
@@ -788,6 +1002,25 @@ func applySSHConfig(fc *FileConfig, cfg *service.Config) (err error) {
 	if fc.SSH.Namespace != "" {
 		cfg.SSH.Namespace = fc.SSH.Namespace
 	}
+	cfg.SSH.ImportCloudTags = fc.SSH.ImportCloudTags
+	// Compile once to surface pattern errors at config load.
+	if _, err := srv.NewCommandPolicy(fc.SSH.AllowedCommands, fc.SSH.DeniedCommands); err != nil {
+		return trace.Wrap(err)
+	}
+	cfg.SSH.AllowedCommands = fc.SSH.AllowedCommands
+	cfg.SSH.DeniedCommands = fc.SSH.DeniedCommands
+	if _, err := srv.NewEnvPolicy(fc.SSH.AcceptEnv); err != nil {
+		return trace.Wrap(err)
+	}
+	cfg.SSH.AcceptEnv = fc.SSH.AcceptEnv
+	cfg.SSH.Banner, err = readBannerOption(fc.SSH.Banner, fc.SSH.BannerFile)
+	if err != nil {
+		return trace.Wrap(err)
+	}
+	cfg.SSH.MOTD, err = readBannerOption(fc.SSH.MOTD, fc.SSH.MOTDFile)
+	if err != nil {
+		return trace.Wrap(err)
+	}
 	if fc.SSH.PermitUserEnvironment {
 		cfg.SSH.PermitUserEnvironment = true
 	}
@@ -865,6 +1098,7 @@ func applyKubeConfig(fc *FileConfig, cfg *service.Config) error {
 	if fc.Kube.KubeClusterName != "" {
 		cfg.Kube.KubeClusterName = fc.Kube.KubeClusterName
 	}
+	cfg.Kube.DiscoverEKSRegions = fc.Kube.DiscoverEKSRegions
 	if fc.Kube.StaticLabels != nil {
 		cfg.Kube.StaticLabels = make(map[string]string)
 		for k, v := range fc.Kube.StaticLabels {
@@ -893,6 +1127,8 @@ func applyKubeConfig(fc *FileConfig, cfg *service.Config) error {
 // applyDatabasesConfig applies file configuration for the "db_service" section.
 func applyDatabasesConfig(fc *FileConfig, cfg *service.Config) error {
 	cfg.Databases.Enabled = true
+	cfg.Databases.DiscoverRDSRegions = fc.Databases.DiscoverRDSRegions
+	cfg.Databases.DiscoverRedshiftRegions = fc.Databases.DiscoverRedshiftRegions
 	for _, database := range fc.Databases.Databases {
 		staticLabels := make(map[string]string)
 		if database.StaticLabels != nil {
@@ -1231,6 +1467,12 @@ func Configure(clf *CommandLineFlags, cfg *service.Config) error {
 		cfg.Auth.Resources = resources
 	}
 
+	// Apply the --low-memory flag: trim every optional memory consumer.
+	if clf.LowMemory {
+		cfg.LowMemory = true
+		cfg.CachePolicy.Enabled = false
+	}
+
 	// Apply command line --debug flag to override logger severity.
 	if clf.Debug {
 		// If debug logging is requested and no file configuration exists, set the
@@ -25,6 +25,8 @@ import (
 	"net"
 	"net/url"
 	"os"
+	"path/filepath"
+	"regexp"
 	"strings"
 	"time"
 
@@ -61,6 +63,15 @@ var validCASigAlgos = []string{
 //
 // Use config.ReadFromFile() to read the parsed FileConfig from a YAML file.
 type FileConfig struct {
+	// Version is the config file format version, "v1" if not set. Version
+	// "v2" enables includes and environment variable interpolation.
+	Version string `yaml:"version,omitempty"`
+	// Include is a list of configuration files to merge into this one,
+	// paths are relative to the including file. Top-level sections from
+	// included files are merged in order, with the including file taking
+	// precedence. Only supported with version "v2".
+	Include []string `yaml:"include,omitempty"`
+
 	Global `yaml:"teleport,omitempty"`
 	Auth   Auth  `yaml:"auth_service,omitempty"`
 	SSH    SSH   `yaml:"ssh_service,omitempty"`
@@ -79,12 +90,16 @@ type FileConfig struct {
 // ReadFromFile reads Teleport configuration from a file. Currently only YAML
 // format is supported
 func ReadFromFile(filePath string) (*FileConfig, error) {
-	f, err := os.Open(filePath)
+	data, err := ioutil.ReadFile(filePath)
 	if err != nil {
 		return nil, trace.Wrap(err, fmt.Sprintf("failed to open file: %v", filePath))
 	}
-	defer f.Close()
-	return ReadConfig(f)
+	if configVersion(data) == configV2 {
+		if data, err = processIncludes(data, filepath.Dir(filePath)); err != nil {
+			return nil, trace.Wrap(err)
+		}
+	}
+	return ReadConfig(bytes.NewReader(data))
 }
 
 // ReadFromString reads values from base64 encoded byte string
@@ -104,18 +119,124 @@ func ReadConfig(reader io.Reader) (*FileConfig, error) {
 	if err != nil {
 		return nil, trace.Wrap(err, "failed reading Teleport configuration")
 	}
+	if configVersion(bytes) == configV2 {
+		bytes = expandEnvVars(bytes)
+	}
 	var fc FileConfig
 
 	if err := yaml.UnmarshalStrict(bytes, &fc); err != nil {
 		// Remove all newlines in the YAML error, to avoid escaping when printing.
 		return nil, trace.BadParameter("failed parsing the config file: %s", strings.Replace(err.Error(), "\n", "", -1))
 	}
+	// Includes are resolved by ReadFromFile relative to the including file
+	// and removed before this parse; if any are still present, the config
+	// did not come from a file and they cannot be resolved.
+	if len(fc.Include) != 0 && fc.Version == configV2 {
+		return nil, trace.BadParameter("includes are only supported when reading the configuration from a file")
+	}
 	if err := fc.CheckAndSetDefaults(); err != nil {
 		return nil, trace.BadParameter("failed to parse Teleport configuration: %v", err)
 	}
 	return &fc, nil
 }
 
+const (
+	// configV1 is the original config file format
+	configV1 = "v1"
+	// configV2 is the config file format that supports includes and
+	// environment variable interpolation
+	configV2 = "v2"
+)
+
+// versionOnly is used to peek at the config file version before the full
+// strict parse
+type versionOnly struct {
+	Version string `yaml:"version"`
+}
+
+// configVersion returns the version of the config file data, "v1" if the
+// version field is not set or the data does not parse
+func configVersion(data []byte) string {
+	var v versionOnly
+	if err := yaml.Unmarshal(data, &v); err != nil || v.Version == "" {
+		return configV1
+	}
+	return v.Version
+}
+
+// envVarPattern matches ${VAR} style references
+var envVarPattern = regexp.MustCompile(`\$\{([A-Za-z_][A-Za-z0-9_]*)\}`)
+
+// expandEnvVars replaces ${VAR} references with the value of the
+// corresponding environment variable. References to unset variables are
+// left as-is.
+func expandEnvVars(data []byte) []byte {
+	return envVarPattern.ReplaceAllFunc(data, func(match []byte) []byte {
+		name := string(envVarPattern.FindSubmatch(match)[1])
+		if value, ok := os.LookupEnv(name); ok {
+			return []byte(value)
+		}
+		return match
+	})
+}
+
+// processIncludes merges files listed in the include section into the
+// config data. Top-level sections from included files are merged in order,
+// with the including file taking precedence. Included files may not
+// themselves use includes.
+func processIncludes(data []byte, baseDir string) ([]byte, error) {
+	data = expandEnvVars(data)
+
+	var peek struct {
+		Include []string `yaml:"include"`
+	}
+	if err := yaml.Unmarshal(data, &peek); err != nil {
+		// Leave reporting the parse error to the strict parse.
+		return data, nil
+	}
+	if len(peek.Include) == 0 {
+		return data, nil
+	}
+
+	merged := map[string]interface{}{}
+	for _, include := range peek.Include {
+		includePath := include
+		if !filepath.IsAbs(includePath) {
+			includePath = filepath.Join(baseDir, includePath)
+		}
+		includeData, err := ioutil.ReadFile(includePath)
+		if err != nil {
+			return nil, trace.Wrap(err, fmt.Sprintf("failed to read included file: %v", includePath))
+		}
+		includeData = expandEnvVars(includeData)
+		var includeSections map[string]interface{}
+		if err := yaml.Unmarshal(includeData, &includeSections); err != nil {
+			return nil, trace.BadParameter("failed parsing included file %v: %v", includePath, err)
+		}
+		if _, ok := includeSections["include"]; ok {
+			return nil, trace.BadParameter("nested includes are not supported: %v", includePath)
+		}
+		for section, value := range includeSections {
+			merged[section] = value
+		}
+	}
+
+	var mainSections map[string]interface{}
+	if err := yaml.Unmarshal(data, &mainSections); err != nil {
+		return data, nil
+	}
+	delete(mainSections, "include")
+	for section, value := range mainSections {
+		merged[section] = value
+	}
+
+	out, err := yaml.Marshal(merged)
+	if err != nil {
+		return nil, trace.Wrap(err)
+	}
+	return out, nil
+}
+
 // SampleFlags specifies standalone configuration parameters
 type SampleFlags struct {
 	// ClusterName is an optional cluster name
@@ -126,6 +247,51 @@ type SampleFlags struct {
 	ACMEEmail string
 	// ACMEEnabled turns on ACME
 	ACMEEnabled bool
+	// Roles is a comma-separated list of services to enable: "auth",
+	// "proxy" and "node", all are enabled if empty
+	Roles string
+	// AuthServer is the address of the auth server an agent config
+	// should point at, enables the agent deployment pattern: only the
+	// listed roles run and auth/proxy are disabled unless requested
+	AuthServer string
+	// AuthToken is the join token for the agent deployment pattern
+	AuthToken string
+	// NodeLabels is a comma-separated list of key=value labels for the
+	// SSH service
+	NodeLabels string
+	// DataDir is an optional custom data directory
+	DataDir string
+	// PublicAddr is an optional public address of the proxy
+	PublicAddr string
+	// FIPS generates a FedRAMP/FIPS 140-2 compliant config, for use with
+	// Teleport binaries built with FIPS support
+	FIPS bool
+}
+
+// parseRoles validates and normalizes the --roles flag
+func (flags SampleFlags) parseRoles() (auth, proxy, node bool, err error) {
+	if flags.Roles == "" {
+		// Generate an all-in-one config by default, unless the config
+		// points at an external auth server.
+		if flags.AuthServer != "" {
+			return false, false, true, nil
+		}
+		return true, true, true, nil
+	}
+	for _, role := range strings.Split(flags.Roles, ",") {
+		switch strings.TrimSpace(role) {
+		case defaults.RoleAuthService:
+			auth = true
+		case defaults.RoleProxy:
+			proxy = true
+		case defaults.RoleNode:
+			node = true
+		default:
+			return false, false, false, trace.BadParameter("unknown role %q, valid roles are %q, %q and %q",
+				role, defaults.RoleAuthService, defaults.RoleProxy, defaults.RoleNode)
+		}
+	}
+	return auth, proxy, node, nil
 }
 
 // MakeSampleFileConfig returns a sample config to start
@@ -134,6 +300,13 @@ func MakeSampleFileConfig(flags SampleFlags) (fc *FileConfig, err error) {
 	if flags.ACMEEnabled && flags.ClusterName == "" {
 		return nil, trace.BadParameter("please provide --cluster-name when using acme, for example --cluster-name=example.com")
 	}
+	enableAuth, enableProxy, enableNode, err := flags.parseRoles()
+	if err != nil {
+		return nil, trace.Wrap(err)
+	}
+	if flags.AuthServer != "" && enableAuth {
+		return nil, trace.BadParameter("--auth-server is used for agent configs and cannot be combined with the auth role")
+	}
 
 	conf := service.MakeDefaultConfig()
 
@@ -143,10 +316,19 @@ func MakeSampleFileConfig(flags SampleFlags) (fc *FileConfig, err error) {
 	g.Logger.Severity = "INFO"
 	g.Logger.Format.Output = "text"
 	g.DataDir = defaults.DataDir
+	if flags.DataDir != "" {
+		g.DataDir = flags.DataDir
+	}
+	if flags.AuthServer != "" {
+		g.AuthServers = []string{flags.AuthServer}
+	}
+	if flags.AuthToken != "" {
+		g.AuthToken = flags.AuthToken
+	}
 
 	// SSH config:
 	var s SSH
-	s.EnabledFlag = "yes"
+	s.EnabledFlag = enabledFlag(enableNode)
 	s.ListenAddress = conf.SSH.Addr.Addr
 	s.Commands = []CommandLabel{
 		{
@@ -158,12 +340,33 @@ func MakeSampleFileConfig(flags SampleFlags) (fc *FileConfig, err error) {
 	s.Labels = map[string]string{
 		"env": "example",
 	}
+	if flags.NodeLabels != "" {
+		labels, _, err := parseLabels(flags.NodeLabels)
+		if err != nil {
+			return nil, trace.Wrap(err)
+		}
+		s.Labels = labels
+	}
 
 	// Auth config:
 	var a Auth
 	a.ListenAddress = conf.Auth.SSHAddr.Addr
 	a.ClusterName = ClusterName(flags.ClusterName)
-	a.EnabledFlag = "yes"
+	a.EnabledFlag = enabledFlag(enableAuth)
+	if flags.FIPS {
+		// Only SSO based authentication is supported in FIPS mode, and the
+		// allowed cryptographic primitives are pinned explicitly so a later
+		// config edit cannot silently drift out of compliance.
+		a.Authentication = &AuthenticationConfig{
+			Type:         "saml",
+			SecondFactor: constants.SecondFactorOff,
+			LocalAuth:    types.NewBoolOption(false),
+		}
+		g.CipherSuites = defaults.FIPSCipherSuiteNames
+		g.Ciphers = defaults.FIPSCiphers
+		g.KEXAlgorithms = defaults.FIPSKEXAlgorithms
+		g.MACAlgorithms = defaults.FIPSMACAlgorithms
+	}
 
 	if flags.LicensePath != "" {
 		a.LicenseFile = flags.LicensePath
@@ -171,8 +374,11 @@ func MakeSampleFileConfig(flags SampleFlags) (fc *FileConfig, err error) {
 
 	// sample proxy config:
 	var p Proxy
-	p.EnabledFlag = "yes"
+	p.EnabledFlag = enabledFlag(enableProxy)
 	p.ListenAddress = conf.Proxy.SSHAddr.Addr
+	if flags.PublicAddr != "" {
+		p.PublicAddr = apiutils.Strings{flags.PublicAddr}
+	}
 	if flags.ACMEEnabled {
 		p.ACME.EnabledFlag = "yes"
 		p.ACME.Email = flags.ACMEEmail
@@ -191,6 +397,14 @@ func MakeSampleFileConfig(flags SampleFlags) (fc *FileConfig, err error) {
 	return fc, nil
 }
 
+// enabledFlag returns the yes/no value for the enabled config field
+func enabledFlag(enabled bool) string {
+	if enabled {
+		return "yes"
+	}
+	return "no"
+}
+
 // DebugDumpToYAML allows for quick YAML dumping of the config
 func (conf *FileConfig) DebugDumpToYAML() string {
 	bytes, err := yaml.Marshal(&conf)
@@ -204,6 +418,15 @@ func (conf *FileConfig) DebugDumpToYAML() string {
 // algorithms, and mac algorithms set are supported by golang.org/x/crypto/ssh.
 // This ensures we don't start Teleport with invalid configuration.
 func (conf *FileConfig) CheckAndSetDefaults() error {
+	switch conf.Version {
+	case "", configV1:
+		if len(conf.Include) != 0 {
+			return trace.BadParameter("includes require config version %q", configV2)
+		}
+	case configV2:
+	default:
+		return trace.BadParameter("unsupported config version %q, supported versions are %q and %q", conf.Version, configV1, configV2)
+	}
 	conf.Auth.defaultEnabled = true
 	conf.Proxy.defaultEnabled = true
 	conf.SSH.defaultEnabled = true
@@ -269,6 +492,10 @@ type Log struct {
 	Output string `yaml:"output,omitempty"`
 	// Severity defines how verbose the log will be. Possible values are "error", "info", "warn"
 	Severity string `yaml:"severity,omitempty"`
+	// ComponentLevels overrides the severity per component, e.g. turn on
+	// debug logging only for the proxy: {"proxy": "debug"}. Components
+	// are the bracketed names in log output.
+	ComponentLevels map[string]string `yaml:"component_levels,omitempty"`
 	// Format defines the logs output format and extra fields
 	Format LogFormat `yaml:"format,omitempty"`
 }
@@ -321,10 +548,20 @@ type Global struct {
 	CachePolicy CachePolicy      `yaml:"cache,omitempty"`
 	SeedConfig  *bool            `yaml:"seed_config,omitempty"`
 
+	// TunnelConnections is how many parallel reverse tunnel connections
+	// agents maintain per proxy, one if not set. Extra connections allow
+	// fast failover when a proxy restarts.
+	TunnelConnections int `yaml:"tunnel_connections,omitempty"`
+
 	// CipherSuites is a list of TLS ciphersuites that Teleport supports. If
 	// omitted, a Teleport selected list of defaults will be used.
 	CipherSuites []string `yaml:"ciphersuites,omitempty"`
 
+	// SecurityProfile selects a preset of SSH algorithms: default,
+	// strict or legacy. Explicit cipher, KEX or MAC lists take
+	// precedence over the profile.
+	SecurityProfile string `yaml:"security_profile,omitempty"`
+
 	// Ciphers is a list of SSH ciphers that the server supports. If omitted,
 	// the defaults will be used.
 	Ciphers []string `yaml:"ciphers,omitempty"`
@@ -444,6 +681,53 @@ type Auth struct {
 	// The default value is 'on'.
 	ProxyProtocol string `yaml:"proxy_protocol,omitempty"`
 
+	// LoginLockout configures the account lockout policy applied after
+	// repeated failed local login attempts.
+	LoginLockout *LoginLockout `yaml:"login_lockout,omitempty"`
+
+	// PasswordPolicy configures the requirements new local user
+	// passwords must satisfy.
+	PasswordPolicy *PasswordPolicy `yaml:"password_policy,omitempty"`
+
+	// Webhooks are HTTP endpoints notified about selected audit events.
+	Webhooks []Webhook `yaml:"webhooks,omitempty"`
+
+	// AccessRequestNotifications are chat channels notified about access
+	// request activity.
+	AccessRequestNotifications []AccessRequestNotification `yaml:"access_request_notifications,omitempty"`
+
+	// PagerDuty configures auto-approval of access requests from users
+	// who are on call for mapped PagerDuty schedules.
+	PagerDuty *PagerDuty `yaml:"pagerduty,omitempty"`
+
+	// Ticketing configures tracking access requests as Jira or
+	// ServiceNow tickets.
+	Ticketing *Ticketing `yaml:"ticketing,omitempty"`
+
+	// AccessRequests configures the access request lifecycle.
+	AccessRequests *AccessRequests `yaml:"access_requests,omitempty"`
+
+	// VersionSkewLimit is how many major versions an agent may lag
+	// behind the auth server and still join, zero disables the check.
+	VersionSkewLimit int `yaml:"version_skew_limit,omitempty"`
+
+	// MaxWebSessions caps concurrent web sessions per user, zero
+	// disables the limit. The oldest sessions are evicted to make room.
+	MaxWebSessions int `yaml:"max_web_sessions,omitempty"`
+
+	// SignatureAlgorithmSuite selects the key types generated for users
+	// and hosts: rsa2048 (default), ecdsa-p256 or ed25519. CA keys stay
+	// RSA for compatibility with JWT signing and old OpenSSH peers.
+	SignatureAlgorithmSuite string `yaml:"signature_algorithm_suite,omitempty"`
+
+	// SessionRecordingRetention configures automatic pruning of local
+	// session recordings.
+	SessionRecordingRetention *RecordingRetention `yaml:"session_recording_retention,omitempty"`
+
+	// UsageReporting opts into periodic submission of anonymized usage
+	// counters.
+	UsageReporting *UsageReporting `yaml:"usage_reporting,omitempty"`
+
 	// ClusterName is the name of the CA who manages this cluster
 	ClusterName ClusterName `yaml:"cluster_name,omitempty"`
 
@@ -593,6 +877,104 @@ func (t StaticToken) Parse() (*types.ProvisionTokenV1, error) {
 	}, nil
 }
 
+// PasswordPolicy is the password requirements policy for local users
+type PasswordPolicy struct {
+	// MinLength is the minimum password length
+	MinLength int `yaml:"min_length,omitempty"`
+	// RequireUppercase requires at least one upper case character
+	RequireUppercase bool `yaml:"require_uppercase,omitempty"`
+	// RequireDigits requires at least one digit
+	RequireDigits bool `yaml:"require_digits,omitempty"`
+	// RequireSymbols requires at least one punctuation or symbol character
+	RequireSymbols bool `yaml:"require_symbols,omitempty"`
+}
+
+// LoginLockout is the account lockout policy for failed local logins
+type LoginLockout struct {
+	// MaxAttempts is the number of consecutive failed login attempts
+	// after which the account is locked
+	MaxAttempts int `yaml:"max_attempts,omitempty"`
+	// LockDuration is how long the account stays locked
+	LockDuration types.Duration `yaml:"lock_duration,omitempty"`
+}
+
+// Webhook is an HTTP endpoint notified about selected audit events
+type Webhook struct {
+	// URL is the endpoint event payloads are POSTed to
+	URL string `yaml:"url"`
+	// Events is the list of event types to deliver, all events when empty
+	Events []string `yaml:"events,omitempty"`
+	// Secret is used to compute the payload HMAC signature
+	Secret string `yaml:"secret,omitempty"`
+}
+
+// PagerDuty configures auto-approval of access requests based on
+// PagerDuty on-call schedules
+type PagerDuty struct {
+	// APIKey is the PagerDuty REST API token
+	APIKey string `yaml:"api_key"`
+	// APIEndpoint overrides the PagerDuty API address, used in tests
+	APIEndpoint string `yaml:"api_endpoint,omitempty"`
+	// ScheduleMap maps teleport role names to PagerDuty schedule IDs
+	ScheduleMap map[string]string `yaml:"schedule_map"`
+}
+
+// UsageReporting opts into anonymized usage reporting
+type UsageReporting struct {
+	// Enabled turns reporting on
+	Enabled bool `yaml:"enabled"`
+	// Endpoint is where reports are submitted
+	Endpoint string `yaml:"endpoint"`
+}
+
+// RecordingRetention configures automatic pruning of local session
+// recordings
+type RecordingRetention struct {
+	// Period is how long recordings are kept
+	Period types.Duration `yaml:"period"`
+	// DryRun reports what would be pruned instead of deleting
+	DryRun bool `yaml:"dry_run,omitempty"`
+}
+
+// AccessRequests configures the access request lifecycle
+type AccessRequests struct {
+	// MaxDuration caps how long requested access can last
+	MaxDuration types.Duration `yaml:"max_duration,omitempty"`
+	// UnassumedApprovalTTL is how long an approval stays valid without
+	// being assumed
+	UnassumedApprovalTTL types.Duration `yaml:"unassumed_approval_ttl,omitempty"`
+	// RetentionPeriod is how long resolved requests are kept before
+	// being pruned
+	RetentionPeriod types.Duration `yaml:"retention_period,omitempty"`
+}
+
+// Ticketing configures tracking access requests as Jira or ServiceNow
+// tickets
+type Ticketing struct {
+	// System is the ticketing system, "jira" or "servicenow"
+	System string `yaml:"system"`
+	// URL is the base address of the Jira or ServiceNow instance
+	URL string `yaml:"url"`
+	// Username authenticates API calls together with the token
+	Username string `yaml:"username"`
+	// APIToken is the Jira API token or ServiceNow password
+	APIToken string `yaml:"api_token"`
+	// Project is the Jira project key or ServiceNow table name
+	Project string `yaml:"project"`
+}
+
+// AccessRequestNotification is a chat channel notified about access
+// request activity
+type AccessRequestNotification struct {
+	// Type is the chat system, "slack" or "mattermost"
+	Type string `yaml:"type"`
+	// WebhookURL is the incoming webhook URL of the channel
+	WebhookURL string `yaml:"webhook_url"`
+	// RequestsURL is the address of the web UI access requests page used
+	// to link the approve and deny actions
+	RequestsURL string `yaml:"requests_url,omitempty"`
+}
+
 // AuthenticationConfig describes the auth_service/authentication section of teleport.yaml
 type AuthenticationConfig struct {
 	Type              string                     `yaml:"type"`
@@ -686,6 +1068,38 @@ type SSH struct {
 	// Don't read this value directly: call the AllowTCPForwarding method
 	// instead.
 	MaybeAllowTCPForwarding *bool `yaml:"port_forwarding,omitempty"`
+
+	// AllowedCommands are regular expressions, when set only matching
+	// exec command lines may run on this node.
+	AllowedCommands []string `yaml:"allowed_commands,omitempty"`
+
+	// DeniedCommands are regular expressions, matching exec command
+	// lines are rejected on this node.
+	DeniedCommands []string `yaml:"denied_commands,omitempty"`
+
+	// AcceptEnv lists glob patterns of client-supplied environment
+	// variables the node accepts, like OpenSSH's AcceptEnv. Everything
+	// is accepted when unset.
+	AcceptEnv []string `yaml:"accept_env,omitempty"`
+
+	// Banner is served through the SSH banner mechanism before
+	// authentication completes.
+	Banner string `yaml:"banner,omitempty"`
+
+	// BannerFile is a path to a file whose contents are served as the
+	// banner, takes precedence over Banner.
+	BannerFile string `yaml:"banner_file,omitempty"`
+
+	// MOTD is printed to interactive sessions after login.
+	MOTD string `yaml:"motd,omitempty"`
+
+	// MOTDFile is a path to a file whose contents are printed as the
+	// message of the day, takes precedence over MOTD.
+	MOTDFile string `yaml:"motd_file,omitempty"`
+
+	// ImportCloudTags imports AWS/Azure/GCP instance tags as resource
+	// labels when this node runs on one of those clouds.
+	ImportCloudTags bool `yaml:"import_cloud_tags,omitempty"`
 }
 
 // AllowTCPForwarding checks whether the config file allows TCP forwarding or not.
@@ -796,6 +1210,14 @@ type Databases struct {
 	Service `yaml:",inline"`
 	// Databases is a list of databases proxied by the service.
 	Databases []*Database `yaml:"databases"`
+	// DiscoverRDSRegions is a list of AWS regions to discover RDS
+	// instances and Aurora clusters in. Discovered databases are served
+	// in addition to the statically configured ones and use IAM
+	// authentication.
+	DiscoverRDSRegions []string `yaml:"discover_rds_regions,omitempty"`
+	// DiscoverRedshiftRegions is a list of AWS regions to discover
+	// Redshift clusters in.
+	DiscoverRedshiftRegions []string `yaml:"discover_redshift_regions,omitempty"`
 }
 
 // Database represents a single database proxied by the service.
@@ -898,6 +1320,11 @@ type Rewrite struct {
 type Proxy struct {
 	// Service is a generic service configuration section
 	Service `yaml:",inline"`
+
+	// SecurityHeaders overrides or extends the security headers the web
+	// proxy serves with every response. An empty value removes the
+	// corresponding default header.
+	SecurityHeaders map[string]string `yaml:"security_headers,omitempty"`
 	// WebAddr is a web UI listen address
 	WebAddr string `yaml:"web_listen_addr,omitempty"`
 	// TunAddr is a reverse tunnel address
@@ -1022,6 +1449,10 @@ type Kube struct {
 	// running in. If set, this proxy will handle kubernetes requests for the
 	// cluster.
 	KubeClusterName string `yaml:"kube_cluster_name,omitempty"`
+	// DiscoverEKSRegions is a list of AWS regions to discover active EKS
+	// clusters in. Discovered clusters are served in addition to clusters
+	// from the kubeconfig. Requires the aws CLI for authentication.
+	DiscoverEKSRegions []string `yaml:"discover_eks_regions,omitempty"`
 	// Static and dynamic labels for RBAC on kubernetes clusters.
 	StaticLabels  map[string]string `yaml:"labels,omitempty"`
 	DynamicLabels []CommandLabel    `yaml:"commands,omitempty"`
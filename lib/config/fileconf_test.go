@@ -18,11 +18,18 @@ package config
 
 import (
 	"bytes"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
 	"testing"
 
 	"github.com/google/go-cmp/cmp"
 	"github.com/stretchr/testify/require"
 	"gopkg.in/yaml.v2"
+
+	"github.com/gravitational/teleport/lib/defaults"
+	"github.com/gravitational/teleport/lib/utils"
 )
 
 // minimalConfigFile is a minimal subset of a teleport config file that can be
@@ -295,3 +302,137 @@ func TestSSHSection(t *testing.T) {
 		})
 	}
 }
+
+func TestConfigV2EnvInterpolation(t *testing.T) {
+	os.Setenv("TELEPORT_TEST_NODENAME", "node-from-env")
+	defer os.Unsetenv("TELEPORT_TEST_NODENAME")
+
+	conf, err := ReadConfig(strings.NewReader(`
+version: v2
+teleport:
+  nodename: ${TELEPORT_TEST_NODENAME}
+`))
+	require.NoError(t, err)
+	require.Equal(t, "node-from-env", conf.NodeName)
+
+	// References to unset variables are left as-is.
+	conf, err = ReadConfig(strings.NewReader(`
+version: v2
+teleport:
+  nodename: ${TELEPORT_TEST_UNSET_VARIABLE}
+`))
+	require.NoError(t, err)
+	require.Equal(t, "${TELEPORT_TEST_UNSET_VARIABLE}", conf.NodeName)
+
+	// v1 config files are not interpolated.
+	conf, err = ReadConfig(strings.NewReader(`
+teleport:
+  nodename: ${TELEPORT_TEST_NODENAME}
+`))
+	require.NoError(t, err)
+	require.Equal(t, "${TELEPORT_TEST_NODENAME}", conf.NodeName)
+}
+
+func TestConfigV2Includes(t *testing.T) {
+	dir := t.TempDir()
+	require.NoError(t, ioutil.WriteFile(filepath.Join(dir, "ssh.yaml"), []byte(`
+ssh_service:
+  enabled: yes
+  labels:
+    role: worker
+teleport:
+  nodename: node-from-include
+`), 0600))
+	mainPath := filepath.Join(dir, "teleport.yaml")
+	require.NoError(t, ioutil.WriteFile(mainPath, []byte(`
+version: v2
+include:
+  - ssh.yaml
+teleport:
+  nodename: node-from-main
+`), 0600))
+
+	conf, err := ReadFromFile(mainPath)
+	require.NoError(t, err)
+	// The including file takes precedence for sections it defines.
+	require.Equal(t, "node-from-main", conf.NodeName)
+	require.True(t, conf.SSH.Enabled())
+	require.Equal(t, map[string]string{"role": "worker"}, conf.SSH.Labels)
+
+	// Includes are rejected in v1 config files.
+	v1Path := filepath.Join(dir, "v1.yaml")
+	require.NoError(t, ioutil.WriteFile(v1Path, []byte(`
+include:
+  - ssh.yaml
+teleport:
+  nodename: v1
+`), 0600))
+	_, err = ReadFromFile(v1Path)
+	require.Error(t, err)
+	require.Contains(t, err.Error(), "includes require config version")
+}
+
+func TestConfigVersionValidation(t *testing.T) {
+	_, err := ReadConfig(strings.NewReader(`
+version: v3
+teleport:
+  nodename: node
+`))
+	require.Error(t, err)
+	require.Contains(t, err.Error(), "unsupported config version")
+}
+
+func TestMakeSampleFileConfigForRoles(t *testing.T) {
+	// Agent config: node only, pointed at an external auth server.
+	fc, err := MakeSampleFileConfig(SampleFlags{
+		Roles:      "node",
+		AuthServer: "auth.example.com:3025",
+		AuthToken:  "token-value",
+		NodeLabels: "env=staging,cloud=aws",
+		DataDir:    "/var/lib/teleport-node",
+	})
+	require.NoError(t, err)
+	require.True(t, fc.SSH.Enabled())
+	require.False(t, fc.Auth.Enabled())
+	require.False(t, fc.Proxy.Enabled())
+	require.Equal(t, []string{"auth.example.com:3025"}, fc.AuthServers)
+	require.Equal(t, "token-value", fc.AuthToken)
+	require.Equal(t, map[string]string{"env": "staging", "cloud": "aws"}, fc.SSH.Labels)
+	require.Equal(t, "/var/lib/teleport-node", fc.DataDir)
+
+	// Default: all-in-one.
+	fc, err = MakeSampleFileConfig(SampleFlags{})
+	require.NoError(t, err)
+	require.True(t, fc.SSH.Enabled())
+	require.True(t, fc.Auth.Enabled())
+	require.True(t, fc.Proxy.Enabled())
+
+	// --auth-server with no roles implies an agent (node) config.
+	fc, err = MakeSampleFileConfig(SampleFlags{AuthServer: "auth.example.com:3025"})
+	require.NoError(t, err)
+	require.True(t, fc.SSH.Enabled())
+	require.False(t, fc.Auth.Enabled())
+
+	// Unknown roles are rejected.
+	_, err = MakeSampleFileConfig(SampleFlags{Roles: "banana"})
+	require.Error(t, err)
+}
+
+func TestMakeSampleFileConfigFIPS(t *testing.T) {
+	fc, err := MakeSampleFileConfig(SampleFlags{FIPS: true})
+	require.NoError(t, err)
+	require.False(t, fc.Auth.Authentication.LocalAuth.Value)
+	require.Equal(t, defaults.FIPSCiphers, fc.Ciphers)
+	require.Equal(t, defaults.FIPSKEXAlgorithms, fc.KEXAlgorithms)
+	require.Equal(t, defaults.FIPSMACAlgorithms, fc.MACAlgorithms)
+	require.Equal(t, defaults.FIPSCipherSuiteNames, fc.CipherSuites)
+
+	// The generated config must itself pass validation, in particular the
+	// KEX algorithm list must only contain supported algorithms.
+	require.NoError(t, fc.CheckAndSetDefaults())
+
+	// The cipher suite names must map to the FIPS cipher suite IDs.
+	ids, err := utils.CipherSuiteMapping(defaults.FIPSCipherSuiteNames)
+	require.NoError(t, err)
+	require.ElementsMatch(t, defaults.FIPSCipherSuites, ids)
+}
@@ -29,6 +29,7 @@ import (
 	"github.com/gravitational/teleport/lib/utils"
 
 	"github.com/gravitational/trace"
+	log "github.com/sirupsen/logrus"
 	"gopkg.in/square/go-jose.v2"
 
 	"golang.org/x/crypto/ssh"
@@ -443,6 +444,8 @@ const (
 	ProtocolMySQL = "mysql"
 	// ProtocolMongoDB is the MongoDB database protocol.
 	ProtocolMongoDB = "mongodb"
+	// ProtocolElasticsearch is the Elasticsearch database protocol.
+	ProtocolElasticsearch = "elasticsearch"
 )
 
 // DatabaseProtocols is a list of all supported database protocols.
@@ -450,6 +453,7 @@ var DatabaseProtocols = []string{
 	ProtocolPostgres,
 	ProtocolMySQL,
 	ProtocolMongoDB,
+	ProtocolElasticsearch,
 }
 
 const (
@@ -651,6 +655,15 @@ var (
 		tls.TLS_ECDHE_ECDSA_WITH_AES_128_GCM_SHA256,
 	}
 
+	// FIPSCipherSuiteNames is FIPSCipherSuites in the name form used in the
+	// ciphersuites section of the config file.
+	FIPSCipherSuiteNames = []string{
+		"tls-ecdhe-rsa-with-aes-128-gcm-sha256",
+		"tls-ecdhe-rsa-with-aes-256-gcm-sha384",
+		"tls-ecdhe-ecdsa-with-aes-256-gcm-sha384",
+		"tls-ecdhe-ecdsa-with-aes-128-gcm-sha256",
+	}
+
 	// FIPSCiphers is a list of supported FIPS compliant SSH ciphers.
 	FIPSCiphers = []string{
 		"aes128-ctr",
@@ -663,7 +676,7 @@ var (
 	FIPSKEXAlgorithms = []string{
 		"ecdh-sha2-nistp256",
 		"ecdh-sha2-nistp384",
-		"echd-sha2-nistp521",
+		"ecdh-sha2-nistp521",
 	}
 
 	// FIPSMACAlgorithms is a list of supported FIPS compliant SSH mac algorithms.
@@ -716,3 +729,104 @@ func Transport() (*http.Transport, error) {
 
 	return tr, nil
 }
+
+// Security profile names selectable via the security_profile
+// configuration option and the tsh --security-profile flag.
+const (
+	// SecurityProfileDefault uses the built-in algorithm defaults.
+	SecurityProfileDefault = "default"
+	// SecurityProfileStrict restricts negotiation to modern AEAD ciphers
+	// and key exchanges.
+	SecurityProfileStrict = "strict"
+	// SecurityProfileLegacy additionally enables older algorithms for
+	// interoperability with ancient OpenSSH peers.
+	SecurityProfileLegacy = "legacy"
+)
+
+// StrictSSHCiphers are the ciphers allowed by the strict security
+// profile.
+var StrictSSHCiphers = []string{
+	"aes128-gcm@openssh.com",
+	"chacha20-poly1305@openssh.com",
+}
+
+// StrictKEXAlgorithms are the key exchanges allowed by the strict
+// security profile.
+var StrictKEXAlgorithms = []string{
+	"curve25519-sha256@libssh.org",
+	"ecdh-sha2-nistp256",
+}
+
+// StrictMACAlgorithms are the MACs allowed by the strict security
+// profile.
+var StrictMACAlgorithms = []string{
+	"hmac-sha2-256-etm@openssh.com",
+}
+
+// LegacySSHCiphers extend the defaults with older CBC ciphers for
+// interoperability.
+var LegacySSHCiphers = []string{
+	"aes128-gcm@openssh.com",
+	"chacha20-poly1305@openssh.com",
+	"aes128-ctr",
+	"aes192-ctr",
+	"aes256-ctr",
+	"aes128-cbc",
+	"aes256-cbc",
+}
+
+// LegacyKEXAlgorithms extend the defaults with SHA1 based key
+// exchanges.
+var LegacyKEXAlgorithms = []string{
+	"curve25519-sha256@libssh.org",
+	"ecdh-sha2-nistp256",
+	"ecdh-sha2-nistp384",
+	"ecdh-sha2-nistp521",
+	"diffie-hellman-group14-sha1",
+}
+
+// LegacyMACAlgorithms extend the defaults with SHA1 MACs.
+var LegacyMACAlgorithms = []string{
+	"hmac-sha2-256-etm@openssh.com",
+	"hmac-sha2-256",
+	"hmac-sha1",
+}
+
+// WeakSSHAlgorithms are algorithms that trigger a configuration warning
+// when enabled.
+var WeakSSHAlgorithms = []string{
+	"aes128-cbc",
+	"aes256-cbc",
+	"diffie-hellman-group14-sha1",
+	"hmac-sha1",
+}
+
+// SecurityProfileAlgorithms returns the cipher, KEX and MAC lists of a
+// named security profile. The default profile returns nil slices,
+// leaving the built-in defaults in place.
+func SecurityProfileAlgorithms(profile string) (ciphers, kexAlgos, macAlgos []string, err error) {
+	switch profile {
+	case "", SecurityProfileDefault:
+		return nil, nil, nil, nil
+	case SecurityProfileStrict:
+		return StrictSSHCiphers, StrictKEXAlgorithms, StrictMACAlgorithms, nil
+	case SecurityProfileLegacy:
+		return LegacySSHCiphers, LegacyKEXAlgorithms, LegacyMACAlgorithms, nil
+	}
+	return nil, nil, nil, trace.BadParameter("unsupported security profile %q, expected %q, %q or %q",
+		profile, SecurityProfileDefault, SecurityProfileStrict, SecurityProfileLegacy)
+}
+
+// WarnOnWeakAlgorithms logs a warning for every known-weak algorithm in
+// the given lists.
+func WarnOnWeakAlgorithms(lists ...[]string) {
+	for _, list := range lists {
+		for _, algo := range list {
+			for _, weak := range WeakSSHAlgorithms {
+				if algo == weak {
+					log.Warnf("SSH algorithm %q is considered weak, enable it only for legacy interoperability.", algo)
+				}
+			}
+		}
+	}
+}
@@ -0,0 +1,154 @@
+/*
+Copyright 2021 Gravitational, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package events
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	apievents "github.com/gravitational/teleport/api/types/events"
+
+	"github.com/gravitational/trace"
+)
+
+// Access request notification target types.
+const (
+	// AccessNotifyTargetSlack posts Slack-formatted messages.
+	AccessNotifyTargetSlack = "slack"
+	// AccessNotifyTargetMattermost posts Mattermost-formatted messages.
+	AccessNotifyTargetMattermost = "mattermost"
+)
+
+// AccessNotifyTarget is a single chat channel notified about access
+// request activity.
+type AccessNotifyTarget struct {
+	// Type is the target type, "slack" or "mattermost".
+	Type string
+	// WebhookURL is the incoming webhook URL of the channel.
+	WebhookURL string
+	// RequestsURL is the address of the cluster's web UI access request
+	// page, used to link the approve and deny actions. Optional.
+	RequestsURL string
+}
+
+// CheckAndSetDefaults validates the notification target.
+func (t *AccessNotifyTarget) CheckAndSetDefaults() error {
+	switch t.Type {
+	case AccessNotifyTargetSlack, AccessNotifyTargetMattermost:
+	default:
+		return trace.BadParameter("unsupported access request notification type %q, expected %q or %q",
+			t.Type, AccessNotifyTargetSlack, AccessNotifyTargetMattermost)
+	}
+	hook := WebhookConfig{URL: t.WebhookURL}
+	if err := hook.CheckAndSetDefaults(); err != nil {
+		return trace.Wrap(err)
+	}
+	return nil
+}
+
+// AccessRequestNotifier delivers access request lifecycle notifications
+// to Slack and Mattermost channels. It implements apievents.Emitter and,
+// like WebhookEmitter, never propagates delivery failures to the audit
+// path.
+type AccessRequestNotifier struct {
+	targets []AccessNotifyTarget
+	sender  *webhookSender
+}
+
+// NewAccessRequestNotifier returns a notifier posting to the configured
+// chat channels.
+func NewAccessRequestNotifier(targets []AccessNotifyTarget) (*AccessRequestNotifier, error) {
+	if len(targets) == 0 {
+		return nil, trace.BadParameter("missing notification targets")
+	}
+	for i := range targets {
+		if err := targets[i].CheckAndSetDefaults(); err != nil {
+			return nil, trace.Wrap(err)
+		}
+	}
+	return &AccessRequestNotifier{
+		targets: targets,
+		sender:  newWebhookSender(),
+	}, nil
+}
+
+// EmitAuditEvent posts a notification for access request lifecycle
+// events and ignores everything else.
+func (n *AccessRequestNotifier) EmitAuditEvent(ctx context.Context, event apievents.AuditEvent) error {
+	request, ok := event.(*apievents.AccessRequestCreate)
+	if !ok {
+		return nil
+	}
+	for _, target := range n.targets {
+		payload, err := accessNotifyPayload(target, request)
+		if err != nil {
+			n.sender.log.WithError(err).Warningf("Failed to format access request notification for %v.", target.WebhookURL)
+			continue
+		}
+		n.sender.enqueue(webhookPost{
+			url:         target.WebhookURL,
+			payload:     payload,
+			description: fmt.Sprintf("access request %v notification", request.RequestID),
+		})
+	}
+	return nil
+}
+
+// Close stops the notification sender.
+func (n *AccessRequestNotifier) Close() error {
+	return n.sender.Close()
+}
+
+// accessNotifyPayload formats the notification for the target chat
+// system. Both Slack and Mattermost accept the "text" field, Slack
+// additionally gets an attachment with approve and deny links when the
+// web UI address is known.
+func accessNotifyPayload(target AccessNotifyTarget, request *apievents.AccessRequestCreate) ([]byte, error) {
+	var summary string
+	switch request.Metadata.Type {
+	case AccessRequestCreateEvent:
+		summary = fmt.Sprintf("Access request *%v*: user *%v* requests roles *%v*.",
+			request.RequestID, request.User, strings.Join(request.Roles, ", "))
+		if request.Reason != "" {
+			summary += fmt.Sprintf(" Reason: %v", request.Reason)
+		}
+	case AccessRequestUpdateEvent:
+		summary = fmt.Sprintf("Access request *%v* was *%v* by *%v*.",
+			request.RequestID, strings.ToUpper(request.RequestState), request.UpdatedBy)
+	default:
+		return nil, trace.BadParameter("unexpected access request event type %q", request.Metadata.Type)
+	}
+
+	message := map[string]interface{}{"text": summary}
+	if target.Type == AccessNotifyTargetSlack && target.RequestsURL != "" && request.Metadata.Type == AccessRequestCreateEvent {
+		requestURL := fmt.Sprintf("%v/%v", strings.TrimRight(target.RequestsURL, "/"), request.RequestID)
+		message["attachments"] = []map[string]interface{}{{
+			"fallback": requestURL,
+			"actions": []map[string]interface{}{
+				{"type": "button", "text": "Approve", "style": "primary", "url": requestURL},
+				{"type": "button", "text": "Deny", "style": "danger", "url": requestURL},
+			},
+		}}
+	}
+	payload, err := json.Marshal(message)
+	if err != nil {
+		return nil, trace.Wrap(err)
+	}
+	return payload, nil
+}
@@ -55,7 +55,7 @@ func NewAuditWriter(cfg AuditWriterConfig) (*AuditWriter, error) {
 		}),
 		cancel:         cancel,
 		closeCtx:       ctx,
-		eventsCh:       make(chan apievents.AuditEvent),
+		eventsCh:       make(chan apievents.AuditEvent, cfg.BufferSize),
 		doneCh:         make(chan struct{}),
 		lostEvents:     atomic.NewInt64(0),
 		acceptedEvents: atomic.NewInt64(0),
@@ -108,6 +108,12 @@ type AuditWriterConfig struct {
 
 	// ClusterName defines the name of this teleport cluster.
 	ClusterName string
+
+	// BufferSize is the number of events buffered between the session
+	// goroutines and the stream writer. A buffer absorbs bursts (BPF
+	// callbacks, chatty PTYs) without blocking the session data path,
+	// the slow-writes counter signals sustained backpressure.
+	BufferSize int
 }
 
 // CheckAndSetDefaults checks and sets defaults
@@ -139,9 +145,16 @@ func (cfg *AuditWriterConfig) CheckAndSetDefaults() error {
 	if cfg.BackoffDuration == 0 {
 		cfg.BackoffDuration = defaults.NetworkBackoffDuration
 	}
+	if cfg.BufferSize == 0 {
+		cfg.BufferSize = defaultAuditWriterBufferSize
+	}
 	return nil
 }
 
+// defaultAuditWriterBufferSize absorbs short bursts of events per
+// session without blocking the emitting goroutines.
+const defaultAuditWriterBufferSize = 64
+
 // AuditWriter wraps session stream
 // and writes audit events to it
 type AuditWriter struct {
@@ -438,6 +451,9 @@ func (a *AuditWriter) processEvents() {
 				return
 			}
 		case <-a.closeCtx.Done():
+			// Drain events buffered in the channel before completing the
+			// stream so a buffered writer loses nothing on close.
+			a.drainEvents()
 			a.completeStream(a.stream)
 			return
 		}
@@ -587,3 +603,20 @@ func (a *AuditWriter) setupEvent(event apievents.AuditEvent) error {
 	a.lastPrintEvent = printEvent
 	return nil
 }
+
+// drainEvents writes any events still buffered in the channel to the
+// stream, used on close so buffered events are not lost.
+func (a *AuditWriter) drainEvents() {
+	for {
+		select {
+		case event := <-a.eventsCh:
+			a.buffer = append(a.buffer, event)
+			if err := a.stream.EmitAuditEvent(a.cfg.Context, event); err != nil {
+				a.log.WithError(err).Debug("Failed to emit buffered audit event on close.")
+				return
+			}
+		default:
+			return
+		}
+	}
+}
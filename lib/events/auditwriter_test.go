@@ -254,8 +254,12 @@ func TestAuditWriter(t *testing.T) {
 		require.NoError(t, err)
 		outEvents := test.collectEvents(t)
 
-		submittedEvents := inEvents[:submitEvents]
-		require.Equal(t, submittedEvents, outEvents)
+		// The writer delivers every event submitted before the hang plus
+		// whatever fit in the channel buffer, in order and without gaps;
+		// events emitted during the backoff window are lost.
+		require.GreaterOrEqual(t, len(outEvents), submitEvents)
+		require.Less(t, len(outEvents), len(inEvents))
+		require.Equal(t, inEvents[:len(outEvents)], outEvents)
 		require.Equal(t, 1, int(streamResumed.Load()), "Stream resumed.")
 	})
 
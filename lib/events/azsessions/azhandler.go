@@ -0,0 +1,327 @@
+/*
+Copyright 2021 Gravitational, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package azsessions
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/gravitational/teleport"
+	"github.com/gravitational/teleport/lib/session"
+	"github.com/gravitational/teleport/lib/utils"
+	"github.com/gravitational/trace"
+
+	"github.com/Azure/azure-storage-blob-go/azblob"
+	"github.com/prometheus/client_golang/prometheus"
+
+	log "github.com/sirupsen/logrus"
+)
+
+var (
+	uploadRequests = prometheus.NewCounter(
+		prometheus.CounterOpts{
+			Name: "azblob_event_storage_uploads",
+			Help: "Number of uploads to the Azure Blob Storage backend",
+		},
+	)
+	downloadRequests = prometheus.NewCounter(
+		prometheus.CounterOpts{
+			Name: "azblob_event_storage_downloads",
+			Help: "Number of downloads from the Azure Blob Storage backend",
+		},
+	)
+	uploadLatencies = prometheus.NewHistogram(
+		prometheus.HistogramOpts{
+			Name: "azblob_event_storage_uploads_seconds",
+			Help: "Latency for Azure Blob Storage upload operations",
+			// lowest bucket start of upper bound 0.001 sec (1 ms) with factor 2
+			// highest bucket start of 0.001 sec * 2^15 == 32.768 sec
+			Buckets: prometheus.ExponentialBuckets(0.001, 2, 16),
+		},
+	)
+	downloadLatencies = prometheus.NewHistogram(
+		prometheus.HistogramOpts{
+			Name: "azblob_event_storage_downloads_seconds",
+			Help: "Latency for Azure Blob Storage download operations",
+			// lowest bucket start of upper bound 0.001 sec (1 ms) with factor 2
+			// highest bucket start of 0.001 sec * 2^15 == 32.768 sec
+			Buckets: prometheus.ExponentialBuckets(0.001, 2, 16),
+		},
+	)
+
+	prometheusCollectors = []prometheus.Collector{uploadRequests, downloadRequests, uploadLatencies, downloadLatencies}
+)
+
+const (
+	// accountPropertyKey is the URL parameter that sets the storage account name
+	accountPropertyKey = "account"
+	// endpointPropertyKey is the URL parameter that overrides the blob service
+	// endpoint, used with local emulators like azurite
+	endpointPropertyKey = "endpoint"
+	// pathPropertyKey is the URL parameter that sets an optional container path
+	pathPropertyKey = "path"
+
+	// accountNameEnvVar is the environment variable with the storage account name
+	accountNameEnvVar = "AZURE_STORAGE_ACCOUNT"
+	// accountKeyEnvVar is the environment variable with the storage account
+	// shared key, if not set the anonymous credential is used
+	accountKeyEnvVar = "AZURE_STORAGE_KEY"
+)
+
+// Config is a handler configuration
+type Config struct {
+	// Container is the Azure Blob Storage container name
+	Container string
+	// Path is an optional container path
+	Path string
+	// Account is the storage account name, read from the
+	// AZURE_STORAGE_ACCOUNT environment variable if not set
+	Account string
+	// Endpoint overrides the blob service endpoint, used with local emulators
+	Endpoint string
+}
+
+// SetFromURL sets values on the Config from the supplied URI
+func (cfg *Config) SetFromURL(url *url.URL) error {
+	accountParamString := url.Query().Get(accountPropertyKey)
+	if len(accountParamString) > 0 {
+		cfg.Account = accountParamString
+	}
+
+	endpointParamString := url.Query().Get(endpointPropertyKey)
+	if len(endpointParamString) > 0 {
+		cfg.Endpoint = endpointParamString
+	}
+
+	pathParamString := url.Query().Get(pathPropertyKey)
+	if len(pathParamString) > 0 {
+		cfg.Path = pathParamString
+	}
+
+	if url.Host == "" {
+		return trace.BadParameter("host should be set to the container name for recording storage")
+	}
+	cfg.Container = url.Host
+
+	return nil
+}
+
+// CheckAndSetDefaults checks and sets default values
+func (cfg *Config) CheckAndSetDefaults() error {
+	if cfg.Account == "" {
+		cfg.Account = os.Getenv(accountNameEnvVar)
+	}
+	if cfg.Account == "" && cfg.Endpoint == "" {
+		return trace.BadParameter("either storage account or endpoint must be set for Azure Blob Storage, "+
+			"set the %q URL parameter or the %v environment variable", accountPropertyKey, accountNameEnvVar)
+	}
+	if cfg.Endpoint == "" {
+		cfg.Endpoint = fmt.Sprintf("https://%v.blob.core.windows.net", cfg.Account)
+	}
+	return nil
+}
+
+// DefaultNewHandler returns a new handler with client settings derived from
+// the config and the environment
+func DefaultNewHandler(cfg Config) (*Handler, error) {
+	if err := cfg.CheckAndSetDefaults(); err != nil {
+		return nil, trace.Wrap(err)
+	}
+
+	credential := azblob.Credential(azblob.NewAnonymousCredential())
+	if key := os.Getenv(accountKeyEnvVar); key != "" {
+		var err error
+		credential, err = azblob.NewSharedKeyCredential(cfg.Account, key)
+		if err != nil {
+			return nil, trace.Wrap(err)
+		}
+	}
+
+	endpointURL, err := url.Parse(cfg.Endpoint)
+	if err != nil {
+		return nil, trace.Wrap(err)
+	}
+
+	pipeline := azblob.NewPipeline(credential, azblob.PipelineOptions{})
+	container := azblob.NewServiceURL(*endpointURL, pipeline).NewContainerURL(cfg.Container)
+
+	return NewHandler(context.Background(), cfg, container)
+}
+
+// NewHandler returns a new handler with a specific container URL
+func NewHandler(ctx context.Context, cfg Config, container azblob.ContainerURL) (*Handler, error) {
+	err := utils.RegisterPrometheusCollectors(prometheusCollectors...)
+	if err != nil {
+		return nil, trace.Wrap(err)
+	}
+
+	if err := cfg.CheckAndSetDefaults(); err != nil {
+		return nil, trace.Wrap(err)
+	}
+	h := &Handler{
+		Entry: log.WithFields(log.Fields{
+			trace.Component: teleport.Component(teleport.SchemeAZBlob),
+		}),
+		Config:    cfg,
+		container: container,
+	}
+	start := time.Now()
+	h.Infof("Setting up container %q, sessions path %q.", h.Container, h.Path)
+	if err := h.ensureContainer(ctx); err != nil {
+		return nil, trace.Wrap(err)
+	}
+	h.WithFields(log.Fields{"duration": time.Since(start)}).Infof("Setup container %q completed.", h.Container)
+	return h, nil
+}
+
+// Handler handles uploads and downloads to Azure Blob Storage
+type Handler struct {
+	// Config is handler configuration
+	Config
+	// Entry is a logging entry
+	*log.Entry
+	// container is the Azure Blob Storage container used for persistence
+	container azblob.ContainerURL
+}
+
+// Close releases connection and resources associated with log if any
+func (h *Handler) Close() error {
+	return nil
+}
+
+// Upload uploads a session tarball to the Azure Blob Storage container,
+// reading the contents of the object from reader, and returns the target
+// blob path in case of a successful upload.
+func (h *Handler) Upload(ctx context.Context, sessionID session.ID, reader io.Reader) (string, error) {
+	path := h.path(sessionID)
+	h.Logger.Debugf("Uploading %s.", path)
+	blob := h.container.NewBlockBlobURL(path)
+
+	// Make sure we don't overwrite an existing recording.
+	_, err := blob.GetProperties(ctx, azblob.BlobAccessConditions{})
+	if err == nil {
+		return "", trace.AlreadyExists("recording for session %q already exists in Azure Blob Storage", sessionID)
+	}
+	if !trace.IsNotFound(convertAZError(err)) {
+		return "", convertAZError(err)
+	}
+
+	start := time.Now()
+	_, err = azblob.UploadStreamToBlockBlob(ctx, reader, blob, azblob.UploadStreamToBlockBlobOptions{})
+	uploadLatencies.Observe(time.Since(start).Seconds())
+	uploadRequests.Inc()
+	if err != nil {
+		return "", convertAZError(err)
+	}
+	return fmt.Sprintf("%v://%v/%v", teleport.SchemeAZBlob, h.Container, path), nil
+}
+
+// Download downloads a session tarball from the Azure Blob Storage container
+// and writes it to writer
+func (h *Handler) Download(ctx context.Context, sessionID session.ID, writerAt io.WriterAt) error {
+	path := h.path(sessionID)
+	h.Logger.Debugf("Downloading %s.", path)
+	writer, ok := writerAt.(io.Writer)
+	if !ok {
+		return trace.BadParameter("the provided writerAt is %T which does not implement io.Writer", writerAt)
+	}
+
+	resp, err := h.container.NewBlockBlobURL(path).Download(ctx, 0, azblob.CountToEnd, azblob.BlobAccessConditions{}, false)
+	if err != nil {
+		return convertAZError(err)
+	}
+	body := resp.Body(azblob.RetryReaderOptions{})
+	defer body.Close()
+
+	start := time.Now()
+	written, err := io.Copy(writer, body)
+	if err != nil {
+		return convertAZError(err)
+	}
+	downloadLatencies.Observe(time.Since(start).Seconds())
+	downloadRequests.Inc()
+	if written == 0 {
+		return trace.NotFound("recording for %v is empty", sessionID)
+	}
+	return nil
+}
+
+func (h *Handler) path(sessionID session.ID) string {
+	if h.Path == "" {
+		return string(sessionID) + ".tar"
+	}
+	return strings.TrimPrefix(filepath.Join(h.Path, string(sessionID)+".tar"), "/")
+}
+
+// ensureContainer makes sure the container exists, and if it does not,
+// creates it
+func (h *Handler) ensureContainer(ctx context.Context) error {
+	_, err := h.container.GetProperties(ctx, azblob.LeaseAccessConditions{})
+	err = convertAZError(err)
+	// assumes that the container is administered by another entity
+	if err == nil {
+		return nil
+	}
+	if !trace.IsNotFound(err) {
+		h.Errorf("Failed to ensure that container %q exists (%v). Azure Blob Storage session uploads may fail. If you've set up the container already and gave Teleport write-only access, feel free to ignore this error.", h.Container, err)
+		return nil
+	}
+	_, err = h.container.Create(ctx, azblob.Metadata{}, azblob.PublicAccessNone)
+	err = convertAZError(err)
+	if err != nil {
+		if !trace.IsAlreadyExists(err) {
+			return trace.Wrap(err)
+		}
+		// if this handler has not created the container, don't reconfigure it
+		return nil
+	}
+	return nil
+}
+
+// convertAZError converts Azure Blob Storage errors to trace errors
+func convertAZError(err error) error {
+	if err == nil {
+		return nil
+	}
+	stgErr, ok := err.(azblob.StorageError)
+	if !ok {
+		return trace.Wrap(err)
+	}
+	switch stgErr.ServiceCode() {
+	case azblob.ServiceCodeBlobNotFound, azblob.ServiceCodeContainerNotFound:
+		return trace.NotFound(stgErr.Error())
+	case azblob.ServiceCodeBlobAlreadyExists, azblob.ServiceCodeContainerAlreadyExists:
+		return trace.AlreadyExists(stgErr.Error())
+	}
+	if resp := stgErr.Response(); resp != nil {
+		resp.Body.Close()
+		switch resp.StatusCode {
+		case http.StatusNotFound:
+			return trace.NotFound(stgErr.Error())
+		case http.StatusConflict:
+			return trace.AlreadyExists(stgErr.Error())
+		}
+	}
+	return trace.Wrap(err)
+}
@@ -0,0 +1,71 @@
+/*
+Copyright 2021 Gravitational, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package azsessions
+
+import (
+	"net/url"
+	"testing"
+
+	"github.com/gravitational/teleport/lib/events"
+	"github.com/gravitational/teleport/lib/session"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestConfigSetFromURL(t *testing.T) {
+	uri, err := url.Parse("azblob://recordings?account=teleport&path=records&endpoint=http://127.0.0.1:10000/teleport")
+	require.NoError(t, err)
+
+	var cfg Config
+	require.NoError(t, cfg.SetFromURL(uri))
+	require.Equal(t, "recordings", cfg.Container)
+	require.Equal(t, "teleport", cfg.Account)
+	require.Equal(t, "records", cfg.Path)
+	require.Equal(t, "http://127.0.0.1:10000/teleport", cfg.Endpoint)
+
+	require.NoError(t, cfg.CheckAndSetDefaults())
+
+	// Missing container name is rejected.
+	uri, err = url.Parse("azblob://?account=teleport")
+	require.NoError(t, err)
+	require.Error(t, cfg.SetFromURL(uri))
+
+	// Default endpoint is derived from the account name.
+	cfg = Config{Account: "teleport", Container: "recordings"}
+	require.NoError(t, cfg.CheckAndSetDefaults())
+	require.Equal(t, "https://teleport.blob.core.windows.net", cfg.Endpoint)
+}
+
+func TestUploadAndPartPaths(t *testing.T) {
+	h := &Handler{Config: Config{Path: "records"}}
+	sessionID := session.NewID()
+	upload := events.StreamUpload{ID: "upload-id", SessionID: sessionID}
+
+	require.Equal(t, "records/"+string(sessionID)+".tar", h.path(sessionID))
+	require.Equal(t, "records/uploads/upload-id/"+string(sessionID)+".upload", h.uploadPath(upload))
+	require.Equal(t, "records/parts/upload-id/7.part", h.partPath(upload, 7))
+
+	// Round trip through the path parsers.
+	parsedUpload, err := uploadFromPath(h.uploadPath(upload))
+	require.NoError(t, err)
+	require.Equal(t, upload.ID, parsedUpload.ID)
+	require.Equal(t, upload.SessionID, parsedUpload.SessionID)
+
+	part, err := partFromPath(h.partPath(upload, 7))
+	require.NoError(t, err)
+	require.Equal(t, int64(7), part.Number)
+}
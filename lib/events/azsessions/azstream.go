@@ -0,0 +1,339 @@
+/*
+Copyright 2021 Gravitational, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package azsessions
+
+import (
+	"bytes"
+	"context"
+	"encoding/base64"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/gravitational/teleport"
+	"github.com/gravitational/teleport/lib/events"
+	"github.com/gravitational/teleport/lib/session"
+	"github.com/gravitational/trace"
+
+	"github.com/Azure/azure-storage-blob-go/azblob"
+	"github.com/pborman/uuid"
+)
+
+// CreateUpload creates a multipart upload
+func (h *Handler) CreateUpload(ctx context.Context, sessionID session.ID) (*events.StreamUpload, error) {
+	upload := events.StreamUpload{
+		ID:        uuid.New(),
+		SessionID: sessionID,
+		Initiated: time.Now().UTC(),
+	}
+	if err := upload.CheckAndSetDefaults(); err != nil {
+		return nil, trace.Wrap(err)
+	}
+
+	uploadPath := h.uploadPath(upload)
+
+	h.Logger.Debugf("Creating upload at %s", uploadPath)
+	blob := h.container.NewBlockBlobURL(uploadPath)
+
+	// Make sure we don't overwrite an existing upload
+	_, err := blob.GetProperties(ctx, azblob.BlobAccessConditions{})
+	if err == nil {
+		return nil, trace.AlreadyExists("upload %v for session %q already exists in Azure Blob Storage", upload.ID, sessionID)
+	}
+	if !trace.IsNotFound(convertAZError(err)) {
+		return nil, convertAZError(err)
+	}
+
+	start := time.Now()
+	_, err = blob.Upload(ctx, bytes.NewReader([]byte(sessionID)), azblob.BlobHTTPHeaders{}, azblob.Metadata{}, azblob.BlobAccessConditions{})
+	uploadLatencies.Observe(time.Since(start).Seconds())
+	uploadRequests.Inc()
+	if err != nil {
+		return nil, convertAZError(err)
+	}
+	return &upload, nil
+}
+
+// UploadPart uploads a part of the session stream as its own blob, parts
+// are merged into the final session blob on CompleteUpload
+func (h *Handler) UploadPart(ctx context.Context, upload events.StreamUpload, partNumber int64, partBody io.ReadSeeker) (*events.StreamPart, error) {
+	if err := upload.CheckAndSetDefaults(); err != nil {
+		return nil, trace.Wrap(err)
+	}
+
+	partPath := h.partPath(upload, partNumber)
+	blob := h.container.NewBlockBlobURL(partPath)
+	start := time.Now()
+	_, err := blob.Upload(ctx, partBody, azblob.BlobHTTPHeaders{}, azblob.Metadata{}, azblob.BlobAccessConditions{})
+	uploadLatencies.Observe(time.Since(start).Seconds())
+	uploadRequests.Inc()
+	if err != nil {
+		return nil, convertAZError(err)
+	}
+	return &events.StreamPart{Number: partNumber}, nil
+}
+
+// CompleteUpload completes the upload: part blobs are staged as blocks of
+// the final session blob in part order, committed as a single block list
+// and cleaned up afterwards. Parts are copied through the handler one at a
+// time rather than merged server-side, which would require SAS tokens for
+// blob-to-blob authorization.
+func (h *Handler) CompleteUpload(ctx context.Context, upload events.StreamUpload, parts []events.StreamPart) error {
+	if err := upload.CheckAndSetDefaults(); err != nil {
+		return trace.Wrap(err)
+	}
+
+	// If the session has been already created, move to cleanup
+	sessionPath := h.path(upload.SessionID)
+	_, err := h.container.NewBlockBlobURL(sessionPath).GetProperties(ctx, azblob.BlobAccessConditions{})
+	if err == nil {
+		return h.cleanupUpload(ctx, upload)
+	}
+	if !trace.IsNotFound(convertAZError(err)) {
+		return convertAZError(err)
+	}
+
+	// Makes sure that upload has been properly initiated
+	uploadPath := h.uploadPath(upload)
+	_, err = h.container.NewBlockBlobURL(uploadPath).GetProperties(ctx, azblob.BlobAccessConditions{})
+	if err != nil {
+		if trace.IsNotFound(convertAZError(err)) {
+			return trace.NotFound("upload %v for session %q is not found", upload.ID, upload.SessionID)
+		}
+		return convertAZError(err)
+	}
+
+	// Parts must be sorted in ascending order and staged in the same order
+	// the block list is committed in.
+	parts = append([]events.StreamPart(nil), parts...)
+	sort.Slice(parts, func(i, j int) bool {
+		return parts[i].Number < parts[j].Number
+	})
+
+	sessionBlob := h.container.NewBlockBlobURL(sessionPath)
+	blockIDs := make([]string, 0, len(parts))
+	for i, part := range parts {
+		resp, err := h.container.NewBlockBlobURL(h.partPath(upload, part.Number)).Download(
+			ctx, 0, azblob.CountToEnd, azblob.BlobAccessConditions{}, false)
+		if err != nil {
+			return convertAZError(err)
+		}
+		body := resp.Body(azblob.RetryReaderOptions{})
+		data, err := ioutil.ReadAll(body)
+		body.Close()
+		if err != nil {
+			return convertAZError(err)
+		}
+		// Block IDs within a blob must all be base64 strings of equal length.
+		blockID := base64.StdEncoding.EncodeToString([]byte(fmt.Sprintf("%020d", i)))
+		if _, err := sessionBlob.StageBlock(ctx, blockID, bytes.NewReader(data), azblob.LeaseAccessConditions{}, nil); err != nil {
+			return convertAZError(err)
+		}
+		blockIDs = append(blockIDs, blockID)
+	}
+
+	if _, err := sessionBlob.CommitBlockList(ctx, blockIDs, azblob.BlobHTTPHeaders{}, azblob.Metadata{}, azblob.BlobAccessConditions{}); err != nil {
+		return convertAZError(err)
+	}
+
+	return h.cleanupUpload(ctx, upload)
+}
+
+// cleanupUpload iterates over all upload related objects and deletes them
+// in parallel
+func (h *Handler) cleanupUpload(ctx context.Context, upload events.StreamUpload) error {
+	var paths []string
+	for _, prefix := range []string{h.partsPrefix(upload), h.uploadPrefix(upload)} {
+		blobs, err := h.listBlobs(ctx, prefix)
+		if err != nil {
+			return trace.Wrap(err)
+		}
+		paths = append(paths, blobs...)
+	}
+	for _, path := range paths {
+		_, err := h.container.NewBlockBlobURL(path).Delete(ctx, azblob.DeleteSnapshotsOptionNone, azblob.BlobAccessConditions{})
+		if err = convertAZError(err); err != nil && !trace.IsNotFound(err) {
+			return trace.Wrap(err)
+		}
+	}
+	return nil
+}
+
+// ListParts returns all uploaded parts for the completed upload in sorted order
+func (h *Handler) ListParts(ctx context.Context, upload events.StreamUpload) ([]events.StreamPart, error) {
+	if err := upload.CheckAndSetDefaults(); err != nil {
+		return nil, trace.Wrap(err)
+	}
+
+	blobs, err := h.listBlobs(ctx, h.partsPrefix(upload))
+	if err != nil {
+		return nil, trace.Wrap(err)
+	}
+	var parts []events.StreamPart
+	for _, path := range blobs {
+		// Skip entries that are not parts
+		if filepath.Ext(path) != partExt {
+			continue
+		}
+		part, err := partFromPath(path)
+		if err != nil {
+			return nil, trace.Wrap(err)
+		}
+		parts = append(parts, *part)
+	}
+	sort.Slice(parts, func(i, j int) bool {
+		return parts[i].Number < parts[j].Number
+	})
+	return parts, nil
+}
+
+// ListUploads lists uploads that have been initiated but not completed with
+// earlier uploads returned first
+func (h *Handler) ListUploads(ctx context.Context) ([]events.StreamUpload, error) {
+	var uploads []events.StreamUpload
+	for marker := (azblob.Marker{}); marker.NotDone(); {
+		resp, err := h.container.ListBlobsFlatSegment(ctx, marker, azblob.ListBlobsSegmentOptions{
+			Prefix: h.uploadsPrefix(),
+		})
+		if err != nil {
+			return nil, convertAZError(err)
+		}
+		for _, blob := range resp.Segment.BlobItems {
+			// Skip entries that are not uploads
+			if filepath.Ext(blob.Name) != uploadExt {
+				continue
+			}
+			upload, err := uploadFromPath(blob.Name)
+			if err != nil {
+				return nil, trace.Wrap(err)
+			}
+			if blob.Properties.CreationTime != nil {
+				upload.Initiated = *blob.Properties.CreationTime
+			}
+			uploads = append(uploads, *upload)
+		}
+		marker = resp.NextMarker
+	}
+	sort.Slice(uploads, func(i, j int) bool {
+		return uploads[i].Initiated.Before(uploads[j].Initiated)
+	})
+	return uploads, nil
+}
+
+// GetUploadMetadata gets the metadata for session upload
+func (h *Handler) GetUploadMetadata(s session.ID) events.UploadMetadata {
+	return events.UploadMetadata{
+		URL:       fmt.Sprintf("%v://%v/%v", teleport.SchemeAZBlob, h.Container, h.path(s)),
+		SessionID: s,
+	}
+}
+
+// listBlobs returns the names of all blobs with the given prefix
+func (h *Handler) listBlobs(ctx context.Context, prefix string) ([]string, error) {
+	var names []string
+	for marker := (azblob.Marker{}); marker.NotDone(); {
+		resp, err := h.container.ListBlobsFlatSegment(ctx, marker, azblob.ListBlobsSegmentOptions{
+			Prefix: prefix,
+		})
+		if err != nil {
+			return nil, convertAZError(err)
+		}
+		for _, blob := range resp.Segment.BlobItems {
+			names = append(names, blob.Name)
+		}
+		marker = resp.NextMarker
+	}
+	return names, nil
+}
+
+const (
+	// uploadsKey is a key that holds all upload-related objects
+	uploadsKey = "uploads"
+	// partsKey is a key that holds all part-related objects
+	partsKey = "parts"
+	// partExt is a part extension
+	partExt = ".part"
+	// uploadExt is upload extension
+	uploadExt = ".upload"
+	// slash is a forward slash
+	slash = "/"
+)
+
+// uploadsPrefix is "path/uploads"
+func (h *Handler) uploadsPrefix() string {
+	return strings.TrimPrefix(filepath.Join(h.Path, uploadsKey), slash)
+}
+
+// uploadPrefix is "path/uploads/<upload-id>"
+func (h *Handler) uploadPrefix(upload events.StreamUpload) string {
+	return filepath.Join(h.uploadsPrefix(), upload.ID)
+}
+
+// uploadPath is "path/uploads/<upload-id>/<session-id>.upload"
+func (h *Handler) uploadPath(upload events.StreamUpload) string {
+	return filepath.Join(h.uploadPrefix(upload), string(upload.SessionID)) + uploadExt
+}
+
+// partsPrefix is "path/parts/<upload-id>"
+// this path is under different tree from upload to make prefix
+// iteration of uploads more efficient
+func (h *Handler) partsPrefix(upload events.StreamUpload) string {
+	return strings.TrimPrefix(filepath.Join(h.Path, partsKey, upload.ID), slash)
+}
+
+// partPath is "path/parts/<upload-id>/<part-number>.part"
+func (h *Handler) partPath(upload events.StreamUpload, partNumber int64) string {
+	return filepath.Join(h.partsPrefix(upload), fmt.Sprintf("%v%v", partNumber, partExt))
+}
+
+func uploadFromPath(path string) (*events.StreamUpload, error) {
+	dir, file := filepath.Split(path)
+	if filepath.Ext(file) != uploadExt {
+		return nil, trace.BadParameter("expected extension %v, got %v", uploadExt, file)
+	}
+	sessionID := session.ID(strings.TrimSuffix(file, uploadExt))
+	if err := sessionID.Check(); err != nil {
+		return nil, trace.Wrap(err)
+	}
+	parts := strings.Split(strings.TrimSuffix(dir, slash), slash)
+	if len(parts) < 2 {
+		return nil, trace.BadParameter("expected format uploads/<upload-id>, got %v", dir)
+	}
+	uploadID := parts[len(parts)-1]
+	return &events.StreamUpload{
+		SessionID: sessionID,
+		ID:        uploadID,
+	}, nil
+}
+
+func partFromPath(path string) (*events.StreamPart, error) {
+	base := filepath.Base(path)
+	if filepath.Ext(base) != partExt {
+		return nil, trace.BadParameter("expected extension %v, got %v", partExt, base)
+	}
+	numberString := strings.TrimSuffix(base, partExt)
+	partNumber, err := strconv.ParseInt(numberString, 10, 0)
+	if err != nil {
+		return nil, trace.Wrap(err)
+	}
+	return &events.StreamPart{Number: partNumber}, nil
+}
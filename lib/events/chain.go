@@ -0,0 +1,161 @@
+/*
+Copyright 2021 Gravitational, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package events
+
+import (
+	"bufio"
+	"crypto/sha256"
+	"crypto/subtle"
+	"encoding/hex"
+	"hash"
+	"io"
+	"os"
+
+	"github.com/gravitational/trace"
+)
+
+// RecordingChainSuffix is appended to the recording file name to form the
+// name of the sidecar file holding its hash chain.
+const RecordingChainSuffix = ".chain"
+
+// recordingChainChunkSize is the amount of recording data covered by a
+// single chain link. Each link hashes the previous link together with the
+// next chunk, so modifying any byte of a stored recording invalidates
+// every link from that chunk onward.
+const recordingChainChunkSize = 64 * 1024
+
+// ChainWriter computes a recording hash chain incrementally as data is
+// written through it: link[0] = SHA256(chunk[0]), link[n] =
+// SHA256(link[n-1] + chunk[n]).
+type ChainWriter struct {
+	links   [][]byte
+	prev    []byte
+	digest  hash.Hash
+	pending int
+}
+
+// NewChainWriter returns a writer accumulating the hash chain of the data
+// written to it.
+func NewChainWriter() *ChainWriter {
+	return &ChainWriter{digest: sha256.New()}
+}
+
+// Write consumes the next portion of the recording, closing a chain link
+// every recordingChainChunkSize bytes.
+func (w *ChainWriter) Write(data []byte) (int, error) {
+	written := len(data)
+	for len(data) > 0 {
+		if w.pending == 0 {
+			w.digest.Reset()
+			w.digest.Write(w.prev)
+		}
+		n := recordingChainChunkSize - w.pending
+		if n > len(data) {
+			n = len(data)
+		}
+		w.digest.Write(data[:n])
+		w.pending += n
+		data = data[n:]
+		if w.pending == recordingChainChunkSize {
+			w.closeLink()
+		}
+	}
+	return written, nil
+}
+
+// Links finalizes the chain, closing the link over any partial trailing
+// chunk, and returns all links.
+func (w *ChainWriter) Links() [][]byte {
+	if w.pending > 0 {
+		w.closeLink()
+	}
+	return w.links
+}
+
+func (w *ChainWriter) closeLink() {
+	w.prev = w.digest.Sum(nil)
+	w.links = append(w.links, w.prev)
+	w.pending = 0
+}
+
+// ComputeRecordingChain reads a completed session recording and returns
+// its hash chain.
+func ComputeRecordingChain(reader io.Reader) ([][]byte, error) {
+	writer := NewChainWriter()
+	if _, err := io.Copy(writer, reader); err != nil {
+		return nil, trace.ConvertSystemError(err)
+	}
+	return writer.Links(), nil
+}
+
+// WriteRecordingChain stores the hash chain next to the recording, one hex
+// encoded link per line.
+func WriteRecordingChain(path string, links [][]byte) error {
+	file, err := os.OpenFile(path, os.O_WRONLY|os.O_CREATE|os.O_TRUNC, 0600)
+	if err != nil {
+		return trace.ConvertSystemError(err)
+	}
+	writer := bufio.NewWriter(file)
+	for _, link := range links {
+		if _, err := writer.WriteString(hex.EncodeToString(link) + "\n"); err != nil {
+			return trace.NewAggregate(trace.ConvertSystemError(err), file.Close())
+		}
+	}
+	return trace.NewAggregate(writer.Flush(), file.Close())
+}
+
+// ReadRecordingChain loads a hash chain written by WriteRecordingChain.
+func ReadRecordingChain(path string) ([][]byte, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, trace.ConvertSystemError(err)
+	}
+	defer file.Close()
+	var links [][]byte
+	scanner := bufio.NewScanner(file)
+	for scanner.Scan() {
+		link, err := hex.DecodeString(scanner.Text())
+		if err != nil {
+			return nil, trace.BadParameter("corrupted chain file %v: %v", path, err)
+		}
+		links = append(links, link)
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, trace.ConvertSystemError(err)
+	}
+	return links, nil
+}
+
+// VerifyRecordingChain recomputes the hash chain over the recording and
+// compares it to the stored one. It returns the zero-based index of the
+// first chunk that fails verification, or -1 if the chain is intact.
+func VerifyRecordingChain(reader io.Reader, links [][]byte) (int, error) {
+	computed, err := ComputeRecordingChain(reader)
+	if err != nil {
+		return 0, trace.Wrap(err)
+	}
+	for i := range computed {
+		if i >= len(links) || subtle.ConstantTimeCompare(computed[i], links[i]) != 1 {
+			return i, nil
+		}
+	}
+	if len(links) > len(computed) {
+		// The stored chain is longer than the recording: data was truncated.
+		return len(computed), nil
+	}
+	return -1, nil
+}
@@ -0,0 +1,64 @@
+/*
+Copyright 2021 Gravitational, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package events
+
+import (
+	"bytes"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+// TestRecordingChain checks the hash chain round trip and that tampering
+// with any chunk of the recording is detected.
+func TestRecordingChain(t *testing.T) {
+	dir, err := ioutil.TempDir("", "chain-test")
+	require.NoError(t, err)
+	defer os.RemoveAll(dir)
+
+	// Three full chunks and a partial one.
+	recording := bytes.Repeat([]byte{0xde, 0xad, 0xbe, 0xef}, recordingChainChunkSize)[:recordingChainChunkSize*3+100]
+	links, err := ComputeRecordingChain(bytes.NewReader(recording))
+	require.NoError(t, err)
+	require.Len(t, links, 4)
+
+	chainPath := filepath.Join(dir, "recording.tar"+RecordingChainSuffix)
+	require.NoError(t, WriteRecordingChain(chainPath, links))
+	stored, err := ReadRecordingChain(chainPath)
+	require.NoError(t, err)
+	require.Equal(t, links, stored)
+
+	// Intact recording verifies.
+	mismatch, err := VerifyRecordingChain(bytes.NewReader(recording), stored)
+	require.NoError(t, err)
+	require.Equal(t, -1, mismatch)
+
+	// Flipping a byte in the third chunk fails at chunk 2.
+	tampered := append([]byte{}, recording...)
+	tampered[recordingChainChunkSize*2+7] ^= 0xff
+	mismatch, err = VerifyRecordingChain(bytes.NewReader(tampered), stored)
+	require.NoError(t, err)
+	require.Equal(t, 2, mismatch)
+
+	// Truncating the recording is detected too.
+	mismatch, err = VerifyRecordingChain(bytes.NewReader(recording[:recordingChainChunkSize]), stored)
+	require.NoError(t, err)
+	require.Equal(t, 1, mismatch)
+}
@@ -0,0 +1,166 @@
+/*
+Copyright 2021 Gravitational, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package events
+
+import (
+	"archive/tar"
+	"compress/gzip"
+	"crypto"
+	"crypto/rsa"
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/hex"
+	"encoding/json"
+	"encoding/pem"
+	"io"
+	"io/ioutil"
+	"os"
+	"time"
+
+	"github.com/gravitational/trace"
+)
+
+// Audit archive member names. The archive is a gzipped tarball holding the
+// exported events, a manifest describing them, a signature over the
+// manifest and the public key the signature verifies with.
+const (
+	auditArchiveEventsFile    = "events.json"
+	auditArchiveManifestFile  = "manifest.json"
+	auditArchiveSignatureFile = "manifest.sig"
+	auditArchivePublicKeyFile = "signer.pub"
+)
+
+// AuditArchiveManifest describes the contents of a signed audit export
+// archive.
+type AuditArchiveManifest struct {
+	// ClusterName is the name of the cluster the events were exported from.
+	ClusterName string `json:"cluster_name"`
+	// From is the start of the exported time range.
+	From time.Time `json:"from"`
+	// To is the end of the exported time range.
+	To time.Time `json:"to"`
+	// EventCount is the number of exported events.
+	EventCount int `json:"event_count"`
+	// EventsDigest is the hex encoded SHA256 digest of the events file.
+	EventsDigest string `json:"events_digest"`
+	// ExportedAt is the time the archive was produced.
+	ExportedAt time.Time `json:"exported_at"`
+}
+
+// WriteAuditArchive writes a signed audit export archive. The signature
+// covers the SHA256 digest of the manifest, which in turn pins the digest
+// of the events file.
+func WriteAuditArchive(path string, eventsData, manifestData, signature, publicKey []byte) (err error) {
+	file, err := os.OpenFile(path, os.O_WRONLY|os.O_CREATE|os.O_TRUNC, 0600)
+	if err != nil {
+		return trace.ConvertSystemError(err)
+	}
+	defer func() {
+		err = trace.NewAggregate(err, file.Close())
+	}()
+	gzWriter := gzip.NewWriter(file)
+	tarWriter := tar.NewWriter(gzWriter)
+	now := time.Now().UTC()
+	for _, member := range []struct {
+		name string
+		data []byte
+	}{
+		{auditArchiveEventsFile, eventsData},
+		{auditArchiveManifestFile, manifestData},
+		{auditArchiveSignatureFile, signature},
+		{auditArchivePublicKeyFile, publicKey},
+	} {
+		err := tarWriter.WriteHeader(&tar.Header{
+			Name:    member.name,
+			Mode:    0600,
+			Size:    int64(len(member.data)),
+			ModTime: now,
+		})
+		if err != nil {
+			return trace.Wrap(err)
+		}
+		if _, err := tarWriter.Write(member.data); err != nil {
+			return trace.Wrap(err)
+		}
+	}
+	return trace.NewAggregate(tarWriter.Close(), gzWriter.Close())
+}
+
+// VerifyAuditArchive checks the integrity of a signed audit export
+// archive: the signature must verify the manifest with the bundled public
+// key, and the events file must match the digest pinned in the manifest.
+// The caller is expected to compare the returned public key against the
+// cluster host CA out of band.
+func VerifyAuditArchive(path string) (*AuditArchiveManifest, []byte, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, nil, trace.ConvertSystemError(err)
+	}
+	defer file.Close()
+	gzReader, err := gzip.NewReader(file)
+	if err != nil {
+		return nil, nil, trace.Wrap(err)
+	}
+	members := make(map[string][]byte)
+	tarReader := tar.NewReader(gzReader)
+	for {
+		header, err := tarReader.Next()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, nil, trace.Wrap(err)
+		}
+		data, err := ioutil.ReadAll(tarReader)
+		if err != nil {
+			return nil, nil, trace.Wrap(err)
+		}
+		members[header.Name] = data
+	}
+	for _, name := range []string{auditArchiveEventsFile, auditArchiveManifestFile, auditArchiveSignatureFile, auditArchivePublicKeyFile} {
+		if _, ok := members[name]; !ok {
+			return nil, nil, trace.BadParameter("archive is missing %v", name)
+		}
+	}
+
+	block, _ := pem.Decode(members[auditArchivePublicKeyFile])
+	if block == nil {
+		return nil, nil, trace.BadParameter("failed to decode the bundled public key")
+	}
+	publicKey, err := x509.ParsePKIXPublicKey(block.Bytes)
+	if err != nil {
+		return nil, nil, trace.Wrap(err)
+	}
+	rsaKey, ok := publicKey.(*rsa.PublicKey)
+	if !ok {
+		return nil, nil, trace.BadParameter("unsupported public key type %T", publicKey)
+	}
+	manifestDigest := sha256.Sum256(members[auditArchiveManifestFile])
+	if err := rsa.VerifyPKCS1v15(rsaKey, crypto.SHA256, manifestDigest[:], members[auditArchiveSignatureFile]); err != nil {
+		return nil, nil, trace.CompareFailed("archive signature does not verify: %v", err)
+	}
+
+	var manifest AuditArchiveManifest
+	if err := json.Unmarshal(members[auditArchiveManifestFile], &manifest); err != nil {
+		return nil, nil, trace.Wrap(err)
+	}
+	eventsDigest := sha256.Sum256(members[auditArchiveEventsFile])
+	if hex.EncodeToString(eventsDigest[:]) != manifest.EventsDigest {
+		return nil, nil, trace.CompareFailed("events file does not match the digest in the signed manifest")
+	}
+	return &manifest, members[auditArchivePublicKeyFile], nil
+}
@@ -264,7 +264,7 @@ func (u *Uploader) Scan() (*ScanStats, error) {
 			continue
 		}
 		ext := filepath.Ext(fi.Name())
-		if ext == checkpointExt || ext == errorExt {
+		if ext == checkpointExt || ext == errorExt || ext == events.RecordingChainSuffix {
 			continue
 		}
 		stats.Scanned++
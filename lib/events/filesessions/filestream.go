@@ -148,10 +148,18 @@ func (h *Handler) CompleteUpload(ctx context.Context, upload events.StreamUpload
 		readers = append(readers, file)
 	}
 
-	_, err = io.Copy(f, io.MultiReader(readers...))
+	// The hash chain is computed as the recording is assembled and stored
+	// next to it for later tamper detection. A failure to store it is
+	// logged instead of failing the upload, a recording without a chain
+	// beats a lost recording.
+	chain := events.NewChainWriter()
+	_, err = io.Copy(io.MultiWriter(f, chain), io.MultiReader(readers...))
 	if err != nil {
 		return trace.Wrap(err)
 	}
+	if err := events.WriteRecordingChain(uploadPath+events.RecordingChainSuffix, chain.Links()); err != nil {
+		h.WithError(err).Warningf("Failed to write recording hash chain for %v.", upload.SessionID)
+	}
 
 	err = h.Config.OnBeforeComplete(ctx, upload)
 	if err != nil {
@@ -0,0 +1,115 @@
+/*
+Copyright 2021 Gravitational, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package events
+
+import (
+	"sort"
+
+	apievents "github.com/gravitational/teleport/api/types/events"
+
+	"github.com/gravitational/trace"
+)
+
+// eventsMap is the audit event schema registry: it maps every known event
+// type string to a constructor of the concrete protobuf event struct that
+// carries its payload. Event emitters, storage backends and the gRPC one-of
+// conversion all agree on this schema.
+var eventsMap = map[string]func() apievents.AuditEvent{
+	SessionPrintEvent:     func() apievents.AuditEvent { return &apievents.SessionPrint{} },
+	SessionStartEvent:     func() apievents.AuditEvent { return &apievents.SessionStart{} },
+	SessionEndEvent:       func() apievents.AuditEvent { return &apievents.SessionEnd{} },
+	SessionUploadEvent:    func() apievents.AuditEvent { return &apievents.SessionUpload{} },
+	SessionJoinEvent:      func() apievents.AuditEvent { return &apievents.SessionJoin{} },
+	SessionLeaveEvent:     func() apievents.AuditEvent { return &apievents.SessionLeave{} },
+	SessionDataEvent:      func() apievents.AuditEvent { return &apievents.SessionData{} },
+	ClientDisconnectEvent: func() apievents.AuditEvent { return &apievents.ClientDisconnect{} },
+	UserLoginEvent:        func() apievents.AuditEvent { return &apievents.UserLogin{} },
+	UserDeleteEvent:       func() apievents.AuditEvent { return &apievents.UserDelete{} },
+	UserCreateEvent:       func() apievents.AuditEvent { return &apievents.UserCreate{} },
+	// user.update is a custom code applied on top of the same data as the
+	// user.create event and they are thus functionally identical. There
+	// exists no direct gRPC version of user.update.
+	UserUpdatedEvent:         func() apievents.AuditEvent { return &apievents.UserCreate{} },
+	UserPasswordChangeEvent:  func() apievents.AuditEvent { return &apievents.UserPasswordChange{} },
+	AccessRequestCreateEvent: func() apievents.AuditEvent { return &apievents.AccessRequestCreate{} },
+	// access_request.review is a custom code applied on top of the same data
+	// as the access_request.create event and they are thus functionally
+	// identical. There exists no direct gRPC version of access_request.review.
+	AccessRequestReviewEvent:       func() apievents.AuditEvent { return &apievents.AccessRequestCreate{} },
+	AccessRequestUpdateEvent:       func() apievents.AuditEvent { return &apievents.AccessRequestCreate{} },
+	BillingCardCreateEvent:         func() apievents.AuditEvent { return &apievents.BillingCardCreate{} },
+	BillingCardUpdateEvent:         func() apievents.AuditEvent { return &apievents.BillingCardCreate{} },
+	BillingCardDeleteEvent:         func() apievents.AuditEvent { return &apievents.BillingCardDelete{} },
+	BillingInformationUpdateEvent:  func() apievents.AuditEvent { return &apievents.BillingInformationUpdate{} },
+	ResetPasswordTokenCreateEvent:  func() apievents.AuditEvent { return &apievents.ResetPasswordTokenCreate{} },
+	ExecEvent:                      func() apievents.AuditEvent { return &apievents.Exec{} },
+	SubsystemEvent:                 func() apievents.AuditEvent { return &apievents.Subsystem{} },
+	X11ForwardEvent:                func() apievents.AuditEvent { return &apievents.X11Forward{} },
+	PortForwardEvent:               func() apievents.AuditEvent { return &apievents.PortForward{} },
+	AuthAttemptEvent:               func() apievents.AuditEvent { return &apievents.AuthAttempt{} },
+	SCPEvent:                       func() apievents.AuditEvent { return &apievents.SCP{} },
+	ResizeEvent:                    func() apievents.AuditEvent { return &apievents.Resize{} },
+	SessionCommandEvent:            func() apievents.AuditEvent { return &apievents.SessionCommand{} },
+	SessionDiskEvent:               func() apievents.AuditEvent { return &apievents.SessionDisk{} },
+	SessionNetworkEvent:            func() apievents.AuditEvent { return &apievents.SessionNetwork{} },
+	RoleCreatedEvent:               func() apievents.AuditEvent { return &apievents.RoleCreate{} },
+	RoleDeletedEvent:               func() apievents.AuditEvent { return &apievents.RoleDelete{} },
+	TrustedClusterCreateEvent:      func() apievents.AuditEvent { return &apievents.TrustedClusterCreate{} },
+	TrustedClusterDeleteEvent:      func() apievents.AuditEvent { return &apievents.TrustedClusterDelete{} },
+	TrustedClusterTokenCreateEvent: func() apievents.AuditEvent { return &apievents.TrustedClusterTokenCreate{} },
+	GithubConnectorCreatedEvent:    func() apievents.AuditEvent { return &apievents.GithubConnectorCreate{} },
+	GithubConnectorDeletedEvent:    func() apievents.AuditEvent { return &apievents.GithubConnectorDelete{} },
+	OIDCConnectorCreatedEvent:      func() apievents.AuditEvent { return &apievents.OIDCConnectorCreate{} },
+	OIDCConnectorDeletedEvent:      func() apievents.AuditEvent { return &apievents.OIDCConnectorDelete{} },
+	SAMLConnectorCreatedEvent:      func() apievents.AuditEvent { return &apievents.SAMLConnectorCreate{} },
+	SAMLConnectorDeletedEvent:      func() apievents.AuditEvent { return &apievents.SAMLConnectorDelete{} },
+	SessionRejectedEvent:           func() apievents.AuditEvent { return &apievents.SessionReject{} },
+	AppSessionStartEvent:           func() apievents.AuditEvent { return &apievents.AppSessionStart{} },
+	AppSessionChunkEvent:           func() apievents.AuditEvent { return &apievents.AppSessionChunk{} },
+	AppSessionRequestEvent:         func() apievents.AuditEvent { return &apievents.AppSessionRequest{} },
+	DatabaseSessionStartEvent:      func() apievents.AuditEvent { return &apievents.DatabaseSessionStart{} },
+	DatabaseSessionEndEvent:        func() apievents.AuditEvent { return &apievents.DatabaseSessionEnd{} },
+	DatabaseSessionQueryEvent:      func() apievents.AuditEvent { return &apievents.DatabaseSessionQuery{} },
+	// db.session.query.failed is a custom code applied on top of the same
+	// data as the db.session.query event. There exists no direct gRPC
+	// version of db.session.query.failed.
+	DatabaseSessionQueryFailedEvent: func() apievents.AuditEvent { return &apievents.DatabaseSessionQuery{} },
+	KubeRequestEvent:                func() apievents.AuditEvent { return &apievents.KubeRequest{} },
+	MFADeviceAddEvent:               func() apievents.AuditEvent { return &apievents.MFADeviceAdd{} },
+	MFADeviceDeleteEvent:            func() apievents.AuditEvent { return &apievents.MFADeviceDelete{} },
+}
+
+// NewEventFromType returns a new, empty audit event of the concrete type
+// registered for the given event type string.
+func NewEventFromType(eventType string) (apievents.AuditEvent, error) {
+	newEvent, ok := eventsMap[eventType]
+	if !ok {
+		return nil, trace.BadParameter("unknown event type: %q", eventType)
+	}
+	return newEvent(), nil
+}
+
+// KnownEventTypes returns the sorted list of all audit event types in the
+// schema registry.
+func KnownEventTypes() []string {
+	types := make([]string, 0, len(eventsMap))
+	for eventType := range eventsMap {
+		types = append(types, eventType)
+	}
+	sort.Strings(types)
+	return types
+}
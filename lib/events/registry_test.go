@@ -0,0 +1,116 @@
+/*
+Copyright 2021 Gravitational, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package events
+
+import (
+	"testing"
+
+	apievents "github.com/gravitational/teleport/api/types/events"
+
+	"github.com/gravitational/trace"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNewEventFromType(t *testing.T) {
+	event, err := NewEventFromType(SessionStartEvent)
+	require.NoError(t, err)
+	require.IsType(t, &apievents.SessionStart{}, event)
+
+	// user.update shares the user.create schema.
+	event, err = NewEventFromType(UserUpdatedEvent)
+	require.NoError(t, err)
+	require.IsType(t, &apievents.UserCreate{}, event)
+
+	// Every constructor returns a fresh event, not a shared instance.
+	other, err := NewEventFromType(SessionStartEvent)
+	require.NoError(t, err)
+	first, err := NewEventFromType(SessionStartEvent)
+	require.NoError(t, err)
+	require.NotSame(t, first, other)
+
+	_, err = NewEventFromType("no.such.event")
+	require.True(t, trace.IsBadParameter(err))
+}
+
+func TestKnownEventTypes(t *testing.T) {
+	types := KnownEventTypes()
+	require.Len(t, types, len(eventsMap))
+	require.Contains(t, types, SessionStartEvent)
+	require.IsIncreasing(t, types)
+
+	// The registry must cover every event type the FromEventFields switch
+	// handled before it was replaced, or searches of audit logs holding
+	// such events start failing with "unknown event type".
+	legacySwitchTypes := []string{
+		SessionPrintEvent,
+		SessionStartEvent,
+		SessionEndEvent,
+		SessionUploadEvent,
+		SessionJoinEvent,
+		SessionLeaveEvent,
+		SessionDataEvent,
+		ClientDisconnectEvent,
+		UserLoginEvent,
+		UserDeleteEvent,
+		UserCreateEvent,
+		UserUpdatedEvent,
+		UserPasswordChangeEvent,
+		AccessRequestCreateEvent,
+		AccessRequestReviewEvent,
+		AccessRequestUpdateEvent,
+		BillingCardCreateEvent,
+		BillingCardUpdateEvent,
+		BillingCardDeleteEvent,
+		BillingInformationUpdateEvent,
+		ResetPasswordTokenCreateEvent,
+		ExecEvent,
+		SubsystemEvent,
+		X11ForwardEvent,
+		PortForwardEvent,
+		AuthAttemptEvent,
+		SCPEvent,
+		ResizeEvent,
+		SessionCommandEvent,
+		SessionDiskEvent,
+		SessionNetworkEvent,
+		RoleCreatedEvent,
+		RoleDeletedEvent,
+		TrustedClusterCreateEvent,
+		TrustedClusterDeleteEvent,
+		TrustedClusterTokenCreateEvent,
+		GithubConnectorCreatedEvent,
+		GithubConnectorDeletedEvent,
+		OIDCConnectorCreatedEvent,
+		OIDCConnectorDeletedEvent,
+		SAMLConnectorCreatedEvent,
+		SAMLConnectorDeletedEvent,
+		SessionRejectedEvent,
+		AppSessionStartEvent,
+		AppSessionChunkEvent,
+		AppSessionRequestEvent,
+		DatabaseSessionStartEvent,
+		DatabaseSessionEndEvent,
+		DatabaseSessionQueryEvent,
+		DatabaseSessionQueryFailedEvent,
+		KubeRequestEvent,
+		MFADeviceAddEvent,
+		MFADeviceDeleteEvent,
+	}
+	for _, eventType := range legacySwitchTypes {
+		require.Contains(t, types, eventType)
+	}
+}
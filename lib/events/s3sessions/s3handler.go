@@ -58,6 +58,16 @@ type Config struct {
 	Session *awssession.Session
 	// Credentials if supplied are used in tests
 	Credentials *credentials.Credentials
+
+	// StorageClass, when set, uploads recordings with the given S3
+	// storage class (e.g. STANDARD_IA or INTELLIGENT_TIERING), letting
+	// bucket lifecycle rules transition them to colder tiers.
+	StorageClass string
+
+	// LifecycleTag, when set, tags every uploaded recording with
+	// lifecycle=<value> so bucket lifecycle rules can target recordings
+	// for Glacier transitions without affecting other objects.
+	LifecycleTag string
 }
 
 // SetFromURL sets values on the Config from the supplied URI
@@ -69,6 +79,12 @@ func (s *Config) SetFromURL(in *url.URL, inRegion string) error {
 	if endpoint := in.Query().Get(teleport.Endpoint); endpoint != "" {
 		s.Endpoint = endpoint
 	}
+	if storageClass := in.Query().Get("storageClass"); storageClass != "" {
+		s.StorageClass = storageClass
+	}
+	if lifecycleTag := in.Query().Get("lifecycleTag"); lifecycleTag != "" {
+		s.LifecycleTag = lifecycleTag
+	}
 	if val := in.Query().Get(teleport.Insecure); val != "" {
 		insecure, err := strconv.ParseBool(val)
 		if err != nil {
@@ -177,6 +193,12 @@ func (h *Handler) Upload(ctx context.Context, sessionID session.ID, reader io.Re
 	if !h.Config.DisableServerSideEncryption {
 		uploadInput.ServerSideEncryption = aws.String(s3.ServerSideEncryptionAwsKms)
 	}
+	if h.Config.StorageClass != "" {
+		uploadInput.StorageClass = aws.String(h.Config.StorageClass)
+	}
+	if h.Config.LifecycleTag != "" {
+		uploadInput.Tagging = aws.String("lifecycle=" + h.Config.LifecycleTag)
+	}
 	_, err = h.uploader.UploadWithContext(ctx, uploadInput)
 	if err != nil {
 		return "", ConvertS3Error(err)
@@ -203,6 +225,16 @@ func (h *Handler) Download(ctx context.Context, sessionID session.ID, writer io.
 		VersionId: aws.String(versionID),
 	})
 	if err != nil {
+		// Recordings transitioned to a cold tier cannot be read until
+		// they are restored: kick off the restore and tell the user to
+		// retry, S3 restores take minutes to hours depending on tier.
+		if isArchivedObjectError(err) {
+			if restoreErr := h.restoreFromColdStorage(ctx, versionID, sessionID); restoreErr != nil {
+				return trace.Wrap(restoreErr)
+			}
+			return trace.LimitExceeded(
+				"session recording %v is archived in cold storage, a restore was requested: retry playback in a few minutes (expedited) to several hours (deep archive)", sessionID)
+		}
 		return ConvertS3Error(err)
 	}
 	if written == 0 {
@@ -211,6 +243,37 @@ func (h *Handler) Download(ctx context.Context, sessionID session.ID, writer io.
 	return nil
 }
 
+// isArchivedObjectError detects the S3 error returned when reading an
+// object stored in Glacier or another archival tier.
+func isArchivedObjectError(err error) bool {
+	if requestErr, ok := err.(awserr.Error); ok {
+		return requestErr.Code() == "InvalidObjectState"
+	}
+	return false
+}
+
+// restoreFromColdStorage requests an S3 restore of an archived
+// recording. Repeated requests while a restore is in progress are
+// tolerated.
+func (h *Handler) restoreFromColdStorage(ctx context.Context, versionID string, sessionID session.ID) error {
+	h.Infof("Requesting cold storage restore of recording %v.", sessionID)
+	_, err := h.client.RestoreObjectWithContext(ctx, &s3.RestoreObjectInput{
+		Bucket:    aws.String(h.Bucket),
+		Key:       aws.String(h.path(sessionID)),
+		VersionId: aws.String(versionID),
+		RestoreRequest: &s3.RestoreRequest{
+			Days: aws.Int64(1),
+		},
+	})
+	if err != nil {
+		if requestErr, ok := err.(awserr.Error); ok && requestErr.Code() == "RestoreAlreadyInProgress" {
+			return nil
+		}
+		return ConvertS3Error(err)
+	}
+	return nil
+}
+
 // versionID is used to store versions of a key to allow sorting by timestamp.
 type versionID struct {
 	// ID is the version ID.
@@ -48,6 +48,12 @@ func (h *Handler) CreateUpload(ctx context.Context, sessionID session.ID) (*even
 	if !h.Config.DisableServerSideEncryption {
 		input.ServerSideEncryption = aws.String(s3.ServerSideEncryptionAwsKms)
 	}
+	if h.Config.StorageClass != "" {
+		input.StorageClass = aws.String(h.Config.StorageClass)
+	}
+	if h.Config.LifecycleTag != "" {
+		input.Tagging = aws.String("lifecycle=" + h.Config.LifecycleTag)
+	}
 
 	resp, err := h.client.CreateMultipartUploadWithContext(ctx, input)
 	if err != nil {
@@ -0,0 +1,257 @@
+/*
+Copyright 2021 Gravitational, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package events
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"net/http"
+	"net/url"
+	"time"
+
+	"github.com/gravitational/teleport"
+	apidefaults "github.com/gravitational/teleport/api/defaults"
+	apievents "github.com/gravitational/teleport/api/types/events"
+	"github.com/gravitational/teleport/lib/utils"
+
+	"github.com/gravitational/trace"
+	log "github.com/sirupsen/logrus"
+)
+
+const (
+	// WebhookSignatureHeader carries the hex encoded HMAC-SHA256 of the
+	// payload, computed with the webhook secret.
+	WebhookSignatureHeader = "X-Teleport-Signature"
+	// webhookAttempts is how many times a delivery is attempted before the
+	// event is dead-lettered.
+	webhookAttempts = 3
+	// webhookRetryInterval is the initial backoff between delivery
+	// attempts, doubled after every failure.
+	webhookRetryInterval = time.Second
+	// webhookQueueSize bounds the number of deliveries waiting for the
+	// sender. Deliveries are dropped with a warning when the queue is
+	// full, webhooks must not be able to back-pressure the audit path.
+	webhookQueueSize = 1024
+)
+
+// WebhookConfig describes a single webhook target.
+type WebhookConfig struct {
+	// URL is the endpoint the payloads are POSTed to.
+	URL string
+	// Events is the list of event types to deliver. An empty list
+	// subscribes to all events.
+	Events []string
+	// Secret, when set, is used to compute the payload HMAC signature.
+	Secret string
+}
+
+// CheckAndSetDefaults validates the webhook configuration.
+func (w *WebhookConfig) CheckAndSetDefaults() error {
+	u, err := url.Parse(w.URL)
+	if err != nil {
+		return trace.BadParameter("invalid webhook url %q: %v", w.URL, err)
+	}
+	if u.Scheme != "http" && u.Scheme != "https" {
+		return trace.BadParameter("webhook url %q must use http or https", w.URL)
+	}
+	return nil
+}
+
+// webhookPost is a single payload on its way to a webhook target. The
+// description identifies the payload in dead-letter log records.
+type webhookPost struct {
+	url         string
+	secret      string
+	payload     []byte
+	description string
+}
+
+// webhookSender queues payloads and POSTs them with retries. It is shared
+// by the audit webhooks and the access request notifiers.
+type webhookSender struct {
+	client *http.Client
+	queue  chan webhookPost
+	cancel context.CancelFunc
+	log    *log.Entry
+}
+
+// newWebhookSender starts a sender delivering queued payloads.
+func newWebhookSender() *webhookSender {
+	ctx, cancel := context.WithCancel(context.Background())
+	s := &webhookSender{
+		client: &http.Client{
+			Timeout: apidefaults.DefaultDialTimeout,
+		},
+		queue:  make(chan webhookPost, webhookQueueSize),
+		cancel: cancel,
+		log: log.WithFields(log.Fields{
+			trace.Component: teleport.ComponentAuditLog,
+		}),
+	}
+	go s.run(ctx)
+	return s
+}
+
+// enqueue adds a payload to the delivery queue, dropping it with a
+// warning when the queue is full: webhooks must not be able to
+// back-pressure the audit path.
+func (s *webhookSender) enqueue(post webhookPost) {
+	select {
+	case s.queue <- post:
+	default:
+		s.log.Warningf("Webhook queue is full, dropping %v for %v.", post.description, post.url)
+	}
+}
+
+// Close stops the sender. Queued deliveries are dropped.
+func (s *webhookSender) Close() error {
+	s.cancel()
+	return nil
+}
+
+// WebhookEmitter delivers audit events to configured webhook targets. It
+// implements apievents.Emitter and always reports success: deliveries are
+// asynchronous and failures are logged, never propagated to the audit
+// path.
+type WebhookEmitter struct {
+	hooks  []WebhookConfig
+	sender *webhookSender
+}
+
+// NewWebhookEmitter returns an emitter delivering matching events to the
+// configured webhooks.
+func NewWebhookEmitter(hooks []WebhookConfig) (*WebhookEmitter, error) {
+	if len(hooks) == 0 {
+		return nil, trace.BadParameter("missing webhook configuration")
+	}
+	for i := range hooks {
+		if err := hooks[i].CheckAndSetDefaults(); err != nil {
+			return nil, trace.Wrap(err)
+		}
+	}
+	return &WebhookEmitter{
+		hooks:  hooks,
+		sender: newWebhookSender(),
+	}, nil
+}
+
+// EmitAuditEvent queues the event for delivery to every webhook
+// subscribed to its type.
+func (w *WebhookEmitter) EmitAuditEvent(ctx context.Context, event apievents.AuditEvent) error {
+	var payload []byte
+	for _, hook := range w.hooks {
+		if !webhookMatch(hook, event.GetType()) {
+			continue
+		}
+		if payload == nil {
+			var err error
+			payload, err = utils.FastMarshal(event)
+			if err != nil {
+				w.sender.log.WithError(err).Warningf("Failed to marshal event %v for webhook delivery.", event.GetType())
+				return nil
+			}
+		}
+		w.sender.enqueue(webhookPost{
+			url:         hook.URL,
+			secret:      hook.Secret,
+			payload:     payload,
+			description: fmt.Sprintf("event %v (%v)", event.GetType(), event.GetID()),
+		})
+	}
+	return nil
+}
+
+// Close stops the delivery sender. Queued deliveries are dropped.
+func (w *WebhookEmitter) Close() error {
+	return w.sender.Close()
+}
+
+// run delivers queued payloads one at a time, retrying with backoff.
+func (s *webhookSender) run(ctx context.Context) {
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case post := <-s.queue:
+			s.deliver(ctx, post)
+		}
+	}
+}
+
+// deliver POSTs the payload, retrying on failure. Payloads that cannot be
+// delivered after all attempts are dead-lettered into the log.
+func (s *webhookSender) deliver(ctx context.Context, post webhookPost) {
+	interval := webhookRetryInterval
+	for attempt := 1; attempt <= webhookAttempts; attempt++ {
+		err := s.post(ctx, post)
+		if err == nil {
+			return
+		}
+		if attempt == webhookAttempts {
+			s.log.WithError(err).Warningf("Dead-letter: failed to deliver %v to webhook %v after %v attempts.",
+				post.description, post.url, webhookAttempts)
+			return
+		}
+		select {
+		case <-ctx.Done():
+			return
+		case <-time.After(interval):
+		}
+		interval *= 2
+	}
+}
+
+// post performs a single delivery attempt.
+func (s *webhookSender) post(ctx context.Context, post webhookPost) error {
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, post.url, bytes.NewReader(post.payload))
+	if err != nil {
+		return trace.Wrap(err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+	if post.secret != "" {
+		mac := hmac.New(sha256.New, []byte(post.secret))
+		mac.Write(post.payload)
+		req.Header.Set(WebhookSignatureHeader, hex.EncodeToString(mac.Sum(nil)))
+	}
+	resp, err := s.client.Do(req)
+	if err != nil {
+		return trace.Wrap(err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		return trace.BadParameter("webhook %v returned status %v", post.url, resp.StatusCode)
+	}
+	return nil
+}
+
+// webhookMatch reports whether the hook subscribes to the event type. An
+// empty subscription list matches all events.
+func webhookMatch(hook WebhookConfig, event string) bool {
+	if len(hook.Events) == 0 {
+		return true
+	}
+	for _, t := range hook.Events {
+		if t == event {
+			return true
+		}
+	}
+	return false
+}
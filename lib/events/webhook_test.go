@@ -0,0 +1,140 @@
+/*
+Copyright 2021 Gravitational, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package events
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	apievents "github.com/gravitational/teleport/api/types/events"
+
+	"github.com/stretchr/testify/require"
+)
+
+// TestWebhookEmitter checks that subscribed events are delivered with a
+// valid HMAC signature and that unsubscribed events are filtered out.
+func TestWebhookEmitter(t *testing.T) {
+	type received struct {
+		body      []byte
+		signature string
+	}
+	deliveries := make(chan received, 2)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, err := ioutil.ReadAll(r.Body)
+		require.NoError(t, err)
+		deliveries <- received{body: body, signature: r.Header.Get(WebhookSignatureHeader)}
+	}))
+	defer server.Close()
+
+	emitter, err := NewWebhookEmitter([]WebhookConfig{{
+		URL:    server.URL,
+		Events: []string{SessionStartEvent},
+		Secret: "hunter2",
+	}})
+	require.NoError(t, err)
+	defer emitter.Close()
+
+	// A subscribed event is delivered and signed.
+	err = emitter.EmitAuditEvent(context.Background(), &apievents.SessionStart{
+		Metadata: apievents.Metadata{Type: SessionStartEvent, ID: "deadbeef"},
+	})
+	require.NoError(t, err)
+	select {
+	case delivery := <-deliveries:
+		mac := hmac.New(sha256.New, []byte("hunter2"))
+		mac.Write(delivery.body)
+		require.Equal(t, hex.EncodeToString(mac.Sum(nil)), delivery.signature)
+		require.Contains(t, string(delivery.body), SessionStartEvent)
+	case <-time.After(5 * time.Second):
+		t.Fatal("timeout waiting for webhook delivery")
+	}
+
+	// An unsubscribed event is filtered out.
+	err = emitter.EmitAuditEvent(context.Background(), &apievents.SessionEnd{
+		Metadata: apievents.Metadata{Type: SessionEndEvent, ID: "cafebabe"},
+	})
+	require.NoError(t, err)
+	select {
+	case <-deliveries:
+		t.Fatal("received a delivery for an unsubscribed event")
+	case <-time.After(100 * time.Millisecond):
+	}
+}
+
+// TestWebhookConfigCheck checks target URL validation.
+func TestWebhookConfigCheck(t *testing.T) {
+	hook := WebhookConfig{URL: "ftp://example.com/hook"}
+	require.Error(t, hook.CheckAndSetDefaults())
+	hook = WebhookConfig{URL: "https://example.com/hook"}
+	require.NoError(t, hook.CheckAndSetDefaults())
+}
+
+// TestAccessRequestNotifier checks Slack formatting of access request
+// notifications, including the approve/deny action links.
+func TestAccessRequestNotifier(t *testing.T) {
+	deliveries := make(chan []byte, 1)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, err := ioutil.ReadAll(r.Body)
+		require.NoError(t, err)
+		deliveries <- body
+	}))
+	defer server.Close()
+
+	notifier, err := NewAccessRequestNotifier([]AccessNotifyTarget{{
+		Type:        AccessNotifyTargetSlack,
+		WebhookURL:  server.URL,
+		RequestsURL: "https://teleport.example.com/web/requests",
+	}})
+	require.NoError(t, err)
+	defer notifier.Close()
+
+	err = notifier.EmitAuditEvent(context.Background(), &apievents.AccessRequestCreate{
+		Metadata:     apievents.Metadata{Type: AccessRequestCreateEvent},
+		UserMetadata: apievents.UserMetadata{User: "alice"},
+		Roles:        []string{"dbadmin"},
+		RequestID:    "req-1",
+		Reason:       "oncall",
+	})
+	require.NoError(t, err)
+	select {
+	case body := <-deliveries:
+		require.Contains(t, string(body), "alice")
+		require.Contains(t, string(body), "dbadmin")
+		require.Contains(t, string(body), "https://teleport.example.com/web/requests/req-1")
+		require.Contains(t, string(body), "Approve")
+	case <-time.After(5 * time.Second):
+		t.Fatal("timeout waiting for notification delivery")
+	}
+
+	// Unrelated events are ignored.
+	err = notifier.EmitAuditEvent(context.Background(), &apievents.SessionStart{
+		Metadata: apievents.Metadata{Type: SessionStartEvent},
+	})
+	require.NoError(t, err)
+	select {
+	case <-deliveries:
+		t.Fatal("received a delivery for an unrelated event")
+	case <-time.After(100 * time.Millisecond):
+	}
+}
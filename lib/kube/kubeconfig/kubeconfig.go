@@ -52,6 +52,9 @@ type ExecValues struct {
 	// SelectCluster is the name of the kubernetes cluster to set in
 	// current-context.
 	SelectCluster string
+	// Env is a map of environment variables to set for the exec plugin,
+	// e.g. a custom TELEPORT_HOME so the plugin finds the right profile.
+	Env map[string]string
 	// TshBinaryInsecure defines whether to set the --insecure flag in the tsh
 	// exec plugin arguments. This is used when the proxy doesn't have a
 	// trusted TLS cert during login.
@@ -96,6 +99,9 @@ func Update(path string, v Values) error {
 			if v.Exec.TshBinaryInsecure {
 				authInfo.Exec.Args = append(authInfo.Exec.Args, "--insecure")
 			}
+			for name, value := range v.Exec.Env {
+				authInfo.Exec.Env = append(authInfo.Exec.Env, clientcmdapi.ExecEnvVar{Name: name, Value: value})
+			}
 			config.AuthInfos[authName] = authInfo
 
 			setContext(config.Contexts, contextName, clusterName, authName)
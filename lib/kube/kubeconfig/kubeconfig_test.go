@@ -201,6 +201,40 @@ func (s *KubeconfigSuite) TestUpdate(c *check.C) {
 	c.Assert(config, check.DeepEquals, wantConfig)
 }
 
+func (s *KubeconfigSuite) TestUpdateWithExec(c *check.C) {
+	const (
+		clusterName = "teleport-cluster"
+		clusterAddr = "https://1.2.3.6:3080"
+		kubeCluster = "my-cluster"
+		homeEnvVar  = "TELEPORT_HOME"
+		home        = "/alt/home"
+	)
+	creds, _, err := s.genUserKey()
+	c.Assert(err, check.IsNil)
+	err = Update(s.kubeconfigPath, Values{
+		TeleportClusterName: clusterName,
+		ClusterAddr:         clusterAddr,
+		Credentials:         creds,
+		Exec: &ExecValues{
+			TshBinaryPath: "/path/to/tsh",
+			KubeClusters:  []string{kubeCluster},
+			Env:           map[string]string{homeEnvVar: home},
+		},
+	})
+	c.Assert(err, check.IsNil)
+
+	config, err := Load(s.kubeconfigPath)
+	c.Assert(err, check.IsNil)
+
+	contextName := ContextName(clusterName, kubeCluster)
+	authInfo := config.AuthInfos[contextName]
+	c.Assert(authInfo, check.NotNil)
+	c.Assert(authInfo.Exec, check.NotNil)
+	c.Assert(authInfo.Exec.Command, check.Equals, "/path/to/tsh")
+	// The custom tsh home is propagated to the exec plugin environment.
+	c.Assert(authInfo.Exec.Env, check.DeepEquals, []clientcmdapi.ExecEnvVar{{Name: homeEnvVar, Value: home}})
+}
+
 func (s *KubeconfigSuite) TestRemove(c *check.C) {
 	const (
 		clusterName = "teleport-cluster"
@@ -828,3 +828,45 @@ type mockAuthorizer struct {
 func (a mockAuthorizer) Authorize(context.Context) (*auth.Context, error) {
 	return a.ctx, a.err
 }
+
+func TestKubeRequestVerb(t *testing.T) {
+	tests := []struct {
+		method   string
+		name     string
+		wantVerb string
+	}{
+		{method: http.MethodGet, name: "", wantVerb: types.VerbList},
+		{method: http.MethodGet, name: "pod-1", wantVerb: types.VerbRead},
+		{method: http.MethodPost, name: "", wantVerb: types.VerbCreate},
+		{method: http.MethodPatch, name: "pod-1", wantVerb: types.VerbUpdate},
+		{method: http.MethodDelete, name: "pod-1", wantVerb: types.VerbDelete},
+	}
+	for _, tt := range tests {
+		require.Equal(t, tt.wantVerb, kubeRequestVerb(tt.method, tt.name))
+	}
+}
+
+func TestRoleSetMentionsKind(t *testing.T) {
+	role, err := types.NewRole("kube-rbac", types.RoleSpecV4{
+		Allow: types.RoleConditions{
+			Rules: []types.Rule{
+				types.NewRule(types.KindKubePod, []string{types.VerbRead, types.VerbList}),
+			},
+		},
+	})
+	require.NoError(t, err)
+	wildcard, err := types.NewRole("admin-like", types.RoleSpecV4{
+		Allow: types.RoleConditions{
+			Rules: []types.Rule{
+				types.NewRule(types.Wildcard, services.RW()),
+			},
+		},
+	})
+	require.NoError(t, err)
+
+	set := services.NewRoleSet(role)
+	require.True(t, roleSetMentionsKind(set, types.KindKubePod))
+	require.False(t, roleSetMentionsKind(set, types.KindKubeNamespace))
+	// Wildcard rules do not opt a user into resource-level checks.
+	require.False(t, roleSetMentionsKind(services.NewRoleSet(wildcard), types.KindKubePod))
+}
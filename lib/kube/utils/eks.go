@@ -0,0 +1,125 @@
+/*
+Copyright 2021 Gravitational, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package utils
+
+import (
+	"context"
+	"encoding/base64"
+
+	"github.com/gravitational/trace"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/session"
+	"github.com/aws/aws-sdk-go/service/eks"
+	"github.com/aws/aws-sdk-go/service/eks/eksiface"
+	"k8s.io/client-go/tools/clientcmd"
+	clientcmdapi "k8s.io/client-go/tools/clientcmd/api"
+)
+
+// EKSCluster describes an EKS cluster discovered through the AWS API.
+type EKSCluster struct {
+	// Name is the EKS cluster name
+	Name string
+	// Endpoint is the Kubernetes API endpoint of the cluster
+	Endpoint string
+	// CertificateAuthority is the PEM encoded cluster CA
+	CertificateAuthority []byte
+	// Region is the AWS region the cluster runs in
+	Region string
+}
+
+// DiscoverEKSClusters lists all active EKS clusters in the given region.
+func DiscoverEKSClusters(ctx context.Context, region string) ([]EKSCluster, error) {
+	awsSession, err := session.NewSessionWithOptions(session.Options{
+		SharedConfigState: session.SharedConfigEnable,
+		Config:            aws.Config{Region: aws.String(region)},
+	})
+	if err != nil {
+		return nil, trace.Wrap(err)
+	}
+	return discoverEKSClusters(ctx, eks.New(awsSession), region)
+}
+
+// discoverEKSClusters lists clusters using the provided EKS client, split
+// out so tests can pass a fake.
+func discoverEKSClusters(ctx context.Context, client eksiface.EKSAPI, region string) ([]EKSCluster, error) {
+	var clusters []EKSCluster
+	err := client.ListClustersPagesWithContext(ctx, &eks.ListClustersInput{},
+		func(page *eks.ListClustersOutput, lastPage bool) bool {
+			for _, name := range page.Clusters {
+				cluster, err := client.DescribeClusterWithContext(ctx, &eks.DescribeClusterInput{
+					Name: name,
+				})
+				if err != nil {
+					// Skip clusters we cannot describe instead of failing the
+					// whole discovery.
+					continue
+				}
+				if aws.StringValue(cluster.Cluster.Status) != eks.ClusterStatusActive {
+					continue
+				}
+				ca, err := base64.StdEncoding.DecodeString(
+					aws.StringValue(cluster.Cluster.CertificateAuthority.Data))
+				if err != nil {
+					continue
+				}
+				clusters = append(clusters, EKSCluster{
+					Name:                 aws.StringValue(cluster.Cluster.Name),
+					Endpoint:             aws.StringValue(cluster.Cluster.Endpoint),
+					CertificateAuthority: ca,
+					Region:               region,
+				})
+			}
+			return true
+		})
+	if err != nil {
+		return nil, trace.Wrap(err)
+	}
+	return clusters, nil
+}
+
+// WriteEKSKubeconfig writes a kubeconfig with a context per discovered EKS
+// cluster, authenticating through the standard "aws eks get-token" exec
+// plugin. The aws CLI must be available on the host.
+func WriteEKSKubeconfig(path string, clusters []EKSCluster) error {
+	config := clientcmdapi.NewConfig()
+	for _, cluster := range clusters {
+		config.Clusters[cluster.Name] = &clientcmdapi.Cluster{
+			Server:                   cluster.Endpoint,
+			CertificateAuthorityData: cluster.CertificateAuthority,
+		}
+		config.AuthInfos[cluster.Name] = &clientcmdapi.AuthInfo{
+			Exec: &clientcmdapi.ExecConfig{
+				APIVersion: "client.authentication.k8s.io/v1alpha1",
+				Command:    "aws",
+				Args: []string{
+					"eks", "get-token",
+					"--region", cluster.Region,
+					"--cluster-name", cluster.Name,
+				},
+			},
+		}
+		config.Contexts[cluster.Name] = &clientcmdapi.Context{
+			Cluster:  cluster.Name,
+			AuthInfo: cluster.Name,
+		}
+		if config.CurrentContext == "" {
+			config.CurrentContext = cluster.Name
+		}
+	}
+	return trace.Wrap(clientcmd.WriteToFile(*config, path))
+}
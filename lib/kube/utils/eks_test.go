@@ -0,0 +1,77 @@
+/*
+Copyright 2021 Gravitational, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package utils
+
+import (
+	"context"
+	"encoding/base64"
+	"testing"
+
+	"github.com/aws/aws-sdk-go/aws"
+	awsrequest "github.com/aws/aws-sdk-go/aws/request"
+	"github.com/aws/aws-sdk-go/service/eks"
+	"github.com/aws/aws-sdk-go/service/eks/eksiface"
+
+	"github.com/stretchr/testify/require"
+)
+
+type fakeEKSClient struct {
+	eksiface.EKSAPI
+	clusters map[string]*eks.Cluster
+}
+
+func (f *fakeEKSClient) ListClustersPagesWithContext(ctx aws.Context, input *eks.ListClustersInput, fn func(*eks.ListClustersOutput, bool) bool, opts ...awsrequest.Option) error {
+	var names []*string
+	for name := range f.clusters {
+		names = append(names, aws.String(name))
+	}
+	fn(&eks.ListClustersOutput{Clusters: names}, true)
+	return nil
+}
+
+func (f *fakeEKSClient) DescribeClusterWithContext(ctx aws.Context, input *eks.DescribeClusterInput, opts ...awsrequest.Option) (*eks.DescribeClusterOutput, error) {
+	return &eks.DescribeClusterOutput{Cluster: f.clusters[aws.StringValue(input.Name)]}, nil
+}
+
+func TestDiscoverEKSClusters(t *testing.T) {
+	t.Parallel()
+
+	caPEM := []byte("-----BEGIN CERTIFICATE-----\nfake\n-----END CERTIFICATE-----")
+	client := &fakeEKSClient{clusters: map[string]*eks.Cluster{
+		"prod": {
+			Name:     aws.String("prod"),
+			Status:   aws.String(eks.ClusterStatusActive),
+			Endpoint: aws.String("https://prod.eks.example.com"),
+			CertificateAuthority: &eks.Certificate{
+				Data: aws.String(base64.StdEncoding.EncodeToString(caPEM)),
+			},
+		},
+		"creating": {
+			Name:   aws.String("creating"),
+			Status: aws.String(eks.ClusterStatusCreating),
+		},
+	}}
+
+	clusters, err := discoverEKSClusters(context.Background(), client, "us-west-2")
+	require.NoError(t, err)
+	// Only active clusters are discovered.
+	require.Len(t, clusters, 1)
+	require.Equal(t, "prod", clusters[0].Name)
+	require.Equal(t, "https://prod.eks.example.com", clusters[0].Endpoint)
+	require.Equal(t, caPEM, clusters[0].CertificateAuthority)
+	require.Equal(t, "us-west-2", clusters[0].Region)
+}
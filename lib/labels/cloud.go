@@ -0,0 +1,192 @@
+/*
+Copyright 2021 Gravitational, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package labels
+
+import (
+	"context"
+	"encoding/json"
+	"io/ioutil"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/gravitational/teleport/api/types"
+	"github.com/gravitational/trace"
+)
+
+const (
+	// awsPrefix is the label key prefix for tags imported from AWS
+	awsPrefix = "aws/"
+	// azurePrefix is the label key prefix for tags imported from Azure
+	azurePrefix = "azure/"
+	// gcpPrefix is the label key prefix for metadata imported from GCP
+	gcpPrefix = "gcp/"
+
+	// metadataEndpoint is the link-local address of the cloud instance
+	// metadata service on all supported clouds
+	metadataEndpoint = "http://169.254.169.254"
+
+	// cloudRequestTimeout bounds each request to the metadata service,
+	// which either answers within milliseconds or is not there at all
+	cloudRequestTimeout = 2 * time.Second
+)
+
+// ImportCloudTags detects whether this host runs on AWS, Azure or GCP by
+// probing the instance metadata service and, if so, returns the instance
+// tags as resource labels prefixed with the cloud provider name. An empty
+// map is returned when no metadata service is reachable.
+func ImportCloudTags(ctx context.Context) (map[string]string, error) {
+	return importCloudTags(ctx, metadataEndpoint)
+}
+
+// importCloudTags probes the metadata service at the given endpoint, it is
+// separate from ImportCloudTags so tests can point it at a fake server.
+func importCloudTags(ctx context.Context, endpoint string) (map[string]string, error) {
+	client := &http.Client{Timeout: cloudRequestTimeout}
+
+	if tags, err := fetchAWSTags(ctx, client, endpoint); err == nil {
+		return tags, nil
+	}
+	if tags, err := fetchAzureTags(ctx, client, endpoint); err == nil {
+		return tags, nil
+	}
+	if tags, err := fetchGCPAttributes(ctx, client, endpoint); err == nil {
+		return tags, nil
+	}
+	return map[string]string{}, nil
+}
+
+// fetchAWSTags fetches instance tags from the EC2 instance metadata
+// service using IMDSv2 session tokens. Tags must be made available in
+// instance metadata in the EC2 instance settings.
+func fetchAWSTags(ctx context.Context, client *http.Client, endpoint string) (map[string]string, error) {
+	// Acquire an IMDSv2 session token first.
+	req, err := http.NewRequestWithContext(ctx, http.MethodPut, endpoint+"/latest/api/token", nil)
+	if err != nil {
+		return nil, trace.Wrap(err)
+	}
+	req.Header.Set("X-aws-ec2-metadata-token-ttl-seconds", "300")
+	token, err := doReadBody(client, req)
+	if err != nil {
+		return nil, trace.Wrap(err)
+	}
+
+	keys, err := awsMetadataGet(ctx, client, endpoint, token, "/latest/meta-data/tags/instance")
+	if err != nil {
+		return nil, trace.Wrap(err)
+	}
+	labels := make(map[string]string)
+	for _, key := range strings.Split(strings.TrimSpace(keys), "\n") {
+		if key == "" {
+			continue
+		}
+		value, err := awsMetadataGet(ctx, client, endpoint, token, "/latest/meta-data/tags/instance/"+key)
+		if err != nil {
+			return nil, trace.Wrap(err)
+		}
+		addCloudLabel(labels, awsPrefix+key, value)
+	}
+	return labels, nil
+}
+
+// awsMetadataGet issues a GET to the EC2 metadata service with the IMDSv2
+// session token set.
+func awsMetadataGet(ctx context.Context, client *http.Client, endpoint, token, path string) (string, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, endpoint+path, nil)
+	if err != nil {
+		return "", trace.Wrap(err)
+	}
+	req.Header.Set("X-aws-ec2-metadata-token", token)
+	return doReadBody(client, req)
+}
+
+// fetchAzureTags fetches instance tags from the Azure instance metadata
+// service.
+func fetchAzureTags(ctx context.Context, client *http.Client, endpoint string) (map[string]string, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, endpoint+"/metadata/instance/compute/tagsList?api-version=2019-06-04", nil)
+	if err != nil {
+		return nil, trace.Wrap(err)
+	}
+	req.Header.Set("Metadata", "true")
+	body, err := doReadBody(client, req)
+	if err != nil {
+		return nil, trace.Wrap(err)
+	}
+	var tags []struct {
+		Name  string `json:"name"`
+		Value string `json:"value"`
+	}
+	if err := json.Unmarshal([]byte(body), &tags); err != nil {
+		return nil, trace.Wrap(err)
+	}
+	labels := make(map[string]string)
+	for _, tag := range tags {
+		addCloudLabel(labels, azurePrefix+tag.Name, tag.Value)
+	}
+	return labels, nil
+}
+
+// fetchGCPAttributes fetches custom instance attributes from the GCP
+// metadata service.
+func fetchGCPAttributes(ctx context.Context, client *http.Client, endpoint string) (map[string]string, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, endpoint+"/computeMetadata/v1/instance/attributes/?recursive=true", nil)
+	if err != nil {
+		return nil, trace.Wrap(err)
+	}
+	req.Header.Set("Metadata-Flavor", "Google")
+	body, err := doReadBody(client, req)
+	if err != nil {
+		return nil, trace.Wrap(err)
+	}
+	var attributes map[string]string
+	if err := json.Unmarshal([]byte(body), &attributes); err != nil {
+		return nil, trace.Wrap(err)
+	}
+	labels := make(map[string]string)
+	for name, value := range attributes {
+		addCloudLabel(labels, gcpPrefix+name, value)
+	}
+	return labels, nil
+}
+
+// addCloudLabel adds the label to the map if the key is valid, invalid
+// keys (e.g. tags with spaces in them) are silently skipped since tag
+// naming is outside of the operator's control.
+func addCloudLabel(labels map[string]string, key, value string) {
+	if !types.IsValidLabelKey(key) {
+		return
+	}
+	labels[key] = value
+}
+
+// doReadBody performs the request and returns the response body, treating
+// any non-200 response as an error.
+func doReadBody(client *http.Client, req *http.Request) (string, error) {
+	resp, err := client.Do(req)
+	if err != nil {
+		return "", trace.Wrap(err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return "", trace.BadParameter("metadata service returned %v", resp.Status)
+	}
+	body, err := ioutil.ReadAll(resp.Body)
+	if err != nil {
+		return "", trace.Wrap(err)
+	}
+	return string(body), nil
+}
@@ -0,0 +1,105 @@
+/*
+Copyright 2021 Gravitational, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package labels
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestImportAWSTags(t *testing.T) {
+	t.Parallel()
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch {
+		case r.Method == http.MethodPut && r.URL.Path == "/latest/api/token":
+			fmt.Fprint(w, "session-token")
+		case r.URL.Path == "/latest/meta-data/tags/instance":
+			require.Equal(t, "session-token", r.Header.Get("X-aws-ec2-metadata-token"))
+			fmt.Fprint(w, "Name\nenv\nbad key")
+		case r.URL.Path == "/latest/meta-data/tags/instance/Name":
+			fmt.Fprint(w, "web-1")
+		case r.URL.Path == "/latest/meta-data/tags/instance/env":
+			fmt.Fprint(w, "staging")
+		case r.URL.Path == "/latest/meta-data/tags/instance/bad key":
+			fmt.Fprint(w, "skipped")
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+	defer server.Close()
+
+	tags, err := importCloudTags(context.Background(), server.URL)
+	require.NoError(t, err)
+	// Tags with invalid label characters are skipped.
+	require.Equal(t, map[string]string{
+		"aws/Name": "web-1",
+		"aws/env":  "staging",
+	}, tags)
+}
+
+func TestImportAzureTags(t *testing.T) {
+	t.Parallel()
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/metadata/instance/compute/tagsList" {
+			require.Equal(t, "true", r.Header.Get("Metadata"))
+			fmt.Fprint(w, `[{"name":"team","value":"sre"}]`)
+			return
+		}
+		http.NotFound(w, r)
+	}))
+	defer server.Close()
+
+	tags, err := importCloudTags(context.Background(), server.URL)
+	require.NoError(t, err)
+	require.Equal(t, map[string]string{"azure/team": "sre"}, tags)
+}
+
+func TestImportGCPAttributes(t *testing.T) {
+	t.Parallel()
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/computeMetadata/v1/instance/attributes/" {
+			require.Equal(t, "Google", r.Header.Get("Metadata-Flavor"))
+			fmt.Fprint(w, `{"cluster":"prod"}`)
+			return
+		}
+		http.NotFound(w, r)
+	}))
+	defer server.Close()
+
+	tags, err := importCloudTags(context.Background(), server.URL)
+	require.NoError(t, err)
+	require.Equal(t, map[string]string{"gcp/cluster": "prod"}, tags)
+}
+
+func TestImportCloudTagsNoMetadataService(t *testing.T) {
+	t.Parallel()
+
+	server := httptest.NewServer(http.NotFoundHandler())
+	server.Close()
+
+	tags, err := importCloudTags(context.Background(), server.URL)
+	require.NoError(t, err)
+	require.Empty(t, tags)
+}
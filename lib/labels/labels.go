@@ -27,10 +27,17 @@ import (
 
 	"github.com/gravitational/teleport/api/types"
 	"github.com/gravitational/teleport/lib/services"
+	"github.com/gravitational/teleport/lib/utils"
 	"github.com/gravitational/trace"
 	"github.com/sirupsen/logrus"
 )
 
+const (
+	// labelMaxBackoffMultiplier caps the failure backoff at this multiple
+	// of the label update period
+	labelMaxBackoffMultiplier = 16
+)
+
 // DynamicConfig is the configuration for dynamic labels.
 type DynamicConfig struct {
 	// Labels is the list of dynamic labels to update.
@@ -129,24 +136,40 @@ func (l *Dynamic) Close() {
 	l.closeFunc()
 }
 
-// periodicUpdateLabel ticks at the update period defined for each label and
-// updates its value.
+// periodicUpdateLabel runs at the update period defined for each label and
+// updates its value. Periods are jittered so labels sharing a period don't
+// all run their commands at the same instant, and failing commands back
+// off exponentially to avoid hammering the host with a broken command.
 func (l *Dynamic) periodicUpdateLabel(name string, label types.CommandLabel) {
-	ticker := time.NewTicker(label.GetPeriod())
-	defer ticker.Stop()
+	jitter := utils.NewJitter()
+	period := label.GetPeriod()
+	backoff := period
 
 	for {
-		l.updateLabel(name, label.Clone())
+		if err := l.updateLabel(name, label.Clone()); err != nil {
+			// Back off exponentially on consecutive failures, up to a
+			// multiple of the configured period.
+			backoff *= 2
+			if max := period * labelMaxBackoffMultiplier; backoff > max {
+				backoff = max
+			}
+		} else {
+			backoff = period
+		}
+		wait := time.NewTimer(backoff - jitter(backoff/5))
 		select {
-		case <-ticker.C:
+		case <-wait.C:
 		case <-l.closeContext.Done():
+			wait.Stop()
 			return
 		}
 	}
 }
 
-// updateLabel will run a command, then update the value of a label.
-func (l *Dynamic) updateLabel(name string, label types.CommandLabel) {
+// updateLabel will run a command, then update the value of a label. The
+// returned error reports whether the command succeeded, the label value is
+// updated either way.
+func (l *Dynamic) updateLabel(name string, label types.CommandLabel) error {
 	out, err := exec.Command(label.GetCommand()[0], label.GetCommand()[1:]...).Output()
 	if err != nil {
 		l.c.Log.Errorf("Failed to run command and update label: %v.", err)
@@ -157,6 +180,7 @@ func (l *Dynamic) updateLabel(name string, label types.CommandLabel) {
 
 	// Perform the actual label update under a lock.
 	l.setLabel(name, label)
+	return trace.Wrap(err)
 }
 
 // setLabel updates the value of a particular label under a lock.
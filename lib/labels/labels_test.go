@@ -103,3 +103,22 @@ func (s *LabelSuite) TestInvalidCommand(c *check.C) {
 	c.Assert(ok, check.Equals, true)
 	c.Assert(strings.Contains(val.GetResult(), "output:"), check.Equals, true)
 }
+
+func (s *LabelSuite) TestUpdateError(c *check.C) {
+	// Create dynamic labels with a command that always fails.
+	label := &types.CommandLabelV2{
+		Period:  types.NewDuration(1 * time.Second),
+		Command: []string{"false"},
+	}
+	l, err := NewDynamic(context.Background(), &DynamicConfig{
+		Labels: map[string]types.CommandLabel{
+			"broken": label,
+		},
+	})
+	c.Assert(err, check.IsNil)
+
+	// A failing command reports an error so the update loop can back off,
+	// the label value still records the failure.
+	err = l.updateLabel("broken", label.Clone())
+	c.Assert(err, check.NotNil)
+}
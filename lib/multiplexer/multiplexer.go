@@ -300,6 +300,18 @@ func detect(conn net.Conn, enableProxyProtocol bool) (*Conn, error) {
 				return nil, trace.Wrap(err)
 			}
 			// repeat the cycle to detect the protocol
+		case ProtoProxyV2:
+			if !enableProxyProtocol {
+				return nil, trace.BadParameter("proxy protocol support is disabled")
+			}
+			if proxyLine != nil {
+				return nil, trace.BadParameter("duplicate proxy line")
+			}
+			proxyLine, err = ReadProxyLineV2(reader)
+			if err != nil {
+				return nil, trace.Wrap(err)
+			}
+			// repeat the cycle to detect the protocol
 		case ProtoTLS, ProtoSSH, ProtoHTTP:
 			return &Conn{
 				protocol:  proto,
@@ -331,6 +343,8 @@ const (
 	ProtoSSH
 	// ProtoProxy is a HAProxy proxy line protocol
 	ProtoProxy
+	// ProtoProxyV2 is a HAProxy binary protocol version 2
+	ProtoProxyV2
 	// ProtoHTTP is HTTP protocol
 	ProtoHTTP
 	// ProtoPostgres is PostgreSQL wire protocol
@@ -387,6 +401,9 @@ func detectProto(in []byte) (Protocol, error) {
 	// reader peeks only 3 bytes, slice the longer proxy prefix
 	case bytes.HasPrefix(in, proxyPrefix[:3]):
 		return ProtoProxy, nil
+	// reader peeks only 8 bytes, slice the longer v2 signature
+	case bytes.HasPrefix(in, proxyV2Prefix[:8]):
+		return ProtoProxyV2, nil
 	case bytes.HasPrefix(in, sshPrefix):
 		return ProtoSSH, nil
 	case bytes.HasPrefix(in, tlsPrefix):
@@ -184,6 +184,57 @@ func TestMux(t *testing.T) {
 		require.Equal(t, out, remoteAddr.String())
 	})
 
+	// ProxyLineV2 tests proxy protocol version 2
+	t.Run("ProxyLineV2", func(t *testing.T) {
+		listener, err := net.Listen("tcp", "127.0.0.1:0")
+		require.Nil(t, err)
+
+		mux, err := New(Config{
+			Listener:            listener,
+			EnableProxyProtocol: true,
+		})
+		require.Nil(t, err)
+		go mux.Serve()
+		defer mux.Close()
+
+		backend1 := &httptest.Server{
+			Listener: mux.TLS(),
+			Config: &http.Server{Handler: http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				fmt.Fprintf(w, r.RemoteAddr)
+			}),
+			},
+		}
+		backend1.StartTLS()
+		defer backend1.Close()
+
+		parsedURL, err := url.Parse(backend1.URL)
+		require.Nil(t, err)
+
+		conn, err := net.Dial("tcp", parsedURL.Host)
+		require.Nil(t, err)
+		defer conn.Close()
+		// send proxy protocol v2 header first before establishing TLS connection
+		_, err = conn.Write(append(proxyV2Prefix, []byte{
+			0x21, 0x11, // PROXY command, TCP over IPv4
+			0x00, 0x0C, // 12 bytes of address data follow
+			0x7F, 0x00, 0x00, 0x01, // source 127.0.0.1
+			0x7F, 0x00, 0x00, 0x01, // destination 127.0.0.1
+			0x1F, 0x40, // source port 8000
+			0x23, 0x28, // destination port 9000
+		}...))
+		require.Nil(t, err)
+
+		// upgrade connection to TLS
+		tlsConn := tls.Client(conn, clientConfig(backend1))
+		defer tlsConn.Close()
+
+		// make sure the TLS call succeeded and we got the client address
+		// from the proxy protocol v2 header
+		out, err := utils.RoundtripWithConn(tlsConn)
+		require.Nil(t, err)
+		require.Equal(t, out, "127.0.0.1:8000")
+	})
+
 	// TestDisabledProxy makes sure the connection gets dropped
 	// when Proxy line support protocol is turned off
 	t.Run("DisabledProxy", func(t *testing.T) {
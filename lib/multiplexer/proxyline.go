@@ -20,7 +20,10 @@ package multiplexer
 
 import (
 	"bufio"
+	"bytes"
+	"encoding/binary"
 	"fmt"
+	"io"
 	"net"
 	"strconv"
 	"strings"
@@ -122,3 +125,70 @@ func parseIP(protocol string, addrString string) (net.IP, error) {
 	}
 	return addr, nil
 }
+
+// proxyV2Prefix is the unique signature that starts every PROXY protocol
+// version 2 binary header
+var proxyV2Prefix = []byte{0x0D, 0x0A, 0x0D, 0x0A, 0x00, 0x0D, 0x0A, 0x51, 0x55, 0x49, 0x54, 0x0A}
+
+const (
+	// proxyV2CmdLocal is a connection established on behalf of the proxy
+	// itself (e.g. a health check), it carries no address information
+	proxyV2CmdLocal = 0x20
+	// proxyV2CmdProxy is a connection relayed on behalf of a client
+	proxyV2CmdProxy = 0x21
+
+	// proxyV2FamilyTCP4 is TCP over IPv4
+	proxyV2FamilyTCP4 = 0x11
+	// proxyV2FamilyTCP6 is TCP over IPv6
+	proxyV2FamilyTCP6 = 0x21
+)
+
+// ReadProxyLineV2 reads and parses the PROXY protocol version 2 binary
+// header from the reader. A nil proxy line with no error is returned for
+// LOCAL connections that carry no address information.
+func ReadProxyLineV2(reader *bufio.Reader) (*ProxyLine, error) {
+	var header [16]byte
+	if _, err := io.ReadFull(reader, header[:]); err != nil {
+		return nil, trace.ConvertSystemError(err)
+	}
+	if !bytes.Equal(header[:12], proxyV2Prefix) {
+		return nil, trace.BadParameter("unrecognized PROXY protocol version 2 signature")
+	}
+	length := binary.BigEndian.Uint16(header[14:16])
+	payload := make([]byte, length)
+	if _, err := io.ReadFull(reader, payload); err != nil {
+		return nil, trace.ConvertSystemError(err)
+	}
+
+	switch header[12] {
+	case proxyV2CmdLocal:
+		// LOCAL connections (e.g. load balancer health checks) carry no
+		// client address information.
+		return nil, nil
+	case proxyV2CmdProxy:
+	default:
+		return nil, trace.BadParameter("unsupported PROXY protocol version 2 command %#x", header[12])
+	}
+
+	ret := ProxyLine{}
+	switch header[13] {
+	case proxyV2FamilyTCP4:
+		if len(payload) < 12 {
+			return nil, trace.BadParameter("truncated PROXY protocol version 2 address block")
+		}
+		ret.Protocol = TCP4
+		ret.Source = net.TCPAddr{IP: net.IP(payload[0:4]), Port: int(binary.BigEndian.Uint16(payload[8:10]))}
+		ret.Destination = net.TCPAddr{IP: net.IP(payload[4:8]), Port: int(binary.BigEndian.Uint16(payload[10:12]))}
+	case proxyV2FamilyTCP6:
+		if len(payload) < 36 {
+			return nil, trace.BadParameter("truncated PROXY protocol version 2 address block")
+		}
+		ret.Protocol = TCP6
+		ret.Source = net.TCPAddr{IP: net.IP(payload[0:16]), Port: int(binary.BigEndian.Uint16(payload[32:34]))}
+		ret.Destination = net.TCPAddr{IP: net.IP(payload[16:32]), Port: int(binary.BigEndian.Uint16(payload[34:36]))}
+	default:
+		return nil, trace.BadParameter("unsupported PROXY protocol version 2 address family %#x", header[13])
+	}
+	// Any remaining payload bytes are TLV vectors which are ignored.
+	return &ret, nil
+}
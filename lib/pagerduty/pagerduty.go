@@ -0,0 +1,149 @@
+/*
+Copyright 2021 Gravitational, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package pagerduty implements a minimal client for the PagerDuty REST
+// API, used by the auth server to auto-approve access requests from users
+// who are currently on call.
+package pagerduty
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+
+	apidefaults "github.com/gravitational/teleport/api/defaults"
+
+	"github.com/gravitational/trace"
+)
+
+// DefaultAPIEndpoint is the public PagerDuty REST API address.
+const DefaultAPIEndpoint = "https://api.pagerduty.com"
+
+// Config describes the PagerDuty auto-approval integration.
+type Config struct {
+	// APIEndpoint is the PagerDuty REST API address, overridden in tests.
+	APIEndpoint string
+	// APIKey is the PagerDuty REST API token.
+	APIKey string
+	// ScheduleMap maps teleport role names to the PagerDuty schedule
+	// whose on-call users may have requests for that role auto-approved.
+	ScheduleMap map[string]string
+}
+
+// CheckAndSetDefaults validates the integration configuration.
+func (c *Config) CheckAndSetDefaults() error {
+	if c.APIKey == "" {
+		return trace.BadParameter("missing PagerDuty API key")
+	}
+	if len(c.ScheduleMap) == 0 {
+		return trace.BadParameter("missing PagerDuty schedule map")
+	}
+	if c.APIEndpoint == "" {
+		c.APIEndpoint = DefaultAPIEndpoint
+	}
+	if _, err := url.Parse(c.APIEndpoint); err != nil {
+		return trace.BadParameter("invalid PagerDuty API endpoint %q: %v", c.APIEndpoint, err)
+	}
+	return nil
+}
+
+// OnCall describes a single on-call shift on a schedule.
+type OnCall struct {
+	// UserEmail is the email of the user on call.
+	UserEmail string
+	// ScheduleID is the schedule the shift belongs to.
+	ScheduleID string
+	// ScheduleSummary is the human readable schedule name.
+	ScheduleSummary string
+	// Start and End delimit the shift in PagerDuty's timestamp format.
+	Start string
+	End   string
+}
+
+// Client is a minimal PagerDuty REST API client.
+type Client struct {
+	config Config
+	client *http.Client
+}
+
+// NewClient returns a client for the configured PagerDuty account.
+func NewClient(config Config) (*Client, error) {
+	if err := config.CheckAndSetDefaults(); err != nil {
+		return nil, trace.Wrap(err)
+	}
+	return &Client{
+		config: config,
+		client: &http.Client{
+			Timeout: apidefaults.DefaultDialTimeout,
+		},
+	}, nil
+}
+
+// Schedule returns the schedule mapped to the role, if any.
+func (c *Client) Schedule(role string) (string, bool) {
+	schedule, ok := c.config.ScheduleMap[role]
+	return schedule, ok
+}
+
+// OnCalls lists the current on-call shifts of a schedule.
+func (c *Client) OnCalls(ctx context.Context, scheduleID string) ([]OnCall, error) {
+	endpoint := fmt.Sprintf("%v/oncalls?%v", c.config.APIEndpoint,
+		url.Values{"schedule_ids[]": []string{scheduleID}}.Encode())
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, endpoint, nil)
+	if err != nil {
+		return nil, trace.Wrap(err)
+	}
+	req.Header.Set("Accept", "application/vnd.pagerduty+json;version=2")
+	req.Header.Set("Authorization", fmt.Sprintf("Token token=%v", c.config.APIKey))
+	resp, err := c.client.Do(req)
+	if err != nil {
+		return nil, trace.Wrap(err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return nil, trace.BadParameter("PagerDuty API returned status %v", resp.StatusCode)
+	}
+
+	var payload struct {
+		OnCalls []struct {
+			User struct {
+				Email string `json:"email"`
+			} `json:"user"`
+			Schedule struct {
+				ID      string `json:"id"`
+				Summary string `json:"summary"`
+			} `json:"schedule"`
+			Start string `json:"start"`
+			End   string `json:"end"`
+		} `json:"oncalls"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&payload); err != nil {
+		return nil, trace.Wrap(err)
+	}
+	oncalls := make([]OnCall, 0, len(payload.OnCalls))
+	for _, shift := range payload.OnCalls {
+		oncalls = append(oncalls, OnCall{
+			UserEmail:       shift.User.Email,
+			ScheduleID:      shift.Schedule.ID,
+			ScheduleSummary: shift.Schedule.Summary,
+			Start:           shift.Start,
+			End:             shift.End,
+		})
+	}
+	return oncalls, nil
+}
@@ -0,0 +1,73 @@
+/*
+Copyright 2021 Gravitational, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package pagerduty
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+// TestOnCalls checks on-call shift listing against a fake PagerDuty API.
+func TestOnCalls(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		require.Equal(t, "/oncalls", r.URL.Path)
+		require.Equal(t, "PD123", r.URL.Query().Get("schedule_ids[]"))
+		require.Equal(t, "Token token=secret", r.Header.Get("Authorization"))
+		fmt.Fprint(w, `{"oncalls": [
+			{"user": {"email": "alice@example.com"},
+			 "schedule": {"id": "PD123", "summary": "Database On-Call"},
+			 "start": "2021-04-01T00:00:00Z", "end": "2021-04-02T00:00:00Z"}
+		]}`)
+	}))
+	defer server.Close()
+
+	client, err := NewClient(Config{
+		APIEndpoint: server.URL,
+		APIKey:      "secret",
+		ScheduleMap: map[string]string{"dbadmin": "PD123"},
+	})
+	require.NoError(t, err)
+
+	schedule, ok := client.Schedule("dbadmin")
+	require.True(t, ok)
+	oncalls, err := client.OnCalls(context.Background(), schedule)
+	require.NoError(t, err)
+	require.Len(t, oncalls, 1)
+	require.Equal(t, "alice@example.com", oncalls[0].UserEmail)
+	require.Equal(t, "Database On-Call", oncalls[0].ScheduleSummary)
+
+	_, ok = client.Schedule("unmapped")
+	require.False(t, ok)
+}
+
+// TestConfigCheck checks integration configuration validation.
+func TestConfigCheck(t *testing.T) {
+	cfg := Config{ScheduleMap: map[string]string{"a": "b"}}
+	require.Error(t, cfg.CheckAndSetDefaults())
+
+	cfg = Config{APIKey: "secret"}
+	require.Error(t, cfg.CheckAndSetDefaults())
+
+	cfg = Config{APIKey: "secret", ScheduleMap: map[string]string{"a": "b"}}
+	require.NoError(t, cfg.CheckAndSetDefaults())
+	require.Equal(t, DefaultAPIEndpoint, cfg.APIEndpoint)
+}
@@ -1,4 +1,4 @@
-// +build !bpf 386
+// +build !bpf 386 arm
 
 /*
 Copyright 2021 Gravitational, Inc.
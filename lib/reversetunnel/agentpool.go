@@ -90,6 +90,9 @@ type AgentPoolConfig struct {
 	ProxyAddr string
 	// Cluster is a cluster name of the proxy.
 	Cluster string
+	// ConnectionCount is how many parallel tunnel connections to
+	// maintain per proxy, one if not set.
+	ConnectionCount int
 }
 
 // CheckAndSetDefaults checks and sets defaults
@@ -136,7 +139,7 @@ func NewAgentPool(ctx context.Context, cfg AgentPoolConfig) (*AgentPool, error)
 	}
 
 	ctx, cancel := context.WithCancel(ctx)
-	tr, err := track.New(ctx, track.Config{ClusterName: cfg.Cluster})
+	tr, err := track.New(ctx, track.Config{ClusterName: cfg.Cluster, ConnectionCount: cfg.ConnectionCount})
 	if err != nil {
 		cancel()
 		return nil, trace.Wrap(err)
@@ -108,6 +108,11 @@ type connConfig struct {
 	// offlineThreshold is how long to wait for a keep alive message before
 	// marking a reverse tunnel connection as invalid.
 	offlineThreshold time.Duration
+
+	// activeSessions counts the sessions currently routed over this
+	// connection, used for least-loaded routing when a target is
+	// reachable over several tunnels.
+	activeSessions int64
 }
 
 func newRemoteConn(cfg *connConfig) *remoteConn {
@@ -125,6 +130,24 @@ func newRemoteConn(cfg *connConfig) *remoteConn {
 	return c
 }
 
+// incrementActiveSessions records a new session routed over this
+// connection.
+func (c *remoteConn) incrementActiveSessions() {
+	atomic.AddInt64(&c.activeSessions, 1)
+}
+
+// decrementActiveSessions records the end of a session routed over this
+// connection.
+func (c *remoteConn) decrementActiveSessions() {
+	atomic.AddInt64(&c.activeSessions, -1)
+}
+
+// activeSessionCount returns how many sessions are currently routed over
+// this connection.
+func (c *remoteConn) activeSessionCount() int64 {
+	return atomic.LoadInt64(&c.activeSessions)
+}
+
 func (c *remoteConn) String() string {
 	return fmt.Sprintf("remoteConn(remoteAddr=%v)", c.conn.RemoteAddr())
 }
@@ -59,7 +59,7 @@ func newlocalSite(srv *server, domainName string, client auth.ClientI) (*localSi
 		accessPoint:      accessPoint,
 		certificateCache: certificateCache,
 		domainName:       domainName,
-		remoteConns:      make(map[connKey]*remoteConn),
+		remoteConns:      make(map[connKey][]*remoteConn),
 		clock:            srv.Clock,
 		log: log.WithFields(log.Fields{
 			trace.Component: teleport.ComponentReverseTunnelServer,
@@ -91,8 +91,10 @@ type localSite struct {
 	// certificateCache caches host certificates for the forwarding server.
 	certificateCache *certificateCache
 
-	// remoteConns maps UUID and connection type to an remote connection.
-	remoteConns map[connKey]*remoteConn
+	// remoteConns maps UUID and connection type to the remote
+	// connections that can reach the target. Agents may hold several
+	// parallel tunnels, the dialer picks the least-loaded one.
+	remoteConns map[connKey][]*remoteConn
 
 	// clock is used to control time in tests.
 	clock clockwork.Clock
@@ -107,7 +109,11 @@ func (s *localSite) GetTunnelsCount() int {
 	s.Lock()
 	defer s.Unlock()
 
-	return len(s.remoteConns)
+	var count int
+	for _, conns := range s.remoteConns {
+		count += len(conns)
+	}
+	return count
 }
 
 // CachingAccessPoint returns a auth.AccessPoint for this cluster.
@@ -271,7 +277,24 @@ func (s *localSite) dialTunnel(dreq *sshutils.DialReq) (net.Conn, error) {
 		return nil, trace.Wrap(err)
 	}
 
-	return conn, nil
+	// Account for the session on the chosen tunnel until the connection
+	// closes, feeding the least-loaded selection above.
+	rconn.incrementActiveSessions()
+	return &sessionTrackingConn{Conn: conn, rconn: rconn}, nil
+}
+
+// sessionTrackingConn releases the session slot on its tunnel connection
+// when the proxied connection closes.
+type sessionTrackingConn struct {
+	net.Conn
+	rconn *remoteConn
+	once  sync.Once
+}
+
+// Close closes the proxied connection and releases the session slot.
+func (c *sessionTrackingConn) Close() error {
+	c.once.Do(c.rconn.decrementActiveSessions)
+	return c.Conn.Close()
 }
 
 func (s *localSite) getConn(params DialParams) (conn net.Conn, useTunnel bool, err error) {
@@ -348,7 +371,7 @@ func (s *localSite) addConn(nodeID string, connType types.TunnelType, conn net.C
 		uuid:     nodeID,
 		connType: connType,
 	}
-	s.remoteConns[key] = rconn
+	s.remoteConns[key] = append(s.remoteConns[key], rconn)
 
 	return rconn, nil
 }
@@ -359,8 +382,10 @@ func (s *localSite) addConn(nodeID string, connType types.TunnelType, conn net.C
 func (s *localSite) fanOutProxies(proxies []types.Server) {
 	s.Lock()
 	defer s.Unlock()
-	for _, conn := range s.remoteConns {
-		conn.updateProxies(proxies)
+	for _, conns := range s.remoteConns {
+		for _, conn := range conns {
+			conn.updateProxies(proxies)
+		}
 	}
 }
 
@@ -430,25 +455,48 @@ func (s *localSite) getRemoteConn(dreq *sshutils.DialReq) (*remoteConn, error) {
 	s.Lock()
 	defer s.Unlock()
 
-	// Loop over all connections and remove and invalid connections from the
+	// Loop over all connections and remove invalid connections from the
 	// connection map.
 	for key := range s.remoteConns {
-		if s.remoteConns[key].isInvalid() {
+		var valid []*remoteConn
+		for _, conn := range s.remoteConns[key] {
+			if !conn.isInvalid() {
+				valid = append(valid, conn)
+			}
+		}
+		if len(valid) == 0 {
 			delete(s.remoteConns, key)
+			continue
 		}
+		s.remoteConns[key] = valid
 	}
 
 	key := connKey{
 		uuid:     dreq.ServerID,
 		connType: dreq.ConnType,
 	}
-	rconn, ok := s.remoteConns[key]
+	conns, ok := s.remoteConns[key]
 	if !ok {
 		return nil, trace.NotFound("no %v reverse tunnel for %v found", dreq.ConnType, dreq.ServerID)
 	}
-	if !rconn.isReady() {
+	// Among the ready tunnels pick the one carrying the fewest sessions,
+	// so a target reachable over several parallel tunnels spreads its
+	// load and fails over without touching loaded paths.
+	var rconn *remoteConn
+	for _, conn := range conns {
+		if !conn.isReady() {
+			continue
+		}
+		if rconn == nil || conn.activeSessionCount() < rconn.activeSessionCount() {
+			rconn = conn
+		}
+	}
+	if rconn == nil {
 		return nil, trace.NotFound("%v is offline: no active %v tunnels found", dreq.ConnType, dreq.ServerID)
 	}
+	if len(conns) > 1 {
+		routingDecisions.WithLabelValues(string(dreq.ConnType)).Inc()
+	}
 
 	return rconn, nil
 }
@@ -64,7 +64,44 @@ var (
 		[]string{"cluster", "state"},
 	)
 
-	prometheusCollectors = []prometheus.Collector{remoteClustersStats, trustedClustersStats}
+	tunnelStreamsActive = prometheus.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Name: "reversetunnel_streams_active",
+			Help: "Number of streams currently proxied over the reverse tunnel transport",
+		},
+		[]string{"component"},
+	)
+
+	tunnelStreamBytes = prometheus.NewCounterVec(
+		prometheus.CounterOpts{
+			Name: "reversetunnel_stream_bytes_total",
+			Help: "Bytes proxied over the reverse tunnel transport per direction",
+		},
+		[]string{"component", "direction"},
+	)
+
+	tunnelStreamDuration = prometheus.NewHistogramVec(
+		prometheus.HistogramOpts{
+			Name:    "reversetunnel_stream_duration_seconds",
+			Help:    "Lifetime of streams proxied over the reverse tunnel transport",
+			Buckets: prometheus.ExponentialBuckets(0.1, 4, 8),
+		},
+		[]string{"component"},
+	)
+
+	routingDecisions = prometheus.NewCounterVec(
+		prometheus.CounterOpts{
+			Name: "reversetunnel_routing_decisions_total",
+			Help: "Number of dials where the least-loaded tunnel was picked among several candidates",
+		},
+		[]string{"type"},
+	)
+
+	prometheusCollectors = []prometheus.Collector{
+		remoteClustersStats, trustedClustersStats,
+		tunnelStreamsActive, tunnelStreamBytes, tunnelStreamDuration,
+		routingDecisions,
+	}
 )
 
 // server is a "reverse tunnel server". it exposes the cluster capabilities
@@ -39,6 +39,10 @@ type Config struct {
 	TickRate time.Duration
 	// ClusterName is the name of the tracked cluster.
 	ClusterName string
+	// ConnectionCount is how many parallel tunnel connections are
+	// maintained per proxy, one if not set. Extra connections let
+	// sessions fail over without a blip when a proxy restarts.
+	ConnectionCount int
 }
 
 // SetDefaults set default values for Config.
@@ -52,6 +56,9 @@ func (c *Config) CheckAndSetDefaults() error {
 	if c.ClusterName == "" {
 		return trace.BadParameter("missing ClusterName in track.Config")
 	}
+	if c.ConnectionCount < 1 {
+		c.ConnectionCount = 1
+	}
 	return nil
 }
 
@@ -119,9 +126,9 @@ func (t *Tracker) TrackExpected(lease Lease, proxies ...string) {
 	for _, name := range proxies {
 		set.markSeen(now, name)
 	}
-	count := len(set.proxies)
+	count := len(set.proxies) * t.ConnectionCount
 	if count < 1 {
-		count = 1
+		count = t.ConnectionCount
 	}
 	t.wp.Set(addr, uint64(count))
 }
@@ -156,9 +163,9 @@ func (t *Tracker) tick() {
 	cutoff := time.Now().Add(-1 * t.ProxyExpiry)
 	for addr, set := range t.sets {
 		if set.expire(cutoff) > 0 {
-			count := len(set.proxies)
+			count := len(set.proxies) * t.ConnectionCount
 			if count < 1 {
-				count = 1
+				count = t.ConnectionCount
 			}
 			t.wp.Set(addr, uint64(count))
 		}
@@ -169,9 +176,9 @@ func (t *Tracker) getOrCreate(addr utils.NetAddr) *proxySet {
 	if s, ok := t.sets[addr]; ok {
 		return s
 	}
-	set := newProxySet(addr, t.ClusterName)
+	set := newProxySet(addr, t.ClusterName, t.ConnectionCount)
 	t.sets[addr] = set
-	t.wp.Set(addr, 1)
+	t.wp.Set(addr, uint64(t.ConnectionCount))
 	return set
 }
 
@@ -210,21 +217,23 @@ func (t *Tracker) unclaim(addr utils.NetAddr, principals ...string) {
 
 type entry struct {
 	lastSeen time.Time
-	claimed  bool
+	claims   int
 }
 
-func newProxySet(addr utils.NetAddr, clusterName string) *proxySet {
+func newProxySet(addr utils.NetAddr, clusterName string, connectionCount int) *proxySet {
 	return &proxySet{
-		addr:        addr,
-		clusterName: clusterName,
-		proxies:     make(map[string]entry),
+		addr:            addr,
+		clusterName:     clusterName,
+		connectionCount: connectionCount,
+		proxies:         make(map[string]entry),
 	}
 }
 
 type proxySet struct {
-	addr        utils.NetAddr
-	clusterName string
-	proxies     map[string]entry
+	addr            utils.NetAddr
+	clusterName     string
+	connectionCount int
+	proxies         map[string]entry
 }
 
 func (p *proxySet) claim(principals ...string) (ok bool) {
@@ -232,23 +241,26 @@ func (p *proxySet) claim(principals ...string) (ok bool) {
 	e, ok := p.proxies[proxy]
 	if !ok {
 		p.proxies[proxy] = entry{
-			claimed: true,
+			claims: 1,
 		}
 		return true
 	}
-	if e.claimed {
+	if e.claims >= p.connectionCount {
 		return false
 	}
-	e.claimed = true
+	e.claims++
 	p.proxies[proxy] = e
 	return true
 }
 
 func (p *proxySet) unclaim(principals ...string) {
 	proxy := p.resolveName(principals)
-	p.proxies[proxy] = entry{
-		lastSeen: time.Now(),
+	e := p.proxies[proxy]
+	if e.claims > 0 {
+		e.claims--
 	}
+	e.lastSeen = time.Now()
+	p.proxies[proxy] = e
 }
 
 func (p *proxySet) markSeen(t time.Time, proxy string) {
@@ -268,7 +280,7 @@ func (p *proxySet) markSeen(t time.Time, proxy string) {
 
 func (p *proxySet) expire(cutoff time.Time) (removed int) {
 	for name, entry := range p.proxies {
-		if entry.claimed {
+		if entry.claims > 0 {
 			continue
 		}
 		if entry.lastSeen.Before(cutoff) {
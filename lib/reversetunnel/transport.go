@@ -261,17 +261,29 @@ func (p *transport) start() {
 
 	errorCh := make(chan error, 2)
 
+	// Account for the stream while it is alive: the gauge, per-direction
+	// byte counters and the duration histogram drive the tunnel
+	// transport dashboards.
+	tunnelStreamsActive.WithLabelValues(p.component).Inc()
+	streamStarted := time.Now()
+	defer func() {
+		tunnelStreamsActive.WithLabelValues(p.component).Dec()
+		tunnelStreamDuration.WithLabelValues(p.component).Observe(time.Since(streamStarted).Seconds())
+	}()
+
 	go func() {
 		// Make sure that we close the client connection on a channel
 		// close, otherwise the other goroutine would never know
 		// as it will block on read from the connection.
 		defer conn.Close()
-		_, err := io.Copy(conn, p.channel)
+		written, err := io.Copy(conn, p.channel)
+		tunnelStreamBytes.WithLabelValues(p.component, "inbound").Add(float64(written))
 		errorCh <- err
 	}()
 
 	go func() {
-		_, err := io.Copy(p.channel, conn)
+		written, err := io.Copy(p.channel, conn)
+		tunnelStreamBytes.WithLabelValues(p.component, "outbound").Add(float64(written))
 		errorCh <- err
 	}()
 
@@ -44,12 +44,14 @@ import (
 	"github.com/gravitational/teleport/lib/defaults"
 	"github.com/gravitational/teleport/lib/events"
 	"github.com/gravitational/teleport/lib/limiter"
+	"github.com/gravitational/teleport/lib/pagerduty"
 	"github.com/gravitational/teleport/lib/pam"
 	"github.com/gravitational/teleport/lib/plugin"
 	restricted "github.com/gravitational/teleport/lib/restrictedsession"
 	"github.com/gravitational/teleport/lib/services"
 	"github.com/gravitational/teleport/lib/srv/app"
 	"github.com/gravitational/teleport/lib/sshca"
+	"github.com/gravitational/teleport/lib/tickets"
 	"github.com/gravitational/teleport/lib/tlsca"
 	"github.com/gravitational/teleport/lib/utils"
 
@@ -211,6 +213,22 @@ type Config struct {
 	// Log optionally specifies the logger
 	Log utils.Logger
 
+	// LogOutput records where the process logs to: a file path, or one
+	// of stderr/stdout/syslog
+	LogOutput string
+
+	// TunnelConnectionCount is how many parallel reverse tunnel
+	// connections agents maintain per proxy, one if not set
+	TunnelConnectionCount int
+
+	// SignatureAlgorithmSuite selects the key types generated for users
+	// and hosts: rsa2048 (default), ecdsa-p256 or ed25519
+	SignatureAlgorithmSuite string
+
+	// LowMemory trims caches, buffers and key precomputation for
+	// resource-constrained agents
+	LowMemory bool
+
 	// PluginRegistry allows adding enterprise logic to Teleport services
 	PluginRegistry plugin.Registry
 }
@@ -323,6 +341,9 @@ type ProxyConfig struct {
 	// Enabled turns proxy role on or off for this process
 	Enabled bool
 
+	// SecurityHeaders overrides or extends the web security headers
+	SecurityHeaders map[string]string
+
 	//DisableTLS is enabled if we don't want self-signed certs
 	DisableTLS bool
 
@@ -456,6 +477,64 @@ type AuthConfig struct {
 	// Enabled turns auth role on or off for this process
 	Enabled bool
 
+	// MaxLoginAttempts is the number of consecutive failed local login
+	// attempts after which the account is locked, zero means use the
+	// default
+	MaxLoginAttempts int
+
+	// AccountLockInterval is how long the account stays locked after too
+	// many failed login attempts, zero means use the default
+	AccountLockInterval time.Duration
+
+	// PasswordPolicy is the password requirements policy for local users
+	PasswordPolicy services.PasswordPolicy
+
+	// Webhooks are HTTP endpoints notified about selected audit events
+	Webhooks []events.WebhookConfig
+
+	// AccessRequestNotifications are chat channels notified about access
+	// request activity
+	AccessRequestNotifications []events.AccessNotifyTarget
+
+	// PagerDuty configures auto-approval of access requests from users on
+	// call for mapped PagerDuty schedules
+	PagerDuty pagerduty.Config
+
+	// Ticketing configures tracking access requests as Jira or ServiceNow
+	// tickets
+	Ticketing tickets.Config
+
+	// AccessRequestMaxDuration caps how long requested access can last
+	AccessRequestMaxDuration time.Duration
+
+	// AccessRequestUnassumedTTL is how long an approval stays valid
+	// without being assumed
+	AccessRequestUnassumedTTL time.Duration
+
+	// AccessRequestRetention is how long resolved requests are kept
+	// before being pruned
+	AccessRequestRetention time.Duration
+
+	// VersionSkewLimit is how many major versions an agent may lag
+	// behind the auth server and still join
+	VersionSkewLimit int
+
+	// MaxWebSessions caps concurrent web sessions per user, zero
+	// disables the limit
+	MaxWebSessions int
+
+	// RecordingRetention is how long session recordings are kept before
+	// being pruned
+	RecordingRetention time.Duration
+
+	// RecordingRetentionDryRun reports what would be pruned instead of
+	// deleting
+	RecordingRetentionDryRun bool
+
+	// UsageReportingEndpoint, when set, enables periodic submission of
+	// anonymized usage reports
+	UsageReportingEndpoint string
+
 	// EnableProxyProtocol enables proxy protocol support
 	EnableProxyProtocol bool
 
@@ -552,6 +631,28 @@ type SSHConfig struct {
 	// the inactivity timeout expiring. The empty string indicates that no
 	// timeout message will be sent.
 	IdleTimeoutMessage string
+
+	// AllowedCommands restricts exec on this node to matching command
+	// lines
+	AllowedCommands []string
+
+	// DeniedCommands rejects matching exec command lines on this node
+	DeniedCommands []string
+
+	// AcceptEnv lists glob patterns of client-supplied environment
+	// variables the node accepts
+	AcceptEnv []string
+
+	// Banner is served through the SSH banner mechanism before
+	// authentication completes
+	Banner string
+
+	// MOTD is printed to interactive sessions after login
+	MOTD string
+
+	// ImportCloudTags imports AWS/Azure/GCP instance tags as resource
+	// labels when this node runs on one of those clouds.
+	ImportCloudTags bool
 }
 
 // KubeConfig specifies configuration for kubernetes service
@@ -574,6 +675,10 @@ type KubeConfig struct {
 	// KubeconfigPath is a path to kubeconfig
 	KubeconfigPath string
 
+	// DiscoverEKSRegions is a list of AWS regions to discover active EKS
+	// clusters in at startup.
+	DiscoverEKSRegions []string
+
 	// Labels are used for RBAC on clusters.
 	StaticLabels  map[string]string
 	DynamicLabels services.CommandLabels
@@ -588,6 +693,12 @@ type DatabasesConfig struct {
 	Enabled bool
 	// Databases is a list of databases proxied by this service.
 	Databases []Database
+	// DiscoverRDSRegions is a list of AWS regions to discover RDS
+	// instances and Aurora clusters in at startup.
+	DiscoverRDSRegions []string
+	// DiscoverRedshiftRegions is a list of AWS regions to discover
+	// Redshift clusters in at startup.
+	DiscoverRedshiftRegions []string
 }
 
 // Database represents a single database that's being proxied.
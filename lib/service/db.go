@@ -24,13 +24,16 @@ import (
 	"github.com/gravitational/teleport/lib/events"
 	"github.com/gravitational/teleport/lib/reversetunnel"
 	"github.com/gravitational/teleport/lib/srv/db"
+	"github.com/gravitational/teleport/lib/srv/db/common"
 	"github.com/gravitational/teleport/lib/utils"
 
 	"github.com/gravitational/trace"
 )
 
 func (process *TeleportProcess) initDatabases() {
-	if len(process.Config.Databases.Databases) == 0 {
+	if len(process.Config.Databases.Databases) == 0 &&
+		len(process.Config.Databases.DiscoverRDSRegions) == 0 &&
+		len(process.Config.Databases.DiscoverRedshiftRegions) == 0 {
 		return
 	}
 	process.registerWithAuthServer(types.RoleDatabase, DatabasesIdentityEvent)
@@ -82,8 +85,53 @@ func (process *TeleportProcess) initDatabaseService() (retErr error) {
 	}
 
 	// Create database server for each of the proxied databases.
+	databases := process.Config.Databases.Databases
+	// Discover RDS instances and Aurora clusters, if configured. The
+	// databases use IAM authentication, which must be enabled on the AWS
+	// side.
+	for _, region := range process.Config.Databases.DiscoverRDSRegions {
+		discovered, err := common.DiscoverRDSDatabases(process.ExitContext(), region)
+		if err != nil {
+			log.WithError(err).Warnf("Failed to discover RDS databases in region %q.", region)
+			continue
+		}
+		log.Infof("Discovered %v RDS database(s) in region %q.", len(discovered), region)
+		for _, d := range discovered {
+			databases = append(databases, Database{
+				Name:         d.Name,
+				Description:  d.Description,
+				Protocol:     d.Protocol,
+				URI:          d.URI,
+				StaticLabels: d.Labels,
+				AWS:          DatabaseAWS{Region: d.Region},
+			})
+		}
+	}
+
+	for _, region := range process.Config.Databases.DiscoverRedshiftRegions {
+		discovered, err := common.DiscoverRedshiftClusters(process.ExitContext(), region)
+		if err != nil {
+			log.WithError(err).Warnf("Failed to discover Redshift clusters in region %q.", region)
+			continue
+		}
+		log.Infof("Discovered %v Redshift cluster(s) in region %q.", len(discovered), region)
+		for _, d := range discovered {
+			databases = append(databases, Database{
+				Name:         d.Name,
+				Description:  d.Description,
+				Protocol:     d.Protocol,
+				URI:          d.URI,
+				StaticLabels: d.Labels,
+				AWS: DatabaseAWS{
+					Region:   d.Region,
+					Redshift: DatabaseAWSRedshift{ClusterID: d.RedshiftClusterID},
+				},
+			})
+		}
+	}
+
 	var databaseServers []types.DatabaseServer
-	for _, db := range process.Config.Databases.Databases {
+	for _, db := range databases {
 		db, err := types.NewDatabaseServerV3(
 			db.Name,
 			db.StaticLabels,
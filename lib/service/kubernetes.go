@@ -19,6 +19,7 @@ package service
 import (
 	"net"
 	"net/http"
+	"path/filepath"
 
 	"github.com/gravitational/teleport"
 	apidefaults "github.com/gravitational/teleport/api/defaults"
@@ -27,6 +28,7 @@ import (
 	"github.com/gravitational/teleport/lib/cache"
 	"github.com/gravitational/teleport/lib/events"
 	kubeproxy "github.com/gravitational/teleport/lib/kube/proxy"
+	kubeutils "github.com/gravitational/teleport/lib/kube/utils"
 	"github.com/gravitational/teleport/lib/labels"
 	"github.com/gravitational/teleport/lib/reversetunnel"
 	"github.com/gravitational/teleport/lib/utils"
@@ -175,6 +177,32 @@ func (process *TeleportProcess) initKubernetesService(log *logrus.Entry, conn *C
 		}()
 	}
 
+	// Discover EKS clusters and serve them through a synthesized
+	// kubeconfig, if discovery is configured.
+	kubeconfigPath := cfg.Kube.KubeconfigPath
+	if len(cfg.Kube.DiscoverEKSRegions) != 0 {
+		if kubeconfigPath != "" {
+			return trace.BadParameter("kubeconfig_file and discover_eks_regions cannot be combined")
+		}
+		var eksClusters []kubeutils.EKSCluster
+		for _, region := range cfg.Kube.DiscoverEKSRegions {
+			clusters, err := kubeutils.DiscoverEKSClusters(process.ExitContext(), region)
+			if err != nil {
+				log.WithError(err).Warnf("Failed to discover EKS clusters in region %q.", region)
+				continue
+			}
+			eksClusters = append(eksClusters, clusters...)
+		}
+		if len(eksClusters) == 0 {
+			return trace.NotFound("no EKS clusters discovered in regions %v", cfg.Kube.DiscoverEKSRegions)
+		}
+		kubeconfigPath = filepath.Join(cfg.DataDir, "eks-kubeconfig")
+		if err := kubeutils.WriteEKSKubeconfig(kubeconfigPath, eksClusters); err != nil {
+			return trace.Wrap(err)
+		}
+		log.Infof("Discovered %v EKS cluster(s), serving them via %v.", len(eksClusters), kubeconfigPath)
+	}
+
 	teleportClusterName := conn.ServerIdentity.Cert.Extensions[utils.CertExtensionAuthority]
 
 	// Create the kube server to service listener.
@@ -218,7 +246,7 @@ func (process *TeleportProcess) initKubernetesService(log *logrus.Entry, conn *C
 			CachingAuthClient: accessPoint,
 			ServerID:          cfg.HostUUID,
 			Context:           process.ExitContext(),
-			KubeconfigPath:    cfg.Kube.KubeconfigPath,
+			KubeconfigPath:    kubeconfigPath,
 			KubeClusterName:   cfg.Kube.KubeClusterName,
 			KubeServiceType:   kubeproxy.KubeService,
 			Component:         teleport.ComponentKube,
@@ -19,6 +19,7 @@ limitations under the License.
 package service
 
 import (
+	"bytes"
 	"context"
 	"crypto/rand"
 	"crypto/tls"
@@ -34,6 +35,7 @@ import (
 	"os"
 	"path/filepath"
 	"runtime"
+	rpprof "runtime/pprof"
 	"strconv"
 	"strings"
 	"sync"
@@ -65,12 +67,14 @@ import (
 	"github.com/gravitational/teleport/lib/cache"
 	"github.com/gravitational/teleport/lib/defaults"
 	"github.com/gravitational/teleport/lib/events"
+	"github.com/gravitational/teleport/lib/events/azsessions"
 	"github.com/gravitational/teleport/lib/events/dynamoevents"
 	"github.com/gravitational/teleport/lib/events/filesessions"
 	"github.com/gravitational/teleport/lib/events/firestoreevents"
 	"github.com/gravitational/teleport/lib/events/gcssessions"
 	"github.com/gravitational/teleport/lib/events/s3sessions"
 	kubeproxy "github.com/gravitational/teleport/lib/kube/proxy"
+	"github.com/gravitational/teleport/lib/labels"
 	"github.com/gravitational/teleport/lib/limiter"
 	"github.com/gravitational/teleport/lib/modules"
 	"github.com/gravitational/teleport/lib/multiplexer"
@@ -699,7 +703,13 @@ func NewTeleport(cfg *Config) (*TeleportProcess, error) {
 		if !cfg.Auth.Enabled && !cfg.Proxy.Enabled {
 			precomputeCount = 0
 		}
-		cfg.Keygen = native.New(process.ExitContext(), native.PrecomputeKeys(precomputeCount))
+		// precomputed keys hold memory hostage on constrained hosts
+		if cfg.LowMemory {
+			precomputeCount = 0
+		}
+		cfg.Keygen = native.New(process.ExitContext(),
+			native.PrecomputeKeys(precomputeCount),
+			native.WithAlgorithmSuite(cfg.SignatureAlgorithmSuite))
 	}
 
 	// Produce global TeleportReadyEvent
@@ -773,7 +783,12 @@ func NewTeleport(cfg *Config) (*TeleportProcess, error) {
 		warnOnErr(process.closeImportedDescriptors(teleport.ComponentDatabase), process.log)
 	}
 
+	if cfg.LowMemory {
+		process.RegisterFunc("common.memory", process.runMemoryReporter)
+	}
+
 	process.RegisterFunc("common.rotate", process.periodicSyncRotationState)
+	process.RegisterFunc("common.sdnotify", process.notifySystemdReady)
 
 	if !serviceStarted {
 		return nil, trace.BadParameter("all services failed to start")
@@ -905,6 +920,16 @@ func initUploadHandler(auditConfig types.ClusterAuditConfig, dataDir string) (ev
 			return nil, trace.Wrap(err)
 		}
 		return handler, nil
+	case teleport.SchemeAZBlob:
+		config := azsessions.Config{}
+		if err := config.SetFromURL(uri); err != nil {
+			return nil, trace.Wrap(err)
+		}
+		handler, err := azsessions.DefaultNewHandler(config)
+		if err != nil {
+			return nil, trace.Wrap(err)
+		}
+		return handler, nil
 	case teleport.SchemeS3:
 		config := s3sessions.Config{}
 		if err := config.SetFromURL(uri, auditConfig.Region()); err != nil {
@@ -928,8 +953,8 @@ func initUploadHandler(auditConfig types.ClusterAuditConfig, dataDir string) (ev
 		return handler, nil
 	default:
 		return nil, trace.BadParameter(
-			"unsupported scheme for audit_sesions_uri: %q, currently supported schemes are %q and %q",
-			uri.Scheme, teleport.SchemeS3, teleport.SchemeFile)
+			"unsupported scheme for audit_sesions_uri: %q, currently supported schemes are %q, %q, %q and %q",
+			uri.Scheme, teleport.SchemeS3, teleport.SchemeGCS, teleport.SchemeAZBlob, teleport.SchemeFile)
 	}
 }
 
@@ -1129,8 +1154,29 @@ func (process *TeleportProcess) initAuthService() error {
 		}
 	}
 
+	emitters := []apievents.Emitter{events.NewLoggingEmitter(), emitter}
+	if len(cfg.Auth.Webhooks) != 0 {
+		webhookEmitter, err := events.NewWebhookEmitter(cfg.Auth.Webhooks)
+		if err != nil {
+			return trace.Wrap(err)
+		}
+		process.OnExit("webhook.emitter", func(payload interface{}) {
+			warnOnErr(webhookEmitter.Close(), process.log)
+		})
+		emitters = append(emitters, webhookEmitter)
+	}
+	if len(cfg.Auth.AccessRequestNotifications) != 0 {
+		notifier, err := events.NewAccessRequestNotifier(cfg.Auth.AccessRequestNotifications)
+		if err != nil {
+			return trace.Wrap(err)
+		}
+		process.OnExit("accessrequest.notifier", func(payload interface{}) {
+			warnOnErr(notifier.Close(), process.log)
+		})
+		emitters = append(emitters, notifier)
+	}
 	checkingEmitter, err := events.NewCheckingEmitter(events.CheckingEmitterConfig{
-		Inner:       events.NewMultiEmitter(events.NewLoggingEmitter(), emitter),
+		Inner:       events.NewMultiEmitter(emitters...),
 		Clock:       process.Clock,
 		ClusterName: cfg.Auth.ClusterName.GetClusterName(),
 	})
@@ -1149,36 +1195,51 @@ func (process *TeleportProcess) initAuthService() error {
 
 	// first, create the AuthServer
 	authServer, err := auth.Init(auth.InitConfig{
-		Backend:                 b,
-		Authority:               cfg.Keygen,
-		ClusterConfiguration:    cfg.ClusterConfiguration,
-		ClusterConfig:           cfg.Auth.ClusterConfig,
-		ClusterAuditConfig:      cfg.Auth.AuditConfig,
-		ClusterNetworkingConfig: cfg.Auth.NetworkingConfig,
-		SessionRecordingConfig:  cfg.Auth.SessionRecordingConfig,
-		ClusterName:             cfg.Auth.ClusterName,
-		AuthServiceName:         cfg.Hostname,
-		DataDir:                 cfg.DataDir,
-		HostUUID:                cfg.HostUUID,
-		NodeName:                cfg.Hostname,
-		Authorities:             cfg.Auth.Authorities,
-		Resources:               cfg.Auth.Resources,
-		ReverseTunnels:          cfg.ReverseTunnels,
-		Trust:                   cfg.Trust,
-		Presence:                cfg.Presence,
-		Events:                  cfg.Events,
-		Provisioner:             cfg.Provisioner,
-		Identity:                cfg.Identity,
-		Access:                  cfg.Access,
-		StaticTokens:            cfg.Auth.StaticTokens,
-		Roles:                   cfg.Auth.Roles,
-		AuthPreference:          cfg.Auth.Preference,
-		OIDCConnectors:          cfg.OIDCConnectors,
-		AuditLog:                process.auditLog,
-		CipherSuites:            cfg.CipherSuites,
-		CASigningAlg:            cfg.CASignatureAlgorithm,
-		Emitter:                 checkingEmitter,
-		Streamer:                events.NewReportingStreamer(checkingStreamer, process.Config.UploadEventsC),
+		Backend:                   b,
+		MaxLoginAttempts:          cfg.Auth.MaxLoginAttempts,
+		PagerDuty:                 cfg.Auth.PagerDuty,
+		Ticketing:                 cfg.Auth.Ticketing,
+		AccessRequestMaxDuration:  cfg.Auth.AccessRequestMaxDuration,
+		AccessRequestUnassumedTTL: cfg.Auth.AccessRequestUnassumedTTL,
+		AccessRequestRetention:    cfg.Auth.AccessRequestRetention,
+		VersionSkewLimit:          cfg.Auth.VersionSkewLimit,
+		MaxWebSessions:            cfg.Auth.MaxWebSessions,
+		RecordingRetention:        cfg.Auth.RecordingRetention,
+		RecordingRetentionDryRun:  cfg.Auth.RecordingRetentionDryRun,
+		RecordsDir:                filepath.Join(cfg.DataDir, events.RecordsDir),
+		UsageReportingEndpoint:    cfg.Auth.UsageReportingEndpoint,
+		LicenseFile:               cfg.Auth.LicenseFile,
+		AccountLockInterval:       cfg.Auth.AccountLockInterval,
+		PasswordPolicy:            cfg.Auth.PasswordPolicy,
+		Authority:                 cfg.Keygen,
+		ClusterConfiguration:      cfg.ClusterConfiguration,
+		ClusterConfig:             cfg.Auth.ClusterConfig,
+		ClusterAuditConfig:        cfg.Auth.AuditConfig,
+		ClusterNetworkingConfig:   cfg.Auth.NetworkingConfig,
+		SessionRecordingConfig:    cfg.Auth.SessionRecordingConfig,
+		ClusterName:               cfg.Auth.ClusterName,
+		AuthServiceName:           cfg.Hostname,
+		DataDir:                   cfg.DataDir,
+		HostUUID:                  cfg.HostUUID,
+		NodeName:                  cfg.Hostname,
+		Authorities:               cfg.Auth.Authorities,
+		Resources:                 cfg.Auth.Resources,
+		ReverseTunnels:            cfg.ReverseTunnels,
+		Trust:                     cfg.Trust,
+		Presence:                  cfg.Presence,
+		Events:                    cfg.Events,
+		Provisioner:               cfg.Provisioner,
+		Identity:                  cfg.Identity,
+		Access:                    cfg.Access,
+		StaticTokens:              cfg.Auth.StaticTokens,
+		Roles:                     cfg.Auth.Roles,
+		AuthPreference:            cfg.Auth.Preference,
+		OIDCConnectors:            cfg.OIDCConnectors,
+		AuditLog:                  process.auditLog,
+		CipherSuites:              cfg.CipherSuites,
+		CASigningAlg:              cfg.CASignatureAlgorithm,
+		Emitter:                   checkingEmitter,
+		Streamer:                  events.NewReportingStreamer(checkingStreamer, process.Config.UploadEventsC),
 	})
 	if err != nil {
 		return trace.Wrap(err)
@@ -1611,8 +1672,13 @@ func (process *TeleportProcess) newAsyncEmitter(clt apievents.Emitter) (*events.
 
 	// asyncEmitter makes sure that sessions do not block
 	// in case if connections are slow
+	bufferSize := 0 // library default
+	if process.Config.LowMemory {
+		bufferSize = lowMemoryEventBufferSize
+	}
 	return events.NewAsyncEmitter(events.AsyncEmitterConfig{
-		Inner: emitter,
+		Inner:      emitter,
+		BufferSize: bufferSize,
 	})
 }
 
@@ -1652,6 +1718,27 @@ func (process *TeleportProcess) initSSH() error {
 
 		cfg := process.Config
 
+		// Import cloud instance tags as labels, if requested. Labels set
+		// explicitly in the configuration take precedence.
+		if cfg.SSH.ImportCloudTags {
+			cloudLabels, err := labels.ImportCloudTags(process.ExitContext())
+			if err != nil {
+				log.WithError(err).Warn("Failed to import cloud instance tags.")
+			}
+			for key, value := range cloudLabels {
+				if _, ok := cfg.SSH.Labels[key]; ok {
+					continue
+				}
+				if cfg.SSH.Labels == nil {
+					cfg.SSH.Labels = make(map[string]string)
+				}
+				cfg.SSH.Labels[key] = value
+			}
+			if len(cloudLabels) != 0 {
+				log.Infof("Imported %v cloud instance tags as labels.", len(cloudLabels))
+			}
+		}
+
 		limiter, err := limiter.NewLimiter(cfg.SSH.Limiter)
 		if err != nil {
 			return trace.Wrap(err)
@@ -1680,12 +1767,16 @@ func (process *TeleportProcess) initSSH() error {
 				"to be enabled")
 		}
 
-		// If BPF is enabled in file configuration, but the operating system does
-		// not support enhanced session recording (like macOS), exit right away.
+		// If BPF is enabled in file configuration, but this platform or
+		// build does not support enhanced session recording (like macOS
+		// or 32-bit ARM), degrade gracefully: sessions still work, they
+		// are just not enhanced. Edge fleets share one configuration
+		// across architectures.
 		if cfg.SSH.BPF.Enabled && !bpf.SystemHasBPF() {
-			return trace.BadParameter("operating system does not support enhanced " +
-				"session recording, check Teleport documentation for more details on " +
-				"supported operating systems, kernels, and configuration")
+			log.Warning("Enhanced session recording is enabled but not supported on this " +
+				"platform or build, continuing without it. Check Teleport documentation " +
+				"for supported operating systems, kernels, and architectures.")
+			cfg.SSH.BPF.Enabled = false
 		}
 
 		// Start BPF programs. This is blocking and if the BPF programs fail to
@@ -1754,6 +1845,16 @@ func (process *TeleportProcess) initSSH() error {
 			return trace.Wrap(err)
 		}
 
+		revocations := srv.NewRevocationCache(cfg.DataDir, conn.ServerIdentity.Cert.SignatureKey)
+		commandPolicy, err := srv.NewCommandPolicy(cfg.SSH.AllowedCommands, cfg.SSH.DeniedCommands)
+		if err != nil {
+			return trace.Wrap(err)
+		}
+		envPolicy, err := srv.NewEnvPolicy(cfg.SSH.AcceptEnv)
+		if err != nil {
+			return trace.Wrap(err)
+		}
+
 		s, err = regular.New(cfg.SSH.Addr,
 			cfg.Hostname,
 			[]ssh.Signer{conn.ServerIdentity.KeySigner},
@@ -1764,6 +1865,11 @@ func (process *TeleportProcess) initSSH() error {
 			regular.SetLimiter(limiter),
 			regular.SetShell(cfg.SSH.Shell),
 			regular.SetEmitter(&events.StreamerAndEmitter{Emitter: asyncEmitter, Streamer: streamer}),
+			regular.SetRevocationCache(revocations),
+			regular.SetCommandPolicy(commandPolicy),
+			regular.SetEnvPolicy(envPolicy),
+			regular.SetBanner(cfg.SSH.Banner),
+			regular.SetMOTD(cfg.SSH.MOTD),
 			regular.SetSessionServer(conn.Client),
 			regular.SetLabels(cfg.SSH.Labels, cfg.SSH.CmdLabels),
 			regular.SetNamespace(namespace),
@@ -1827,14 +1933,15 @@ func (process *TeleportProcess) initSSH() error {
 			agentPool, err = reversetunnel.NewAgentPool(
 				process.ExitContext(),
 				reversetunnel.AgentPoolConfig{
-					Component:   teleport.ComponentNode,
-					HostUUID:    conn.ServerIdentity.ID.HostUUID,
-					ProxyAddr:   conn.TunnelProxy(),
-					Client:      conn.Client,
-					AccessPoint: conn.Client,
-					HostSigner:  conn.ServerIdentity.KeySigner,
-					Cluster:     conn.ServerIdentity.Cert.Extensions[utils.CertExtensionAuthority],
-					Server:      s,
+					Component:       teleport.ComponentNode,
+					ConnectionCount: cfg.TunnelConnectionCount,
+					HostUUID:        conn.ServerIdentity.ID.HostUUID,
+					ProxyAddr:       conn.TunnelProxy(),
+					Client:          conn.Client,
+					AccessPoint:     conn.Client,
+					HostSigner:      conn.ServerIdentity.KeySigner,
+					Cluster:         conn.ServerIdentity.Cert.Extensions[utils.CertExtensionAuthority],
+					Server:          s,
 				})
 			if err != nil {
 				return trace.Wrap(err)
@@ -1850,6 +1957,14 @@ func (process *TeleportProcess) initSSH() error {
 			process.BroadcastEvent(Event{Name: NodeSSHReady, Payload: nil})
 		}
 
+		// Connect to the auth server's inventory control channel for
+		// remote management commands.
+		go process.runInventoryControlLoop(conn.Client, types.RoleNode, log)
+
+		// Keep the revocation list fresh, the cache keeps enforcing the
+		// last good copy while auth is unreachable.
+		go revocations.Run(process.ExitContext(), conn.Client)
+
 		// Block and wait while the node is running.
 		s.Wait()
 		if conn.UseTunnel() {
@@ -2058,25 +2173,32 @@ func (process *TeleportProcess) initDiagnosticService() error {
 		}
 	})
 	mux.HandleFunc("/readyz", func(w http.ResponseWriter, r *http.Request) {
+		// Include the per-component states, so it is visible which
+		// dependency is degraded.
+		components := ps.componentStates()
 		switch ps.getState() {
 		// 503
 		case stateDegraded:
 			roundtrip.ReplyJSON(w, http.StatusServiceUnavailable, map[string]interface{}{
-				"status": "teleport is in a degraded state, check logs for details",
+				"status":     "teleport is in a degraded state, check logs for details",
+				"components": components,
 			})
 		// 400
 		case stateRecovering:
 			roundtrip.ReplyJSON(w, http.StatusBadRequest, map[string]interface{}{
-				"status": "teleport is recovering from a degraded state, check logs for details",
+				"status":     "teleport is recovering from a degraded state, check logs for details",
+				"components": components,
 			})
 		case stateStarting:
 			roundtrip.ReplyJSON(w, http.StatusBadRequest, map[string]interface{}{
-				"status": "teleport is starting and hasn't joined the cluster yet",
+				"status":     "teleport is starting and hasn't joined the cluster yet",
+				"components": components,
 			})
 		// 200
 		case stateOK:
 			roundtrip.ReplyJSON(w, http.StatusOK, map[string]interface{}{
-				"status": "ok",
+				"status":     "ok",
+				"components": components,
 			})
 		}
 	})
@@ -2197,9 +2319,9 @@ func (process *TeleportProcess) getAdditionalPrincipals(role types.SystemRole) (
 
 // initProxy gets called if teleport runs with 'proxy' role enabled.
 // this means it will do two things:
-//    1. serve a web UI
-//    2. proxy SSH connections to nodes running with 'node' role
-//    3. take care of reverse tunnels
+//  1. serve a web UI
+//  2. proxy SSH connections to nodes running with 'node' role
+//  3. take care of reverse tunnels
 func (process *TeleportProcess) initProxy() error {
 	// If no TLS key was provided for the web UI, generate a self-signed cert
 	if len(process.Config.Proxy.KeyPairs) == 0 &&
@@ -2598,6 +2720,7 @@ func (process *TeleportProcess) initProxyEndpoint(conn *Connector) error {
 
 		webHandler, err = web.NewHandler(
 			web.Config{
+				SecurityHeaders:  cfg.Proxy.SecurityHeaders,
 				Proxy:            tsrv,
 				AuthServers:      cfg.AuthServers[0],
 				DomainName:       cfg.Hostname,
@@ -3552,3 +3675,143 @@ func isDebugMode() bool {
 	v, _ := strconv.ParseBool(os.Getenv(teleport.DebugEnvVar))
 	return v
 }
+
+// runInventoryControlLoop keeps a long-poll control channel open to the
+// auth server, reporting identity and version and executing
+// server-initiated commands. The channel supplements the periodic
+// resource heartbeats rather than replacing them: folding presence into
+// the control stream to cut backend writes needs a server-side presence
+// redesign that has not happened yet.
+func (process *TeleportProcess) runInventoryControlLoop(client auth.ClientI, role types.SystemRole, log logrus.FieldLogger) {
+	hello := auth.InventoryHello{
+		HostID:   process.Config.HostUUID,
+		NodeName: process.Config.Hostname,
+		Version:  teleport.Version,
+		Services: []string{string(role)},
+		Health:   "ok",
+	}
+	for {
+		select {
+		case <-process.ExitContext().Done():
+			return
+		default:
+		}
+		commands, err := client.InventoryHello(process.ExitContext(), hello)
+		if err != nil {
+			log.WithError(err).Debug("Inventory control channel unavailable, retrying.")
+			select {
+			case <-process.ExitContext().Done():
+				return
+			case <-time.After(defaults.HeartbeatCheckPeriod * 10):
+			}
+			continue
+		}
+		for _, command := range commands {
+			switch command.Name {
+			case auth.InventoryCommandPing:
+				log.Debug("Inventory control channel ping.")
+			case auth.InventoryCommandUpgrade:
+				log.Warningf("The auth server recommends upgrading this agent (running %v).", teleport.Version)
+			case auth.InventoryCommandShutdown:
+				log.Info("Received graceful shutdown command over the inventory control channel.")
+				process.BroadcastEvent(Event{Name: TeleportExitEvent})
+				return
+			case auth.InventoryCommandCollectLogs:
+				process.answerInventoryCommand(client, command, hello.HostID, process.collectRecentLogs, log)
+			case auth.InventoryCommandCollectProfile:
+				process.answerInventoryCommand(client, command, hello.HostID, collectPprofProfile, log)
+			default:
+				log.Debugf("Ignoring unknown inventory command %q.", command.Name)
+			}
+		}
+	}
+}
+
+// inventoryLogTail is how much of the agent's log file is returned by a
+// collect-logs command.
+const inventoryLogTail = 64 * 1024
+
+// answerInventoryCommand runs a collector and posts its output back to
+// the auth server.
+func (process *TeleportProcess) answerInventoryCommand(client auth.ClientI, command auth.InventoryCommand, hostID string, collect func() ([]byte, error), log logrus.FieldLogger) {
+	result := auth.InventoryResult{HostID: hostID, ID: command.ID}
+	data, err := collect()
+	if err != nil {
+		result.Error = err.Error()
+	} else {
+		result.Data = data
+	}
+	if err := client.PostInventoryResult(process.ExitContext(), result); err != nil {
+		log.WithError(err).Warningf("Failed to post inventory result for command %v.", command.Name)
+	}
+}
+
+// collectRecentLogs returns the tail of the agent's log file.
+func (process *TeleportProcess) collectRecentLogs() ([]byte, error) {
+	output := process.Config.LogOutput
+	switch output {
+	case "", "stderr", "stdout", teleport.Syslog:
+		return nil, trace.NotFound("agent logs to %q, only file logging can be collected remotely", output)
+	}
+	file, err := os.Open(output)
+	if err != nil {
+		return nil, trace.ConvertSystemError(err)
+	}
+	defer file.Close()
+	info, err := file.Stat()
+	if err != nil {
+		return nil, trace.ConvertSystemError(err)
+	}
+	offset := info.Size() - inventoryLogTail
+	if offset < 0 {
+		offset = 0
+	}
+	if _, err := file.Seek(offset, io.SeekStart); err != nil {
+		return nil, trace.ConvertSystemError(err)
+	}
+	return ioutil.ReadAll(file)
+}
+
+// collectPprofProfile returns human-readable goroutine and heap
+// profiles.
+func collectPprofProfile() ([]byte, error) {
+	var buf bytes.Buffer
+	for _, name := range []string{"goroutine", "heap"} {
+		profile := rpprof.Lookup(name)
+		if profile == nil {
+			continue
+		}
+		fmt.Fprintf(&buf, "=== %v profile ===\n", name)
+		if err := profile.WriteTo(&buf, 1); err != nil {
+			return nil, trace.Wrap(err)
+		}
+		buf.WriteString("\n")
+	}
+	return buf.Bytes(), nil
+}
+
+// lowMemoryEventBufferSize is the async event queue size used in low
+// memory mode, a fraction of the library default.
+const lowMemoryEventBufferSize = 64
+
+// lowMemoryReportPeriod is how often the memory footprint is logged in
+// low memory mode.
+const lowMemoryReportPeriod = 5 * time.Minute
+
+// runMemoryReporter periodically logs the process memory footprint so
+// operators can validate the low memory mode on constrained hosts.
+func (process *TeleportProcess) runMemoryReporter() error {
+	ticker := time.NewTicker(lowMemoryReportPeriod)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-process.ExitContext().Done():
+			return nil
+		case <-ticker.C:
+			var stats runtime.MemStats
+			runtime.ReadMemStats(&stats)
+			process.log.Infof("Memory footprint: heap in use %v KiB, system %v KiB, goroutines %v.",
+				stats.HeapInuse/1024, stats.Sys/1024, runtime.NumGoroutine())
+		}
+	}
+}
@@ -253,6 +253,15 @@ func (process *TeleportProcess) importListener(typ listenerType, address string)
 
 // createListener creates listener and adds to a list of tracked listeners
 func (process *TeleportProcess) createListener(typ listenerType, address string) (net.Listener, error) {
+	// A listener may have been handed over by systemd socket activation.
+	if listener := process.systemdListener(typ, address); listener != nil {
+		process.Lock()
+		defer process.Unlock()
+		r := registeredListener{typ: typ, address: address, listener: listener}
+		process.registeredListeners = append(process.registeredListeners, r)
+		return listener, nil
+	}
+
 	listener, err := net.Listen("tcp", address)
 	if err != nil {
 		return nil, trace.Wrap(err)
@@ -397,6 +406,12 @@ const (
 )
 
 func (process *TeleportProcess) forkChild() error {
+	// Let systemd know a reload is in progress, the child reports READY
+	// again once its services are up.
+	if err := sdNotify(systemdStateReloading); err != nil {
+		process.log.WithError(err).Debug("Failed to notify systemd of reload.")
+	}
+
 	readPipe, writePipe, err := os.Pipe()
 	if err != nil {
 		return trace.ConvertSystemError(err)
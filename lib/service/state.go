@@ -168,3 +168,31 @@ func (f *processState) getState() componentStateEnum {
 	defer f.mu.Unlock()
 	return f.getStateLocked()
 }
+
+// stateName maps a component state to a human readable name for the
+// readiness endpoint.
+func stateName(state componentStateEnum) string {
+	switch state {
+	case stateOK:
+		return "ok"
+	case stateRecovering:
+		return "recovering"
+	case stateDegraded:
+		return "degraded"
+	case stateStarting:
+		return "starting"
+	}
+	return "unknown"
+}
+
+// componentStates returns the state of each registered component, so the
+// readiness endpoint can report which dependency is degraded.
+func (f *processState) componentStates() map[string]string {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	out := make(map[string]string, len(f.states))
+	for name, state := range f.states {
+		out[name] = stateName(state.state)
+	}
+	return out
+}
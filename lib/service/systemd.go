@@ -0,0 +1,121 @@
+/*
+Copyright 2021 Gravitational, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package service
+
+import (
+	"net"
+	"os"
+	"strconv"
+	"time"
+
+	"github.com/gravitational/trace"
+)
+
+// Systemd notification states, see sd_notify(3).
+const (
+	// systemdStateReady reports that startup is finished.
+	systemdStateReady = "READY=1"
+	// systemdStateReloading reports the start of a configuration reload.
+	systemdStateReloading = "RELOADING=1"
+	// systemdStateWatchdog pets the systemd watchdog.
+	systemdStateWatchdog = "WATCHDOG=1"
+)
+
+// sdNotify sends a state notification to the systemd notify socket. It
+// is a no-op outside of a systemd unit with NotifyAccess configured.
+func sdNotify(state string) error {
+	socket := os.Getenv("NOTIFY_SOCKET")
+	if socket == "" {
+		return nil
+	}
+	conn, err := net.DialUnix("unixgram", nil, &net.UnixAddr{Name: socket, Net: "unixgram"})
+	if err != nil {
+		return trace.Wrap(err)
+	}
+	defer conn.Close()
+	if _, err := conn.Write([]byte(state)); err != nil {
+		return trace.Wrap(err)
+	}
+	return nil
+}
+
+// notifySystemdReady reports readiness once all services initialized and
+// keeps petting the systemd watchdog when one is configured, so systemd
+// restarts a hung process.
+func (process *TeleportProcess) notifySystemdReady() error {
+	eventC := make(chan Event, 1)
+	process.WaitForEvent(process.ExitContext(), TeleportReadyEvent, eventC)
+	select {
+	case <-process.ExitContext().Done():
+		return nil
+	case <-eventC:
+	}
+	if err := sdNotify(systemdStateReady); err != nil {
+		process.log.WithError(err).Warning("Failed to notify systemd of readiness.")
+	}
+
+	// WATCHDOG_USEC is set by systemd when WatchdogSec is configured,
+	// ping at half the interval per sd_watchdog_enabled(3).
+	usec, err := strconv.ParseInt(os.Getenv("WATCHDOG_USEC"), 10, 64)
+	if err != nil || usec <= 0 {
+		return nil
+	}
+	interval := time.Duration(usec) * time.Microsecond / 2
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-process.ExitContext().Done():
+			return nil
+		case <-ticker.C:
+			if err := sdNotify(systemdStateWatchdog); err != nil {
+				process.log.WithError(err).Debug("Failed to pet the systemd watchdog.")
+			}
+		}
+	}
+}
+
+// systemdListenFDsStart is the first file descriptor used by systemd
+// socket activation, see sd_listen_fds(3).
+const systemdListenFDsStart = 3
+
+// systemdListener returns a listener passed through systemd socket
+// activation whose local address matches the requested one, nil when
+// socket activation is not in use or no socket matches.
+func (process *TeleportProcess) systemdListener(typ listenerType, address string) net.Listener {
+	if pid, err := strconv.Atoi(os.Getenv("LISTEN_PID")); err != nil || pid != os.Getpid() {
+		return nil
+	}
+	count, err := strconv.Atoi(os.Getenv("LISTEN_FDS"))
+	if err != nil || count <= 0 {
+		return nil
+	}
+	for fd := systemdListenFDsStart; fd < systemdListenFDsStart+count; fd++ {
+		file := os.NewFile(uintptr(fd), "systemd-socket")
+		listener, err := net.FileListener(file)
+		file.Close()
+		if err != nil {
+			continue
+		}
+		if listener.Addr().String() == address {
+			process.log.Infof("Using systemd activated socket for %v %v.", typ, address)
+			return listener
+		}
+		listener.Close()
+	}
+	return nil
+}
@@ -275,6 +275,13 @@ type UserCertParams struct {
 	// ActiveRequests tracks privilege escalation requests applied during
 	// certificate construction.
 	ActiveRequests RequestIDs
+	// AllowedResourceIDs, when set, limits the certificate to the listed
+	// resources ("kind/name"), set for approved resource access requests
+	AllowedResourceIDs []string
+
+	// SessionID binds the certificate to a specific session, set on
+	// single-use certificates issued for that session.
+	SessionID string
 	// MFAVerified is the UUID of an MFA device when this Identity was
 	// confirmed immediately after an MFA check.
 	MFAVerified string
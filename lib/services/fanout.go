@@ -23,6 +23,8 @@ import (
 	"github.com/gravitational/teleport/api/types"
 
 	"github.com/gravitational/trace"
+	log "github.com/sirupsen/logrus"
+	"go.uber.org/atomic"
 )
 
 const defaultQueueSize = 64
@@ -174,6 +176,14 @@ func (f *Fanout) Emit(events ...types.Event) {
 				emitEvent = fullEvent
 			}
 			if err := entry.watcher.emit(emitEvent); err != nil {
+				entry.watcher.setError(err)
+				// watchers that went away on their own get removed
+				// quietly, only slow consumers are worth a warning.
+				select {
+				case <-entry.watcher.ctx.Done():
+				default:
+					log.Warningf("Closing watcher %v, %v.", entry.watcher.watch.Name, err)
+				}
 				remove = append(remove, entry.watcher)
 				continue Inner
 			}
@@ -329,3 +339,82 @@ func (w *fanoutWatcher) Error() error {
 		return nil
 	}
 }
+
+// DefaultFanoutSetSize is the default number of members of a fanout set.
+const DefaultFanoutSetSize = 16
+
+// FanoutSet is a collection of separate Fanout instances. It dramatically
+// improves concurrent processing by fanning events out to the members in
+// parallel branches with independent locks, so a slow or heavily loaded
+// watcher only contends with the other watchers of its branch instead of
+// stalling the whole set.
+type FanoutSet struct {
+	// rw is used to ensure that sets of operations applied
+	// across all members are not interleaved.
+	rw      sync.RWMutex
+	counter *atomic.Uint64
+	members []*Fanout
+}
+
+// NewFanoutSet creates a new FanoutSet instance in an uninitialized
+// state. Until initialized, watchers will be queued but no events will
+// be sent.
+func NewFanoutSet() *FanoutSet {
+	members := make([]*Fanout, 0, DefaultFanoutSetSize)
+	for i := 0; i < DefaultFanoutSetSize; i++ {
+		members = append(members, NewFanout())
+	}
+	return &FanoutSet{
+		counter: atomic.NewUint64(0),
+		members: members,
+	}
+}
+
+// NewWatcher attaches a new watcher to a fanout set, assigning members
+// round-robin so watchers spread evenly across the branches.
+func (s *FanoutSet) NewWatcher(ctx context.Context, watch types.Watch) (types.Watcher, error) {
+	s.rw.RLock()
+	defer s.rw.RUnlock()
+	fi := int(s.counter.Inc() % uint64(len(s.members)))
+	return s.members[fi].NewWatcher(ctx, watch)
+}
+
+// SetInit sets the fanout set into an initialized state, sending OpInit
+// events to any watchers which were added prior to initialization.
+func (s *FanoutSet) SetInit() {
+	s.rw.RLock()
+	defer s.rw.RUnlock()
+	for _, f := range s.members {
+		f.SetInit()
+	}
+}
+
+// Emit broadcasts events to all matching watchers that have been
+// attached to this fanout set.
+func (s *FanoutSet) Emit(events ...types.Event) {
+	s.rw.RLock()
+	defer s.rw.RUnlock()
+	for _, f := range s.members {
+		f.Emit(events...)
+	}
+}
+
+// Reset closes all attached watchers and places the fanout set
+// into an uninitialized state.
+func (s *FanoutSet) Reset() {
+	s.rw.Lock()
+	defer s.rw.Unlock()
+	for _, f := range s.members {
+		f.Reset()
+	}
+}
+
+// Close permanently closes the fanout set. Existing watchers will be
+// closed and no new watchers will be added.
+func (s *FanoutSet) Close() {
+	s.rw.Lock()
+	defer s.rw.Unlock()
+	for _, f := range s.members {
+		f.Close()
+	}
+}
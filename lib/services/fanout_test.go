@@ -44,3 +44,56 @@ func TestFanoutWatcherClose(t *testing.T) {
 	assert.NoError(t, err)
 	assert.Equal(t, f.Len(), 0)
 }
+
+// TestFanoutSet verifies that a fanout set delivers events to watchers
+// spread across its members and closes watchers that fall behind.
+func TestFanoutSet(t *testing.T) {
+	s := NewFanoutSet()
+	defer s.Close()
+	s.SetInit()
+
+	node := &types.ServerV2{
+		Kind:     types.KindNode,
+		Metadata: types.Metadata{Name: "node-1"},
+	}
+
+	var watchers []types.Watcher
+	for i := 0; i < DefaultFanoutSetSize*2; i++ {
+		w, err := s.NewWatcher(context.TODO(), types.Watch{
+			Name:      "test",
+			Kinds:     []types.WatchKind{{Kind: types.KindNode}},
+			QueueSize: 2,
+		})
+		assert.NoError(t, err)
+		defer w.Close()
+		select {
+		case event := <-w.Events():
+			assert.Equal(t, types.OpInit, event.Type)
+		case <-time.After(time.Second):
+			t.Fatalf("Timeout waiting for init event")
+		}
+		watchers = append(watchers, w)
+	}
+
+	s.Emit(types.Event{Type: types.OpPut, Resource: node})
+	for _, w := range watchers {
+		select {
+		case event := <-w.Events():
+			assert.Equal(t, types.OpPut, event.Type)
+		case <-time.After(time.Second):
+			t.Fatalf("Timeout waiting for event")
+		}
+	}
+
+	// overflow the watcher queues without consuming, slow consumers
+	// get detected and closed instead of blocking the emitter.
+	slow := watchers[0]
+	for i := 0; i < 3; i++ {
+		s.Emit(types.Event{Type: types.OpPut, Resource: node})
+	}
+	select {
+	case <-slow.Done():
+	case <-time.After(time.Second):
+		t.Fatalf("Timeout waiting for slow consumer to be closed")
+	}
+}
@@ -22,7 +22,9 @@ package services
 
 import (
 	"context"
+	"strings"
 	"time"
+	"unicode"
 
 	apidefaults "github.com/gravitational/teleport/api/defaults"
 	"github.com/gravitational/teleport/api/types"
@@ -228,20 +230,55 @@ type AppSession interface {
 	DeleteAllAppSessions(context.Context) error
 }
 
-// VerifyPassword makes sure password satisfies our requirements (relaxed),
-// mostly to avoid putting garbage in
-func VerifyPassword(password []byte) error {
-	if len(password) < defaults.MinPasswordLength {
+// PasswordPolicy specifies the requirements new local user passwords
+// must satisfy.
+type PasswordPolicy struct {
+	// MinLength is the minimum password length, the default minimum is
+	// applied when zero
+	MinLength int
+	// RequireUppercase requires at least one upper case character
+	RequireUppercase bool
+	// RequireDigits requires at least one digit
+	RequireDigits bool
+	// RequireSymbols requires at least one punctuation or symbol character
+	RequireSymbols bool
+}
+
+// Check makes sure the password satisfies the policy.
+func (p PasswordPolicy) Check(password []byte) error {
+	minLength := p.MinLength
+	if minLength == 0 {
+		minLength = defaults.MinPasswordLength
+	}
+	if len(password) < minLength {
 		return trace.BadParameter(
-			"password is too short, min length is %v", defaults.MinPasswordLength)
+			"password is too short, min length is %v", minLength)
 	}
 	if len(password) > defaults.MaxPasswordLength {
 		return trace.BadParameter(
 			"password is too long, max length is %v", defaults.MaxPasswordLength)
 	}
+	pass := string(password)
+	if p.RequireUppercase && strings.IndexFunc(pass, unicode.IsUpper) < 0 {
+		return trace.BadParameter("password must contain at least one upper case character")
+	}
+	if p.RequireDigits && strings.IndexFunc(pass, unicode.IsDigit) < 0 {
+		return trace.BadParameter("password must contain at least one digit")
+	}
+	if p.RequireSymbols && strings.IndexFunc(pass, func(r rune) bool {
+		return unicode.IsPunct(r) || unicode.IsSymbol(r)
+	}) < 0 {
+		return trace.BadParameter("password must contain at least one symbol")
+	}
 	return nil
 }
 
+// VerifyPassword makes sure password satisfies our requirements (relaxed),
+// mostly to avoid putting garbage in
+func VerifyPassword(password []byte) error {
+	return PasswordPolicy{}.Check(password)
+}
+
 // GithubAuthRequest is the request to start Github OAuth2 flow
 type GithubAuthRequest struct {
 	// ConnectorID is the name of the connector to use
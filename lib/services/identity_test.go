@@ -0,0 +1,85 @@
+/*
+Copyright 2021 Gravitational, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package services
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestPasswordPolicyCheck(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		desc     string
+		policy   PasswordPolicy
+		password string
+		wantErr  string
+	}{
+		{
+			desc:     "default policy accepts simple passwords",
+			password: "qwerty",
+		},
+		{
+			desc:     "default policy rejects short passwords",
+			password: "abc",
+			wantErr:  "too short",
+		},
+		{
+			desc:     "custom minimum length",
+			policy:   PasswordPolicy{MinLength: 12},
+			password: "tooshort",
+			wantErr:  "too short, min length is 12",
+		},
+		{
+			desc:     "uppercase required",
+			policy:   PasswordPolicy{RequireUppercase: true},
+			password: "lowercase1!",
+			wantErr:  "upper case",
+		},
+		{
+			desc:     "digits required",
+			policy:   PasswordPolicy{RequireDigits: true},
+			password: "NoDigitsHere!",
+			wantErr:  "digit",
+		},
+		{
+			desc:     "symbols required",
+			policy:   PasswordPolicy{RequireSymbols: true},
+			password: "NoSymbols123",
+			wantErr:  "symbol",
+		},
+		{
+			desc:     "full policy accepts conforming password",
+			policy:   PasswordPolicy{MinLength: 10, RequireUppercase: true, RequireDigits: true, RequireSymbols: true},
+			password: "Sup3r-secret",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.desc, func(t *testing.T) {
+			err := tt.policy.Check([]byte(tt.password))
+			if tt.wantErr == "" {
+				require.NoError(t, err)
+				return
+			}
+			require.Error(t, err)
+			require.Contains(t, err.Error(), tt.wantErr)
+		})
+	}
+}
@@ -25,6 +25,7 @@ import (
 	"github.com/gravitational/teleport"
 	"github.com/gravitational/teleport/api/types"
 	"github.com/gravitational/teleport/api/types/wrappers"
+	"github.com/gravitational/teleport/lib/session"
 
 	"github.com/gravitational/trace"
 	log "github.com/sirupsen/logrus"
@@ -176,6 +177,9 @@ type Context struct {
 	// Resource is an optional resource, in case if the rule
 	// checks access to the resource
 	Resource types.Resource
+	// Session is an optional session, in case if the rule checks access
+	// to a tracked session
+	Session *session.Session
 }
 
 // String returns user friendly representation of this context
@@ -188,6 +192,9 @@ const (
 	UserIdentifier = "user"
 	// ResourceIdentifier represents resource registered identifier in the rules
 	ResourceIdentifier = "resource"
+	// SessionIdentifier represents a session in the rules, populated when
+	// checking access to tracked sessions
+	SessionIdentifier = "session"
 	// ImpersonateRoleIdentifier is a role to impersonate
 	ImpersonateRoleIdentifier = "impersonate_role"
 	// ImpersonateUserIdentifier is a user to impersonate
@@ -222,6 +229,22 @@ func (ctx *Context) GetIdentifier(fields []string) (interface{}, error) {
 			resource = ctx.Resource
 		}
 		return predicate.GetFieldByTag(resource, "json", fields[1:])
+	case SessionIdentifier:
+		sess := ctx.Session
+		if sess == nil {
+			sess = emptySession
+		}
+		// participants is a computed list of the party usernames, the
+		// most common condition (e.g. contains(session.participants,
+		// user.metadata.name)).
+		if len(fields) == 2 && fields[1] == "participants" {
+			participants := make([]string, 0, len(sess.Parties))
+			for _, party := range sess.Parties {
+				participants = append(participants, party.User)
+			}
+			return participants, nil
+		}
+		return predicate.GetFieldByTag(sess, "json", fields[1:])
 	default:
 		return nil, trace.NotFound("%v is not defined", strings.Join(fields, "."))
 	}
@@ -230,6 +253,9 @@ func (ctx *Context) GetIdentifier(fields []string) (interface{}, error) {
 // emptyResource is used when no resource is specified
 var emptyResource = &EmptyResource{}
 
+// emptySession is used when no session is specified
+var emptySession = &session.Session{}
+
 // emptyUser is used when no user is specified
 var emptyUser = &types.UserV2{}
 
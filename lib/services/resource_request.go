@@ -0,0 +1,62 @@
+/*
+Copyright 2021 Gravitational, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package services
+
+import (
+	"strings"
+
+	"github.com/gravitational/trace"
+)
+
+// ResourceRequestPrefix marks entries in an access request's role list
+// that request access to a single resource instead of a role, e.g.
+// "resource:node/db-1". The auth server translates these entries into a
+// narrow synthesized role and scopes the issued certificate to the
+// requested resources.
+const ResourceRequestPrefix = "resource:"
+
+// Resource kinds that can be requested individually.
+var requestableResourceKinds = []string{"node", "db", "kube"}
+
+// ParseResourceRequestIDs splits an access request's role list into
+// requested resource IDs ("kind/name") and plain role names.
+func ParseResourceRequestIDs(roles []string) (resourceIDs []string, remaining []string, err error) {
+	for _, role := range roles {
+		if !strings.HasPrefix(role, ResourceRequestPrefix) {
+			remaining = append(remaining, role)
+			continue
+		}
+		resourceID := strings.TrimPrefix(role, ResourceRequestPrefix)
+		parts := strings.SplitN(resourceID, "/", 2)
+		if len(parts) != 2 || parts[0] == "" || parts[1] == "" {
+			return nil, nil, trace.BadParameter("invalid resource request %q, expected resource:<kind>/<name>", role)
+		}
+		var supported bool
+		for _, kind := range requestableResourceKinds {
+			if parts[0] == kind {
+				supported = true
+				break
+			}
+		}
+		if !supported {
+			return nil, nil, trace.BadParameter("unsupported resource kind %q in request %q, supported kinds: %v",
+				parts[0], role, strings.Join(requestableResourceKinds, ", "))
+		}
+		resourceIDs = append(resourceIDs, resourceID)
+	}
+	return resourceIDs, remaining, nil
+}
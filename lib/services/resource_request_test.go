@@ -0,0 +1,45 @@
+/*
+Copyright 2021 Gravitational, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package services
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+// TestParseResourceRequestIDs checks splitting of role lists into
+// resource requests and plain roles.
+func TestParseResourceRequestIDs(t *testing.T) {
+	ids, roles, err := ParseResourceRequestIDs([]string{
+		"resource:node/db-1", "dbadmin", "resource:kube/staging",
+	})
+	require.NoError(t, err)
+	require.Equal(t, []string{"node/db-1", "kube/staging"}, ids)
+	require.Equal(t, []string{"dbadmin"}, roles)
+
+	_, _, err = ParseResourceRequestIDs([]string{"resource:desktop/w1"})
+	require.Error(t, err)
+
+	_, _, err = ParseResourceRequestIDs([]string{"resource:node"})
+	require.Error(t, err)
+
+	ids, roles, err = ParseResourceRequestIDs([]string{"admin"})
+	require.NoError(t, err)
+	require.Empty(t, ids)
+	require.Equal(t, []string{"admin"}, roles)
+}
@@ -746,6 +746,13 @@ type AccessChecker interface {
 	// for this role set, otherwise it returns ttl unchanged
 	AdjustSessionTTL(ttl time.Duration) time.Duration
 
+	// AdjustMFAVerifiedSessionTTL implements certificate TTL laddering based
+	// on MFA freshness: logins verified with a hardware key tap may use the
+	// full session TTL configured by the role set, other logins are capped
+	// at the strictest unverified-session TTL configured by the roles, if
+	// any.
+	AdjustMFAVerifiedSessionTTL(ttl time.Duration, mfaVerified bool) time.Duration
+
 	// AdjustClientIdleTimeout adjusts requested idle timeout
 	// to the lowest max allowed timeout, the most restrictive
 	// option will be picked
@@ -1094,6 +1101,53 @@ func (set RoleSet) AdjustSessionTTL(ttl time.Duration) time.Duration {
 	return ttl
 }
 
+// UnverifiedSessionTTLLabel is the role label that opts the role into
+// certificate TTL laddering: its value caps the session TTL of logins
+// not verified by a hardware key tap, e.g. "8h". Roles without the
+// label impose no extra cap beyond max_session_ttl, preserving the
+// pre-laddering behavior.
+const UnverifiedSessionTTLLabel = "session.unverified-max-ttl"
+
+// AdjustMFAVerifiedSessionTTL implements certificate TTL laddering based on
+// MFA freshness: logins verified with a hardware key tap may use the full
+// session TTL configured by the role set (via max_session_ttl), other logins
+// are capped at the strictest unverified-session TTL configured via the
+// UnverifiedSessionTTLLabel role label, so a stolen certificate has a
+// narrower window of use. Without the label the TTL is unchanged.
+func (set RoleSet) AdjustMFAVerifiedSessionTTL(ttl time.Duration, mfaVerified bool) time.Duration {
+	ttl = set.AdjustSessionTTL(ttl)
+	if mfaVerified {
+		return ttl
+	}
+	if limit := set.UnverifiedSessionTTL(); limit != 0 && ttl > limit {
+		ttl = limit
+	}
+	return ttl
+}
+
+// UnverifiedSessionTTL returns the strictest cap on sessions not
+// verified by a hardware key tap, as configured by the
+// UnverifiedSessionTTLLabel role label. Zero means no role configures a
+// cap.
+func (set RoleSet) UnverifiedSessionTTL() time.Duration {
+	var limit time.Duration
+	for _, role := range set {
+		value, ok := role.GetMetadata().Labels[UnverifiedSessionTTLLabel]
+		if !ok {
+			continue
+		}
+		d, err := time.ParseDuration(value)
+		if err != nil || d <= 0 {
+			log.Warnf("Ignoring invalid %v label value %q on role %v.", UnverifiedSessionTTLLabel, value, role.GetName())
+			continue
+		}
+		if limit == 0 || d < limit {
+			limit = d
+		}
+	}
+	return limit
+}
+
 // MaxConnections returns the maximum number of concurrent ssh connections
 // allowed.  If MaxConnections is zero then no maximum was defined
 // and the number of concurrent connections is unconstrained.
@@ -1441,6 +1495,57 @@ func (set RoleSet) CheckAccessToServer(login string, s types.Server, mfa AccessM
 	return trace.AccessDenied("access to server denied")
 }
 
+// ServerAccessExplanation describes why access to a server was allowed or
+// denied for a particular login, used by "tctl access ls" to debug RBAC.
+type ServerAccessExplanation struct {
+	// Allowed is true when at least one allow rule matched and no deny
+	// rule matched.
+	Allowed bool
+	// AllowedBy lists the roles whose allow rules matched.
+	AllowedBy []string
+	// DeniedBy is the role whose deny rule matched, if any.
+	DeniedBy string
+	// MFARequired is true when a session would have to pass a per-session
+	// MFA check.
+	MFARequired bool
+}
+
+// ExplainAccessToServer runs the same checks as CheckAccessToServer but
+// records which roles allowed or denied access instead of short-circuiting,
+// for interactive RBAC debugging. The outcome models a session without a
+// verified MFA tap.
+func (set RoleSet) ExplainAccessToServer(login string, s types.Server, mfa AccessMFAParams) (ServerAccessExplanation, error) {
+	expl := ServerAccessExplanation{MFARequired: mfa.AlwaysRequired}
+	for _, role := range set {
+		matchNamespace, _ := MatchNamespace(role.GetNamespaces(Deny), s.GetNamespace())
+		matchLabels, _, err := MatchLabels(role.GetNodeLabels(Deny), s.GetAllLabels())
+		if err != nil {
+			return ServerAccessExplanation{}, trace.Wrap(err)
+		}
+		matchLogin, _ := MatchLogin(role.GetLogins(Deny), login)
+		if matchNamespace && (matchLabels || matchLogin) {
+			expl.DeniedBy = role.GetName()
+			return expl, nil
+		}
+	}
+	for _, role := range set {
+		matchNamespace, _ := MatchNamespace(role.GetNamespaces(Allow), s.GetNamespace())
+		matchLabels, _, err := MatchLabels(role.GetNodeLabels(Allow), s.GetAllLabels())
+		if err != nil {
+			return ServerAccessExplanation{}, trace.Wrap(err)
+		}
+		matchLogin, _ := MatchLogin(role.GetLogins(Allow), login)
+		if matchNamespace && matchLabels && matchLogin {
+			expl.Allowed = true
+			expl.AllowedBy = append(expl.AllowedBy, role.GetName())
+			if role.GetOptions().RequireSessionMFA {
+				expl.MFARequired = true
+			}
+		}
+	}
+	return expl, nil
+}
+
 // CheckAccessToApp checks if a role has access to an application. Deny rules
 // are checked first, then allow rules. Access to an application is determined by
 // namespaces and labels.
@@ -2106,6 +2211,25 @@ func UnmarshalRole(bytes []byte, opts ...MarshalOption) (types.Role, error) {
 	}
 
 	switch h.Version {
+	case types.V2:
+		// Legacy V2 roles store the allow rules at the top level of the
+		// spec, migrate them into a V3 allow condition so ancient backups
+		// can still be imported.
+		role, err := migrateLegacyRoleV2(bytes)
+		if err != nil {
+			return nil, trace.Wrap(err)
+		}
+		if err := ValidateRole(role); err != nil {
+			return nil, trace.Wrap(err)
+		}
+		if cfg.ID != 0 {
+			role.SetResourceID(cfg.ID)
+		}
+		if !cfg.Expires.IsZero() {
+			role.SetExpiry(cfg.Expires)
+		}
+		log.Warnf("Migrated deprecated V2 role %q to %v.", role.GetName(), role.Version)
+		return role, nil
 	case types.V4:
 		// V4 roles are identical to V3 except for their defaults
 		fallthrough
@@ -2131,6 +2255,60 @@ func UnmarshalRole(bytes []byte, opts ...MarshalOption) (types.Role, error) {
 	return nil, trace.BadParameter("role version %q is not supported", h.Version)
 }
 
+// migrateLegacyRoleV2 converts the flat V2 role spec into a V3 role.
+func migrateLegacyRoleV2(bytes []byte) (*types.RoleV4, error) {
+	var legacy struct {
+		Kind     string         `json:"kind"`
+		Metadata types.Metadata `json:"metadata"`
+		Spec     struct {
+			MaxSessionTTL types.Duration      `json:"max_session_ttl"`
+			Logins        []string            `json:"logins"`
+			NodeLabels    map[string]string   `json:"node_labels"`
+			Namespaces    []string            `json:"namespaces"`
+			Resources     map[string][]string `json:"resources"`
+		} `json:"spec"`
+	}
+	if err := utils.FastUnmarshal(bytes, &legacy); err != nil {
+		return nil, trace.BadParameter(err.Error())
+	}
+
+	nodeLabels := types.Labels{}
+	for key, value := range legacy.Spec.NodeLabels {
+		nodeLabels[key] = apiutils.Strings{value}
+	}
+	// Sort resources so migrated rules come out in a stable order.
+	resources := make([]string, 0, len(legacy.Spec.Resources))
+	for resource := range legacy.Spec.Resources {
+		resources = append(resources, resource)
+	}
+	sort.Strings(resources)
+	var rules []types.Rule
+	for _, resource := range resources {
+		rules = append(rules, types.NewRule(resource, legacy.Spec.Resources[resource]))
+	}
+
+	role := &types.RoleV4{
+		Kind:     types.KindRole,
+		Version:  types.V3,
+		Metadata: legacy.Metadata,
+		Spec: types.RoleSpecV4{
+			Options: types.RoleOptions{
+				MaxSessionTTL: legacy.Spec.MaxSessionTTL,
+			},
+			Allow: types.RoleConditions{
+				Logins:     legacy.Spec.Logins,
+				NodeLabels: nodeLabels,
+				Namespaces: legacy.Spec.Namespaces,
+				Rules:      rules,
+			},
+		},
+	}
+	if err := role.CheckAndSetDefaults(); err != nil {
+		return nil, trace.Wrap(err)
+	}
+	return role, nil
+}
+
 // MarshalRole marshals the Role resource to JSON.
 func MarshalRole(role types.Role, opts ...MarshalOption) ([]byte, error) {
 	if err := ValidateRole(role); err != nil {
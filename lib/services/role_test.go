@@ -30,6 +30,7 @@ import (
 	"github.com/gravitational/teleport/api/types"
 	"github.com/gravitational/teleport/api/types/wrappers"
 	apiutils "github.com/gravitational/teleport/api/utils"
+	"github.com/gravitational/teleport/lib/session"
 	"github.com/gravitational/teleport/api/utils/sshutils"
 	"github.com/gravitational/teleport/lib/fixtures"
 	"github.com/gravitational/teleport/lib/tlsca"
@@ -92,6 +93,172 @@ func TestConnAndSessLimits(t *testing.T) {
 	}
 }
 
+func TestAdjustMFAVerifiedSessionTTL(t *testing.T) {
+	tts := []struct {
+		desc          string
+		maxTTL        time.Duration
+		unverifiedTTL string
+		reqTTL        time.Duration
+		mfaVerified   bool
+		want          time.Duration
+	}{
+		{
+			desc:          "MFA-verified login keeps the role-configured TTL",
+			maxTTL:        30 * time.Hour,
+			unverifiedTTL: "8h",
+			reqTTL:        30 * time.Hour,
+			mfaVerified:   true,
+			want:          30 * time.Hour,
+		},
+		{
+			desc:          "unverified login is capped at the configured rung",
+			maxTTL:        30 * time.Hour,
+			unverifiedTTL: "8h",
+			reqTTL:        30 * time.Hour,
+			mfaVerified:   false,
+			want:          8 * time.Hour,
+		},
+		{
+			desc:        "no configured rung leaves the TTL unchanged",
+			maxTTL:      30 * time.Hour,
+			reqTTL:      30 * time.Hour,
+			mfaVerified: false,
+			want:        30 * time.Hour,
+		},
+		{
+			desc:          "role max below the rung applies to both",
+			maxTTL:        time.Hour,
+			unverifiedTTL: "8h",
+			reqTTL:        30 * time.Hour,
+			mfaVerified:   false,
+			want:          time.Hour,
+		},
+		{
+			desc:          "invalid rung value is ignored",
+			maxTTL:        30 * time.Hour,
+			unverifiedTTL: "not-a-duration",
+			reqTTL:        30 * time.Hour,
+			mfaVerified:   false,
+			want:          30 * time.Hour,
+		},
+	}
+	for ti, tt := range tts {
+		cmt := fmt.Sprintf("test case %d: %s", ti, tt.desc)
+		labels := map[string]string{}
+		if tt.unverifiedTTL != "" {
+			labels[UnverifiedSessionTTLLabel] = tt.unverifiedTTL
+		}
+		role := &types.RoleV4{
+			Kind:    types.KindRole,
+			Version: types.V3,
+			Metadata: types.Metadata{
+				Name:      "ladder",
+				Namespace: apidefaults.Namespace,
+				Labels:    labels,
+			},
+			Spec: types.RoleSpecV4{
+				Options: types.RoleOptions{
+					MaxSessionTTL: types.NewDuration(tt.maxTTL),
+				},
+			},
+		}
+		require.NoError(t, role.CheckAndSetDefaults(), cmt)
+		set := NewRoleSet(role)
+		require.Equal(t, tt.want, set.AdjustMFAVerifiedSessionTTL(tt.reqTTL, tt.mfaVerified), cmt)
+	}
+}
+
+func TestExplainAccessToServer(t *testing.T) {
+	newRole := func(name string, spec types.RoleSpecV4) types.Role {
+		role := &types.RoleV4{
+			Kind:    types.KindRole,
+			Version: types.V3,
+			Metadata: types.Metadata{
+				Name:      name,
+				Namespace: apidefaults.Namespace,
+			},
+			Spec: spec,
+		}
+		require.NoError(t, role.CheckAndSetDefaults())
+		return role
+	}
+	dev := newRole("dev", types.RoleSpecV4{
+		Allow: types.RoleConditions{
+			Logins:     []string{"ubuntu"},
+			NodeLabels: types.Labels{"env": apiutils.Strings{"dev"}},
+			Namespaces: []string{apidefaults.Namespace},
+		},
+	})
+	mfaProd := newRole("prod", types.RoleSpecV4{
+		Options: types.RoleOptions{RequireSessionMFA: true},
+		Allow: types.RoleConditions{
+			Logins:     []string{"root"},
+			NodeLabels: types.Labels{types.Wildcard: apiutils.Strings{types.Wildcard}},
+			Namespaces: []string{apidefaults.Namespace},
+		},
+	})
+	noRoot := newRole("no-root", types.RoleSpecV4{
+		Deny: types.RoleConditions{
+			Logins:     []string{"root"},
+			Namespaces: []string{apidefaults.Namespace},
+		},
+	})
+	node := &types.ServerV2{
+		Kind:    types.KindNode,
+		Version: types.V2,
+		Metadata: types.Metadata{
+			Name:      "node-a",
+			Namespace: apidefaults.Namespace,
+			Labels:    map[string]string{"env": "dev"},
+		},
+		Spec: types.ServerSpecV2{Hostname: "node-a"},
+	}
+
+	// Allow rule in "dev" matches, no MFA requirement.
+	expl, err := NewRoleSet(dev, mfaProd).ExplainAccessToServer("ubuntu", node, AccessMFAParams{})
+	require.NoError(t, err)
+	require.True(t, expl.Allowed)
+	require.Equal(t, []string{"dev"}, expl.AllowedBy)
+	require.False(t, expl.MFARequired)
+
+	// "prod" matches and carries the per-session MFA requirement.
+	expl, err = NewRoleSet(dev, mfaProd).ExplainAccessToServer("root", node, AccessMFAParams{})
+	require.NoError(t, err)
+	require.True(t, expl.Allowed)
+	require.Equal(t, []string{"prod"}, expl.AllowedBy)
+	require.True(t, expl.MFARequired)
+
+	// Deny rules short-circuit and name the denying role.
+	expl, err = NewRoleSet(dev, mfaProd, noRoot).ExplainAccessToServer("root", node, AccessMFAParams{})
+	require.NoError(t, err)
+	require.False(t, expl.Allowed)
+	require.Equal(t, "no-root", expl.DeniedBy)
+}
+
+func TestMigrateLegacyRoleV2(t *testing.T) {
+	role, err := UnmarshalRole([]byte(`{
+		"kind": "role",
+		"version": "v2",
+		"metadata": {"name": "legacy"},
+		"spec": {
+			"max_session_ttl": "4h0m0s",
+			"logins": ["root", "admin"],
+			"node_labels": {"env": "legacy"},
+			"namespaces": ["default"],
+			"resources": {"session": ["list", "read"]}
+		}
+	}`))
+	require.NoError(t, err)
+	require.Equal(t, "legacy", role.GetName())
+	require.Equal(t, types.V3, role.GetVersion())
+	require.Equal(t, []string{"root", "admin"}, role.GetLogins(Allow))
+	require.Equal(t, types.Labels{"env": apiutils.Strings{"legacy"}}, role.GetNodeLabels(Allow))
+	require.Equal(t, 4*time.Hour, role.GetOptions().MaxSessionTTL.Duration())
+	rules := role.GetRules(Allow)
+	require.Len(t, rules, 1)
+	require.Equal(t, []string{"session"}, rules[0].Resources)
+}
+
 func TestRoleParse(t *testing.T) {
 	testCases := []struct {
 		name         string
@@ -169,11 +336,35 @@ func TestRoleParse(t *testing.T) {
 			matchMessage: `role version "" is not supported`,
 		},
 		{
-			name:         "validation error, bad version",
-			in:           `{"kind": "role", "version": "v2", "metadata": {"name": "defrole"}, "spec": {}}`,
-			role:         types.RoleV4{},
-			error:        trace.BadParameter(""),
-			matchMessage: `role version "v2" is not supported`,
+			name: "v2 role migrates to v3 defaults",
+			in:   `{"kind": "role", "version": "v2", "metadata": {"name": "defrole"}, "spec": {}}`,
+			role: types.RoleV4{
+				Kind:    types.KindRole,
+				Version: types.V3,
+				Metadata: types.Metadata{
+					Name:      "defrole",
+					Namespace: apidefaults.Namespace,
+				},
+				Spec: types.RoleSpecV4{
+					Options: types.RoleOptions{
+						CertificateFormat: constants.CertificateFormatStandard,
+						MaxSessionTTL:     types.NewDuration(apidefaults.MaxCertDuration),
+						PortForwarding:    types.NewBoolOption(true),
+						BPF:               apidefaults.EnhancedEvents(),
+					},
+					Allow: types.RoleConditions{
+						NodeLabels:       types.Labels{},
+						AppLabels:        types.Labels{types.Wildcard: []string{types.Wildcard}},
+						KubernetesLabels: types.Labels{types.Wildcard: []string{types.Wildcard}},
+						DatabaseLabels:   types.Labels{types.Wildcard: []string{types.Wildcard}},
+						Namespaces:       []string{apidefaults.Namespace},
+					},
+					Deny: types.RoleConditions{
+						Namespaces: []string{apidefaults.Namespace},
+					},
+				},
+			},
+			error: nil,
 		},
 		{
 			name: "v3 role with no spec gets v3 defaults",
@@ -2944,3 +3135,55 @@ func (f *userGetter) GetUser(name string, _ bool) (types.User, error) {
 	user.SetTraits(f.traits)
 	return user, nil
 }
+
+// TestSessionWhereClause checks that rules with where clauses can
+// reference the session being accessed.
+func TestSessionWhereClause(t *testing.T) {
+	role := &types.RoleV4{
+		Kind:    types.KindRole,
+		Version: types.V3,
+		Metadata: types.Metadata{
+			Name:      "participant",
+			Namespace: apidefaults.Namespace,
+		},
+		Spec: types.RoleSpecV4{
+			Allow: types.RoleConditions{
+				Namespaces: []string{apidefaults.Namespace},
+			},
+			// Sessions are readable by default through the implicit
+			// rules, the deny-side where clause narrows visibility to
+			// sessions the user participates in.
+			Deny: types.RoleConditions{
+				Namespaces: []string{apidefaults.Namespace},
+				Rules: []types.Rule{{
+					Resources: []string{types.KindSSHSession},
+					Verbs:     []string{types.VerbList, types.VerbRead},
+					Where:     `!contains(session.participants, user.metadata.name)`,
+				}},
+			},
+		},
+	}
+	require.NoError(t, role.CheckAndSetDefaults())
+	set := NewRoleSet(role)
+
+	user := &types.UserV2{
+		Kind:     types.KindUser,
+		Version:  types.V2,
+		Metadata: types.Metadata{Name: "alice", Namespace: apidefaults.Namespace},
+	}
+
+	mySession := &session.Session{
+		Parties: []session.Party{{User: "alice"}},
+	}
+	otherSession := &session.Session{
+		Parties: []session.Party{{User: "bob"}},
+	}
+
+	err := set.CheckAccessToRule(&Context{User: user, Session: mySession},
+		apidefaults.Namespace, types.KindSSHSession, types.VerbRead, true)
+	require.NoError(t, err)
+
+	err = set.CheckAccessToRule(&Context{User: user, Session: otherSession},
+		apidefaults.Namespace, types.KindSSHSession, types.VerbRead, true)
+	require.Error(t, err)
+}
@@ -20,6 +20,7 @@ import (
 	"encoding/json"
 	"fmt"
 	"net"
+	"strings"
 	"time"
 
 	"golang.org/x/crypto/ssh"
@@ -74,6 +75,10 @@ type AuthHandlerConfig struct {
 	// AccessPoint is used to access the Auth Server.
 	AccessPoint auth.AccessPoint
 
+	// Revocations, when set, rejects users on the cached cluster
+	// revocation list even while auth is unreachable.
+	Revocations *RevocationCache
+
 	// FIPS mode means Teleport started in a FedRAMP/FIPS 140-2 compliant
 	// configuration.
 	FIPS bool
@@ -140,6 +145,7 @@ func (h *AuthHandlers) CreateIdentityContext(sconn *ssh.ServerConn) (IdentityCon
 	}
 	identity.RoleSet = roleSet
 	identity.Impersonator = certificate.Extensions[teleport.CertExtensionImpersonator]
+	identity.CertSessionID = certificate.Extensions[teleport.CertExtensionSessionID]
 	accessRequestIDs, err := parseAccessRequestIDs(certificate.Extensions[teleport.CertExtensionTeleportActiveRequests])
 	if err != nil {
 		return IdentityContext{}, trace.Wrap(err)
@@ -416,15 +422,43 @@ func (h *AuthHandlers) canLoginWithRBAC(cert *ssh.Certificate, clusterName strin
 		AlwaysRequired: ap.GetRequireSessionMFA(),
 	}
 
+	// reject revoked users before any role evaluation, the cached list
+	// keeps working while auth is unreachable
+	if h.c.Revocations != nil && h.c.Revocations.IsRevoked(teleportUser) {
+		return trace.AccessDenied("user %s is on the cluster revocation list", teleportUser)
+	}
+
 	// check if roles allow access to server
 	if err := roles.CheckAccessToServer(osUser, h.c.Server.GetInfo(), mfaParams); err != nil {
 		return trace.AccessDenied("user %s@%s is not authorized to login as %v@%s: %v",
 			teleportUser, ca.GetClusterName(), osUser, clusterName, err)
 	}
 
+	// certificates issued for resource access requests are scoped to the
+	// requested resources on top of the role checks
+	if allowed := cert.Extensions[teleport.CertExtensionAllowedResources]; allowed != "" {
+		if !h.serverInAllowedResources(allowed) {
+			return trace.AccessDenied("user %s@%s is not authorized to login to %v: certificate is scoped to resources %v",
+				teleportUser, ca.GetClusterName(), h.c.Server.GetInfo().GetHostname(), allowed)
+		}
+	}
+
 	return nil
 }
 
+// serverInAllowedResources reports whether this server is in the
+// certificate's allowed resource list, matching "node/<hostname>" or
+// "node/<server name>" entries.
+func (h *AuthHandlers) serverInAllowedResources(allowed string) bool {
+	info := h.c.Server.GetInfo()
+	for _, resourceID := range strings.Split(allowed, ",") {
+		if resourceID == "node/"+info.GetHostname() || resourceID == "node/"+info.GetName() {
+			return true
+		}
+	}
+	return false
+}
+
 // fetchRoleSet fetches the services.RoleSet assigned to a Teleport user.
 func (h *AuthHandlers) fetchRoleSet(cert *ssh.Certificate, ca types.CertAuthority, teleportUser string, clusterName string) (services.RoleSet, error) {
 	// for local users, go and check their individual permissions
@@ -0,0 +1,74 @@
+/*
+Copyright 2021 Gravitational, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package srv
+
+import (
+	"regexp"
+
+	"github.com/gravitational/trace"
+)
+
+// CommandPolicy restricts which exec command lines may run on this node.
+// Deny patterns are checked first, then, when any allow patterns are
+// configured, the command must match one of them.
+type CommandPolicy struct {
+	allowed []*regexp.Regexp
+	denied  []*regexp.Regexp
+}
+
+// NewCommandPolicy compiles the allow and deny patterns into a policy.
+// Returns nil when both lists are empty, no policy to enforce.
+func NewCommandPolicy(allowed, denied []string) (*CommandPolicy, error) {
+	if len(allowed) == 0 && len(denied) == 0 {
+		return nil, nil
+	}
+	policy := &CommandPolicy{}
+	for _, pattern := range allowed {
+		re, err := regexp.Compile(pattern)
+		if err != nil {
+			return nil, trace.BadParameter("invalid allowed_commands pattern %q: %v", pattern, err)
+		}
+		policy.allowed = append(policy.allowed, re)
+	}
+	for _, pattern := range denied {
+		re, err := regexp.Compile(pattern)
+		if err != nil {
+			return nil, trace.BadParameter("invalid denied_commands pattern %q: %v", pattern, err)
+		}
+		policy.denied = append(policy.denied, re)
+	}
+	return policy, nil
+}
+
+// Check returns an error when the command line is not permitted on this
+// node.
+func (p *CommandPolicy) Check(command string) error {
+	for _, re := range p.denied {
+		if re.MatchString(command) {
+			return trace.AccessDenied("command %q is denied by this node's command policy (matched %q)", command, re.String())
+		}
+	}
+	if len(p.allowed) == 0 {
+		return nil
+	}
+	for _, re := range p.allowed {
+		if re.MatchString(command) {
+			return nil
+		}
+	}
+	return trace.AccessDenied("command %q does not match any allowed command pattern on this node", command)
+}
@@ -0,0 +1,80 @@
+/*
+Copyright 2021 Gravitational, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package srv
+
+import (
+	"os/exec"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+// TestCommandPolicy checks allow and deny pattern evaluation.
+func TestCommandPolicy(t *testing.T) {
+	// No patterns means no policy.
+	policy, err := NewCommandPolicy(nil, nil)
+	require.NoError(t, err)
+	require.Nil(t, policy)
+
+	// Deny wins over allow.
+	policy, err = NewCommandPolicy([]string{`^kubectl .*`}, []string{`.*delete.*`})
+	require.NoError(t, err)
+	require.NoError(t, policy.Check("kubectl get pods"))
+	require.Error(t, policy.Check("kubectl delete pod x"))
+	require.Error(t, policy.Check("rm -rf /"))
+
+	// Deny-only policy allows everything else.
+	policy, err = NewCommandPolicy(nil, []string{`^shutdown`})
+	require.NoError(t, err)
+	require.NoError(t, policy.Check("uptime"))
+	require.Error(t, policy.Check("shutdown -h now"))
+
+	// Invalid patterns are rejected.
+	_, err = NewCommandPolicy([]string{"("}, nil)
+	require.Error(t, err)
+}
+
+// TestEnvPolicy checks AcceptEnv-style pattern evaluation.
+func TestEnvPolicy(t *testing.T) {
+	policy, err := NewEnvPolicy(nil)
+	require.NoError(t, err)
+	require.Nil(t, policy)
+
+	policy, err = NewEnvPolicy([]string{"LANG", "LC_*"})
+	require.NoError(t, err)
+	require.True(t, policy.Accept("LANG"))
+	require.True(t, policy.Accept("LC_ALL"))
+	require.False(t, policy.Accept("LD_PRELOAD"))
+
+	_, err = NewEnvPolicy([]string{"[invalid"})
+	require.Error(t, err)
+}
+
+// TestExitSignal checks terminating signal extraction from exec errors.
+func TestExitSignal(t *testing.T) {
+	cmd := exec.Command("sh", "-c", "kill -KILL $$")
+	err := cmd.Run()
+	require.Error(t, err)
+	signal, coreDumped := exitSignal(err)
+	require.Equal(t, "KILL", signal)
+	require.False(t, coreDumped)
+
+	cmd = exec.Command("true")
+	require.NoError(t, cmd.Run())
+	signal, _ = exitSignal(nil)
+	require.Empty(t, signal)
+}
@@ -166,6 +166,10 @@ type IdentityContext struct {
 
 	// ActiveRequests is active access request IDs
 	ActiveRequests []string
+
+	// CertSessionID is the session ID the certificate is bound to, set on
+	// single-use session certificates.
+	CertSessionID string
 }
 
 // ServerContext holds session specific context, such as SSH auth agents, PTYs,
@@ -0,0 +1,214 @@
+/*
+Copyright 2021 Gravitational, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package common
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/gravitational/teleport/api/types"
+	"github.com/gravitational/teleport/lib/defaults"
+	"github.com/gravitational/trace"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/session"
+	"github.com/aws/aws-sdk-go/service/rds"
+	"github.com/aws/aws-sdk-go/service/rds/rdsiface"
+	"github.com/aws/aws-sdk-go/service/redshift"
+	"github.com/aws/aws-sdk-go/service/redshift/redshiftiface"
+)
+
+// DiscoveredDatabase describes an RDS instance or Aurora cluster found
+// through the AWS API.
+type DiscoveredDatabase struct {
+	// Name is the instance or cluster identifier.
+	Name string
+	// Description is a human readable description of the database.
+	Description string
+	// Protocol is the teleport database protocol, e.g. postgres or mysql.
+	Protocol string
+	// URI is the endpoint to connect to.
+	URI string
+	// Region is the AWS region the database runs in.
+	Region string
+	// Labels are the database resource tags.
+	Labels map[string]string
+	// RedshiftClusterID is set for discovered Redshift clusters.
+	RedshiftClusterID string
+}
+
+// DiscoverRDSDatabases returns all available RDS instances and Aurora
+// clusters in the region. Databases use IAM authentication, which must be
+// enabled on the instances.
+func DiscoverRDSDatabases(ctx context.Context, region string) ([]DiscoveredDatabase, error) {
+	awsSession, err := session.NewSessionWithOptions(session.Options{
+		SharedConfigState: session.SharedConfigEnable,
+		Config:            aws.Config{Region: aws.String(region)},
+	})
+	if err != nil {
+		return nil, trace.Wrap(err)
+	}
+	return discoverRDSDatabases(ctx, rds.New(awsSession), region)
+}
+
+// discoverRDSDatabases lists databases using the provided RDS client,
+// split out so tests can pass a fake.
+func discoverRDSDatabases(ctx context.Context, client rdsiface.RDSAPI, region string) ([]DiscoveredDatabase, error) {
+	var databases []DiscoveredDatabase
+
+	// Standalone RDS instances. Aurora cluster members are skipped in
+	// favor of their cluster endpoint below.
+	err := client.DescribeDBInstancesPagesWithContext(ctx, &rds.DescribeDBInstancesInput{},
+		func(page *rds.DescribeDBInstancesOutput, lastPage bool) bool {
+			for _, instance := range page.DBInstances {
+				if aws.StringValue(instance.DBClusterIdentifier) != "" {
+					continue
+				}
+				if aws.StringValue(instance.DBInstanceStatus) != "available" {
+					continue
+				}
+				protocol, err := rdsEngineToProtocol(aws.StringValue(instance.Engine))
+				if err != nil {
+					continue
+				}
+				if instance.Endpoint == nil {
+					continue
+				}
+				databases = append(databases, DiscoveredDatabase{
+					Name:        aws.StringValue(instance.DBInstanceIdentifier),
+					Description: fmt.Sprintf("RDS instance in %v", region),
+					Protocol:    protocol,
+					URI: fmt.Sprintf("%v:%v", aws.StringValue(instance.Endpoint.Address),
+						aws.Int64Value(instance.Endpoint.Port)),
+					Region: region,
+					Labels: rdsTagsToLabels(instance.TagList),
+				})
+			}
+			return true
+		})
+	if err != nil {
+		return nil, trace.Wrap(err)
+	}
+
+	// Aurora clusters, registered via their primary cluster endpoint.
+	err = client.DescribeDBClustersPagesWithContext(ctx, &rds.DescribeDBClustersInput{},
+		func(page *rds.DescribeDBClustersOutput, lastPage bool) bool {
+			for _, cluster := range page.DBClusters {
+				if aws.StringValue(cluster.Status) != "available" {
+					continue
+				}
+				protocol, err := rdsEngineToProtocol(aws.StringValue(cluster.Engine))
+				if err != nil {
+					continue
+				}
+				databases = append(databases, DiscoveredDatabase{
+					Name:        aws.StringValue(cluster.DBClusterIdentifier),
+					Description: fmt.Sprintf("Aurora cluster in %v", region),
+					Protocol:    protocol,
+					URI: fmt.Sprintf("%v:%v", aws.StringValue(cluster.Endpoint),
+						aws.Int64Value(cluster.Port)),
+					Region: region,
+					Labels: rdsTagsToLabels(cluster.TagList),
+				})
+			}
+			return true
+		})
+	if err != nil {
+		return nil, trace.Wrap(err)
+	}
+	return databases, nil
+}
+
+// rdsEngineToProtocol converts the RDS engine name to the teleport
+// database protocol.
+func rdsEngineToProtocol(engine string) (string, error) {
+	switch engine {
+	case "postgres", "aurora-postgresql":
+		return defaults.ProtocolPostgres, nil
+	case "mysql", "mariadb", "aurora", "aurora-mysql":
+		return defaults.ProtocolMySQL, nil
+	}
+	return "", trace.BadParameter("unsupported RDS engine %q", engine)
+}
+
+// rdsTagsToLabels converts RDS resource tags to static labels. Tags with
+// characters that are invalid in label keys are skipped, tag naming is
+// outside of the operator's control.
+func rdsTagsToLabels(tags []*rds.Tag) map[string]string {
+	labels := make(map[string]string)
+	for _, tag := range tags {
+		key := aws.StringValue(tag.Key)
+		if !types.IsValidLabelKey(key) {
+			continue
+		}
+		labels[key] = aws.StringValue(tag.Value)
+	}
+	return labels
+}
+
+// DiscoverRedshiftClusters returns all available Redshift clusters in the
+// region, served over the postgres protocol with IAM authentication.
+func DiscoverRedshiftClusters(ctx context.Context, region string) ([]DiscoveredDatabase, error) {
+	awsSession, err := session.NewSessionWithOptions(session.Options{
+		SharedConfigState: session.SharedConfigEnable,
+		Config:            aws.Config{Region: aws.String(region)},
+	})
+	if err != nil {
+		return nil, trace.Wrap(err)
+	}
+	return discoverRedshiftClusters(ctx, redshift.New(awsSession), region)
+}
+
+// discoverRedshiftClusters lists clusters using the provided Redshift
+// client, split out so tests can pass a fake.
+func discoverRedshiftClusters(ctx context.Context, client redshiftiface.RedshiftAPI, region string) ([]DiscoveredDatabase, error) {
+	var databases []DiscoveredDatabase
+	err := client.DescribeClustersPagesWithContext(ctx, &redshift.DescribeClustersInput{},
+		func(page *redshift.DescribeClustersOutput, lastPage bool) bool {
+			for _, cluster := range page.Clusters {
+				if aws.StringValue(cluster.ClusterStatus) != "available" {
+					continue
+				}
+				if cluster.Endpoint == nil {
+					continue
+				}
+				labels := make(map[string]string)
+				for _, tag := range cluster.Tags {
+					key := aws.StringValue(tag.Key)
+					if !types.IsValidLabelKey(key) {
+						continue
+					}
+					labels[key] = aws.StringValue(tag.Value)
+				}
+				databases = append(databases, DiscoveredDatabase{
+					Name:        aws.StringValue(cluster.ClusterIdentifier),
+					Description: fmt.Sprintf("Redshift cluster in %v", region),
+					Protocol:    defaults.ProtocolPostgres,
+					URI: fmt.Sprintf("%v:%v", aws.StringValue(cluster.Endpoint.Address),
+						aws.Int64Value(cluster.Endpoint.Port)),
+					Region:            region,
+					Labels:            labels,
+					RedshiftClusterID: aws.StringValue(cluster.ClusterIdentifier),
+				})
+			}
+			return true
+		})
+	if err != nil {
+		return nil, trace.Wrap(err)
+	}
+	return databases, nil
+}
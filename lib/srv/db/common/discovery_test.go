@@ -0,0 +1,140 @@
+/*
+Copyright 2021 Gravitational, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package common
+
+import (
+	"context"
+	"testing"
+
+	"github.com/gravitational/teleport/lib/defaults"
+
+	"github.com/aws/aws-sdk-go/aws"
+	awsrequest "github.com/aws/aws-sdk-go/aws/request"
+	"github.com/aws/aws-sdk-go/service/rds"
+	"github.com/aws/aws-sdk-go/service/rds/rdsiface"
+	"github.com/aws/aws-sdk-go/service/redshift"
+	"github.com/aws/aws-sdk-go/service/redshift/redshiftiface"
+
+	"github.com/stretchr/testify/require"
+)
+
+type fakeRDSClient struct {
+	rdsiface.RDSAPI
+	instances []*rds.DBInstance
+	clusters  []*rds.DBCluster
+}
+
+func (f *fakeRDSClient) DescribeDBInstancesPagesWithContext(ctx aws.Context, input *rds.DescribeDBInstancesInput, fn func(*rds.DescribeDBInstancesOutput, bool) bool, opts ...awsrequest.Option) error {
+	fn(&rds.DescribeDBInstancesOutput{DBInstances: f.instances}, true)
+	return nil
+}
+
+func (f *fakeRDSClient) DescribeDBClustersPagesWithContext(ctx aws.Context, input *rds.DescribeDBClustersInput, fn func(*rds.DescribeDBClustersOutput, bool) bool, opts ...awsrequest.Option) error {
+	fn(&rds.DescribeDBClustersOutput{DBClusters: f.clusters}, true)
+	return nil
+}
+
+func TestDiscoverRDSDatabases(t *testing.T) {
+	t.Parallel()
+
+	client := &fakeRDSClient{
+		instances: []*rds.DBInstance{
+			{
+				DBInstanceIdentifier: aws.String("postgres-prod"),
+				DBInstanceStatus:     aws.String("available"),
+				Engine:               aws.String("postgres"),
+				Endpoint: &rds.Endpoint{
+					Address: aws.String("postgres-prod.rds.example.com"),
+					Port:    aws.Int64(5432),
+				},
+				TagList: []*rds.Tag{{Key: aws.String("env"), Value: aws.String("prod")}},
+			},
+			{
+				// Aurora cluster member, skipped in favor of the cluster.
+				DBInstanceIdentifier: aws.String("aurora-member-1"),
+				DBInstanceStatus:     aws.String("available"),
+				Engine:               aws.String("aurora-mysql"),
+				DBClusterIdentifier:  aws.String("aurora-prod"),
+			},
+			{
+				// Unsupported engine, skipped.
+				DBInstanceIdentifier: aws.String("oracle-db"),
+				DBInstanceStatus:     aws.String("available"),
+				Engine:               aws.String("oracle-ee"),
+			},
+		},
+		clusters: []*rds.DBCluster{
+			{
+				DBClusterIdentifier: aws.String("aurora-prod"),
+				Status:              aws.String("available"),
+				Engine:              aws.String("aurora-mysql"),
+				Endpoint:            aws.String("aurora-prod.cluster.rds.example.com"),
+				Port:                aws.Int64(3306),
+			},
+		},
+	}
+
+	databases, err := discoverRDSDatabases(context.Background(), client, "us-east-1")
+	require.NoError(t, err)
+	require.Len(t, databases, 2)
+
+	require.Equal(t, "postgres-prod", databases[0].Name)
+	require.Equal(t, defaults.ProtocolPostgres, databases[0].Protocol)
+	require.Equal(t, "postgres-prod.rds.example.com:5432", databases[0].URI)
+	require.Equal(t, map[string]string{"env": "prod"}, databases[0].Labels)
+
+	require.Equal(t, "aurora-prod", databases[1].Name)
+	require.Equal(t, defaults.ProtocolMySQL, databases[1].Protocol)
+	require.Equal(t, "aurora-prod.cluster.rds.example.com:3306", databases[1].URI)
+}
+
+type fakeRedshiftClient struct {
+	redshiftiface.RedshiftAPI
+	clusters []*redshift.Cluster
+}
+
+func (f *fakeRedshiftClient) DescribeClustersPagesWithContext(ctx aws.Context, input *redshift.DescribeClustersInput, fn func(*redshift.DescribeClustersOutput, bool) bool, opts ...awsrequest.Option) error {
+	fn(&redshift.DescribeClustersOutput{Clusters: f.clusters}, true)
+	return nil
+}
+
+func TestDiscoverRedshiftClusters(t *testing.T) {
+	t.Parallel()
+
+	client := &fakeRedshiftClient{clusters: []*redshift.Cluster{
+		{
+			ClusterIdentifier: aws.String("warehouse"),
+			ClusterStatus:     aws.String("available"),
+			Endpoint: &redshift.Endpoint{
+				Address: aws.String("warehouse.redshift.example.com"),
+				Port:    aws.Int64(5439),
+			},
+		},
+		{
+			ClusterIdentifier: aws.String("rebooting"),
+			ClusterStatus:     aws.String("rebooting"),
+		},
+	}}
+
+	databases, err := discoverRedshiftClusters(context.Background(), client, "us-east-2")
+	require.NoError(t, err)
+	require.Len(t, databases, 1)
+	require.Equal(t, "warehouse", databases[0].Name)
+	require.Equal(t, defaults.ProtocolPostgres, databases[0].Protocol)
+	require.Equal(t, "warehouse.redshift.example.com:5439", databases[0].URI)
+	require.Equal(t, "warehouse", databases[0].RedshiftClusterID)
+}
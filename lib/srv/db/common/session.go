@@ -50,6 +50,15 @@ type Session struct {
 	Statements *StatementsCache
 }
 
+// WithUser returns a shallow copy of the session with the database user
+// replaced, used e.g. when the engine needs an administrative connection
+// to the same database.
+func (c *Session) WithUser(user string) *Session {
+	copy := *c
+	copy.DatabaseUser = user
+	return &copy
+}
+
 // String returns string representation of the session parameters.
 func (c *Session) String() string {
 	return fmt.Sprintf("db[%v] identity[%v] dbUser[%v] dbName[%v]",
@@ -0,0 +1,130 @@
+/*
+Copyright 2021 Gravitational, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package elasticsearch implements basic Elasticsearch support for
+// database access: connections are authorized and audited by Teleport and
+// proxied to the cluster over TLS. Requests are not interpreted, clients
+// keep using their regular Elasticsearch credentials with the target.
+package elasticsearch
+
+import (
+	"context"
+	"crypto/tls"
+	"io"
+	"net"
+	"time"
+
+	"github.com/gravitational/teleport/lib/services"
+	"github.com/gravitational/teleport/lib/srv/db/common"
+	"github.com/gravitational/teleport/lib/utils"
+
+	"github.com/gravitational/trace"
+	"github.com/jonboulle/clockwork"
+	"github.com/sirupsen/logrus"
+)
+
+// Engine implements basic Elasticsearch support: it terminates the
+// Teleport side of the connection and proxies the HTTP traffic to the
+// Elasticsearch cluster over TLS.
+//
+// Implements common.Engine.
+type Engine struct {
+	// Auth handles database access authentication.
+	Auth common.Auth
+	// Audit emits database access audit events.
+	Audit common.Audit
+	// Context is the database server close context.
+	Context context.Context
+	// Clock is the clock interface.
+	Clock clockwork.Clock
+	// Log is used for logging.
+	Log logrus.FieldLogger
+}
+
+// HandleConnection authorizes the connection and proxies it to the
+// Elasticsearch cluster.
+func (e *Engine) HandleConnection(ctx context.Context, sessionCtx *common.Session, clientConn net.Conn) error {
+	if err := e.authorizeConnection(ctx, sessionCtx); err != nil {
+		return trace.Wrap(err, "error authorizing database access")
+	}
+
+	serverConn, err := e.connect(ctx, sessionCtx)
+	if err != nil {
+		return trace.Wrap(err, "error connecting to the database")
+	}
+	defer serverConn.Close()
+
+	e.Audit.OnSessionStart(e.Context, sessionCtx, nil)
+	defer e.Audit.OnSessionEnd(e.Context, sessionCtx)
+
+	// Terminate the session when the client certificate expires, in
+	// line with how the other protocols enforce disconnect_expired_cert.
+	if sessionCtx.Checker.AdjustDisconnectExpiredCert(false) && !sessionCtx.Identity.Expires.IsZero() {
+		timer := time.AfterFunc(time.Until(sessionCtx.Identity.Expires), func() {
+			e.Log.Debug("Closing session, client certificate expired.")
+			clientConn.Close()
+			serverConn.Close()
+		})
+		defer timer.Stop()
+	}
+
+	errC := make(chan error, 2)
+	go func() {
+		_, err := io.Copy(serverConn, clientConn)
+		errC <- err
+	}()
+	go func() {
+		_, err := io.Copy(clientConn, serverConn)
+		errC <- err
+	}()
+	if err := <-errC; err != nil && !utils.IsOKNetworkError(err) {
+		return trace.Wrap(err)
+	}
+	return nil
+}
+
+// authorizeConnection checks if the user has access to the database.
+func (e *Engine) authorizeConnection(ctx context.Context, sessionCtx *common.Session) error {
+	ap, err := e.Auth.GetAuthPreference(ctx)
+	if err != nil {
+		return trace.Wrap(err)
+	}
+	mfaParams := services.AccessMFAParams{
+		Verified:       sessionCtx.Identity.MFAVerified != "",
+		AlwaysRequired: ap.GetRequireSessionMFA(),
+	}
+	err = sessionCtx.Checker.CheckAccessToDatabase(sessionCtx.Server, mfaParams,
+		&services.DatabaseLabelsMatcher{Labels: sessionCtx.Server.GetAllLabels()},
+		&services.DatabaseUserMatcher{User: sessionCtx.DatabaseUser})
+	if err != nil {
+		e.Audit.OnSessionStart(e.Context, sessionCtx, err)
+		return trace.Wrap(err)
+	}
+	return nil
+}
+
+// connect establishes the TLS connection to the Elasticsearch cluster.
+func (e *Engine) connect(ctx context.Context, sessionCtx *common.Session) (net.Conn, error) {
+	tlsConfig, err := e.Auth.GetTLSConfig(ctx, sessionCtx)
+	if err != nil {
+		return nil, trace.Wrap(err)
+	}
+	serverConn, err := tls.Dial("tcp", sessionCtx.Server.GetURI(), tlsConfig)
+	if err != nil {
+		return nil, trace.Wrap(err)
+	}
+	return serverConn, nil
+}
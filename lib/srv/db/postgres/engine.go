@@ -21,6 +21,8 @@ import (
 	"errors"
 	"fmt"
 	"net"
+	"strconv"
+	"strings"
 
 	"github.com/gravitational/teleport/api/types"
 	"github.com/gravitational/teleport/lib/services"
@@ -98,6 +100,11 @@ func (e *Engine) HandleConnection(ctx context.Context, sessionCtx *common.Sessio
 	if err != nil {
 		return trace.Wrap(err)
 	}
+	// Create the database user first if the database is configured for
+	// automatic user provisioning.
+	if err := e.maybeProvisionUser(ctx, sessionCtx); err != nil {
+		e.Log.WithError(err).Warnf("Failed to auto-provision database user %q.", sessionCtx.DatabaseUser)
+	}
 	// This is where we connect to the actual Postgres database.
 	server, hijackedConn, err := e.connect(ctx, sessionCtx)
 	if err != nil {
@@ -330,6 +337,73 @@ func (e *Engine) receiveFromClient(client *pgproto3.Backend, server *pgproto3.Fr
 	}
 }
 
+// AdminUserLabel is the static database label that enables automatic user
+// provisioning: its value is the admin user the database agent connects as
+// to create missing database users before a session.
+const AdminUserLabel = "teleport.dev/admin-user"
+
+// maybeProvisionUser creates the session's database user if the database
+// is configured for automatic user provisioning and the user does not
+// exist yet. The created user has login rights and no other privileges.
+func (e *Engine) maybeProvisionUser(ctx context.Context, sessionCtx *common.Session) error {
+	adminUser := sessionCtx.Server.GetStaticLabels()[AdminUserLabel]
+	if adminUser == "" || adminUser == sessionCtx.DatabaseUser {
+		return nil
+	}
+	// Connect as the admin user using the same certificate authentication
+	// the session itself uses.
+	adminSession := sessionCtx.WithUser(adminUser)
+	config, err := e.getConnectConfig(ctx, adminSession)
+	if err != nil {
+		return trace.Wrap(err)
+	}
+	conn, err := pgconn.ConnectConfig(ctx, config)
+	if err != nil {
+		return trace.Wrap(err)
+	}
+	defer conn.Close(ctx)
+
+	// CREATE USER has no IF NOT EXISTS form, so tolerate the duplicate
+	// object error for users that already exist.
+	result := conn.Exec(ctx, fmt.Sprintf("CREATE USER %v LOGIN", pgQuoteIdentifier(sessionCtx.DatabaseUser)))
+	if _, err := result.ReadAll(); err != nil {
+		var pgErr *pgconn.PgError
+		if errors.As(err, &pgErr) && pgErr.Code == pgDuplicateObjectCode {
+			return nil
+		}
+		return trace.Wrap(err)
+	}
+	e.Log.Infof("Auto-provisioned database user %q.", sessionCtx.DatabaseUser)
+	return nil
+}
+
+const (
+	// pgDuplicateObjectCode is the Postgres error code returned when the
+	// created object already exists.
+	pgDuplicateObjectCode = "42710"
+)
+
+// pgQuoteIdentifier quotes a Postgres identifier, doubling any embedded
+// quotes.
+func pgQuoteIdentifier(name string) string {
+	return `"` + strings.ReplaceAll(name, `"`, `""`) + `"`
+}
+
+// parseCommandCompleteRows extracts the row count from a Postgres command
+// completion tag, e.g. "SELECT 5" or "UPDATE 2". Not all commands report
+// row counts, in which case false is returned.
+func parseCommandCompleteRows(tag []byte) (int, bool) {
+	parts := strings.Split(string(tag), " ")
+	if len(parts) < 2 {
+		return 0, false
+	}
+	count, err := strconv.Atoi(parts[len(parts)-1])
+	if err != nil {
+		return 0, false
+	}
+	return count, true
+}
+
 // receiveFromServer receives messages from the provided frontend (which
 // is connected to the database instance) and relays them back to the psql
 // or other client via the provided backend.
@@ -349,10 +423,15 @@ func (e *Engine) receiveFromServer(server *pgproto3.Frontend, client *pgproto3.B
 			return
 		}
 		log.Debugf("Received server message: %#v.", message)
-		// This is where we would plug in custom logic for particular
-		// messages received from the Postgres server (i.e. emitting
-		// an audit event), but for now just pass them along back to
-		// the client.
+		switch msg := message.(type) {
+		case *pgproto3.CommandComplete:
+			// The command tag carries the number of rows the completed
+			// command affected or returned, e.g. "SELECT 5" or "UPDATE 2".
+			if count, ok := parseCommandCompleteRows(msg.CommandTag); ok {
+				resultRows.Add(float64(count))
+				log.Debugf("Command completed with %v result row(s).", count)
+			}
+		}
 		err = client.Send(message)
 		if err != nil {
 			log.WithError(err).Error("Failed to send message to client.")
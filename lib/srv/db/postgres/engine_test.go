@@ -0,0 +1,56 @@
+/*
+Copyright 2021 Gravitational, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package postgres
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseCommandCompleteRows(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		tag       string
+		wantRows  int
+		wantFound bool
+	}{
+		{tag: "SELECT 5", wantRows: 5, wantFound: true},
+		{tag: "UPDATE 2", wantRows: 2, wantFound: true},
+		{tag: "INSERT 0 1", wantRows: 1, wantFound: true},
+		{tag: "DELETE 0", wantRows: 0, wantFound: true},
+		{tag: "BEGIN", wantFound: false},
+		{tag: "CREATE TABLE", wantFound: false},
+	}
+	for _, tt := range tests {
+		rows, found := parseCommandCompleteRows([]byte(tt.tag))
+		require.Equal(t, tt.wantFound, found, tt.tag)
+		if tt.wantFound {
+			require.Equal(t, tt.wantRows, rows, tt.tag)
+		}
+	}
+}
+
+func TestPGQuoteIdentifier(t *testing.T) {
+	t.Parallel()
+
+	require.Equal(t, `"alice"`, pgQuoteIdentifier("alice"))
+	// Embedded quotes are doubled so the identifier cannot break out.
+	require.Equal(t, `"al""ice"`, pgQuoteIdentifier(`al"ice`))
+	require.Equal(t, `"a;DROP TABLE x"`, pgQuoteIdentifier("a;DROP TABLE x"))
+}
@@ -0,0 +1,40 @@
+/*
+Copyright 2021 Gravitational, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package postgres
+
+import (
+	"github.com/gravitational/teleport/lib/utils"
+
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+var (
+	// resultRows counts rows affected or returned by completed commands,
+	// as reported by the Postgres command completion tags.
+	resultRows = prometheus.NewCounter(
+		prometheus.CounterOpts{
+			Name: "teleport_db_postgres_result_rows_total",
+			Help: "Number of result rows returned or affected by proxied Postgres commands",
+		},
+	)
+)
+
+func init() {
+	// Ignore the error: collectors are registered once, duplicate
+	// registrations only happen in tests.
+	_ = utils.RegisterPrometheusCollectors(resultRows)
+}
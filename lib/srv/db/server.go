@@ -31,6 +31,7 @@ import (
 	"github.com/gravitational/teleport/lib/labels"
 	"github.com/gravitational/teleport/lib/srv"
 	"github.com/gravitational/teleport/lib/srv/db/common"
+	"github.com/gravitational/teleport/lib/srv/db/elasticsearch"
 	"github.com/gravitational/teleport/lib/srv/db/mongodb"
 	"github.com/gravitational/teleport/lib/srv/db/mysql"
 	"github.com/gravitational/teleport/lib/srv/db/postgres"
@@ -416,6 +417,14 @@ func (s *Server) dispatch(sessionCtx *common.Session, streamWriter events.Stream
 			Clock:   s.cfg.Clock,
 			Log:     sessionCtx.Log,
 		}, nil
+	case defaults.ProtocolElasticsearch:
+		return &elasticsearch.Engine{
+			Auth:    s.cfg.Auth,
+			Audit:   audit,
+			Context: s.closeContext,
+			Clock:   s.cfg.Clock,
+			Log:     sessionCtx.Log,
+		}, nil
 	}
 	return nil, trace.BadParameter("unsupported database protocol %q",
 		sessionCtx.Server.GetProtocol())
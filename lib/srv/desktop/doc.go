@@ -0,0 +1,26 @@
+/*
+Copyright 2021 Gravitational, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package desktop will implement the Windows desktop access subsystem.
+//
+// This tree does not ship desktop access yet: there is no RDP transport,
+// no windows_desktop_service and no desktop resource kinds to attach
+// clipboard or directory sharing to. When the subsystem lands, clipboard
+// sharing and local directory sharing over the RDP virtual channels are
+// expected to be gated by the allow_clipboard and allow_directory_sharing
+// role options and audited per transfer, mirroring how enhanced session
+// recording gates node sessions today.
+package desktop
@@ -0,0 +1,55 @@
+/*
+Copyright 2021 Gravitational, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package srv
+
+import (
+	"path/filepath"
+
+	"github.com/gravitational/trace"
+)
+
+// EnvPolicy controls which client-supplied environment variables the
+// node accepts, the equivalent of OpenSSH's AcceptEnv. Patterns use
+// shell globs, e.g. "LANG" or "LC_*". An empty policy accepts
+// everything, preserving historic behavior.
+type EnvPolicy struct {
+	patterns []string
+}
+
+// NewEnvPolicy validates the accept patterns. Returns nil when no
+// patterns are configured, everything is accepted.
+func NewEnvPolicy(patterns []string) (*EnvPolicy, error) {
+	if len(patterns) == 0 {
+		return nil, nil
+	}
+	for _, pattern := range patterns {
+		if _, err := filepath.Match(pattern, ""); err != nil {
+			return nil, trace.BadParameter("invalid accept_env pattern %q: %v", pattern, err)
+		}
+	}
+	return &EnvPolicy{patterns: patterns}, nil
+}
+
+// Accept reports whether the variable name may be set by the client.
+func (p *EnvPolicy) Accept(name string) bool {
+	for _, pattern := range p.patterns {
+		if ok, _ := filepath.Match(pattern, name); ok {
+			return true
+		}
+	}
+	return false
+}
@@ -29,6 +29,7 @@ import (
 	"time"
 
 	"golang.org/x/crypto/ssh"
+	"golang.org/x/sys/unix"
 
 	"github.com/gravitational/teleport"
 	apievents "github.com/gravitational/teleport/api/types/events"
@@ -57,6 +58,13 @@ type ExecResult struct {
 
 	// Code is return code that execution of the command resulted in.
 	Code int
+
+	// Signal is the name of the signal that terminated the command (e.g.
+	// "KILL"), empty when the command exited normally.
+	Signal string
+
+	// CoreDumped indicates the terminating signal produced a core dump.
+	CoreDumped bool
 }
 
 // Exec executes an "exec" request.
@@ -201,9 +209,12 @@ func (e *localExec) Wait() *ExecResult {
 	// Emit the result of execution to the Audit Log.
 	emitExecAuditEvent(e.Ctx, e.GetCommand(), err)
 
+	signal, coreDumped := exitSignal(err)
 	execResult := &ExecResult{
-		Command: e.GetCommand(),
-		Code:    exitCode(err),
+		Command:    e.GetCommand(),
+		Code:       exitCode(err),
+		Signal:     signal,
+		CoreDumped: coreDumped,
 	}
 
 	return execResult
@@ -345,9 +356,12 @@ func (e *remoteExec) Wait() *ExecResult {
 	// Emit the result of execution to the Audit Log.
 	emitExecAuditEvent(e.ctx, e.command, err)
 
+	signal, coreDumped := exitSignal(err)
 	return &ExecResult{
-		Command: e.GetCommand(),
-		Code:    exitCode(err),
+		Command:    e.GetCommand(),
+		Code:       exitCode(err),
+		Signal:     signal,
+		CoreDumped: coreDumped,
 	}
 }
 
@@ -575,3 +589,19 @@ func exitCode(err error) int {
 		return teleport.RemoteCommandFailure
 	}
 }
+
+// exitSignal extracts the name of the signal that terminated the command
+// and whether it produced a core dump, so the client receives an
+// "exit-signal" request instead of a synthetic exit code.
+func exitSignal(err error) (string, bool) {
+	switch v := err.(type) {
+	case *exec.ExitError:
+		waitStatus, ok := v.Sys().(syscall.WaitStatus)
+		if ok && waitStatus.Signaled() {
+			return strings.TrimPrefix(unix.SignalName(waitStatus.Signal()), "SIG"), waitStatus.CoreDump()
+		}
+	case *ssh.ExitError:
+		return v.Signal(), false
+	}
+	return "", false
+}
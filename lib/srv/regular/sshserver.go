@@ -162,6 +162,25 @@ type Server struct {
 	// configuration.
 	fips bool
 
+	// revocations, when set, rejects users on the cached cluster
+	// revocation list
+	revocations *srv.RevocationCache
+
+	// commandPolicy, when set, restricts which exec commands may run on
+	// this node
+	commandPolicy *srv.CommandPolicy
+
+	// envPolicy, when set, controls which client-supplied environment
+	// variables are accepted
+	envPolicy *srv.EnvPolicy
+
+	// banner is served through the SSH banner mechanism before
+	// authentication completes
+	banner string
+
+	// motd is printed to interactive sessions after login
+	motd string
+
 	// ebpf is the service used for enhanced session recording.
 	ebpf bpf.BPF
 
@@ -494,6 +513,49 @@ func SetUseTunnel(useTunnel bool) ServerOption {
 	}
 }
 
+// SetBanner serves a banner before authentication completes.
+func SetBanner(banner string) ServerOption {
+	return func(s *Server) error {
+		s.banner = banner
+		return nil
+	}
+}
+
+// SetMOTD prints a message of the day to interactive sessions after
+// login.
+func SetMOTD(motd string) ServerOption {
+	return func(s *Server) error {
+		s.motd = motd
+		return nil
+	}
+}
+
+// SetEnvPolicy controls which client-supplied environment variables the
+// node accepts.
+func SetEnvPolicy(policy *srv.EnvPolicy) ServerOption {
+	return func(s *Server) error {
+		s.envPolicy = policy
+		return nil
+	}
+}
+
+// SetCommandPolicy restricts which exec commands may run on this node.
+func SetCommandPolicy(policy *srv.CommandPolicy) ServerOption {
+	return func(s *Server) error {
+		s.commandPolicy = policy
+		return nil
+	}
+}
+
+// SetRevocationCache provides the cached cluster revocation list
+// enforced at login.
+func SetRevocationCache(cache *srv.RevocationCache) ServerOption {
+	return func(s *Server) error {
+		s.revocations = cache
+		return nil
+	}
+}
+
 func SetFIPS(fips bool) ServerOption {
 	return func(s *Server) error {
 		s.fips = fips
@@ -617,6 +679,7 @@ func New(addr utils.NetAddr,
 		FIPS:        s.fips,
 		Emitter:     s.StreamEmitter,
 		Clock:       s.clock,
+		Revocations: s.revocations,
 	}
 
 	s.authHandlers, err = srv.NewAuthHandlers(&authHandlerConfig)
@@ -626,6 +689,8 @@ func New(addr utils.NetAddr,
 
 	// common term handlers
 	s.termHandlers = &srv.TermHandlers{
+		CommandPolicy: s.commandPolicy,
+		MOTD:          s.motd,
 		SessionRegistry: s.reg,
 	}
 
@@ -640,6 +705,7 @@ func New(addr utils.NetAddr,
 		sshutils.SetKEXAlgorithms(s.kexAlgorithms),
 		sshutils.SetMACAlgorithms(s.macAlgorithms),
 		sshutils.SetFIPS(s.fips),
+		sshutils.SetBanner(s.banner),
 	)
 	if err != nil {
 		return nil, trace.Wrap(err)
@@ -1336,8 +1402,23 @@ func (s *Server) handleSessionRequests(ctx context.Context, ccx *sshutils.Connec
 		case result := <-scx.ExecResultCh:
 			scx.Debugf("Exec request (%q) complete: %v", result.Command, result.Code)
 
-			// The exec process has finished and delivered the execution result, send
-			// the result back to the client, and close the session and channel.
+			// The exec process has finished and delivered the execution
+			// result, send the result back to the client, and close the
+			// session and channel. Commands terminated by a signal are
+			// reported through "exit-signal" (RFC 4254 section 6.10) so
+			// clients see the real cause instead of a synthetic code.
+			if result.Signal != "" {
+				_, err := ch.SendRequest("exit-signal", false, ssh.Marshal(struct {
+					Signal     string
+					CoreDumped bool
+					Error      string
+					Lang       string
+				}{Signal: result.Signal, CoreDumped: result.CoreDumped, Error: fmt.Sprintf("Process terminated by signal %v", result.Signal)}))
+				if err != nil {
+					scx.Infof("Failed to send exit signal for %v: %v", result.Command, err)
+				}
+				return
+			}
 			_, err := ch.SendRequest("exit-status", false, ssh.Marshal(struct{ C uint32 }{C: uint32(result.Code)}))
 			if err != nil {
 				scx.Infof("Failed to send exit status for %v: %v", result.Command, err)
@@ -1486,13 +1567,18 @@ func (s *Server) handleSubsystem(ch ssh.Channel, req *ssh.Request, ctx *srv.Serv
 }
 
 // handleEnv accepts environment variables sent by the client and stores them
-// in connection context
+// in connection context. Variables outside the node's accept_env policy
+// are dropped and logged.
 func (s *Server) handleEnv(ch ssh.Channel, req *ssh.Request, ctx *srv.ServerContext) error {
 	var e sshutils.EnvReqParams
 	if err := ssh.Unmarshal(req.Payload, &e); err != nil {
 		ctx.Error(err)
 		return trace.Wrap(err, "failed to parse env request")
 	}
+	if s.envPolicy != nil && !s.envPolicy.Accept(e.Name) {
+		ctx.Warningf("Rejected client environment variable %q: not in this node's accept_env policy.", e.Name)
+		return nil
+	}
 	ctx.SetEnv(e.Name, e.Value)
 	return nil
 }
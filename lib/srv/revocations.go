@@ -0,0 +1,162 @@
+/*
+Copyright 2021 Gravitational, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package srv
+
+import (
+	"context"
+	"crypto"
+	"crypto/rsa"
+	"encoding/json"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+
+	"github.com/gravitational/teleport/lib/auth"
+
+	"github.com/gravitational/trace"
+	log "github.com/sirupsen/logrus"
+	"golang.org/x/crypto/ssh"
+)
+
+// revocationRefreshPeriod is how often the cached revocation list is
+// refreshed from auth.
+const revocationRefreshPeriod = time.Minute
+
+// revocationCacheFile is the name of the on-disk cache inside the data
+// directory, enforced across restarts while auth is unreachable.
+const revocationCacheFile = "revocations.json"
+
+// RevocationCache caches the cluster revocation list so nodes keep
+// enforcing it while disconnected from auth.
+type RevocationCache struct {
+	mu      sync.RWMutex
+	locked  map[string]bool
+	path    string
+	trusted []*rsa.PublicKey
+}
+
+// NewRevocationCache returns a cache backed by a file in the data
+// directory, loading the last persisted list if one exists. Lists are
+// only accepted when signed by one of the trusted cluster CA keys,
+// typically the host CA key that signed the node's own certificate.
+func NewRevocationCache(dataDir string, trusted ...ssh.PublicKey) *RevocationCache {
+	cache := &RevocationCache{
+		locked: map[string]bool{},
+		path:   filepath.Join(dataDir, revocationCacheFile),
+	}
+	for _, key := range trusted {
+		cryptoKey, ok := key.(ssh.CryptoPublicKey)
+		if !ok {
+			continue
+		}
+		rsaKey, ok := cryptoKey.CryptoPublicKey().(*rsa.PublicKey)
+		if !ok {
+			log.Warningf("Ignoring unsupported cluster CA key type %T for revocation list verification.", cryptoKey.CryptoPublicKey())
+			continue
+		}
+		cache.trusted = append(cache.trusted, rsaKey)
+	}
+	data, err := ioutil.ReadFile(cache.path)
+	if err != nil {
+		if !os.IsNotExist(err) {
+			log.WithError(err).Warning("Failed to load the cached revocation list.")
+		}
+		return cache
+	}
+	var list auth.RevocationList
+	if err := json.Unmarshal(data, &list); err != nil {
+		log.WithError(err).Warning("Ignoring corrupted cached revocation list.")
+		return cache
+	}
+	if err := cache.verify(&list); err != nil {
+		log.WithError(err).Warning("Ignoring cached revocation list with a bad signature.")
+		return cache
+	}
+	cache.apply(&list)
+	return cache
+}
+
+// IsRevoked reports whether certificates of the user must be rejected.
+func (c *RevocationCache) IsRevoked(user string) bool {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	return c.locked[user]
+}
+
+// Run refreshes the cache from auth until the context is canceled,
+// keeping the last good copy on failures.
+func (c *RevocationCache) Run(ctx context.Context, client auth.ClientI) {
+	ticker := time.NewTicker(revocationRefreshPeriod)
+	defer ticker.Stop()
+	for {
+		list, err := client.GetRevocationList(ctx)
+		switch {
+		case err != nil:
+			log.WithError(err).Debug("Failed to refresh the revocation list, keeping the cached copy.")
+		case c.verify(list) != nil:
+			log.Warning("Refusing a revocation list with an invalid signature.")
+		default:
+			c.apply(list)
+			c.persist(list)
+		}
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// apply replaces the in-memory lock set.
+func (c *RevocationCache) apply(list *auth.RevocationList) {
+	locked := make(map[string]bool, len(list.LockedUsers))
+	for _, user := range list.LockedUsers {
+		locked[user] = true
+	}
+	c.mu.Lock()
+	c.locked = locked
+	c.mu.Unlock()
+}
+
+// persist writes the list to the data directory for offline enforcement
+// across restarts.
+func (c *RevocationCache) persist(list *auth.RevocationList) {
+	data, err := json.Marshal(list)
+	if err != nil {
+		return
+	}
+	if err := ioutil.WriteFile(c.path, data, 0600); err != nil {
+		log.WithError(err).Warning("Failed to persist the revocation list.")
+	}
+}
+
+// verify checks the list signature against the trusted cluster CA
+// keys, so a tampered document cannot simply be re-signed with an
+// attacker-chosen key.
+func (c *RevocationCache) verify(list *auth.RevocationList) error {
+	if len(c.trusted) == 0 {
+		return trace.BadParameter("no trusted cluster CA keys to verify the revocation list with")
+	}
+	for _, key := range c.trusted {
+		if rsa.VerifyPKCS1v15(key, crypto.SHA256, list.Digest(), list.Signature) == nil {
+			return nil
+		}
+	}
+	return trace.BadParameter("revocation list signature does not match any trusted cluster CA key")
+}
@@ -17,6 +17,8 @@ limitations under the License.
 package srv
 
 import (
+	"strings"
+
 	"golang.org/x/crypto/ssh"
 
 	rsession "github.com/gravitational/teleport/lib/session"
@@ -28,6 +30,13 @@ import (
 // regular and forwarding server.
 type TermHandlers struct {
 	SessionRegistry *SessionRegistry
+
+	// CommandPolicy, when set, restricts which exec commands may run on
+	// this node.
+	CommandPolicy *CommandPolicy
+
+	// MOTD, when set, is printed to interactive sessions after login.
+	MOTD string
 }
 
 // HandleExec handles requests of type "exec" which can execute with or
@@ -38,11 +47,20 @@ func (t *TermHandlers) HandleExec(ch ssh.Channel, req *ssh.Request, ctx *ServerC
 	ctx.request = req
 
 	// Parse the exec request and store it in the context.
-	_, err := parseExecRequest(req, ctx)
+	execRequest, err := parseExecRequest(req, ctx)
 	if err != nil {
 		return trace.Wrap(err)
 	}
 
+	// Enforce the node command policy before anything is spawned, denied
+	// attempts land in the audit log.
+	if t.CommandPolicy != nil {
+		if err := t.CommandPolicy.Check(execRequest.GetCommand()); err != nil {
+			emitExecAuditEvent(ctx, execRequest.GetCommand(), err)
+			return trace.Wrap(err)
+		}
+	}
+
 	// If a terminal was previously allocated for this command, run command in
 	// an interactive session. Otherwise run it in an exec session.
 	if ctx.GetTerm() != nil {
@@ -110,6 +128,14 @@ func (t *TermHandlers) HandleShell(ch ssh.Channel, req *ssh.Request, ctx *Server
 	if err != nil {
 		return trace.Wrap(err)
 	}
+
+	// Print the node's message of the day before the shell starts.
+	if t.MOTD != "" {
+		if _, err := ch.Write([]byte(strings.ReplaceAll(t.MOTD, "\n", "\r\n") + "\r\n")); err != nil {
+			ctx.WithError(err).Warning("Failed to write the message of the day.")
+		}
+	}
+
 	if err := t.SessionRegistry.OpenSession(ch, req, ctx); err != nil {
 		return trace.Wrap(err)
 	}
@@ -130,6 +130,20 @@ func SetLimiter(limiter *limiter.Limiter) ServerOption {
 }
 
 // SetShutdownPollPeriod sets a polling period for graceful shutdowns of SSH servers
+// SetBanner serves a banner to clients through the SSH banner
+// mechanism before authentication completes.
+func SetBanner(banner string) ServerOption {
+	return func(s *Server) error {
+		if banner == "" {
+			return nil
+		}
+		s.cfg.BannerCallback = func(ssh.ConnMetadata) string {
+			return banner
+		}
+		return nil
+	}
+}
+
 func SetShutdownPollPeriod(period time.Duration) ServerOption {
 	return func(s *Server) error {
 		s.shutdownPollPeriod = period
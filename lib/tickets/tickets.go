@@ -0,0 +1,250 @@
+/*
+Copyright 2021 Gravitational, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package tickets implements minimal Jira and ServiceNow clients used by
+// the auth server to track access requests as tickets.
+package tickets
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+
+	apidefaults "github.com/gravitational/teleport/api/defaults"
+
+	"github.com/gravitational/trace"
+)
+
+// Supported ticketing systems.
+const (
+	// SystemJira tracks requests as Jira issues.
+	SystemJira = "jira"
+	// SystemServiceNow tracks requests as ServiceNow records.
+	SystemServiceNow = "servicenow"
+)
+
+// Normalized ticket statuses.
+const (
+	// StatusPending means the ticket has not been decided yet.
+	StatusPending = "pending"
+	// StatusApproved means the ticket was approved or resolved.
+	StatusApproved = "approved"
+	// StatusDenied means the ticket was rejected.
+	StatusDenied = "denied"
+)
+
+// Config describes the ticketing integration.
+type Config struct {
+	// System is the ticketing system, "jira" or "servicenow".
+	System string
+	// URL is the base address of the Jira or ServiceNow instance.
+	URL string
+	// Username authenticates the API calls together with APIToken.
+	Username string
+	// APIToken is the Jira API token or ServiceNow password.
+	APIToken string
+	// Project is the Jira project key or the ServiceNow table name the
+	// tickets are created in.
+	Project string
+}
+
+// CheckAndSetDefaults validates the integration configuration.
+func (c *Config) CheckAndSetDefaults() error {
+	switch c.System {
+	case SystemJira, SystemServiceNow:
+	default:
+		return trace.BadParameter("unsupported ticketing system %q, expected %q or %q",
+			c.System, SystemJira, SystemServiceNow)
+	}
+	u, err := url.Parse(c.URL)
+	if err != nil || (u.Scheme != "http" && u.Scheme != "https") {
+		return trace.BadParameter("ticketing url %q must use http or https", c.URL)
+	}
+	if c.Username == "" || c.APIToken == "" {
+		return trace.BadParameter("ticketing integration requires username and api_token")
+	}
+	if c.Project == "" {
+		return trace.BadParameter("ticketing integration requires a project key or table name")
+	}
+	return nil
+}
+
+// Client creates and polls tickets in the configured system.
+type Client struct {
+	config Config
+	client *http.Client
+}
+
+// NewClient returns a ticketing client.
+func NewClient(config Config) (*Client, error) {
+	if err := config.CheckAndSetDefaults(); err != nil {
+		return nil, trace.Wrap(err)
+	}
+	return &Client{
+		config: config,
+		client: &http.Client{
+			Timeout: apidefaults.DefaultDialTimeout,
+		},
+	}, nil
+}
+
+// System returns the configured ticketing system.
+func (c *Client) System() string {
+	return c.config.System
+}
+
+// CreateTicket opens a ticket and returns its identifier: the issue key
+// for Jira, the sys_id for ServiceNow.
+func (c *Client) CreateTicket(ctx context.Context, summary, description string) (string, error) {
+	switch c.config.System {
+	case SystemJira:
+		return c.createJiraIssue(ctx, summary, description)
+	case SystemServiceNow:
+		return c.createServiceNowRecord(ctx, summary, description)
+	}
+	return "", trace.BadParameter("unsupported ticketing system %q", c.config.System)
+}
+
+// TicketStatus returns the normalized status of a ticket.
+func (c *Client) TicketStatus(ctx context.Context, id string) (string, error) {
+	switch c.config.System {
+	case SystemJira:
+		return c.jiraIssueStatus(ctx, id)
+	case SystemServiceNow:
+		return c.serviceNowRecordStatus(ctx, id)
+	}
+	return "", trace.BadParameter("unsupported ticketing system %q", c.config.System)
+}
+
+func (c *Client) createJiraIssue(ctx context.Context, summary, description string) (string, error) {
+	body := map[string]interface{}{
+		"fields": map[string]interface{}{
+			"project":     map[string]string{"key": c.config.Project},
+			"summary":     summary,
+			"description": description,
+			"issuetype":   map[string]string{"name": "Task"},
+		},
+	}
+	var response struct {
+		Key string `json:"key"`
+	}
+	err := c.do(ctx, http.MethodPost, "/rest/api/2/issue", body, &response)
+	if err != nil {
+		return "", trace.Wrap(err)
+	}
+	if response.Key == "" {
+		return "", trace.BadParameter("Jira did not return an issue key")
+	}
+	return response.Key, nil
+}
+
+func (c *Client) jiraIssueStatus(ctx context.Context, id string) (string, error) {
+	var response struct {
+		Fields struct {
+			Status struct {
+				Name string `json:"name"`
+			} `json:"status"`
+		} `json:"fields"`
+	}
+	err := c.do(ctx, http.MethodGet, fmt.Sprintf("/rest/api/2/issue/%v?fields=status", url.PathEscape(id)), nil, &response)
+	if err != nil {
+		return "", trace.Wrap(err)
+	}
+	switch strings.ToLower(response.Fields.Status.Name) {
+	case "approved", "done", "closed", "resolved":
+		return StatusApproved, nil
+	case "rejected", "declined", "denied":
+		return StatusDenied, nil
+	}
+	return StatusPending, nil
+}
+
+func (c *Client) createServiceNowRecord(ctx context.Context, summary, description string) (string, error) {
+	body := map[string]string{
+		"short_description": summary,
+		"description":       description,
+	}
+	var response struct {
+		Result struct {
+			SysID string `json:"sys_id"`
+		} `json:"result"`
+	}
+	err := c.do(ctx, http.MethodPost, fmt.Sprintf("/api/now/table/%v", url.PathEscape(c.config.Project)), body, &response)
+	if err != nil {
+		return "", trace.Wrap(err)
+	}
+	if response.Result.SysID == "" {
+		return "", trace.BadParameter("ServiceNow did not return a sys_id")
+	}
+	return response.Result.SysID, nil
+}
+
+func (c *Client) serviceNowRecordStatus(ctx context.Context, id string) (string, error) {
+	var response struct {
+		Result struct {
+			Approval string `json:"approval"`
+		} `json:"result"`
+	}
+	err := c.do(ctx, http.MethodGet, fmt.Sprintf("/api/now/table/%v/%v?sysparm_fields=approval",
+		url.PathEscape(c.config.Project), url.PathEscape(id)), nil, &response)
+	if err != nil {
+		return "", trace.Wrap(err)
+	}
+	switch strings.ToLower(response.Result.Approval) {
+	case "approved":
+		return StatusApproved, nil
+	case "rejected":
+		return StatusDenied, nil
+	}
+	return StatusPending, nil
+}
+
+// do performs an authenticated JSON API call.
+func (c *Client) do(ctx context.Context, method, path string, body, response interface{}) error {
+	var reader *bytes.Reader
+	if body != nil {
+		data, err := json.Marshal(body)
+		if err != nil {
+			return trace.Wrap(err)
+		}
+		reader = bytes.NewReader(data)
+	} else {
+		reader = bytes.NewReader(nil)
+	}
+	req, err := http.NewRequestWithContext(ctx, method, strings.TrimRight(c.config.URL, "/")+path, reader)
+	if err != nil {
+		return trace.Wrap(err)
+	}
+	req.SetBasicAuth(c.config.Username, c.config.APIToken)
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("Accept", "application/json")
+	resp, err := c.client.Do(req)
+	if err != nil {
+		return trace.Wrap(err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		return trace.BadParameter("%v API returned status %v", c.config.System, resp.StatusCode)
+	}
+	if err := json.NewDecoder(resp.Body).Decode(response); err != nil {
+		return trace.Wrap(err)
+	}
+	return nil
+}
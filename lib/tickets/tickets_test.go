@@ -0,0 +1,107 @@
+/*
+Copyright 2021 Gravitational, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package tickets
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+// TestJiraTickets checks issue creation and status mapping against a
+// fake Jira API.
+func TestJiraTickets(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		user, token, ok := r.BasicAuth()
+		require.True(t, ok)
+		require.Equal(t, "bot@example.com", user)
+		require.Equal(t, "secret", token)
+		switch {
+		case r.Method == http.MethodPost && r.URL.Path == "/rest/api/2/issue":
+			fmt.Fprint(w, `{"key": "REQ-42"}`)
+		case r.Method == http.MethodGet && r.URL.Path == "/rest/api/2/issue/REQ-42":
+			fmt.Fprint(w, `{"fields": {"status": {"name": "Done"}}}`)
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+	defer server.Close()
+
+	client, err := NewClient(Config{
+		System:   SystemJira,
+		URL:      server.URL,
+		Username: "bot@example.com",
+		APIToken: "secret",
+		Project:  "REQ",
+	})
+	require.NoError(t, err)
+
+	id, err := client.CreateTicket(context.Background(), "summary", "description")
+	require.NoError(t, err)
+	require.Equal(t, "REQ-42", id)
+
+	status, err := client.TicketStatus(context.Background(), id)
+	require.NoError(t, err)
+	require.Equal(t, StatusApproved, status)
+}
+
+// TestServiceNowTickets checks record creation and approval mapping
+// against a fake ServiceNow API.
+func TestServiceNowTickets(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch {
+		case r.Method == http.MethodPost && r.URL.Path == "/api/now/table/u_access":
+			fmt.Fprint(w, `{"result": {"sys_id": "abc123"}}`)
+		case r.Method == http.MethodGet && r.URL.Path == "/api/now/table/u_access/abc123":
+			fmt.Fprint(w, `{"result": {"approval": "rejected"}}`)
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+	defer server.Close()
+
+	client, err := NewClient(Config{
+		System:   SystemServiceNow,
+		URL:      server.URL,
+		Username: "bot",
+		APIToken: "secret",
+		Project:  "u_access",
+	})
+	require.NoError(t, err)
+
+	id, err := client.CreateTicket(context.Background(), "summary", "description")
+	require.NoError(t, err)
+	require.Equal(t, "abc123", id)
+
+	status, err := client.TicketStatus(context.Background(), id)
+	require.NoError(t, err)
+	require.Equal(t, StatusDenied, status)
+}
+
+// TestTicketingConfigCheck checks integration configuration validation.
+func TestTicketingConfigCheck(t *testing.T) {
+	cfg := Config{System: "trello", URL: "https://x", Username: "u", APIToken: "t", Project: "p"}
+	require.Error(t, cfg.CheckAndSetDefaults())
+	cfg = Config{System: SystemJira, URL: "https://x", Username: "u", APIToken: "t"}
+	require.Error(t, cfg.CheckAndSetDefaults())
+	cfg = Config{System: SystemJira, URL: "https://x", Username: "u", APIToken: "t", Project: "p"}
+	require.NoError(t, cfg.CheckAndSetDefaults())
+}
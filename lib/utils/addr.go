@@ -160,6 +160,11 @@ func ParseAddr(a string) (*NetAddr, error) {
 		return nil, trace.BadParameter("missing parameter address")
 	}
 	if !strings.Contains(a, "://") {
+		// Bracket raw IPv6 literals, e.g. "::1" -> "[::1]", so the
+		// URL parser does not treat the colons as a port separator.
+		if ip := net.ParseIP(a); ip != nil && ip.To4() == nil {
+			a = "[" + a + "]"
+		}
 		a = "tcp://" + a
 	}
 	u, err := url.Parse(a)
@@ -270,10 +275,11 @@ func IsLocalhost(host string) bool {
 // host's loopback interface
 func IsLoopback(host string) bool {
 	if strings.Contains(host, ":") {
-		var err error
-		host, _, err = net.SplitHostPort(host)
-		if err != nil {
-			return false
+		// The host can be in the host:port form, but a bare IPv6
+		// literal contains colons as well, so only strip the port if
+		// the address actually splits.
+		if h, _, err := net.SplitHostPort(host); err == nil {
+			host = h
 		}
 	}
 	ips, err := net.LookupIP(host)
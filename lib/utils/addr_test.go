@@ -92,6 +92,20 @@ func (s *AddrTestSuite) TestParseIPV6(c *C) {
 	c.Assert(addr, NotNil)
 	c.Assert(addr.Addr, Equals, "[::1]")
 	c.Assert(addr.Host(), Equals, "::1")
+
+	// A raw IPv6 literal without brackets is bracketed automatically
+	addr, err = ParseAddr("::1")
+	c.Assert(err, IsNil)
+	c.Assert(addr, NotNil)
+	c.Assert(addr.Addr, Equals, "[::1]")
+	c.Assert(addr.Host(), Equals, "::1")
+
+	// The IPv6 unspecified address can be used for dual-stack listeners
+	addr, err = ParseAddr("::")
+	c.Assert(err, IsNil)
+	c.Assert(addr, NotNil)
+	c.Assert(addr.Addr, Equals, "[::]")
+	c.Assert(addr.Host(), Equals, "::")
 }
 
 func (s *AddrTestSuite) TestParseEmptyPort(c *C) {
@@ -169,6 +183,8 @@ func (s *AddrTestSuite) TestLoopbackAddrs(c *C) {
 		{in: "localhost", expected: true},
 		{in: "localhost:5000", expected: true},
 		{in: "127.0.0.2:4003", expected: true},
+		{in: "::1", expected: true},
+		{in: "[::1]:3080", expected: true},
 		{in: "", expected: false},
 		{in: "bad-host.example.com", expected: false},
 		{in: "bad-host.example.com:443", expected: false},
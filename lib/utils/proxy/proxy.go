@@ -30,6 +30,7 @@ import (
 	"github.com/gravitational/trace"
 
 	"golang.org/x/crypto/ssh"
+	socks "golang.org/x/net/proxy"
 
 	"github.com/sirupsen/logrus"
 )
@@ -71,7 +72,8 @@ func (d directDial) DialTimeout(network, address string, timeout time.Duration)
 }
 
 type proxyDial struct {
-	proxyHost string
+	proxyHost   string
+	proxyScheme string
 }
 
 // DialTimeout acts like Dial but takes a timeout.
@@ -83,14 +85,14 @@ func (d proxyDial) DialTimeout(network, address string, timeout time.Duration) (
 		defer cancel()
 		ctx = timeoutCtx
 	}
-	return dialProxy(ctx, d.proxyHost, address)
+	return dialProxy(ctx, d.proxyScheme, d.proxyHost, address)
 }
 
 // Dial first connects to a proxy, then uses the connection to establish a new
 // SSH connection.
 func (d proxyDial) Dial(network string, addr string, config *ssh.ClientConfig) (*ssh.Client, error) {
 	// Build a proxy connection first.
-	pconn, err := dialProxy(context.Background(), d.proxyHost, addr)
+	pconn, err := dialProxy(context.Background(), d.proxyScheme, d.proxyHost, addr)
 	if err != nil {
 		return nil, trace.Wrap(err)
 	}
@@ -110,23 +112,49 @@ func (d proxyDial) Dial(network string, addr string, config *ssh.ClientConfig) (
 
 // DialerFromEnvironment returns a Dial function. If the https_proxy or http_proxy
 // environment variable are set, it returns a function that will dial through
-// said proxy server. If neither variable is set, it will connect to the SSH
-// server directly.
+// said proxy server, using HTTP CONNECT for http(s) proxies and the SOCKS5
+// protocol for socks5:// proxies. If neither variable is set, it will
+// connect to the SSH server directly.
 func DialerFromEnvironment(addr string) Dialer {
 	// Try and get proxy addr from the environment.
-	proxyAddr := getProxyAddress(addr)
+	proxyURL := getProxyAddress(addr)
 
 	// If no proxy settings are in environment return regular ssh dialer,
 	// otherwise return a proxy dialer.
-	if proxyAddr == "" {
+	if proxyURL == nil {
 		log.Debugf("No proxy set in environment, returning direct dialer.")
 		return directDial{}
 	}
-	log.Debugf("Found proxy %q in environment, returning proxy dialer.", proxyAddr)
-	return proxyDial{proxyHost: proxyAddr}
+	log.Debugf("Found proxy %v in environment, returning proxy dialer.", proxyURL)
+	return proxyDial{proxyHost: proxyURL.Host, proxyScheme: proxyURL.Scheme}
 }
 
-func dialProxy(ctx context.Context, proxyAddr string, addr string) (net.Conn, error) {
+// dialProxy dials the target addr through the outbound proxy at proxyAddr,
+// using the protocol indicated by proxyScheme.
+func dialProxy(ctx context.Context, proxyScheme, proxyAddr, addr string) (net.Conn, error) {
+	if proxyScheme == "socks5" {
+		return dialProxySOCKS5(ctx, proxyAddr, addr)
+	}
+	return dialProxyCONNECT(ctx, proxyAddr, addr)
+}
+
+// dialProxySOCKS5 dials the target addr through a SOCKS5 proxy.
+func dialProxySOCKS5(ctx context.Context, proxyAddr, addr string) (net.Conn, error) {
+	dialer, err := socks.SOCKS5("tcp", proxyAddr, nil, &net.Dialer{})
+	if err != nil {
+		return nil, trace.Wrap(err)
+	}
+	if contextDialer, ok := dialer.(socks.ContextDialer); ok {
+		conn, err := contextDialer.DialContext(ctx, "tcp", addr)
+		return conn, trace.ConvertSystemError(err)
+	}
+	conn, err := dialer.Dial("tcp", addr)
+	return conn, trace.ConvertSystemError(err)
+}
+
+// dialProxyCONNECT dials the target addr through an HTTP proxy using the
+// CONNECT method.
+func dialProxyCONNECT(ctx context.Context, proxyAddr string, addr string) (net.Conn, error) {
 
 	var d net.Dialer
 	conn, err := d.DialContext(ctx, "tcp", proxyAddr)
@@ -178,7 +206,7 @@ func dialProxy(ctx context.Context, proxyAddr string, addr string) (net.Conn, er
 	}, nil
 }
 
-func getProxyAddress(addr string) string {
+func getProxyAddress(addr string) *url.URL {
 	envs := []string{
 		teleport.HTTPSProxy,
 		strings.ToLower(teleport.HTTPSProxy),
@@ -191,35 +219,35 @@ func getProxyAddress(addr string) string {
 		if envAddr == "" {
 			continue
 		}
-		proxyAddr, err := parse(envAddr)
+		proxyURL, err := parse(envAddr)
 		if err != nil {
 			log.Debugf("Unable to parse environment variable %q: %q.", v, envAddr)
 			continue
 		}
-		log.Debugf("Successfully parsed environment variable %q: %q to %q.", v, envAddr, proxyAddr)
+		log.Debugf("Successfully parsed environment variable %q: %q to %v.", v, envAddr, proxyURL)
 		if !useProxy(addr) {
 			log.Debugf("Matched NO_PROXY override for %q: %q, going to ignore proxy variable.", v, envAddr)
-			return ""
+			return nil
 		}
-		return proxyAddr
+		return proxyURL
 	}
 
 	log.Debugf("No valid environment variables found.")
-	return ""
+	return nil
 }
 
-// parse will extract the host:port of the proxy to dial to. If the
-// value is not prefixed by "http", then it will prepend "http" and try.
-func parse(addr string) (string, error) {
+// parse will extract the proxy URL to dial to. If the value is not
+// prefixed by a supported scheme, then it will prepend "http" and try.
+func parse(addr string) (*url.URL, error) {
 	proxyurl, err := url.Parse(addr)
-	if err != nil || !strings.HasPrefix(proxyurl.Scheme, "http") {
+	if err != nil || !(strings.HasPrefix(proxyurl.Scheme, "http") || proxyurl.Scheme == "socks5") {
 		proxyurl, err = url.Parse("http://" + addr)
 		if err != nil {
-			return "", trace.Wrap(err)
+			return nil, trace.Wrap(err)
 		}
 	}
 
-	return proxyurl.Host, nil
+	return proxyurl, nil
 }
 
 // bufferedConn is used when part of the data on a connection has already been
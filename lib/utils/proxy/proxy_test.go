@@ -42,10 +42,11 @@ func (s *ProxySuite) TestGetProxyAddress(c *check.C) {
 		val  string
 	}
 	var tests = []struct {
-		info       string
-		env        []env
-		targetAddr string
-		proxyAddr  string
+		info        string
+		env         []env
+		targetAddr  string
+		proxyAddr   string
+		proxyScheme string
 	}{
 		{
 			info:       "valid, can be raw host:port",
@@ -71,6 +72,13 @@ func (s *ProxySuite) TestGetProxyAddress(c *check.C) {
 			proxyAddr:  "proxy:1234",
 			targetAddr: "192.168.1.1:3030",
 		},
+		{
+			info:        "valid, socks5 endpoint can be set in https_proxy",
+			env:         []env{{name: "https_proxy", val: "socks5://proxy:1080"}},
+			proxyAddr:   "proxy:1080",
+			proxyScheme: "socks5",
+			targetAddr:  "192.168.1.1:3030",
+		},
 		{
 			info: "valid, http endpoint can be set in https_proxy, but no_proxy override matches domain",
 			env: []env{
@@ -107,7 +115,15 @@ func (s *ProxySuite) TestGetProxyAddress(c *check.C) {
 		p := getProxyAddress(tt.targetAddr)
 		unsetEnv()
 
-		c.Assert(p, check.Equals, tt.proxyAddr, comment)
+		if tt.proxyAddr == "" {
+			c.Assert(p, check.IsNil, comment)
+			continue
+		}
+		c.Assert(p, check.NotNil, comment)
+		c.Assert(p.Host, check.Equals, tt.proxyAddr, comment)
+		if tt.proxyScheme != "" {
+			c.Assert(p.Scheme, check.Equals, tt.proxyScheme, comment)
+		}
 	}
 }
 
@@ -67,6 +67,7 @@ import (
 	lemma_secret "github.com/mailgun/lemma/secret"
 	"github.com/sirupsen/logrus"
 	"golang.org/x/crypto/ssh"
+	"golang.org/x/net/websocket"
 )
 
 const (
@@ -119,6 +120,10 @@ func SetClock(clock clockwork.Clock) HandlerOption {
 type Config struct {
 	// PluginRegistry handles plugin registration
 	PluginRegistry plugin.Registry
+
+	// SecurityHeaders overrides or extends the security headers served
+	// with every response, an empty value removes a default header.
+	SecurityHeaders map[string]string
 	// Proxy is a reverse tunnel proxy that handles connections
 	// to local cluster or remote clusters using unified interface
 	Proxy reversetunnel.Tunnel
@@ -178,9 +183,43 @@ type RewritingHandler struct {
 	appHandler *app.Handler
 }
 
+// securityHeaderDefaults are applied to every proxy response unless
+// overridden through proxy_service.security_headers. An empty configured
+// value removes the header.
+var securityHeaderDefaults = map[string]string{
+	"X-Content-Type-Options":    "nosniff",
+	"Referrer-Policy":           "origin",
+	"X-Frame-Options":           "SAMEORIGIN",
+	"Strict-Transport-Security": "max-age=31536000; includeSubDomains",
+}
+
+// setSecurityHeaders writes the effective security headers.
+func (h *Handler) setSecurityHeaders(header http.Header) {
+	for name, value := range securityHeaderDefaults {
+		if override, ok := h.cfg.SecurityHeaders[name]; ok {
+			if override == "" {
+				continue
+			}
+			header.Set(name, override)
+			continue
+		}
+		header.Set(name, value)
+	}
+	for name, value := range h.cfg.SecurityHeaders {
+		if _, isDefault := securityHeaderDefaults[name]; isDefault || value == "" {
+			continue
+		}
+		header.Set(name, value)
+	}
+}
+
 // Check if this request should be forwarded to an application handler to
 // be handled by the UI and handle the request appropriately.
 func (h *RewritingHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	// Apply the deployment's security headers to every response served by
+	// the proxy, replacing per-handler header juggling.
+	h.handler.setSecurityHeaders(w.Header())
+
 	// If the request is either to the fragment authentication endpoint or if the
 	// request is already authenticated (has a session cookie), forward to
 	// application handlers. If the request is unauthenticated and requesting a
@@ -260,6 +299,14 @@ func NewHandler(cfg Config, opts ...HandlerOption) (*RewritingHandler, error) {
 	// Unauthenticated access to the message of the day
 	h.GET("/webapi/motd", httplib.MakeHandler(h.motd))
 
+	// Agent upgrade channel served by the cluster: agents poll this
+	// endpoint to discover the version they should run.
+	h.GET("/webapi/automaticupgrades/channel/default/version", httplib.MakeHandler(h.automaticUpgradeChannel))
+
+	// Websocket-based SSH transport for clients behind firewalls that only
+	// allow HTTPS traffic: bridges the websocket to the proxy SSH listener.
+	h.GET("/webapi/ssh/transport", h.sshTransport())
+
 	// DELETE IN: 5.1.0
 	//
 	// Migrated this endpoint to /webapi/sessions/web below.
@@ -303,6 +350,7 @@ func NewHandler(cfg Config, opts ...HandlerOption) (*RewritingHandler, error) {
 	h.GET("/webapi/sites/:site/namespaces/:namespace/sessions", h.WithClusterAuth(h.siteSessionsGet))      // get active list of sessions
 	h.POST("/webapi/sites/:site/namespaces/:namespace/sessions", h.WithClusterAuth(h.siteSessionGenerate)) // create active session metadata
 	h.GET("/webapi/sites/:site/namespaces/:namespace/sessions/:sid", h.WithClusterAuth(h.siteSessionGet))  // get active session metadata
+	h.DELETE("/webapi/sites/:site/namespaces/:namespace/sessions/:sid", h.WithClusterAuth(h.siteSessionTerminate))
 
 	// Audit events handlers.
 	h.GET("/webapi/sites/:site/events/search", h.WithClusterAuth(h.clusterSearchEvents))                               // search site events
@@ -441,6 +489,8 @@ func NewHandler(cfg Config, opts ...HandlerOption) (*RewritingHandler, error) {
 				}
 			}
 			httplib.SetIndexHTMLHeaders(w.Header())
+			// Configured security headers win over the index defaults.
+			h.setSecurityHeaders(w.Header())
 			if err := indexPage.Execute(w, session); err != nil {
 				h.log.WithError(err).Error("Failed to execute index page template.")
 			}
@@ -891,6 +941,70 @@ func (h *Handler) motd(w http.ResponseWriter, r *http.Request, p httprouter.Para
 	return webclient.MotD{Text: authPrefs.GetMessageOfTheDay()}, nil
 }
 
+// sshTransport returns a handler that upgrades the connection to a
+// websocket and bridges it to the proxy SSH listener, allowing clients
+// behind restrictive firewalls to tunnel SSH over the HTTPS port.
+func (h *Handler) sshTransport() httprouter.Handle {
+	return func(w http.ResponseWriter, r *http.Request, p httprouter.Params) {
+		// Use an explicit server to set an empty origin checker: the
+		// endpoint carries no browser credentials, so the same-origin
+		// policy is not load bearing here (see the terminal handler).
+		ws := &websocket.Server{Handler: func(ws *websocket.Conn) {
+			ws.PayloadType = websocket.BinaryFrame
+			h.bridgeSSHTransport(ws)
+		}}
+		ws.ServeHTTP(w, r)
+	}
+}
+
+// bridgeSSHTransport pipes the websocket to the proxy SSH listener.
+func (h *Handler) bridgeSSHTransport(ws *websocket.Conn) {
+	conn, err := net.Dial("tcp", h.cfg.ProxySSHAddr.Addr)
+	if err != nil {
+		h.log.WithError(err).Warn("Failed to dial proxy SSH listener for websocket transport.")
+		ws.Close()
+		return
+	}
+	defer conn.Close()
+	defer ws.Close()
+
+	errC := make(chan error, 2)
+	go func() {
+		_, err := io.Copy(conn, ws)
+		errC <- err
+	}()
+	go func() {
+		_, err := io.Copy(ws, conn)
+		errC <- err
+	}()
+	if err := <-errC; err != nil && !utils.IsOKNetworkError(err) {
+		h.log.WithError(err).Debug("Websocket SSH transport closed.")
+	}
+}
+
+// automaticUpgradeChannel returns the agent version served by the default
+// upgrade channel, which is the version of the cluster's auth server.
+// Agents on an automatic upgrade schedule poll this endpoint and upgrade
+// themselves when their version falls behind.
+func (h *Handler) automaticUpgradeChannel(w http.ResponseWriter, r *http.Request, p httprouter.Params) (interface{}, error) {
+	resp, err := h.cfg.ProxyClient.Ping(r.Context())
+	if err != nil {
+		return nil, trace.Wrap(err)
+	}
+	version := resp.ServerVersion
+	if version == "" {
+		version = teleport.Version
+	}
+	return upgradeChannelResponse{Version: version}, nil
+}
+
+// upgradeChannelResponse is returned from the automatic upgrade channel
+// endpoint
+type upgradeChannelResponse struct {
+	// Version is the agent version served by the channel
+	Version string `json:"version"`
+}
+
 func (h *Handler) oidcLoginWeb(w http.ResponseWriter, r *http.Request, p httprouter.Params) string {
 	logger := h.log.WithField("auth", "oidc")
 	logger.Debug("Web login start.")
@@ -1426,7 +1540,7 @@ func (h *Handler) changePasswordWithToken(w http.ResponseWriter, r *http.Request
 		return nil, trace.Wrap(err)
 	}
 
-	sess, err := h.auth.proxyClient.ChangePasswordWithToken(r.Context(), req)
+	sess, recoveryCodes, err := h.auth.proxyClient.ChangePasswordWithToken(r.Context(), req)
 	if err != nil {
 		return nil, trace.Wrap(err)
 	}
@@ -1438,7 +1552,23 @@ func (h *Handler) changePasswordWithToken(w http.ResponseWriter, r *http.Request
 		return nil, trace.Wrap(err)
 	}
 
-	return newSessionResponse(ctx)
+	resp, err := newSessionResponse(ctx)
+	if err != nil {
+		return nil, trace.Wrap(err)
+	}
+	return &changePasswordWithTokenWebResponse{
+		CreateSessionResponse: resp,
+		RecoveryCodes:         recoveryCodes,
+	}, nil
+}
+
+// changePasswordWithTokenWebResponse adds the one-time account recovery
+// codes to the session response, so the UI can show them to the user once
+// during account setup
+type changePasswordWithTokenWebResponse struct {
+	*CreateSessionResponse
+	// RecoveryCodes are one-time account recovery codes
+	RecoveryCodes []string `json:"recovery_codes,omitempty"`
 }
 
 // createResetPasswordToken allows a UI user to reset a user's password.
@@ -1797,6 +1927,34 @@ type siteSessionsGetResponse struct {
 	Sessions []session.Session `json:"sessions"`
 }
 
+// siteSessionTerminate removes an active session, disconnecting the
+// parties watching it through the web UI
+//
+// DELETE /v1/webapi/sites/:site/namespaces/:namespace/sessions/:sid
+//
+// Response body:
+//
+// {"message": "ok"}
+func (h *Handler) siteSessionTerminate(w http.ResponseWriter, r *http.Request, p httprouter.Params, ctx *SessionContext, site reversetunnel.RemoteSite) (interface{}, error) {
+	sessionID, err := session.ParseID(p.ByName("sid"))
+	if err != nil {
+		return nil, trace.Wrap(err)
+	}
+	namespace := p.ByName("namespace")
+	if !types.IsValidNamespace(namespace) {
+		return nil, trace.BadParameter("invalid namespace %q", namespace)
+	}
+
+	clt, err := ctx.GetUserClient(site)
+	if err != nil {
+		return nil, trace.Wrap(err)
+	}
+	if err := clt.DeleteSession(namespace, *sessionID); err != nil {
+		return nil, trace.Wrap(err)
+	}
+	return OK(), nil
+}
+
 // siteSessionGet gets the list of site sessions filtered by creation time
 // and either they're active or not
 //
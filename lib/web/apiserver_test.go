@@ -1478,7 +1478,7 @@ func testU2FLogin(t *testing.T, secondFactor constants.SecondFactorType) {
 	require.NoError(t, err)
 
 	tempPass := []byte("abc123")
-	_, err = env.proxies[0].client.ChangePasswordWithToken(context.TODO(), auth.ChangePasswordWithTokenRequest{
+	_, _, err = env.proxies[0].client.ChangePasswordWithToken(context.TODO(), auth.ChangePasswordWithTokenRequest{
 		TokenID:             token.GetName(),
 		U2FRegisterResponse: u2fRegResp,
 		Password:            tempPass,
@@ -1611,6 +1611,20 @@ func (s *WebSuite) TestEmptyMotD(c *C) {
 	c.Assert(motdResponse.Text, Equals, "")
 }
 
+// TestAutomaticUpgradeChannel ensures that the default upgrade channel
+// serves the cluster version to polling agents
+func (s *WebSuite) TestAutomaticUpgradeChannel(c *C) {
+	ctx := context.Background()
+	wc := s.client()
+
+	re, err := wc.Get(ctx, wc.Endpoint("webapi", "automaticupgrades", "channel", "default", "version"), url.Values{})
+	c.Assert(err, IsNil)
+
+	var channelResponse *upgradeChannelResponse
+	c.Assert(json.Unmarshal(re.Bytes(), &channelResponse), IsNil)
+	c.Assert(channelResponse.Version, Equals, teleport.Version)
+}
+
 // TestMotD ensures that a response is returned by both /webapi/ping and /webapi/motd
 // and that that the response bodies contain their MOTD components
 func (s *WebSuite) TestMotD(c *C) {
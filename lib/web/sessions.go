@@ -37,6 +37,7 @@ import (
 	"github.com/gravitational/teleport/lib/auth"
 	"github.com/gravitational/teleport/lib/auth/u2f"
 	"github.com/gravitational/teleport/lib/client"
+	"github.com/gravitational/teleport/lib/defaults"
 	"github.com/gravitational/teleport/lib/reversetunnel"
 	"github.com/gravitational/teleport/lib/services"
 	"github.com/gravitational/teleport/lib/services/local"
@@ -427,6 +428,9 @@ func newSessionCache(config sessionCacheOptions) (*sessionCache, error) {
 	}
 	// periodically close expired and unused sessions
 	go cache.expireSessions()
+	// invalidate cached sessions as soon as another proxy replica logs
+	// them out, instead of waiting for local expiry
+	go cache.watchWebSessions()
 	return cache, nil
 }
 
@@ -466,6 +470,78 @@ func (s *sessionCache) Close() error {
 	return s.closer.Close()
 }
 
+// watchWebSessions drops cached sessions when their backing web session
+// is deleted on the auth server (e.g. logout served by another proxy
+// replica), so replicas converge without sticky load balancing. The
+// watcher is retried forever, time-based expiry remains the fallback.
+func (s *sessionCache) watchWebSessions() {
+	ctx, cancel := context.WithCancel(context.TODO())
+	defer cancel()
+	go func() {
+		<-s.closer.C
+		cancel()
+	}()
+	for {
+		err := s.runSessionWatch(ctx)
+		if err != nil {
+			s.log.WithError(err).Debug("Web session watcher closed, retrying.")
+		}
+		select {
+		case <-ctx.Done():
+			return
+		case <-s.clock.After(defaults.HighResPollingPeriod):
+		}
+	}
+}
+
+// runSessionWatch consumes a single watcher until it closes.
+func (s *sessionCache) runSessionWatch(ctx context.Context) error {
+	watcher, err := s.accessPoint.NewWatcher(ctx, types.Watch{
+		Name: teleport.ComponentWeb,
+		Kinds: []types.WatchKind{{
+			Kind:    types.KindWebSession,
+			SubKind: types.KindWebSession,
+		}},
+	})
+	if err != nil {
+		return trace.Wrap(err)
+	}
+	defer watcher.Close()
+	for {
+		select {
+		case <-ctx.Done():
+			return nil
+		case event := <-watcher.Events():
+			if event.Type != types.OpDelete || event.Resource == nil {
+				continue
+			}
+			s.invalidateCachedSession(event.Resource.GetName())
+		case <-watcher.Done():
+			return trace.ConnectionProblem(watcher.Error(), "web session watcher closed")
+		}
+	}
+}
+
+// invalidateCachedSession removes cached contexts whose session ID
+// matches, regardless of user.
+func (s *sessionCache) invalidateCachedSession(sessionID string) {
+	s.mu.Lock()
+	var contexts []*SessionContext
+	for key, ctx := range s.sessions {
+		if ctx.session.GetName() != sessionID {
+			continue
+		}
+		delete(s.sessions, key)
+		contexts = append(contexts, ctx)
+	}
+	s.mu.Unlock()
+	for _, ctx := range contexts {
+		if err := ctx.Close(); err != nil {
+			s.log.WithError(err).Debug("Failed to close invalidated session context.")
+		}
+	}
+}
+
 func (s *sessionCache) expireSessions() {
 	ticker := s.clock.NewTicker(1 * time.Second)
 	defer ticker.Stop()
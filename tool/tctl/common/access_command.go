@@ -0,0 +1,142 @@
+/*
+Copyright 2021 Gravitational, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package common
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	apidefaults "github.com/gravitational/teleport/api/defaults"
+	"github.com/gravitational/teleport/lib/asciitable"
+	"github.com/gravitational/teleport/lib/auth"
+	"github.com/gravitational/teleport/lib/service"
+	"github.com/gravitational/teleport/lib/services"
+
+	"github.com/gravitational/kingpin"
+	"github.com/gravitational/trace"
+)
+
+// AccessCommand implements the "tctl access" group of commands, used to
+// answer "what can this user do" questions without logging in as the user.
+type AccessCommand struct {
+	config *service.Config
+
+	user  string
+	login string
+
+	// accessList implements the "tctl access ls" subcommand.
+	accessList *kingpin.CmdClause
+}
+
+// Initialize allows AccessCommand to plug itself into the CLI parser.
+func (c *AccessCommand) Initialize(app *kingpin.Application, config *service.Config) {
+	c.config = config
+
+	access := app.Command("access", "Inspect user access to resources.")
+	c.accessList = access.Command("ls", "Show which servers a user can access and which roles grant or deny it.")
+	c.accessList.Flag("user", "Name of the user to inspect").Required().StringVar(&c.user)
+	c.accessList.Flag("login", "Check a specific OS login instead of every login the roles allow").StringVar(&c.login)
+}
+
+// TryRun attempts to run subcommands like "access ls".
+func (c *AccessCommand) TryRun(cmd string, client auth.ClientI) (match bool, err error) {
+	switch cmd {
+	case c.accessList.FullCommand():
+		err = c.ListAccess(client)
+	default:
+		return false, nil
+	}
+	return true, trace.Wrap(err)
+}
+
+// ListAccess prints a what-if access report: one row per server and login
+// with the verdict, the roles that allowed or denied it, and whether a
+// per-session MFA check would be required.
+func (c *AccessCommand) ListAccess(client auth.ClientI) error {
+	ctx := context.TODO()
+	user, err := client.GetUser(c.user, false)
+	if err != nil {
+		return trace.Wrap(err)
+	}
+	roleSet, err := services.FetchRoles(user.GetRoles(), client, user.GetTraits())
+	if err != nil {
+		return trace.Wrap(err)
+	}
+	authPref, err := client.GetAuthPreference(ctx)
+	if err != nil {
+		return trace.Wrap(err)
+	}
+	mfa := services.AccessMFAParams{AlwaysRequired: authPref.GetRequireSessionMFA()}
+
+	logins := []string{c.login}
+	if c.login == "" {
+		logins = allowedLogins(roleSet)
+		if len(logins) == 0 {
+			fmt.Printf("User %q has no OS logins in any allow rule, pass --login to check a specific one.\n", c.user)
+			return nil
+		}
+	}
+
+	nodes, err := client.GetNodes(ctx, apidefaults.Namespace)
+	if err != nil {
+		return trace.Wrap(err)
+	}
+
+	t := asciitable.MakeTable([]string{"Node", "Login", "Access", "Matched Roles", "MFA"})
+	for _, node := range nodes {
+		for _, login := range logins {
+			expl, err := roleSet.ExplainAccessToServer(login, node, mfa)
+			if err != nil {
+				return trace.Wrap(err)
+			}
+			verdict, matched := "denied", "-"
+			switch {
+			case expl.DeniedBy != "":
+				matched = fmt.Sprintf("denied by %v", expl.DeniedBy)
+			case expl.Allowed:
+				verdict = "allowed"
+				matched = fmt.Sprintf("allowed by %v", strings.Join(expl.AllowedBy, ","))
+			}
+			mfaColumn := "no"
+			if expl.MFARequired {
+				mfaColumn = "required"
+			}
+			t.AddRow([]string{node.GetHostname(), login, verdict, matched, mfaColumn})
+		}
+	}
+	fmt.Println(t.AsBuffer().String())
+	return nil
+}
+
+// allowedLogins collects the OS logins mentioned in the role set's allow
+// rules. Logins with unexpanded trait templates are skipped, traits were
+// already applied when the roles were fetched.
+func allowedLogins(roleSet services.RoleSet) []string {
+	seen := make(map[string]bool)
+	var logins []string
+	for _, role := range roleSet {
+		for _, login := range role.GetLogins(services.Allow) {
+			if strings.Contains(login, "{{") || seen[login] {
+				continue
+			}
+			seen[login] = true
+			logins = append(logins, login)
+		}
+	}
+	return logins
+}
@@ -46,6 +46,8 @@ type AccessRequestCommand struct {
 	delegator   string
 	reason      string
 	annotations string
+	// assumeAfter delays when an approval can be assumed
+	assumeAfter string
 	// format is the output format, e.g. text or json
 	format string
 
@@ -81,6 +83,7 @@ func (c *AccessRequestCommand) Initialize(app *kingpin.Application, config *serv
 	c.requestApprove.Flag("reason", "Optional reason message").StringVar(&c.reason)
 	c.requestApprove.Flag("annotations", "Resolution attributes <key>=<val>[,...]").StringVar(&c.annotations)
 	c.requestApprove.Flag("roles", "Override requested roles <role>[,...]").StringVar(&c.roles)
+	c.requestApprove.Flag("assume-after", "Delay before the approval can be assumed, e.g. 2h or an RFC3339 time").StringVar(&c.assumeAfter)
 
 	c.requestDeny = requests.Command("deny", "Deny pending access request")
 	c.requestDeny.Arg("request-id", "ID of target request(s)").Required().StringVar(&c.reqIDs)
@@ -220,6 +223,16 @@ func (c *AccessRequestCommand) Approve(client auth.ClientI) error {
 	if err != nil {
 		return trace.Wrap(err)
 	}
+	if c.assumeAfter != "" {
+		start, err := parseAssumeAfter(c.assumeAfter)
+		if err != nil {
+			return trace.Wrap(err)
+		}
+		if annotations == nil {
+			annotations = map[string][]string{}
+		}
+		annotations[auth.AssumeStartTimeAnnotation] = []string{start.Format(time.RFC3339)}
+	}
 	for _, reqID := range strings.Split(c.reqIDs, ",") {
 		if err := client.SetAccessRequestState(ctx, types.AccessRequestUpdate{
 			RequestID:   reqID,
@@ -234,6 +247,19 @@ func (c *AccessRequestCommand) Approve(client auth.ClientI) error {
 	return nil
 }
 
+// parseAssumeAfter accepts a relative duration like "2h" or an absolute
+// RFC3339 timestamp.
+func parseAssumeAfter(value string) (time.Time, error) {
+	if d, err := time.ParseDuration(value); err == nil {
+		return time.Now().UTC().Add(d), nil
+	}
+	t, err := time.Parse(time.RFC3339, value)
+	if err != nil {
+		return time.Time{}, trace.BadParameter("failed to parse --assume-after %q, expected a duration or an RFC3339 time", value)
+	}
+	return t.UTC(), nil
+}
+
 func (c *AccessRequestCommand) Deny(client auth.ClientI) error {
 	ctx := context.TODO()
 	if c.delegator != "" {
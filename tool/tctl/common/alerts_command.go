@@ -0,0 +1,81 @@
+/*
+Copyright 2021 Gravitational, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package common
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/gravitational/teleport/lib/asciitable"
+	"github.com/gravitational/teleport/lib/auth"
+	"github.com/gravitational/teleport/lib/service"
+
+	"github.com/gravitational/kingpin"
+	"github.com/gravitational/trace"
+)
+
+// AlertsCommand implements "tctl alerts", showing active cluster health
+// alerts raised by the auth server.
+type AlertsCommand struct {
+	config *service.Config
+
+	// alertsList implements "tctl alerts ls".
+	alertsList *kingpin.CmdClause
+}
+
+// Initialize allows AlertsCommand to plug itself into the CLI parser.
+func (c *AlertsCommand) Initialize(app *kingpin.Application, config *service.Config) {
+	c.config = config
+
+	alerts := app.Command("alerts", "Show cluster health alerts.")
+	c.alertsList = alerts.Command("ls", "List active cluster health alerts.")
+}
+
+// TryRun attempts to run subcommands like "alerts ls".
+func (c *AlertsCommand) TryRun(cmd string, client auth.ClientI) (match bool, err error) {
+	switch cmd {
+	case c.alertsList.FullCommand():
+		err = c.List(client)
+	default:
+		return false, nil
+	}
+	return true, trace.Wrap(err)
+}
+
+// List prints the active cluster alerts.
+func (c *AlertsCommand) List(client auth.ClientI) error {
+	notifications, err := client.GetNotifications(context.TODO())
+	if err != nil {
+		return trace.Wrap(err)
+	}
+	t := asciitable.MakeTable([]string{"Alert", "Raised", "Message"})
+	var count int
+	for _, notification := range notifications {
+		if notification.Type != auth.NotificationTypeClusterAlert {
+			continue
+		}
+		t.AddRow([]string{notification.ID, notification.Created.Format(time.RFC822), notification.Message})
+		count++
+	}
+	if count == 0 {
+		fmt.Println("No active cluster alerts.")
+		return nil
+	}
+	fmt.Println(t.AsBuffer().String())
+	return nil
+}
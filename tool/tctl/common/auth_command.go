@@ -16,6 +16,7 @@ import (
 	"github.com/gravitational/teleport/api/client/proto"
 	apidefaults "github.com/gravitational/teleport/api/defaults"
 	"github.com/gravitational/teleport/api/types"
+	apisshutils "github.com/gravitational/teleport/api/utils/sshutils"
 	"github.com/gravitational/teleport/lib/auth"
 	"github.com/gravitational/teleport/lib/auth/native"
 	"github.com/gravitational/teleport/lib/client"
@@ -31,6 +32,7 @@ import (
 	"github.com/gravitational/kingpin"
 	"github.com/gravitational/trace"
 	"github.com/sirupsen/logrus"
+	"golang.org/x/crypto/ssh"
 )
 
 // AuthCommand implements `tctl auth` group of commands
@@ -52,6 +54,7 @@ type AuthCommand struct {
 	leafCluster                string
 	kubeCluster                string
 	signOverwrite              bool
+	signDryRun                 bool
 
 	rotateGracePeriod time.Duration
 	rotateType        string
@@ -84,13 +87,14 @@ func (a *AuthCommand) Initialize(app *kingpin.Application, config *service.Confi
 	a.authSign.Flag("user", "Teleport user name").StringVar(&a.genUser)
 	a.authSign.Flag("host", "Teleport host name").StringVar(&a.genHost)
 	a.authSign.Flag("out", "identity output").Short('o').Required().StringVar(&a.output)
-	a.authSign.Flag("format", fmt.Sprintf("identity format: %q (default), %q, %q, %q, %q or %q",
+	a.authSign.Flag("format", fmt.Sprintf("identity format: %q (default), %q, %q, %q, %q, %q or %q",
 		identityfile.FormatFile,
 		identityfile.FormatOpenSSH,
 		identityfile.FormatTLS,
 		identityfile.FormatKubernetes,
 		identityfile.FormatDatabase,
-		identityfile.FormatMongo)).
+		identityfile.FormatMongo,
+		identityfile.FormatWindows)).
 		Default(string(identityfile.DefaultFormat)).
 		StringVar((*string)(&a.outputFormat))
 	a.authSign.Flag("ttl", "TTL (time to live) for the generated certificate").
@@ -99,6 +103,7 @@ func (a *AuthCommand) Initialize(app *kingpin.Application, config *service.Confi
 	a.authSign.Flag("compat", "OpenSSH compatibility flag").StringVar(&a.compatibility)
 	a.authSign.Flag("proxy", `Address of the teleport proxy. When --format is set to "kubernetes", this address will be set as cluster address in the generated kubeconfig file`).StringVar(&a.proxyAddr)
 	a.authSign.Flag("overwrite", "Whether to overwrite existing destination files. When not set, user will be prompted before overwriting any existing file.").BoolVar(&a.signOverwrite)
+	a.authSign.Flag("dry-run", "Print the decoded certificate(s) to stdout instead of writing identity files").BoolVar(&a.signDryRun)
 	// --kube-cluster was an unfortunately chosen flag name, before teleport
 	// supported kubernetes_service and registered kubernetes clusters that are
 	// not trusted teleport clusters.
@@ -276,6 +281,9 @@ func (a *AuthCommand) GenerateKeys() error {
 
 // GenerateAndSignKeys generates a new keypair and signs it for role
 func (a *AuthCommand) GenerateAndSignKeys(clusterAPI auth.ClientI) error {
+	if a.genTTL <= 0 {
+		return trace.BadParameter("--ttl must be a positive duration, got %v", a.genTTL)
+	}
 	switch {
 	case a.outputFormat == identityfile.FormatDatabase || a.outputFormat == identityfile.FormatMongo:
 		return a.generateDatabaseKeys(clusterAPI)
@@ -320,6 +328,11 @@ func (a *AuthCommand) generateHostKeys(clusterAPI auth.ClientI) error {
 
 	// split up comma separated list
 	principals := strings.Split(a.genHost, ",")
+	for _, principal := range principals {
+		if strings.TrimSpace(principal) == "" {
+			return trace.BadParameter("--host must be a comma-separated list of non-empty principals, got %q", a.genHost)
+		}
+	}
 
 	// generate a keypair
 	key, err := client.NewKey()
@@ -351,6 +364,10 @@ func (a *AuthCommand) generateHostKeys(clusterAPI auth.ClientI) error {
 		filePath = principals[0]
 	}
 
+	if a.signDryRun {
+		return trace.Wrap(printDecodedCerts(key))
+	}
+
 	filesWritten, err := identityfile.Write(identityfile.WriteConfig{
 		OutputPath:           filePath,
 		Key:                  key,
@@ -415,6 +432,9 @@ func (a *AuthCommand) generateDatabaseKeysForKey(clusterAPI auth.ClientI, key *c
 	}
 	key.TLSCert = resp.Cert
 	key.TrustedCA = []auth.TrustedCerts{{TLSCertificates: resp.CACerts}}
+	if a.signDryRun {
+		return trace.Wrap(printDecodedCerts(key))
+	}
 	filesWritten, err := identityfile.Write(identityfile.WriteConfig{
 		OutputPath:           a.output,
 		Key:                  key,
@@ -529,6 +549,10 @@ func (a *AuthCommand) generateUserKeys(clusterAPI auth.ClientI) error {
 	}
 	key.TrustedCA = auth.AuthoritiesToTrustedCerts(hostCAs)
 
+	if a.signDryRun {
+		return trace.Wrap(printDecodedCerts(key))
+	}
+
 	// write the cert+private key to the output:
 	filesWritten, err := identityfile.Write(identityfile.WriteConfig{
 		OutputPath:           a.output,
@@ -645,6 +669,39 @@ func (a *AuthCommand) checkProxyAddr(clusterAPI auth.ClientI) error {
 	return trace.BadParameter("couldn't find registered public proxies, specify --proxy when using --format=%q", identityfile.FormatKubernetes)
 }
 
+// printDecodedCerts prints the signed certificates in a human-readable form
+// to stdout instead of writing identity files to disk. It is used by the
+// --dry-run flag of "tctl auth sign".
+func printDecodedCerts(key *client.Key) error {
+	if len(key.Cert) != 0 {
+		cert, err := apisshutils.ParseCertificate(key.Cert)
+		if err != nil {
+			return trace.Wrap(err)
+		}
+		fmt.Printf("SSH certificate:\n")
+		fmt.Printf("  Key ID: %v\n", cert.KeyId)
+		fmt.Printf("  Principals: %v\n", strings.Join(cert.ValidPrincipals, ", "))
+		fmt.Printf("  Valid after: %v\n", time.Unix(int64(cert.ValidAfter), 0).UTC().Format(time.RFC3339))
+		if cert.ValidBefore == uint64(ssh.CertTimeInfinity) {
+			fmt.Printf("  Valid before: never\n")
+		} else {
+			fmt.Printf("  Valid before: %v\n", time.Unix(int64(cert.ValidBefore), 0).UTC().Format(time.RFC3339))
+		}
+	}
+	if len(key.TLSCert) != 0 {
+		cert, err := tlsca.ParseCertificatePEM(key.TLSCert)
+		if err != nil {
+			return trace.Wrap(err)
+		}
+		fmt.Printf("TLS certificate:\n")
+		fmt.Printf("  Subject: %v\n", cert.Subject)
+		fmt.Printf("  DNS names: %v\n", strings.Join(cert.DNSNames, ", "))
+		fmt.Printf("  Not before: %v\n", cert.NotBefore.Format(time.RFC3339))
+		fmt.Printf("  Not after: %v\n", cert.NotAfter.Format(time.RFC3339))
+	}
+	return nil
+}
+
 // userCAFormat returns the certificate authority public key exported as a single
 // line that can be placed in ~/.ssh/authorized_keys file. The format adheres to the
 // man sshd (8) authorized_keys format, a space-separated list of: options, keytype,
@@ -424,3 +424,52 @@ func TestGenerateDatabaseKeys(t *testing.T) {
 		})
 	}
 }
+
+func TestAuthSignTTLValidation(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name    string
+		ttl     time.Duration
+		wantErr string
+	}{
+		{
+			name:    "zero ttl",
+			ttl:     0,
+			wantErr: "--ttl must be a positive duration",
+		},
+		{
+			name:    "negative ttl",
+			ttl:     -time.Hour,
+			wantErr: "--ttl must be a positive duration",
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			ac := AuthCommand{
+				genUser:      "alice",
+				output:       "alice",
+				outputFormat: identityfile.FormatFile,
+				genTTL:       test.ttl,
+			}
+			err := ac.GenerateAndSignKeys(&mockClient{})
+			require.Error(t, err)
+			require.Contains(t, err.Error(), test.wantErr)
+		})
+	}
+}
+
+func TestAuthSignHostPrincipalValidation(t *testing.T) {
+	t.Parallel()
+
+	ac := AuthCommand{
+		genHost:      "node.example.com,,node2.example.com",
+		output:       "node",
+		outputFormat: identityfile.FormatOpenSSH,
+		genTTL:       time.Hour,
+	}
+	err := ac.GenerateAndSignKeys(&mockClient{})
+	require.Error(t, err)
+	require.Contains(t, err.Error(), "non-empty principals")
+}
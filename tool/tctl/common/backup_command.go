@@ -0,0 +1,66 @@
+/*
+Copyright 2021 Gravitational, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package common
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/gravitational/teleport/lib/auth"
+	"github.com/gravitational/teleport/lib/service"
+
+	"github.com/gravitational/kingpin"
+	"github.com/gravitational/trace"
+)
+
+// BackupCommand implements "tctl backup create".
+type BackupCommand struct {
+	config *service.Config
+
+	// backupCreate implements "tctl backup create".
+	backupCreate *kingpin.CmdClause
+}
+
+// Initialize allows BackupCommand to plug itself into the CLI parser.
+func (c *BackupCommand) Initialize(app *kingpin.Application, config *service.Config) {
+	c.config = config
+
+	backup := app.Command("backup", "Back up the auth server state.")
+	c.backupCreate = backup.Command("create", "Snapshot the backend into the auth server data directory without downtime.")
+}
+
+// TryRun attempts to run subcommands like "backup create".
+func (c *BackupCommand) TryRun(cmd string, client auth.ClientI) (match bool, err error) {
+	switch cmd {
+	case c.backupCreate.FullCommand():
+		err = c.Create(client)
+	default:
+		return false, nil
+	}
+	return true, trace.Wrap(err)
+}
+
+// Create requests an online backend snapshot from the auth server.
+func (c *BackupCommand) Create(client auth.ClientI) error {
+	result, err := client.CreateBackup(context.TODO())
+	if err != nil {
+		return trace.Wrap(err)
+	}
+	fmt.Printf("Created backup %v at %v.\n", result.Path, result.CreatedAt.Format(time.RFC822))
+	return nil
+}
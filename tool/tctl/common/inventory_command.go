@@ -0,0 +1,246 @@
+/*
+Copyright 2021 Gravitational, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package common
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/gravitational/teleport"
+	apidefaults "github.com/gravitational/teleport/api/defaults"
+	"github.com/gravitational/teleport/api/types"
+	"github.com/gravitational/teleport/lib/asciitable"
+	"github.com/gravitational/teleport/lib/auth"
+	"github.com/gravitational/teleport/lib/service"
+
+	"github.com/gravitational/kingpin"
+	"github.com/gravitational/trace"
+	"github.com/pborman/uuid"
+)
+
+// InventoryCommand implements "tctl inventory", listing connected agents
+// and their versions.
+type InventoryCommand struct {
+	config *service.Config
+
+	upgradeNeeded bool
+	hostID        string
+	command       string
+
+	// inventoryList implements "tctl inventory ls".
+	inventoryList *kingpin.CmdClause
+	// inventoryStatus implements "tctl inventory status".
+	inventoryStatus *kingpin.CmdClause
+	// inventoryCommand implements "tctl inventory command".
+	inventoryCommand *kingpin.CmdClause
+	// inventoryLogs implements "tctl inventory logs".
+	inventoryLogs *kingpin.CmdClause
+	// inventoryProfile implements "tctl inventory profile".
+	inventoryProfile *kingpin.CmdClause
+}
+
+// Initialize allows InventoryCommand to plug itself into the CLI parser.
+func (c *InventoryCommand) Initialize(app *kingpin.Application, config *service.Config) {
+	c.config = config
+
+	inventory := app.Command("inventory", "Inspect the agents connected to the cluster.")
+	c.inventoryList = inventory.Command("ls", "List agents with their Teleport versions.")
+	c.inventoryList.Flag("upgrade-needed", "Only show agents more than one major version behind the auth server").BoolVar(&c.upgradeNeeded)
+
+	c.inventoryStatus = inventory.Command("status", "Show agents connected to the inventory control channel.")
+
+	c.inventoryCommand = inventory.Command("command", "Send a control command (ping, upgrade, shutdown) to a connected agent.")
+	c.inventoryCommand.Arg("host-id", "Host ID of the agent").Required().StringVar(&c.hostID)
+	c.inventoryCommand.Arg("command", "Command to send: ping, upgrade or shutdown").Required().StringVar(&c.command)
+
+	c.inventoryLogs = inventory.Command("logs", "Pull recent logs from a connected agent.")
+	c.inventoryLogs.Arg("host-id", "Host ID of the agent").Required().StringVar(&c.hostID)
+
+	c.inventoryProfile = inventory.Command("profile", "Pull goroutine and heap profiles from a connected agent.")
+	c.inventoryProfile.Arg("host-id", "Host ID of the agent").Required().StringVar(&c.hostID)
+}
+
+// TryRun attempts to run subcommands like "inventory ls".
+func (c *InventoryCommand) TryRun(cmd string, client auth.ClientI) (match bool, err error) {
+	switch cmd {
+	case c.inventoryList.FullCommand():
+		err = c.List(client)
+	case c.inventoryStatus.FullCommand():
+		err = c.Status(client)
+	case c.inventoryCommand.FullCommand():
+		err = c.Send(client)
+	case c.inventoryLogs.FullCommand():
+		err = c.Collect(client, auth.InventoryCommandCollectLogs)
+	case c.inventoryProfile.FullCommand():
+		err = c.Collect(client, auth.InventoryCommandCollectProfile)
+	default:
+		return false, nil
+	}
+	return true, trace.Wrap(err)
+}
+
+// inventoryEntry is one agent in the inventory listing.
+type inventoryEntry struct {
+	kind     string
+	hostname string
+	version  string
+}
+
+// List prints the agents heartbeating into the cluster, flagging the
+// ones running a major version more than one behind the auth server.
+func (c *InventoryCommand) List(client auth.ClientI) error {
+	ctx := context.TODO()
+	var entries []inventoryEntry
+
+	nodes, err := client.GetNodes(ctx, apidefaults.Namespace)
+	if err != nil {
+		return trace.Wrap(err)
+	}
+	for _, node := range nodes {
+		entries = append(entries, inventoryEntry{kind: types.KindNode, hostname: node.GetHostname(), version: node.GetTeleportVersion()})
+	}
+	proxies, err := client.GetProxies()
+	if err != nil {
+		return trace.Wrap(err)
+	}
+	for _, proxy := range proxies {
+		entries = append(entries, inventoryEntry{kind: types.KindProxy, hostname: proxy.GetHostname(), version: proxy.GetTeleportVersion()})
+	}
+	authServers, err := client.GetAuthServers()
+	if err != nil {
+		return trace.Wrap(err)
+	}
+	for _, authServer := range authServers {
+		entries = append(entries, inventoryEntry{kind: types.KindAuthServer, hostname: authServer.GetHostname(), version: authServer.GetTeleportVersion()})
+	}
+
+	authMajor, err := inventoryMajorVersion(teleport.Version)
+	if err != nil {
+		return trace.Wrap(err)
+	}
+	t := asciitable.MakeTable([]string{"Kind", "Hostname", "Version", "Status"})
+	var shown int
+	for _, entry := range entries {
+		status := "up-to-date"
+		entryMajor, err := inventoryMajorVersion(entry.version)
+		switch {
+		case err != nil:
+			status = "unknown version"
+		case authMajor-entryMajor > 1:
+			status = "UPGRADE NEEDED"
+		case authMajor-entryMajor == 1:
+			status = "one major behind"
+		}
+		if c.upgradeNeeded && status != "UPGRADE NEEDED" {
+			continue
+		}
+		t.AddRow([]string{entry.kind, entry.hostname, entry.version, status})
+		shown++
+	}
+	if shown == 0 {
+		if c.upgradeNeeded {
+			fmt.Println("All agents are within one major version of the auth server.")
+		} else {
+			fmt.Println("No agents found.")
+		}
+		return nil
+	}
+	fmt.Println(t.AsBuffer().String())
+	return nil
+}
+
+// Status prints the agents connected to the inventory control channel.
+func (c *InventoryCommand) Status(client auth.ClientI) error {
+	statuses, err := client.InventoryStatus(context.TODO())
+	if err != nil {
+		return trace.Wrap(err)
+	}
+	if len(statuses) == 0 {
+		fmt.Println("No agents are connected to the inventory control channel.")
+		return nil
+	}
+	t := asciitable.MakeTable([]string{"Host ID", "Hostname", "Services", "Version", "Health", "Last Seen"})
+	for _, status := range statuses {
+		t.AddRow([]string{
+			status.HostID,
+			status.NodeName,
+			strings.Join(status.Services, ","),
+			status.Version,
+			status.Health,
+			status.LastSeen.Format("2006-01-02 15:04:05"),
+		})
+	}
+	fmt.Println(t.AsBuffer().String())
+	return nil
+}
+
+// Send queues a control command for a connected agent.
+func (c *InventoryCommand) Send(client auth.ClientI) error {
+	err := client.SendInventoryCommand(context.TODO(), c.hostID, auth.InventoryCommand{Name: c.command})
+	if err != nil {
+		return trace.Wrap(err)
+	}
+	fmt.Printf("Command %q queued for agent %v.\n", c.command, c.hostID)
+	return nil
+}
+
+// collectTimeout bounds how long a diagnostics collection waits for the
+// agent to answer. The agent only picks the command up on its next
+// control channel poll.
+const collectTimeout = 90 * time.Second
+
+// Collect sends a diagnostics command to an agent and waits for the
+// result.
+func (c *InventoryCommand) Collect(client auth.ClientI, command string) error {
+	ctx := context.TODO()
+	requestID := uuid.New()
+	err := client.SendInventoryCommand(ctx, c.hostID, auth.InventoryCommand{Name: command, ID: requestID})
+	if err != nil {
+		return trace.Wrap(err)
+	}
+	fmt.Fprintf(os.Stderr, "Waiting for agent %v to answer...\n", c.hostID)
+	deadline := time.Now().Add(collectTimeout)
+	for time.Now().Before(deadline) {
+		result, err := client.GetInventoryResult(ctx, requestID)
+		if trace.IsNotFound(err) {
+			time.Sleep(time.Second)
+			continue
+		}
+		if err != nil {
+			return trace.Wrap(err)
+		}
+		if result.Error != "" {
+			return trace.Errorf("agent %v: %v", c.hostID, result.Error)
+		}
+		os.Stdout.Write(result.Data)
+		return nil
+	}
+	return trace.LimitExceeded("timed out waiting for agent %v to answer, is it connected to the control channel?", c.hostID)
+}
+
+// inventoryMajorVersion parses the major component of a semver string.
+func inventoryMajorVersion(version string) (int, error) {
+	major, err := strconv.Atoi(strings.SplitN(version, ".", 2)[0])
+	if err != nil {
+		return 0, trace.BadParameter("failed to parse version %q", version)
+	}
+	return major, nil
+}
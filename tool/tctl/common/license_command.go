@@ -0,0 +1,83 @@
+/*
+Copyright 2021 Gravitational, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package common
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/gravitational/teleport/lib/auth"
+	"github.com/gravitational/teleport/lib/service"
+
+	"github.com/gravitational/kingpin"
+	"github.com/gravitational/trace"
+)
+
+// LicenseCommand implements "tctl license status".
+type LicenseCommand struct {
+	config *service.Config
+
+	// licenseStatus implements "tctl license status".
+	licenseStatus *kingpin.CmdClause
+}
+
+// Initialize allows LicenseCommand to plug itself into the CLI parser.
+func (c *LicenseCommand) Initialize(app *kingpin.Application, config *service.Config) {
+	c.config = config
+
+	license := app.Command("license", "Inspect the cluster license.")
+	c.licenseStatus = license.Command("status", "Show the effective license state and limits.")
+}
+
+// TryRun attempts to run subcommands like "license status".
+func (c *LicenseCommand) TryRun(cmd string, client auth.ClientI) (match bool, err error) {
+	switch cmd {
+	case c.licenseStatus.FullCommand():
+		err = c.Status(client)
+	default:
+		return false, nil
+	}
+	return true, trace.Wrap(err)
+}
+
+// Status prints the effective license state.
+func (c *LicenseCommand) Status(client auth.ClientI) error {
+	status, err := client.GetLicenseStatus(context.TODO())
+	if err != nil {
+		return trace.Wrap(err)
+	}
+	if !status.Present {
+		fmt.Printf("No license file loaded, running with community defaults. Registered nodes: %v.\n", status.NodeCount)
+		return nil
+	}
+	fmt.Printf("License:      loaded\n")
+	fmt.Printf("Valid:        %v\n", status.Valid)
+	if len(status.Features) > 0 {
+		fmt.Printf("Features:     %v\n", strings.Join(status.Features, ", "))
+	}
+	if status.MaxNodes > 0 {
+		fmt.Printf("Node limit:   %v (currently %v)\n", status.MaxNodes, status.NodeCount)
+	} else {
+		fmt.Printf("Node limit:   unlimited (currently %v)\n", status.NodeCount)
+	}
+	if !status.Expires.IsZero() {
+		fmt.Printf("Expires:      %v\n", status.Expires.Format(time.RFC822))
+	}
+	return nil
+}
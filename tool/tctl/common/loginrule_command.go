@@ -0,0 +1,125 @@
+/*
+Copyright 2021 Gravitational, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package common
+
+import (
+	"context"
+	"fmt"
+	"io/ioutil"
+	"strings"
+
+	"github.com/gravitational/teleport/lib/asciitable"
+	"github.com/gravitational/teleport/lib/auth"
+	"github.com/gravitational/teleport/lib/service"
+
+	"github.com/gravitational/kingpin"
+	"github.com/gravitational/trace"
+	"gopkg.in/yaml.v2"
+)
+
+// LoginRuleCommand implements "tctl login-rules", managing the rules
+// that transform SSO traits at login.
+type LoginRuleCommand struct {
+	config *service.Config
+
+	file string
+	name string
+
+	// loginRuleCreate implements "tctl login-rules create".
+	loginRuleCreate *kingpin.CmdClause
+	// loginRuleList implements "tctl login-rules ls".
+	loginRuleList *kingpin.CmdClause
+	// loginRuleDelete implements "tctl login-rules rm".
+	loginRuleDelete *kingpin.CmdClause
+}
+
+// Initialize allows LoginRuleCommand to plug itself into the CLI parser.
+func (c *LoginRuleCommand) Initialize(app *kingpin.Application, config *service.Config) {
+	c.config = config
+
+	loginRules := app.Command("login-rules", "Manage rules transforming SSO traits at login.")
+	c.loginRuleCreate = loginRules.Command("create", "Create or update a login rule from a YAML file.")
+	c.loginRuleCreate.Arg("file", "Path to the login rule file").Required().StringVar(&c.file)
+
+	c.loginRuleList = loginRules.Command("ls", "List login rules.")
+
+	c.loginRuleDelete = loginRules.Command("rm", "Delete a login rule.").Alias("del")
+	c.loginRuleDelete.Arg("name", "Name of the rule to delete").Required().StringVar(&c.name)
+}
+
+// TryRun attempts to run subcommands like "login-rules create".
+func (c *LoginRuleCommand) TryRun(cmd string, client auth.ClientI) (match bool, err error) {
+	switch cmd {
+	case c.loginRuleCreate.FullCommand():
+		err = c.Create(client)
+	case c.loginRuleList.FullCommand():
+		err = c.List(client)
+	case c.loginRuleDelete.FullCommand():
+		err = c.Delete(client)
+	default:
+		return false, nil
+	}
+	return true, trace.Wrap(err)
+}
+
+// Create stores a login rule read from a YAML file.
+func (c *LoginRuleCommand) Create(client auth.ClientI) error {
+	data, err := ioutil.ReadFile(c.file)
+	if err != nil {
+		return trace.ConvertSystemError(err)
+	}
+	var rule auth.LoginRule
+	if err := yaml.Unmarshal(data, &rule); err != nil {
+		return trace.BadParameter("failed to parse login rule: %v", err)
+	}
+	if err := client.UpsertLoginRule(context.TODO(), rule); err != nil {
+		return trace.Wrap(err)
+	}
+	fmt.Printf("Login rule %q has been created.\n", rule.Name)
+	return nil
+}
+
+// List prints the login rules in priority order.
+func (c *LoginRuleCommand) List(client auth.ClientI) error {
+	rules, err := client.GetLoginRules(context.TODO())
+	if err != nil {
+		return trace.Wrap(err)
+	}
+	if len(rules) == 0 {
+		fmt.Println("No login rules configured.")
+		return nil
+	}
+	t := asciitable.MakeTable([]string{"Name", "Priority", "Traits"})
+	for _, rule := range rules {
+		var traits []string
+		for trait := range rule.TraitsMap {
+			traits = append(traits, trait)
+		}
+		t.AddRow([]string{rule.Name, fmt.Sprintf("%v", rule.Priority), strings.Join(traits, ", ")})
+	}
+	fmt.Println(t.AsBuffer().String())
+	return nil
+}
+
+// Delete removes a login rule.
+func (c *LoginRuleCommand) Delete(client auth.ClientI) error {
+	if err := client.DeleteLoginRule(context.TODO(), c.name); err != nil {
+		return trace.Wrap(err)
+	}
+	fmt.Printf("Login rule %q has been deleted.\n", c.name)
+	return nil
+}
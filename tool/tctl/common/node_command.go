@@ -50,9 +50,21 @@ type NodeCommand struct {
 	// if not specified, is autogenerated
 	token string
 
+	// expiredOnly limits the rm command to nodes whose heartbeat has
+	// expired
+	expiredOnly bool
+	// nodeName is the name of the node to remove
+	nodeName string
+
 	// CLI subcommands (clauses)
-	nodeAdd  *kingpin.CmdClause
-	nodeList *kingpin.CmdClause
+	nodeAdd    *kingpin.CmdClause
+	nodeList   *kingpin.CmdClause
+	nodeRemove *kingpin.CmdClause
+
+	// nodeUpdate implements "tctl nodes update"
+	nodeUpdate   *kingpin.CmdClause
+	setLabels    []string
+	removeLabels []string
 }
 
 // Initialize allows NodeCommand to plug itself into the CLI parser
@@ -71,6 +83,16 @@ func (c *NodeCommand) Initialize(app *kingpin.Application, config *service.Confi
 	c.nodeList = nodes.Command("ls", "List all active SSH nodes within the cluster")
 	c.nodeList.Flag("namespace", "Namespace of the nodes").Default(apidefaults.Namespace).StringVar(&c.namespace)
 	c.nodeList.Alias(ListNodesHelp)
+
+	c.nodeUpdate = nodes.Command("update", "Update a node's centrally managed labels")
+	c.nodeUpdate.Arg("name", "Name, UUID or hostname of the node").Required().StringVar(&c.nodeName)
+	c.nodeUpdate.Flag("set-label", "Label to set as <key>=<value>, may be repeated").StringsVar(&c.setLabels)
+	c.nodeUpdate.Flag("remove-label", "Label key to remove, may be repeated").StringsVar(&c.removeLabels)
+
+	c.nodeRemove = nodes.Command("rm", "Remove node(s) from the cluster").Alias("del")
+	c.nodeRemove.Flag("namespace", "Namespace of the nodes").Default(apidefaults.Namespace).StringVar(&c.namespace)
+	c.nodeRemove.Flag("expired", "Remove all nodes whose heartbeat has expired instead of a single node").BoolVar(&c.expiredOnly)
+	c.nodeRemove.Arg("name", "Name or UUID of the node to remove").StringVar(&c.nodeName)
 }
 
 // TryRun takes the CLI command as an argument (like "nodes ls") and executes it.
@@ -80,6 +102,10 @@ func (c *NodeCommand) TryRun(cmd string, client auth.ClientI) (match bool, err e
 		err = c.Invite(client)
 	case c.nodeList.FullCommand():
 		err = c.ListActive(client)
+	case c.nodeUpdate.FullCommand():
+		err = c.Update(client)
+	case c.nodeRemove.FullCommand():
+		err = c.Remove(client)
 
 	default:
 		return false, nil
@@ -181,3 +207,64 @@ func (c *NodeCommand) ListActive(client auth.ClientI) error {
 	}
 	return nil
 }
+
+// Remove deletes a node from the cluster by name, or, with --expired, all
+// nodes whose heartbeat has expired. Nodes normally expire from the backend
+// on their own, this provides a way to garbage collect offline nodes from
+// backends that keep expired entries around.
+func (c *NodeCommand) Remove(client auth.ClientI) error {
+	ctx := context.TODO()
+	switch {
+	case c.expiredOnly:
+		if c.nodeName != "" {
+			return trace.BadParameter("either specify a node name or --expired, not both")
+		}
+		nodes, err := client.GetNodes(ctx, c.namespace)
+		if err != nil {
+			return trace.Wrap(err)
+		}
+		now := time.Now().UTC()
+		removed := 0
+		for _, node := range nodes {
+			expiry := node.Expiry()
+			if expiry.IsZero() || expiry.After(now) {
+				continue
+			}
+			if err := client.DeleteNode(ctx, c.namespace, node.GetName()); err != nil {
+				return trace.Wrap(err)
+			}
+			removed++
+		}
+		fmt.Printf("Removed %v expired node(s)\n", removed)
+		return nil
+	case c.nodeName != "":
+		if err := client.DeleteNode(ctx, c.namespace, c.nodeName); err != nil {
+			return trace.Wrap(err)
+		}
+		fmt.Printf("Node %q has been removed\n", c.nodeName)
+		return nil
+	default:
+		return trace.BadParameter("specify the name of the node to remove or --expired")
+	}
+}
+
+// Update patches a node's centrally managed labels.
+func (c *NodeCommand) Update(client auth.ClientI) error {
+	if len(c.setLabels) == 0 && len(c.removeLabels) == 0 {
+		return trace.BadParameter("pass --set-label or --remove-label")
+	}
+	set := make(map[string]string, len(c.setLabels))
+	for _, label := range c.setLabels {
+		parts := strings.SplitN(label, "=", 2)
+		if len(parts) != 2 || parts[0] == "" {
+			return trace.BadParameter("invalid label %q, expected <key>=<value>", label)
+		}
+		set[parts[0]] = parts[1]
+	}
+	err := client.UpdateManagedLabels(context.TODO(), c.nodeName, set, c.removeLabels)
+	if err != nil {
+		return trace.Wrap(err)
+	}
+	fmt.Printf("Labels of node %q have been updated.\n", c.nodeName)
+	return nil
+}
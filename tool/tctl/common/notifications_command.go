@@ -0,0 +1,132 @@
+/*
+Copyright 2021 Gravitational, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package common
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/gravitational/teleport/lib/asciitable"
+	"github.com/gravitational/teleport/lib/auth"
+	"github.com/gravitational/teleport/lib/service"
+
+	"github.com/gravitational/kingpin"
+	"github.com/gravitational/trace"
+)
+
+// NotificationsCommand implements the "tctl notifications" group of
+// commands, posting and listing user notices.
+type NotificationsCommand struct {
+	config *service.Config
+
+	user    string
+	title   string
+	message string
+	ttl     time.Duration
+	id      string
+
+	// notificationsCreate implements "tctl notifications create".
+	notificationsCreate *kingpin.CmdClause
+	// notificationsList implements "tctl notifications ls".
+	notificationsList *kingpin.CmdClause
+	// notificationsAck implements "tctl notifications ack".
+	notificationsAck *kingpin.CmdClause
+}
+
+// Initialize allows NotificationsCommand to plug itself into the CLI
+// parser.
+func (c *NotificationsCommand) Initialize(app *kingpin.Application, config *service.Config) {
+	c.config = config
+
+	notifications := app.Command("notifications", "Manage user notifications.")
+	c.notificationsCreate = notifications.Command("create", "Post a notification to a user or the whole cluster.")
+	c.notificationsCreate.Flag("user", "Recipient, omit for a cluster-wide announcement").StringVar(&c.user)
+	c.notificationsCreate.Flag("title", "Short headline").StringVar(&c.title)
+	c.notificationsCreate.Flag("message", "Notification body").StringVar(&c.message)
+	c.notificationsCreate.Flag("ttl", "How long the notification is shown").DurationVar(&c.ttl)
+
+	c.notificationsList = notifications.Command("ls", "List your notifications.")
+
+	c.notificationsAck = notifications.Command("ack", "Dismiss one of your notifications.")
+	c.notificationsAck.Arg("id", "Notification ID").Required().StringVar(&c.id)
+}
+
+// TryRun attempts to run subcommands like "notifications create".
+func (c *NotificationsCommand) TryRun(cmd string, client auth.ClientI) (match bool, err error) {
+	switch cmd {
+	case c.notificationsCreate.FullCommand():
+		err = c.Create(client)
+	case c.notificationsList.FullCommand():
+		err = c.List(client)
+	case c.notificationsAck.FullCommand():
+		err = c.Ack(client)
+	default:
+		return false, nil
+	}
+	return true, trace.Wrap(err)
+}
+
+// Create posts a notification.
+func (c *NotificationsCommand) Create(client auth.ClientI) error {
+	notification := auth.Notification{
+		User:    c.user,
+		Title:   c.title,
+		Message: c.message,
+	}
+	if c.ttl > 0 {
+		notification.Expires = time.Now().UTC().Add(c.ttl)
+	}
+	if err := client.CreateNotification(context.TODO(), notification); err != nil {
+		return trace.Wrap(err)
+	}
+	target := "all users"
+	if c.user != "" {
+		target = fmt.Sprintf("user %q", c.user)
+	}
+	fmt.Printf("Notification posted to %v.\n", target)
+	return nil
+}
+
+// List prints the calling user's notifications.
+func (c *NotificationsCommand) List(client auth.ClientI) error {
+	notifications, err := client.GetNotifications(context.TODO())
+	if err != nil {
+		return trace.Wrap(err)
+	}
+	t := asciitable.MakeTable([]string{"ID", "Type", "Created", "Title", "Message"})
+	for _, notification := range notifications {
+		t.AddRow([]string{
+			notification.ID,
+			notification.Type,
+			notification.Created.Format(time.RFC822),
+			notification.Title,
+			notification.Message,
+		})
+	}
+	fmt.Println(t.AsBuffer().String())
+	return nil
+}
+
+// Ack dismisses a notification.
+func (c *NotificationsCommand) Ack(client auth.ClientI) error {
+	if err := client.AckNotification(context.TODO(), c.id); err != nil {
+		return trace.Wrap(err)
+	}
+	fmt.Printf("Notification %v dismissed.\n", c.id)
+	return nil
+}
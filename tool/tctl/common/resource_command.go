@@ -17,10 +17,14 @@ limitations under the License.
 package common
 
 import (
+	"bytes"
 	"context"
 	"fmt"
 	"io"
+	"io/ioutil"
 	"os"
+	"os/exec"
+	"strings"
 	"time"
 
 	"github.com/gravitational/teleport"
@@ -67,6 +71,7 @@ type ResourceCommand struct {
 	getCmd    *kingpin.CmdClause
 	createCmd *kingpin.CmdClause
 	updateCmd *kingpin.CmdClause
+	editCmd   *kingpin.CmdClause
 
 	CreateHandlers map[ResourceKind]ResourceCreateHandler
 }
@@ -95,6 +100,7 @@ func (rc *ResourceCommand) Initialize(app *kingpin.Application, config *service.
 		types.KindSessionRecordingConfig:  rc.createSessionRecordingConfig,
 		types.KindLock:                    rc.createLock,
 		types.KindNetworkRestrictions:     rc.createNetworkRestrictions,
+		types.KindNode:                    rc.createNode,
 	}
 	rc.config = config
 
@@ -127,6 +133,15 @@ func (rc *ResourceCommand) Initialize(app *kingpin.Application, config *service.
 	rc.getCmd.Flag("format", "Output format: 'yaml', 'json' or 'text'").Default(teleport.YAML).StringVar(&rc.format)
 	rc.getCmd.Flag("namespace", "Namespace of the resources").Hidden().Default(apidefaults.Namespace).StringVar(&rc.namespace)
 	rc.getCmd.Flag("with-secrets", "Include secrets in resources like certificate authorities or OIDC connectors").Default("false").BoolVar(&rc.withSecrets)
+	rc.getCmd.Flag("labels", "Filter node resources by labels, e.g. env=prod,cloud=aws").StringVar(&rc.labels)
+
+	rc.editCmd = app.Command("edit", "Edit a resource in your $EDITOR and save it back")
+	rc.editCmd.Arg("resource type/resource name", `Resource to edit
+	<resource type>  Type of a resource [for example: role]
+	<resource name>  Resource name to edit
+
+	Examples:
+	$ tctl edit role/admin`).SetValue(&rc.ref)
 
 	rc.getCmd.Alias(getHelp)
 }
@@ -147,12 +162,93 @@ func (rc *ResourceCommand) TryRun(cmd string, client auth.ClientI) (match bool,
 		// tctl update
 	case rc.updateCmd.FullCommand():
 		err = rc.Update(client)
+		// tctl edit
+	case rc.editCmd.FullCommand():
+		err = rc.Edit(client)
 	default:
 		return false, nil
 	}
 	return true, trace.Wrap(err)
 }
 
+// Edit fetches a resource, lets the user modify it in their $EDITOR and
+// saves the result. The resource is re-fetched before saving: if it was
+// modified by someone else while being edited, the save is aborted so
+// concurrent changes are not silently overwritten.
+func (rc *ResourceCommand) Edit(client auth.ClientI) error {
+	if rc.ref.Kind == "" || rc.ref.Name == "" {
+		return trace.BadParameter("specify the resource to edit, e.g. 'tctl edit role/admin'")
+	}
+
+	fetchYAML := func() (string, error) {
+		collection, err := rc.getCollection(client)
+		if err != nil {
+			return "", trace.Wrap(err)
+		}
+		var buf bytes.Buffer
+		if err := writeYAML(collection, &buf); err != nil {
+			return "", trace.Wrap(err)
+		}
+		return buf.String(), nil
+	}
+
+	original, err := fetchYAML()
+	if err != nil {
+		return trace.Wrap(err)
+	}
+
+	file, err := ioutil.TempFile("", "tctl-edit-*.yaml")
+	if err != nil {
+		return trace.ConvertSystemError(err)
+	}
+	defer os.Remove(file.Name())
+	if _, err := file.WriteString(original); err != nil {
+		return trace.ConvertSystemError(err)
+	}
+	if err := file.Close(); err != nil {
+		return trace.ConvertSystemError(err)
+	}
+
+	editor := os.Getenv("EDITOR")
+	if editor == "" {
+		editor = "vi"
+	}
+	// EDITOR may contain arguments, e.g. "code --wait".
+	editorArgs := append(strings.Fields(editor), file.Name())
+	cmd := exec.Command(editorArgs[0], editorArgs[1:]...)
+	cmd.Stdin, cmd.Stdout, cmd.Stderr = os.Stdin, os.Stdout, os.Stderr
+	if err := cmd.Run(); err != nil {
+		return trace.Wrap(err, "editor %q exited with an error", editor)
+	}
+
+	edited, err := ioutil.ReadFile(file.Name())
+	if err != nil {
+		return trace.ConvertSystemError(err)
+	}
+	if string(edited) == original {
+		fmt.Println("Edit cancelled, no changes made.")
+		return nil
+	}
+
+	// Optimistic locking: make sure the resource was not changed while it
+	// was being edited.
+	current, err := fetchYAML()
+	if err != nil {
+		return trace.Wrap(err)
+	}
+	if current != original {
+		return trace.CompareFailed("resource %v/%v was modified while you were editing it, re-run 'tctl edit' and apply your changes again", rc.ref.Kind, rc.ref.Name)
+	}
+
+	rc.filename = file.Name()
+	rc.force = true
+	if err := rc.Create(client); err != nil {
+		return trace.Wrap(err)
+	}
+	fmt.Printf("%v/%v has been updated\n", rc.ref.Kind, rc.ref.Name)
+	return nil
+}
+
 // IsDeleteSubcommand returns 'true' if the given command is `tctl rm`
 func (rc *ResourceCommand) IsDeleteSubcommand(cmd string) bool {
 	return cmd == rc.deleteCmd.FullCommand()
@@ -750,6 +846,44 @@ func (rc *ResourceCommand) IsForced() bool {
 }
 
 // getCollection lists all resources of a given type
+// createNode implements 'tctl create node.yaml' to register a static node.
+func (rc *ResourceCommand) createNode(client auth.ClientI, raw services.UnknownResource) error {
+	ctx := context.TODO()
+	server, err := services.UnmarshalServer(raw.Raw, types.KindNode)
+	if err != nil {
+		return trace.Wrap(err)
+	}
+	if _, err := client.UpsertNode(ctx, server); err != nil {
+		return trace.Wrap(err)
+	}
+	fmt.Printf("node %q has been created\n", server.GetName())
+	return nil
+}
+
+// filterServersByLabels returns the servers whose labels match all of the
+// labels in the comma-separated key=value spec.
+func filterServersByLabels(servers []types.Server, labelSpec string) ([]types.Server, error) {
+	labels, err := client.ParseLabelSpec(labelSpec)
+	if err != nil {
+		return nil, trace.Wrap(err)
+	}
+	var out []types.Server
+	for _, server := range servers {
+		all := server.GetAllLabels()
+		matches := true
+		for key, value := range labels {
+			if all[key] != value {
+				matches = false
+				break
+			}
+		}
+		if matches {
+			out = append(out, server)
+		}
+	}
+	return out, nil
+}
+
 func (rc *ResourceCommand) getCollection(client auth.ClientI) (ResourceCollection, error) {
 	if rc.ref.Kind == "" {
 		return nil, trace.BadParameter("specify resource to list, e.g. 'tctl get roles'")
@@ -847,6 +981,12 @@ func (rc *ResourceCommand) getCollection(client auth.ClientI) (ResourceCollectio
 		if err != nil {
 			return nil, trace.Wrap(err)
 		}
+		if rc.labels != "" {
+			nodes, err = filterServersByLabels(nodes, rc.labels)
+			if err != nil {
+				return nil, trace.Wrap(err)
+			}
+		}
 		if rc.ref.Name == "" {
 			return &serverCollection{servers: nodes}, nil
 		}
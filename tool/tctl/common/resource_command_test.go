@@ -0,0 +1,58 @@
+/*
+Copyright 2021 Gravitational, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package common
+
+import (
+	"testing"
+
+	"github.com/gravitational/teleport/api/types"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestFilterServersByLabels(t *testing.T) {
+	t.Parallel()
+
+	makeNode := func(name string, labels map[string]string) types.Server {
+		return &types.ServerV2{
+			Kind:     types.KindNode,
+			Version:  types.V2,
+			Metadata: types.Metadata{Name: name, Labels: labels},
+		}
+	}
+	servers := []types.Server{
+		makeNode("web-1", map[string]string{"env": "prod", "role": "web"}),
+		makeNode("web-2", map[string]string{"env": "staging", "role": "web"}),
+		makeNode("db-1", map[string]string{"env": "prod", "role": "db"}),
+	}
+
+	out, err := filterServersByLabels(servers, "env=prod")
+	require.NoError(t, err)
+	require.Len(t, out, 2)
+
+	out, err = filterServersByLabels(servers, "env=prod,role=web")
+	require.NoError(t, err)
+	require.Len(t, out, 1)
+	require.Equal(t, "web-1", out[0].GetName())
+
+	out, err = filterServersByLabels(servers, "env=none")
+	require.NoError(t, err)
+	require.Empty(t, out)
+
+	_, err = filterServersByLabels(servers, "not-a-label")
+	require.Error(t, err)
+}
@@ -73,6 +73,9 @@ type TokenCommand struct {
 	// labels is optional token labels
 	labels string
 
+	// singleUse makes the token usable for a single registration only.
+	singleUse bool
+
 	// tokenAdd is used to add a token.
 	tokenAdd *kingpin.CmdClause
 
@@ -94,6 +97,7 @@ func (c *TokenCommand) Initialize(app *kingpin.Application, config *service.Conf
 	c.tokenAdd.Flag("type", "Type of token to add").Required().StringVar(&c.tokenType)
 	c.tokenAdd.Flag("value", "Value of token to add").StringVar(&c.value)
 	c.tokenAdd.Flag("labels", "Set token labels, e.g. env=prod,region=us-west").StringVar(&c.labels)
+	c.tokenAdd.Flag("single-use", "Delete the token after the first successful registration").BoolVar(&c.singleUse)
 	c.tokenAdd.Flag("ttl", fmt.Sprintf("Set expiration time for token, default is %v hour, maximum is %v hours",
 		int(defaults.SignupTokenTTL/time.Hour), int(defaults.MaxSignupTokenTTL/time.Hour))).
 		Default(fmt.Sprintf("%v", defaults.SignupTokenTTL)).DurationVar(&c.ttl)
@@ -142,6 +146,12 @@ func (c *TokenCommand) Add(client auth.ClientI) error {
 			return trace.Wrap(err)
 		}
 	}
+	if c.singleUse {
+		if labels == nil {
+			labels = make(map[string]string)
+		}
+		labels[teleport.TokenLabelSingleUse] = "yes"
+	}
 
 	// Generate token.
 	token, err := client.GenerateToken(context.TODO(), auth.GenerateTokenRequest{
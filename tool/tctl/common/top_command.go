@@ -186,6 +186,7 @@ func (c *TopCommand) render(ctx context.Context, re Report, eventID string) erro
 	t2.RowSeparator = false
 	t2.Rows = [][]string{
 		[]string{"Start Time", re.Process.StartTime.Format(constants.HumanDateFormatSeconds)},
+		[]string{"Uptime", re.Process.Uptime().Round(time.Second).String()},
 		[]string{"Resident Memory Bytes", humanize.Bytes(uint64(re.Process.ResidentMemoryBytes))},
 		[]string{"Open File Descriptors", humanize.FormatFloat("", re.Process.OpenFDs)},
 		[]string{"CPU Seconds Total", humanize.FormatFloat("", re.Process.CPUSecondsTotal)},
@@ -352,6 +353,14 @@ type ProcessStats struct {
 	StartTime time.Time
 }
 
+// Uptime returns how long the process has been running for.
+func (p ProcessStats) Uptime() time.Duration {
+	if p.StartTime.IsZero() {
+		return 0
+	}
+	return time.Since(p.StartTime)
+}
+
 // GoStats is stats about go runtime
 type GoStats struct {
 	// Info is a runtime info (version, etc)
@@ -0,0 +1,72 @@
+/*
+Copyright 2021 Gravitational, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package common
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+
+	"github.com/gravitational/teleport/lib/auth"
+	"github.com/gravitational/teleport/lib/service"
+
+	"github.com/gravitational/kingpin"
+	"github.com/gravitational/trace"
+)
+
+// UsageReportCommand implements "tctl usage report", showing the exact
+// anonymized payload an enabled usage reporter would submit.
+type UsageReportCommand struct {
+	config *service.Config
+
+	// usageReport implements "tctl usage report".
+	usageReport *kingpin.CmdClause
+}
+
+// Initialize allows UsageReportCommand to plug itself into the CLI
+// parser.
+func (c *UsageReportCommand) Initialize(app *kingpin.Application, config *service.Config) {
+	c.config = config
+
+	usage := app.Command("usage", "Inspect anonymized usage reporting.")
+	c.usageReport = usage.Command("report", "Print the usage report that would be submitted.")
+}
+
+// TryRun attempts to run subcommands like "usage report".
+func (c *UsageReportCommand) TryRun(cmd string, client auth.ClientI) (match bool, err error) {
+	switch cmd {
+	case c.usageReport.FullCommand():
+		err = c.Report(client)
+	default:
+		return false, nil
+	}
+	return true, trace.Wrap(err)
+}
+
+// Report prints the usage report as JSON.
+func (c *UsageReportCommand) Report(client auth.ClientI) error {
+	report, err := client.GetUsageReport(context.TODO())
+	if err != nil {
+		return trace.Wrap(err)
+	}
+	out, err := json.MarshalIndent(report, "", "  ")
+	if err != nil {
+		return trace.Wrap(err)
+	}
+	fmt.Println(string(out))
+	return nil
+}
@@ -21,6 +21,7 @@ import (
 	"encoding/json"
 	"fmt"
 	"net/url"
+	"sort"
 	"strings"
 	"time"
 
@@ -52,12 +53,14 @@ type UserCommand struct {
 	ttl time.Duration
 
 	// updateRoles is used for update users command
-	updateRoles string
+	updateRoles  string
+	updateTraits string
 
 	// format is the output format, e.g. text or json
 	format string
 
 	userAdd           *kingpin.CmdClause
+	userInvites       *kingpin.CmdClause
 	userUpdate        *kingpin.CmdClause
 	userList          *kingpin.CmdClause
 	userDelete        *kingpin.CmdClause
@@ -103,6 +106,10 @@ func (u *UserCommand) Initialize(app *kingpin.Application, config *service.Confi
 	u.userUpdate.Arg("login", "Teleport user login").Required().StringVar(&u.login)
 	u.userUpdate.Flag("set-roles", "Roles to assign to this user").
 		Default("").StringVar(&u.updateRoles)
+	u.userUpdate.Flag("set-traits", "Traits to assign to this user, e.g. logins=root,logins=ubuntu,env=dev").
+		Default("").StringVar(&u.updateTraits)
+
+	u.userInvites = users.Command("invites", "List pending user invite and password reset links")
 
 	u.userList = users.Command("ls", "List all user accounts "+helpPrefix)
 	u.userList.Flag("format", "Output format, 'text' or 'json'").Hidden().Default(teleport.Text).StringVar(&u.format)
@@ -126,6 +133,8 @@ func (u *UserCommand) TryRun(cmd string, client auth.ClientI) (match bool, err e
 		err = u.Add(client)
 	case u.userUpdate.FullCommand():
 		err = u.Update(client)
+	case u.userInvites.FullCommand():
+		err = u.ListInvites(client)
 	case u.userList.FullCommand():
 		err = u.List(client)
 	case u.userDelete.FullCommand():
@@ -371,17 +380,29 @@ func printTokenAsText(token types.ResetPasswordToken, messageFormat string) erro
 
 // Update updates existing user
 func (u *UserCommand) Update(client auth.ClientI) error {
+	if u.updateRoles == "" && u.updateTraits == "" {
+		return trace.BadParameter("use --set-roles or --set-traits to specify what to update")
+	}
 	user, err := client.GetUser(u.login, false)
 	if err != nil {
 		return trace.Wrap(err)
 	}
-	roles := flattenSlice([]string{u.updateRoles})
-	for _, role := range roles {
-		if _, err := client.GetRole(context.TODO(), role); err != nil {
+	if u.updateRoles != "" {
+		roles := flattenSlice([]string{u.updateRoles})
+		for _, role := range roles {
+			if _, err := client.GetRole(context.TODO(), role); err != nil {
+				return trace.Wrap(err)
+			}
+		}
+		user.SetRoles(roles)
+	}
+	if u.updateTraits != "" {
+		traits, err := parseTraitSpec(u.updateTraits)
+		if err != nil {
 			return trace.Wrap(err)
 		}
+		user.SetTraits(traits)
 	}
-	user.SetRoles(roles)
 	if err := client.UpsertUser(user); err != nil {
 		return trace.Wrap(err)
 	}
@@ -389,6 +410,60 @@ func (u *UserCommand) Update(client auth.ClientI) error {
 	return nil
 }
 
+// formatTraits renders user traits as a compact single line for tables.
+func formatTraits(traits map[string][]string) string {
+	if len(traits) == 0 {
+		return ""
+	}
+	keys := make([]string, 0, len(traits))
+	for key := range traits {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	var out []string
+	for _, key := range keys {
+		out = append(out, fmt.Sprintf("%v: %v", key, strings.Join(traits[key], " ")))
+	}
+	return strings.Join(out, ", ")
+}
+
+// parseTraitSpec parses a comma-separated list of key=value trait pairs,
+// repeated keys accumulate values, e.g. "logins=root,logins=ubuntu".
+func parseTraitSpec(spec string) (map[string][]string, error) {
+	traits := make(map[string][]string)
+	for _, pair := range strings.Split(spec, ",") {
+		parts := strings.SplitN(pair, "=", 2)
+		if len(parts) != 2 || strings.TrimSpace(parts[0]) == "" {
+			return nil, trace.BadParameter("invalid trait %q, expected key=value", pair)
+		}
+		key := strings.TrimSpace(parts[0])
+		traits[key] = append(traits[key], strings.TrimSpace(parts[1]))
+	}
+	return traits, nil
+}
+
+// ListInvites prints the pending user invite and password reset tokens,
+// without revealing the token secrets.
+func (u *UserCommand) ListInvites(client auth.ClientI) error {
+	tokens, err := client.GetResetPasswordTokens(context.TODO())
+	if err != nil {
+		return trace.Wrap(err)
+	}
+	if len(tokens) == 0 {
+		fmt.Println("No pending invites found")
+		return nil
+	}
+	t := asciitable.MakeTable([]string{"User", "Expires"})
+	for _, token := range tokens {
+		t.AddRow([]string{
+			token.GetUser(),
+			token.Expiry().Format(time.RFC822),
+		})
+	}
+	fmt.Println(t.AsBuffer().String())
+	return nil
+}
+
 // List prints all existing user accounts
 func (u *UserCommand) List(client auth.ClientI) error {
 	users, err := client.GetUsers(false)
@@ -400,10 +475,10 @@ func (u *UserCommand) List(client auth.ClientI) error {
 			fmt.Println("No users found")
 			return nil
 		}
-		t := asciitable.MakeTable([]string{"User", "Roles"})
+		t := asciitable.MakeTable([]string{"User", "Roles", "Traits"})
 		for _, u := range users {
 			t.AddRow([]string{
-				u.GetName(), strings.Join(u.GetRoles(), ","),
+				u.GetName(), strings.Join(u.GetRoles(), ","), formatTraits(u.GetTraits()),
 			})
 		}
 		fmt.Println(t.AsBuffer().String())
@@ -42,3 +42,15 @@ func TestTrimDurationSuffix(t *testing.T) {
 		})
 	}
 }
+
+func TestParseTraitSpec(t *testing.T) {
+	traits, err := parseTraitSpec("logins=root,logins=ubuntu,env=dev")
+	require.NoError(t, err)
+	require.Equal(t, map[string][]string{
+		"logins": {"root", "ubuntu"},
+		"env":    {"dev"},
+	}, traits)
+
+	_, err = parseTraitSpec("oops")
+	require.Error(t, err)
+}
@@ -32,6 +32,16 @@ func main() {
 		&common.AccessRequestCommand{},
 		&common.AppsCommand{},
 		&common.DBCommand{},
+		&common.AccessCommand{},
+		&common.RecordingsCommand{},
+		&common.AuditCommand{},
+		&common.NotificationsCommand{},
+		&common.AlertsCommand{},
+		&common.InventoryCommand{},
+		&common.LoginRuleCommand{},
+		&common.UsageReportCommand{},
+		&common.LicenseCommand{},
+		&common.BackupCommand{},
 	}
 	common.Run(commands)
 }
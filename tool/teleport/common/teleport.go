@@ -19,12 +19,14 @@ package common
 import (
 	"context"
 	"fmt"
+	"io/ioutil"
 	"net/url"
 	"os"
 	"os/user"
 	"path/filepath"
 	"strconv"
 	"strings"
+	"syscall"
 
 	"github.com/gravitational/teleport"
 	"github.com/gravitational/teleport/lib/config"
@@ -72,6 +74,15 @@ func Run(options Options) (app *kingpin.Application, executedCommand string, con
 	start := app.Command("start", "Starts the Teleport service.")
 	status := app.Command("status", "Print the status of the current SSH session.")
 	dump := app.Command("configure", "Generate a simple config file to get started.")
+	checkConfig := app.Command("check-config", "Validate a Teleport configuration file.")
+	upgrade := app.Command("upgrade", "Perform a graceful in-place upgrade of a running Teleport process.")
+	checkConfig.Flag("config",
+		fmt.Sprintf("Path to a configuration file [%v]", defaults.ConfigFilePath)).
+		Short('c').ExistingFileVar(&ccf.ConfigFile)
+
+	// upgrade flags
+	upgrade.Flag("pid-file",
+		"Full path to the PID file of the running Teleport process").Required().ExistingFileVar(&ccf.PIDFile)
 	ver := app.Command("version", "Print the version.")
 	scpc := app.Command("scp", "Server-side implementation of SCP.").Hidden()
 	exec := app.Command("exec", "Used internally by Teleport to re-exec itself to run a command.").Hidden()
@@ -114,6 +125,8 @@ func Run(options Options) (app *kingpin.Application, executedCommand string, con
 		Short('c').ExistingFileVar(&ccf.ConfigFile)
 	start.Flag("bootstrap",
 		"Path to bootstrap file (ignored if already initialized)").ExistingFileVar(&ccf.BootstrapFile)
+	start.Flag("low-memory",
+		"Trim caches and buffers for resource-constrained hosts").BoolVar(&ccf.LowMemory)
 	start.Flag("config-string",
 		"Base64 encoded configuration string").Hidden().Envar(defaults.ConfigEnvar).
 		StringVar(&ccf.ConfigString)
@@ -223,6 +236,17 @@ func Run(options Options) (app *kingpin.Application, executedCommand string, con
 	dump.Flag("acme-email",
 		"Email to receive updates from Letsencrypt.org.").StringVar(&dumpFlags.ACMEEmail)
 	dump.Flag("test", "Path to a configuration file to test.").ExistingFileVar(&dumpFlags.testConfigFile)
+	dump.Flag("roles",
+		fmt.Sprintf("Comma-separated list of roles to generate config for: %s,%s,%s. All are enabled by default.",
+			defaults.RoleAuthService, defaults.RoleProxy, defaults.RoleNode)).StringVar(&dumpFlags.Roles)
+	dump.Flag("auth-server",
+		"Address of the auth server for an agent config, e.g. auth.example.com:3025.").StringVar(&dumpFlags.AuthServer)
+	dump.Flag("token",
+		"Invitation token to register with the auth server for an agent config.").StringVar(&dumpFlags.AuthToken)
+	dump.Flag("labels", "Comma-separated list of labels to add to newly created nodes, e.g. env=staging,cloud=aws.").StringVar(&dumpFlags.NodeLabels)
+	dump.Flag("data-dir", "Path to a directory where Teleport keeps its data.").StringVar(&dumpFlags.DataDir)
+	dump.Flag("public-addr", "The hostport that the proxy advertises for the HTTP endpoint.").StringVar(&dumpFlags.PublicAddr)
+	dump.Flag("fips", "Generate a FedRAMP/FIPS 140-2 compliant configuration, for use with FIPS Teleport builds.").BoolVar(&dumpFlags.FIPS)
 
 	// parse CLI commands+flags:
 	command, err := app.Parse(options.Args)
@@ -265,6 +289,10 @@ func Run(options Options) (app *kingpin.Application, executedCommand string, con
 		err = onStatus()
 	case dump.FullCommand():
 		err = onConfigDump(dumpFlags)
+	case checkConfig.FullCommand():
+		err = onCheckConfig(ccf.ConfigFile)
+	case upgrade.FullCommand():
+		err = onUpgrade(ccf.PIDFile)
 	case exec.FullCommand():
 		err = onExec()
 	case forward.FullCommand():
@@ -283,6 +311,53 @@ func OnStart(config *service.Config) error {
 	return service.Run(context.TODO(), *config, nil)
 }
 
+// onUpgrade is the handler for "upgrade" CLI command. It signals the
+// running Teleport process identified by the PID file to re-exec itself,
+// handing off listening sockets to the new process. Replace the teleport
+// binary on disk before running this command to upgrade in place without
+// dropping established connections.
+func onUpgrade(pidFile string) error {
+	data, err := ioutil.ReadFile(pidFile)
+	if err != nil {
+		return trace.ConvertSystemError(err)
+	}
+	pid, err := strconv.Atoi(strings.TrimSpace(string(data)))
+	if err != nil {
+		return trace.BadParameter("expected PID file %v to contain a process ID: %v", pidFile, err)
+	}
+	// Make sure the process is actually running before signaling the
+	// restart, signal 0 only performs the liveness check.
+	if err := syscall.Kill(pid, syscall.Signal(0)); err != nil {
+		return trace.NotFound("no running process with PID %v found: %v", pid, err)
+	}
+	if err := syscall.Kill(pid, syscall.SIGHUP); err != nil {
+		return trace.ConvertSystemError(err)
+	}
+	fmt.Printf("Signaled process %v to perform a graceful restart. The new binary takes over listening sockets; check the logs to confirm the upgrade.\n", pid)
+	return nil
+}
+
+// onCheckConfig is the handler for "check-config" CLI command, it parses
+// and validates the configuration file without starting any services
+func onCheckConfig(configPath string) error {
+	if configPath == "" {
+		configPath = defaults.ConfigFilePath
+	}
+	fileConf, err := config.ReadConfigFile(configPath)
+	if err != nil {
+		return trace.Wrap(err)
+	}
+	if fileConf == nil {
+		return trace.NotFound("configuration file %v is not found", configPath)
+	}
+	conf := service.MakeDefaultConfig()
+	if err := config.ApplyFileConfig(fileConf, conf); err != nil {
+		return trace.Wrap(err)
+	}
+	fmt.Printf("Configuration file %v is valid.\n", configPath)
+	return nil
+}
+
 // onStatus is the handler for "status" CLI command
 func onStatus() error {
 	sshClient := os.Getenv("SSH_CLIENT")
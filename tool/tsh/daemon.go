@@ -0,0 +1,223 @@
+/*
+Copyright 2021 Gravitational, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"net"
+	"net/http"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/gravitational/teleport/api/profile"
+	"github.com/gravitational/teleport/lib/client"
+
+	"github.com/gravitational/trace"
+	"github.com/julienschmidt/httprouter"
+)
+
+// daemonEventPollPeriod is how often the daemon checks the profile store
+// for changes when a client waits on the events endpoint.
+const daemonEventPollPeriod = 2 * time.Second
+
+// daemonEventTimeout is how long an events request is held before
+// returning with no change.
+const daemonEventTimeout = 55 * time.Second
+
+// onDaemon serves a local JSON API over a unix socket so desktop GUI
+// clients can drive Teleport without shelling out to the CLI. Access is
+// restricted to the local user through the socket file permissions.
+func onDaemon(cf *CLIConf) error {
+	socketPath := cf.DaemonSocket
+	if socketPath == "" {
+		home := cf.HomePath
+		if home == "" {
+			home = profile.FullProfilePath("")
+		}
+		socketPath = filepath.Join(home, "tshd.sock")
+	}
+	if err := os.Remove(socketPath); err != nil && !os.IsNotExist(err) {
+		return trace.ConvertSystemError(err)
+	}
+	listener, err := net.Listen("unix", socketPath)
+	if err != nil {
+		return trace.Wrap(err)
+	}
+	defer listener.Close()
+	if err := os.Chmod(socketPath, 0600); err != nil {
+		return trace.ConvertSystemError(err)
+	}
+
+	daemon := &tshDaemon{cf: cf}
+	router := httprouter.New()
+	router.GET("/v1/clusters", daemon.listClusters)
+	router.GET("/v1/clusters/:cluster/nodes", daemon.listNodes)
+	router.GET("/v1/events", daemon.waitForEvents)
+
+	fmt.Printf("tsh daemon listening on %v\n", socketPath)
+	server := &http.Server{Handler: router}
+	go func() {
+		<-cf.Context.Done()
+		server.Close()
+	}()
+	err = server.Serve(listener)
+	if err == http.ErrServerClosed {
+		return nil
+	}
+	return trace.Wrap(err)
+}
+
+// tshDaemon implements the local API handlers.
+type tshDaemon struct {
+	cf *CLIConf
+}
+
+// daemonCluster describes one logged-in cluster profile.
+type daemonCluster struct {
+	// Name is the proxy host the profile belongs to.
+	Name string `json:"name"`
+	// Username is the logged-in Teleport user.
+	Username string `json:"username"`
+	// Current is true for the active profile.
+	Current bool `json:"current"`
+	// ValidUntil is when the profile's certificate expires.
+	ValidUntil time.Time `json:"valid_until"`
+}
+
+// listClusters returns the logged-in profiles.
+func (d *tshDaemon) listClusters(w http.ResponseWriter, r *http.Request, _ httprouter.Params) {
+	current, others, err := client.Status(d.cf.HomePath, "")
+	if err != nil && !trace.IsNotFound(err) {
+		daemonError(w, err)
+		return
+	}
+	clusters := []daemonCluster{}
+	if current != nil {
+		clusters = append(clusters, daemonCluster{
+			Name:       current.Name,
+			Username:   current.Username,
+			Current:    true,
+			ValidUntil: current.ValidUntil,
+		})
+	}
+	for _, profile := range others {
+		clusters = append(clusters, daemonCluster{
+			Name:       profile.Name,
+			Username:   profile.Username,
+			ValidUntil: profile.ValidUntil,
+		})
+	}
+	daemonJSON(w, clusters)
+}
+
+// daemonNode describes one server visible to the user.
+type daemonNode struct {
+	// Hostname is the node hostname.
+	Hostname string `json:"hostname"`
+	// Addr is the node address.
+	Addr string `json:"addr"`
+	// Labels are the node's labels.
+	Labels map[string]string `json:"labels,omitempty"`
+}
+
+// listNodes returns the nodes of a cluster the user can see.
+func (d *tshDaemon) listNodes(w http.ResponseWriter, r *http.Request, params httprouter.Params) {
+	cf := *d.cf
+	cf.SiteName = params.ByName("cluster")
+	tc, err := makeClient(&cf, true)
+	if err != nil {
+		daemonError(w, err)
+		return
+	}
+	nodes, err := tc.ListNodes(r.Context())
+	if err != nil {
+		daemonError(w, err)
+		return
+	}
+	out := make([]daemonNode, 0, len(nodes))
+	for _, node := range nodes {
+		out = append(out, daemonNode{
+			Hostname: node.GetHostname(),
+			Addr:     node.GetAddr(),
+			Labels:   node.GetAllLabels(),
+		})
+	}
+	daemonJSON(w, out)
+}
+
+// waitForEvents blocks until the profile store changes or the hold
+// interval passes, letting clients subscribe to state changes with
+// sequential requests.
+func (d *tshDaemon) waitForEvents(w http.ResponseWriter, r *http.Request, _ httprouter.Params) {
+	profileDir := profile.FullProfilePath(d.cf.HomePath)
+	started := time.Now()
+	initial := daemonProfileStamp(profileDir)
+	for time.Since(started) < daemonEventTimeout {
+		select {
+		case <-r.Context().Done():
+			return
+		case <-time.After(daemonEventPollPeriod):
+		}
+		if stamp := daemonProfileStamp(profileDir); stamp != initial {
+			daemonJSON(w, map[string]string{"event": "profiles-changed"})
+			return
+		}
+	}
+	daemonJSON(w, map[string]string{"event": "none"})
+}
+
+// daemonProfileStamp fingerprints the profile directory by names and
+// modification times.
+func daemonProfileStamp(profileDir string) string {
+	entries, err := os.ReadDir(profileDir)
+	if err != nil {
+		return ""
+	}
+	var stamp string
+	for _, entry := range entries {
+		info, err := entry.Info()
+		if err != nil {
+			continue
+		}
+		stamp += fmt.Sprintf("%v:%v;", entry.Name(), info.ModTime().UnixNano())
+	}
+	return stamp
+}
+
+// daemonJSON writes a JSON response.
+func daemonJSON(w http.ResponseWriter, body interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	if err := json.NewEncoder(w).Encode(body); err != nil {
+		log.WithError(err).Debug("Failed to encode daemon response.")
+	}
+}
+
+// daemonError maps an error to a JSON error response.
+func daemonError(w http.ResponseWriter, err error) {
+	status := http.StatusInternalServerError
+	if trace.IsNotFound(err) {
+		status = http.StatusNotFound
+	}
+	if trace.IsAccessDenied(err) {
+		status = http.StatusForbidden
+	}
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	json.NewEncoder(w).Encode(map[string]string{"error": err.Error()})
+}
@@ -17,7 +17,13 @@ limitations under the License.
 package main
 
 import (
+	"crypto/tls"
+	"crypto/x509"
+	"encoding/json"
 	"fmt"
+	"io"
+	"io/ioutil"
+	"net"
 	"os"
 	"os/exec"
 	"sort"
@@ -25,6 +31,7 @@ import (
 	"strings"
 	"text/template"
 
+	"github.com/gravitational/teleport"
 	"github.com/gravitational/teleport/api/client/proto"
 	"github.com/gravitational/teleport/api/types"
 	"github.com/gravitational/teleport/lib/client"
@@ -34,6 +41,7 @@ import (
 	"github.com/gravitational/teleport/lib/utils"
 
 	"github.com/gravitational/trace"
+	"gopkg.in/yaml.v2"
 )
 
 // onListDatabases implements "tsh db ls" command.
@@ -63,8 +71,30 @@ func onListDatabases(cf *CLIConf) error {
 	sort.Slice(servers, func(i, j int) bool {
 		return servers[i].GetName() < servers[j].GetName()
 	})
-	showDatabases(tc.SiteName, types.DeduplicateDatabaseServers(servers),
-		profile.Databases, cf.Verbose)
+	servers = types.DeduplicateDatabaseServers(servers)
+
+	switch strings.ToLower(cf.Format) {
+	case "", teleport.Text:
+		showDatabases(tc.SiteName, servers, profile.Databases, cf.Verbose)
+	case teleport.JSON:
+		out, err := json.MarshalIndent(servers, "", "  ")
+		if err != nil {
+			return trace.Wrap(err)
+		}
+		fmt.Println(string(out))
+	case teleport.YAML:
+		out, err := yaml.Marshal(servers)
+		if err != nil {
+			return trace.Wrap(err)
+		}
+		fmt.Println(string(out))
+	case teleport.Names:
+		for _, server := range servers {
+			fmt.Println(server.GetName())
+		}
+	default:
+		return trace.BadParameter("unsupported format. try 'json', 'yaml', 'text', or 'names'")
+	}
 	return nil
 }
 
@@ -422,3 +452,127 @@ Or view the connect command for the native database CLI client:
 `,
 	utils.Color(utils.Yellow, "tsh db connect {{.ServiceName}}"),
 	utils.Color(utils.Yellow, "tsh db config --format=cmd {{.ServiceName}}"))))
+
+// onDatabaseProxy implements "tsh db proxy" command: it starts a plaintext
+// local listener and forwards connections to the Teleport proxy database
+// endpoint over TLS, authenticating with the database certificate obtained
+// at "tsh db login". This lets GUI clients that cannot be configured with
+// client certificates connect through localhost.
+func onDatabaseProxy(cf *CLIConf) error {
+	tc, err := makeClient(cf, false)
+	if err != nil {
+		return trace.Wrap(err)
+	}
+	// The local proxy runs until interrupted, keep the certificate fresh.
+	tc.StartCertRenewal(cf.Context)
+
+	var database *tlsca.RouteToDatabase
+	var cert tls.Certificate
+	pool := x509.NewCertPool()
+	if cf.IdentityFileIn != "" {
+		// Automation runs from a single identity file produced by
+		// "tctl auth sign --format=tls" with database routing baked into
+		// the certificate.
+		key, err := client.KeyFromIdentityFile(cf.IdentityFileIn)
+		if err != nil {
+			return trace.Wrap(err)
+		}
+		cert, err = key.TLSCertificate()
+		if err != nil {
+			return trace.Wrap(err)
+		}
+		identity, err := key.TLSIdentity()
+		if err != nil {
+			return trace.Wrap(err)
+		}
+		if identity.RouteToDatabase.ServiceName == "" {
+			return trace.BadParameter("identity file %v does not contain database routing information, sign it with tctl auth sign --format=db", cf.IdentityFileIn)
+		}
+		database = &tlsca.RouteToDatabase{
+			ServiceName: identity.RouteToDatabase.ServiceName,
+			Protocol:    identity.RouteToDatabase.Protocol,
+			Username:    identity.RouteToDatabase.Username,
+			Database:    identity.RouteToDatabase.Database,
+		}
+		for _, ca := range key.TrustedCA {
+			for _, certPEM := range ca.TLSCertificates {
+				pool.AppendCertsFromPEM(certPEM)
+			}
+		}
+	} else {
+		profile, err := client.StatusCurrent(cf.HomePath, cf.Proxy)
+		if err != nil {
+			return trace.Wrap(err)
+		}
+		database, err = pickActiveDatabase(cf)
+		if err != nil {
+			return trace.Wrap(err)
+		}
+		cert, err = tls.LoadX509KeyPair(profile.DatabaseCertPath(database.ServiceName), profile.KeyPath())
+		if err != nil {
+			return trace.Wrap(err, "failed to load database certificate, try 'tsh db login'")
+		}
+		caPEM, err := ioutil.ReadFile(profile.CACertPath())
+		if err != nil {
+			return trace.Wrap(err)
+		}
+		if !pool.AppendCertsFromPEM(caPEM) {
+			return trace.BadParameter("failed to parse cluster CA certificate %v", profile.CACertPath())
+		}
+	}
+
+	var host string
+	var port int
+	switch database.Protocol {
+	case defaults.ProtocolPostgres:
+		host, port = tc.PostgresProxyHostPort()
+	case defaults.ProtocolMySQL:
+		host, port = tc.MySQLProxyHostPort()
+	case defaults.ProtocolMongoDB:
+		host, port = tc.WebProxyHostPort()
+	default:
+		return trace.BadParameter("unknown database protocol: %q", database)
+	}
+
+	tlsConfig := &tls.Config{
+		Certificates:       []tls.Certificate{cert},
+		RootCAs:            pool,
+		ServerName:         host,
+		InsecureSkipVerify: cf.InsecureSkipVerify,
+	}
+
+	listener, err := net.Listen("tcp", fmt.Sprintf("localhost:%d", cf.LocalProxyPort))
+	if err != nil {
+		return trace.Wrap(err)
+	}
+	defer listener.Close()
+
+	fmt.Printf("Started local proxy for database %q on %v.\n", database.ServiceName, listener.Addr())
+	fmt.Printf("Point your GUI client at it, authentication happens on connect. Press Ctrl-C to stop.\n")
+
+	for {
+		clientConn, err := listener.Accept()
+		if err != nil {
+			return trace.Wrap(err)
+		}
+		go func() {
+			defer clientConn.Close()
+			serverConn, err := tls.Dial("tcp", net.JoinHostPort(host, strconv.Itoa(port)), tlsConfig)
+			if err != nil {
+				log.WithError(err).Error("Failed to connect to the Teleport proxy.")
+				return
+			}
+			defer serverConn.Close()
+			errC := make(chan error, 2)
+			go func() {
+				_, err := io.Copy(serverConn, clientConn)
+				errC <- err
+			}()
+			go func() {
+				_, err := io.Copy(clientConn, serverConn)
+				errC <- err
+			}()
+			<-errC
+		}()
+	}
+}
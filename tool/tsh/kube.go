@@ -19,6 +19,7 @@ package main
 import (
 	"context"
 	"fmt"
+	"os"
 	"time"
 
 	"github.com/gravitational/kingpin"
@@ -30,30 +31,116 @@ import (
 	"github.com/gravitational/teleport/lib/kube/kubeconfig"
 	kubeutils "github.com/gravitational/teleport/lib/kube/utils"
 
+	corev1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/apimachinery/pkg/runtime/schema"
 	"k8s.io/apimachinery/pkg/runtime/serializer"
+	"k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/kubernetes/scheme"
 	"k8s.io/client-go/pkg/apis/clientauthentication"
 	clientauthv1beta1 "k8s.io/client-go/pkg/apis/clientauthentication/v1beta1"
+	"k8s.io/client-go/tools/clientcmd"
+	"k8s.io/client-go/tools/remotecommand"
 )
 
 type kubeCommands struct {
 	credentials *kubeCredentialsCommand
 	ls          *kubeLSCommand
 	login       *kubeLoginCommand
+	exec        *kubeExecCommand
 }
 
+// newKubeCommand returns the "tsh kube" command set: login, ls,
+// credentials and exec. Session listing and joining are deliberately
+// absent: the kube forwarder only mints a session ID for the recording
+// and never registers a joinable session server-side, so there is
+// nothing to list or attach to until kubernetes session tracking
+// exists.
 func newKubeCommand(app *kingpin.Application) kubeCommands {
 	kube := app.Command("kube", "Manage available kubernetes clusters")
 	cmds := kubeCommands{
 		credentials: newKubeCredentialsCommand(kube),
 		ls:          newKubeLSCommand(kube),
 		login:       newKubeLoginCommand(kube),
+		exec:        newKubeExecCommand(kube),
 	}
 	return cmds
 }
 
+type kubeExecCommand struct {
+	*kingpin.CmdClause
+	namespace string
+	container string
+	stdin     bool
+	tty       bool
+	pod       string
+	command   []string
+}
+
+func newKubeExecCommand(parent *kingpin.CmdClause) *kubeExecCommand {
+	c := &kubeExecCommand{
+		CmdClause: parent.Command("exec", "Execute a command in a kubernetes pod through the logged in cluster"),
+	}
+	c.Flag("pod-namespace", "Namespace of the pod").Short('n').Default("default").StringVar(&c.namespace)
+	c.Flag("container", "Container name, defaults to the first container in the pod").Short('c').StringVar(&c.container)
+	c.Flag("stdin", "Pass stdin to the container").BoolVar(&c.stdin)
+	c.Flag("tty", "Allocate a pseudo terminal").Short('t').BoolVar(&c.tty)
+	c.Arg("pod", "Pod name").Required().StringVar(&c.pod)
+	c.Arg("command", "Command to execute").Required().StringsVar(&c.command)
+	return c
+}
+
+func (c *kubeExecCommand) run(cf *CLIConf) error {
+	// kingpin passes the "--" separator through as an argument, strip it
+	// so it does not end up in the command sent to the pod.
+	if len(c.command) > 0 && c.command[0] == "--" {
+		c.command = c.command[1:]
+	}
+	if len(c.command) == 0 {
+		return trace.BadParameter("no command to execute")
+	}
+	// Use the kubeconfig written by "tsh kube login", the same way kubectl
+	// would.
+	config, err := clientcmd.NewNonInteractiveDeferredLoadingClientConfig(
+		clientcmd.NewDefaultClientConfigLoadingRules(), &clientcmd.ConfigOverrides{}).ClientConfig()
+	if err != nil {
+		return trace.Wrap(err, "failed to load kubeconfig, try \"tsh kube login\" first")
+	}
+	clientset, err := kubernetes.NewForConfig(config)
+	if err != nil {
+		return trace.Wrap(err)
+	}
+
+	req := clientset.CoreV1().RESTClient().Post().
+		Resource("pods").
+		Name(c.pod).
+		Namespace(c.namespace).
+		SubResource("exec")
+	req.VersionedParams(&corev1.PodExecOptions{
+		Container: c.container,
+		Command:   c.command,
+		Stdin:     c.stdin,
+		Stdout:    true,
+		Stderr:    true,
+		TTY:       c.tty,
+	}, scheme.ParameterCodec)
+
+	executor, err := remotecommand.NewSPDYExecutor(config, "POST", req.URL())
+	if err != nil {
+		return trace.Wrap(err)
+	}
+	streams := remotecommand.StreamOptions{
+		Stdout: os.Stdout,
+		Stderr: os.Stderr,
+		Tty:    c.tty,
+	}
+	if c.stdin {
+		streams.Stdin = os.Stdin
+	}
+	return trace.Wrap(executor.Stream(streams))
+}
+
 type kubeCredentialsCommand struct {
 	*kingpin.CmdClause
 	kubeCluster     string
@@ -327,6 +414,11 @@ func buildKubeConfigUpdate(cf *CLIConf, kubeStatus *kubernetesStatus) (*kubeconf
 		TshBinaryInsecure: cf.InsecureSkipVerify,
 		KubeClusters:      kubeStatus.kubeClusters,
 	}
+	if cf.HomePath != "" {
+		// Propagate the custom tsh home to the exec plugin, otherwise
+		// kubectl would invoke it against the default profile directory.
+		v.Exec.Env = map[string]string{homeEnvVar: cf.HomePath}
+	}
 
 	// Only switch the current context if kube-cluster is explicitly set on the command line.
 	if cf.KubernetesCluster != "" {
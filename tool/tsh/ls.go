@@ -0,0 +1,156 @@
+/*
+Copyright 2021 Gravitational, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package main
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+
+	"github.com/gravitational/teleport/api/profile"
+	"github.com/gravitational/teleport/api/types"
+	"github.com/gravitational/teleport/lib/services"
+
+	"github.com/gravitational/trace"
+	"github.com/vulcand/predicate"
+	"gopkg.in/yaml.v2"
+)
+
+// filterNodesByQuery evaluates a predicate-language query, e.g.
+// `equals(labels["env"], "prod")`, against each node's labels and name.
+func filterNodesByQuery(nodes []types.Server, query string) ([]types.Server, error) {
+	if query == "" {
+		return nodes, nil
+	}
+	var filtered []types.Server
+	for _, node := range nodes {
+		match, err := nodeMatchesQuery(node, query)
+		if err != nil {
+			return nil, trace.Wrap(err)
+		}
+		if match {
+			filtered = append(filtered, node)
+		}
+	}
+	return filtered, nil
+}
+
+// nodeMatchesQuery evaluates the query against a single node.
+func nodeMatchesQuery(node types.Server, query string) (bool, error) {
+	parser, err := predicate.NewParser(predicate.Def{
+		Operators: predicate.Operators{
+			AND: predicate.And,
+			OR:  predicate.Or,
+			NOT: predicate.Not,
+			EQ:  predicate.Equals,
+		},
+		Functions: map[string]interface{}{
+			"equals":   predicate.Equals,
+			"contains": predicate.Contains,
+		},
+		GetIdentifier: func(fields []string) (interface{}, error) {
+			switch fields[0] {
+			case "labels":
+				return node.GetAllLabels(), nil
+			case "name":
+				return node.GetHostname(), nil
+			}
+			return nil, trace.BadParameter("unsupported identifier %q, expected labels or name", fields[0])
+		},
+		GetProperty: services.GetStringMapValue,
+	})
+	if err != nil {
+		return false, trace.Wrap(err)
+	}
+	ifn, err := parser.Parse(query)
+	if err != nil {
+		return false, trace.BadParameter("failed to parse query %q: %v", query, err)
+	}
+	fn, ok := ifn.(predicate.BoolPredicate)
+	if !ok {
+		return false, trace.BadParameter("query %q does not evaluate to a boolean", query)
+	}
+	return fn(), nil
+}
+
+// savedSearch is a named combination of a label selector and a query,
+// stored in the profile directory.
+type savedSearch struct {
+	// Labels is the label selector in key=value form.
+	Labels string `yaml:"labels,omitempty"`
+	// Query is the predicate-language query.
+	Query string `yaml:"query,omitempty"`
+}
+
+// savedSearchesFile is the name of the saved searches file inside the
+// profile directory.
+const savedSearchesFile = "searches.yaml"
+
+// loadSavedSearches reads the saved searches from the profile directory.
+func loadSavedSearches(homePath string) (map[string]savedSearch, error) {
+	searches := map[string]savedSearch{}
+	data, err := ioutil.ReadFile(filepath.Join(profile.FullProfilePath(homePath), savedSearchesFile))
+	if err != nil {
+		if os.IsNotExist(err) {
+			return searches, nil
+		}
+		return nil, trace.ConvertSystemError(err)
+	}
+	if err := yaml.Unmarshal(data, &searches); err != nil {
+		return nil, trace.Wrap(err)
+	}
+	return searches, nil
+}
+
+// saveSearch stores a named search in the profile directory.
+func saveSearch(homePath, name string, search savedSearch) error {
+	searches, err := loadSavedSearches(homePath)
+	if err != nil {
+		return trace.Wrap(err)
+	}
+	searches[name] = search
+	data, err := yaml.Marshal(searches)
+	if err != nil {
+		return trace.Wrap(err)
+	}
+	path := filepath.Join(profile.FullProfilePath(homePath), savedSearchesFile)
+	return trace.ConvertSystemError(ioutil.WriteFile(path, data, 0600))
+}
+
+// resolveSavedSearch loads a saved search by name, filling in the labels
+// and query not provided explicitly. Returns the inputs unchanged when
+// no name is given.
+func resolveSavedSearch(homePath, name, labels, query string) (string, string, error) {
+	if name == "" {
+		return labels, query, nil
+	}
+	searches, err := loadSavedSearches(homePath)
+	if err != nil {
+		return "", "", trace.Wrap(err)
+	}
+	search, ok := searches[name]
+	if !ok {
+		return "", "", trace.NotFound("no saved search %q, save one with --save-search", name)
+	}
+	if labels == "" {
+		labels = search.Labels
+	}
+	if query == "" {
+		query = search.Query
+	}
+	return labels, query, nil
+}
@@ -0,0 +1,59 @@
+/*
+Copyright 2021 Gravitational, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package main
+
+import (
+	"testing"
+
+	"github.com/gravitational/teleport/api/types"
+
+	"github.com/stretchr/testify/require"
+)
+
+// TestFilterNodesByQuery checks predicate query evaluation against node
+// labels and names.
+func TestFilterNodesByQuery(t *testing.T) {
+	newNode := func(hostname string, labels map[string]string) types.Server {
+		return &types.ServerV2{
+			Kind:     types.KindNode,
+			Version:  types.V2,
+			Metadata: types.Metadata{Name: hostname, Labels: labels},
+			Spec:     types.ServerSpecV2{Hostname: hostname},
+		}
+	}
+	nodes := []types.Server{
+		newNode("web-1", map[string]string{"env": "prod"}),
+		newNode("web-2", map[string]string{"env": "staging"}),
+	}
+
+	filtered, err := filterNodesByQuery(nodes, `equals(labels["env"], "prod")`)
+	require.NoError(t, err)
+	require.Len(t, filtered, 1)
+	require.Equal(t, "web-1", filtered[0].GetHostname())
+
+	filtered, err = filterNodesByQuery(nodes, `equals(name, "web-2")`)
+	require.NoError(t, err)
+	require.Len(t, filtered, 1)
+	require.Equal(t, "web-2", filtered[0].GetHostname())
+
+	filtered, err = filterNodesByQuery(nodes, "")
+	require.NoError(t, err)
+	require.Len(t, filtered, 2)
+
+	_, err = filterNodesByQuery(nodes, "not a query ((")
+	require.Error(t, err)
+}
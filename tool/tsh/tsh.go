@@ -59,6 +59,7 @@ import (
 
 	"github.com/gravitational/kingpin"
 	"github.com/gravitational/trace"
+	"gopkg.in/yaml.v2"
 
 	gops "github.com/google/gops/agent"
 	"github.com/jonboulle/clockwork"
@@ -77,6 +78,30 @@ type CLIConf struct {
 	RemoteCommand []string
 	// DesiredRoles indicates one or more roles which should be requested.
 	DesiredRoles string
+
+	// RequestedResources lists specific resources to request access to,
+	// as <kind>/<name> identifiers.
+	RequestedResources string
+
+	// HostCheckMode selects between trust-on-first-use and strict host
+	// validation.
+	HostCheckMode string
+
+	// SecurityProfile selects a preset of SSH algorithms for outbound
+	// connections.
+	SecurityProfile string
+
+	// DaemonSocket is the unix socket path the tsh daemon listens on.
+	DaemonSocket string
+
+	// Query is a predicate-language query filtering listed resources.
+	Query string
+
+	// SaveSearch saves the current labels and query under this name.
+	SaveSearch string
+
+	// UseSearch loads a saved search by name.
+	UseSearch string
 	// RequestReason indicates the reason for an access request.
 	RequestReason string
 	// SuggestedReviewers is a list of suggested request reviewers.
@@ -108,6 +133,13 @@ type CLIConf struct {
 	NodeLogin string
 	// InsecureSkipVerify bypasses verification of HTTPS certificate when talking to web proxy
 	InsecureSkipVerify bool
+
+	// WebsocketTransport tunnels SSH to the proxy over a websocket on the
+	// HTTPS port
+	WebsocketTransport bool
+
+	// LocalProxyPort is the port the local database proxy listens on
+	LocalProxyPort int
 	// Remote SSH session to join
 	SessionID string
 	// Src:dest parameter for SCP
@@ -322,8 +354,13 @@ func Run(args []string, opts ...cliOption) error {
 			Default("false").
 			BoolVar(&cf.InsecureSkipVerify)
 	}
+	app.Flag("host-check", "Host validation mode: 'tofu' prompts for unknown hosts, 'strict' requires certificates with matching principals").
+		Default("tofu").StringVar(&cf.HostCheckMode)
+	app.Flag("security-profile", "SSH algorithm preset for outbound connections: 'default', 'strict' or 'legacy'").
+		Default("default").StringVar(&cf.SecurityProfile)
 
 	app.Flag("auth", "Specify the type of authentication connector to use.").Envar(authEnvVar).StringVar(&cf.AuthConnector)
+	app.Flag("websocket-transport", "Tunnel the SSH connection to the proxy over a websocket on the HTTPS port, for networks that only allow HTTPS traffic.").BoolVar(&cf.WebsocketTransport)
 	app.Flag("namespace", "Namespace of the cluster").Default(apidefaults.Namespace).Hidden().StringVar(&cf.Namespace)
 	app.Flag("gops", "Start gops endpoint on a given address").Hidden().BoolVar(&cf.Gops)
 	app.Flag("gops-addr", "Specify gops addr to listen on").Hidden().StringVar(&cf.GopsAddr)
@@ -359,6 +396,7 @@ func Run(args []string, opts ...cliOption) error {
 	// Applications.
 	apps := app.Command("apps", "View and control proxied applications.").Alias("app")
 	lsApps := apps.Command("ls", "List available applications.")
+	lsApps.Flag("format", "Format output (text, json, yaml, names)").Short('f').Default(teleport.Text).StringVar(&cf.Format)
 	lsApps.Flag("verbose", "Show extra application fields.").Short('v').BoolVar(&cf.Verbose)
 	lsApps.Flag("cluster", clusterHelp).StringVar(&cf.SiteName)
 	appLogin := apps.Command("login", "Retrieve short-lived certificate for an app.")
@@ -373,6 +411,7 @@ func Run(args []string, opts ...cliOption) error {
 	// Databases.
 	db := app.Command("db", "View and control proxied databases.")
 	dbList := db.Command("ls", "List all available databases.")
+	dbList.Flag("format", "Format output (text, json, yaml, names)").Short('f').Default(teleport.Text).StringVar(&cf.Format)
 	dbList.Flag("verbose", "Show extra database fields.").Short('v').BoolVar(&cf.Verbose)
 	dbList.Flag("cluster", clusterHelp).StringVar(&cf.SiteName)
 	dbLogin := db.Command("login", "Retrieve credentials for a database.")
@@ -381,6 +420,9 @@ func Run(args []string, opts ...cliOption) error {
 	dbLogin.Flag("db-name", "Optional database name to configure as default.").StringVar(&cf.DatabaseName)
 	dbLogout := db.Command("logout", "Remove database credentials.")
 	dbLogout.Arg("db", "Database to remove credentials for.").StringVar(&cf.DatabaseService)
+	dbProxy := db.Command("proxy", "Start a local TLS proxy to the database, for GUI clients that cannot use client certificates.")
+	dbProxy.Arg("db", "Database to start the local proxy for.").StringVar(&cf.DatabaseService)
+	dbProxy.Flag("port", "Local port to listen on, chosen automatically if not set.").IntVar(&cf.LocalProxyPort)
 	dbEnv := db.Command("env", "Print environment variables for the configured database.")
 	dbEnv.Arg("db", "Print environment for the specified database").StringVar(&cf.DatabaseService)
 	// --db flag is deprecated in favor of positional argument for consistency with other commands.
@@ -418,7 +460,10 @@ func Run(args []string, opts ...cliOption) error {
 	ls.Flag("cluster", clusterHelp).StringVar(&cf.SiteName)
 	ls.Arg("labels", "List of labels to filter node list").StringVar(&cf.UserHost)
 	ls.Flag("verbose", "One-line output (for text format), including node UUIDs").Short('v').BoolVar(&cf.Verbose)
-	ls.Flag("format", "Format output (text, json, names)").Short('f').Default(teleport.Text).StringVar(&cf.Format)
+	ls.Flag("format", "Format output (text, json, yaml, names)").Short('f').Default(teleport.Text).StringVar(&cf.Format)
+	ls.Flag("query", `Predicate query to filter nodes, e.g. 'equals(labels["env"], "prod")'`).StringVar(&cf.Query)
+	ls.Flag("save-search", "Save the labels and query under a name for --search").StringVar(&cf.SaveSearch)
+	ls.Flag("search", "Use a saved search by name").StringVar(&cf.UseSearch)
 	// clusters
 	clusters := app.Command("clusters", "List available Teleport clusters")
 	clusters.Flag("quiet", "Quiet mode").Short('q').BoolVar(&cf.Quiet)
@@ -427,10 +472,11 @@ func Run(args []string, opts ...cliOption) error {
 	// stored in ~/.tsh directory
 	login := app.Command("login", "Log in to a cluster and retrieve the session certificate")
 	login.Flag("out", "Identity output").Short('o').AllowDuplicate().StringVar(&cf.IdentityFileOut)
-	login.Flag("format", fmt.Sprintf("Identity format: %s, %s (for OpenSSH compatibility) or %s (for kubeconfig)",
+	login.Flag("format", fmt.Sprintf("Identity format: %s, %s (for OpenSSH compatibility), %s (for kubeconfig) or %s (DER for Windows tooling)",
 		identityfile.DefaultFormat,
 		identityfile.FormatOpenSSH,
 		identityfile.FormatKubernetes,
+		identityfile.FormatWindows,
 	)).Default(string(identityfile.DefaultFormat)).StringVar((*string)(&cf.IdentityFormat))
 	login.Flag("overwrite", "Whether to overwrite the existing identity file.").BoolVar(&cf.IdentityOverwrite)
 	login.Flag("request-roles", "Request one or more extra roles").StringVar(&cf.DesiredRoles)
@@ -484,10 +530,14 @@ func Run(args []string, opts ...cliOption) error {
 	reqShow.Arg("request-id", "ID of the target request").Required().StringVar(&cf.RequestID)
 
 	reqCreate := req.Command("new", "Create a new access request").Alias("create")
-	reqCreate.Flag("roles", "Roles to be requested").Required().StringVar(&cf.DesiredRoles)
+	reqCreate.Flag("roles", "Roles to be requested").StringVar(&cf.DesiredRoles)
+	reqCreate.Flag("resources", "Specific resources to request access to, as <kind>/<name> (node, db or kube)").StringVar(&cf.RequestedResources)
 	reqCreate.Flag("reason", "Reason for requesting").StringVar(&cf.RequestReason)
 	reqCreate.Flag("reviewers", "Suggested reviewers").StringVar(&cf.SuggestedReviewers)
 
+	daemon := app.Command("daemon", "Run a local API daemon for desktop GUI clients.")
+	daemon.Flag("socket", "Unix socket path to listen on, defaults to tshd.sock in the profile directory").StringVar(&cf.DaemonSocket)
+
 	reqReview := req.Command("review", "Review an access request")
 	reqReview.Arg("request-id", "ID of target request").Required().StringVar(&cf.RequestID)
 	reqReview.Flag("approve", "Review proposes approval").BoolVar(&cf.Approve)
@@ -563,6 +613,8 @@ func Run(args []string, opts ...cliOption) error {
 	readTeleportHome(&cf, os.Getenv)
 
 	switch command {
+	case daemon.FullCommand():
+		err = onDaemon(&cf)
 	case ver.FullCommand():
 		utils.PrintVersion()
 	case ssh.FullCommand():
@@ -600,6 +652,8 @@ func Run(args []string, opts ...cliOption) error {
 		err = onAppConfig(&cf)
 	case kube.credentials.FullCommand():
 		err = kube.credentials.run(&cf)
+	case kube.exec.FullCommand():
+		err = kube.exec.run(&cf)
 	case kube.ls.FullCommand():
 		err = kube.ls.run(&cf)
 	case kube.login.FullCommand():
@@ -612,6 +666,8 @@ func Run(args []string, opts ...cliOption) error {
 		err = onDatabaseLogout(&cf)
 	case dbEnv.FullCommand():
 		err = onDatabaseEnv(&cf)
+	case dbProxy.FullCommand():
+		err = onDatabaseProxy(&cf)
 	case dbConfig.FullCommand():
 		err = onDatabaseConfig(&cf)
 	case dbConnect.FullCommand():
@@ -709,7 +765,7 @@ func onLogin(cf *CLIConf) error {
 	}
 
 	switch cf.IdentityFormat {
-	case identityfile.FormatFile, identityfile.FormatOpenSSH, identityfile.FormatKubernetes:
+	case identityfile.FormatFile, identityfile.FormatOpenSSH, identityfile.FormatKubernetes, identityfile.FormatWindows:
 	default:
 		return trace.BadParameter("invalid identity format: %s", cf.IdentityFormat)
 	}
@@ -1110,6 +1166,20 @@ func onLogout(cf *CLIConf) error {
 
 // onListNodes executes 'tsh ls' command.
 func onListNodes(cf *CLIConf) error {
+	// Expand a saved search and optionally save the current one.
+	labels, query, err := resolveSavedSearch(cf.HomePath, cf.UseSearch, cf.UserHost, cf.Query)
+	if err != nil {
+		return trace.Wrap(err)
+	}
+	cf.UserHost = labels
+	cf.Query = query
+	if cf.SaveSearch != "" {
+		err := saveSearch(cf.HomePath, cf.SaveSearch, savedSearch{Labels: cf.UserHost, Query: cf.Query})
+		if err != nil {
+			return trace.Wrap(err)
+		}
+	}
+
 	tc, err := makeClient(cf, true)
 	if err != nil {
 		return trace.Wrap(err)
@@ -1127,6 +1197,10 @@ func onListNodes(cf *CLIConf) error {
 	sort.Slice(nodes, func(i, j int) bool {
 		return nodes[i].GetHostname() < nodes[j].GetHostname()
 	})
+	nodes, err = filterNodesByQuery(nodes, cf.Query)
+	if err != nil {
+		return trace.Wrap(err)
+	}
 
 	if err := printNodes(nodes, cf.Format, cf.Verbose); err != nil {
 		return trace.Wrap(err)
@@ -1136,10 +1210,13 @@ func onListNodes(cf *CLIConf) error {
 }
 
 func executeAccessRequest(cf *CLIConf, tc *client.TeleportClient) error {
-	if cf.DesiredRoles == "" {
-		return trace.BadParameter("one or more roles must be specified")
+	if cf.DesiredRoles == "" && cf.RequestedResources == "" {
+		return trace.BadParameter("one or more roles or resources must be specified")
 	}
 	roles := utils.SplitIdentifiers(cf.DesiredRoles)
+	for _, resource := range utils.SplitIdentifiers(cf.RequestedResources) {
+		roles = append(roles, services.ResourceRequestPrefix+resource)
+	}
 	reviewers := utils.SplitIdentifiers(cf.SuggestedReviewers)
 	if cf.Username == "" {
 		cf.Username = tc.Username
@@ -1192,12 +1269,18 @@ func printNodes(nodes []types.Server, format string, verbose bool) error {
 			return trace.Wrap(err)
 		}
 		fmt.Println(string(out))
+	case teleport.YAML:
+		out, err := yaml.Marshal(nodes)
+		if err != nil {
+			return trace.Wrap(err)
+		}
+		fmt.Println(string(out))
 	case teleport.Names:
 		for _, n := range nodes {
 			fmt.Println(n.GetHostname())
 		}
 	default:
-		return trace.BadParameter("unsupported format. try 'json', 'text', or 'names'")
+		return trace.BadParameter("unsupported format. try 'json', 'yaml', 'text', or 'names'")
 	}
 
 	return nil
@@ -1440,6 +1523,10 @@ func onSSH(cf *CLIConf) error {
 	if err != nil {
 		return trace.Wrap(err)
 	}
+	// Long-lived port forwards should survive the certificate TTL.
+	if len(cf.LocalForwardPorts) > 0 || len(cf.DynamicForwardedPorts) > 0 {
+		tc.StartCertRenewal(cf.Context)
+	}
 
 	tc.Stdin = os.Stdin
 	err = client.RetryWithRelogin(cf.Context, tc, func() error {
@@ -1739,6 +1826,9 @@ func makeClient(cf *CLIConf, useProfileLogin bool) (*client.TeleportClient, erro
 	c.Labels = labels
 	c.KeyTTL = time.Minute * time.Duration(cf.MinsToLive)
 	c.InsecureSkipVerify = cf.InsecureSkipVerify
+	c.HostCheckMode = cf.HostCheckMode
+	c.SecurityProfile = cf.SecurityProfile
+	c.WebsocketTransport = cf.WebsocketTransport
 
 	// If a TTY was requested, make sure to allocate it. Note this applies to
 	// "exec" command because a shell always has a TTY allocated.
@@ -2005,6 +2095,12 @@ func printStatus(debug bool, p *client.ProfileStatus, isActive bool) {
 // onStatus command shows which proxy the user is logged into and metadata
 // about the certificate.
 func onStatus(cf *CLIConf) error {
+	// With an identity file there is no profile, print a summary of the
+	// identity instead.
+	if cf.IdentityFileIn != "" {
+		return trace.Wrap(printIdentityStatus(cf.IdentityFileIn))
+	}
+
 	// Get the status of the active profile as well as the status
 	// of any other proxies the user is logged into.
 	//
@@ -2168,7 +2264,30 @@ func onApps(cf *CLIConf) error {
 		return servers[i].GetName() < servers[j].GetName()
 	})
 
-	showApps(servers, profile.Apps, cf.Verbose)
+	switch strings.ToLower(cf.Format) {
+	case "", teleport.Text:
+		showApps(servers, profile.Apps, cf.Verbose)
+	case teleport.JSON:
+		out, err := json.MarshalIndent(servers, "", "  ")
+		if err != nil {
+			return trace.Wrap(err)
+		}
+		fmt.Println(string(out))
+	case teleport.YAML:
+		out, err := yaml.Marshal(servers)
+		if err != nil {
+			return trace.Wrap(err)
+		}
+		fmt.Println(string(out))
+	case teleport.Names:
+		for _, server := range servers {
+			for _, app := range server.GetApps() {
+				fmt.Println(app.Name)
+			}
+		}
+	default:
+		return trace.BadParameter("unsupported format. try 'json', 'yaml', 'text', or 'names'")
+	}
 	return nil
 }
 
@@ -2238,3 +2357,30 @@ func readTeleportHome(cf *CLIConf, fn envGetter) {
 		cf.HomePath = path.Clean(homeDir)
 	}
 }
+
+// printIdentityStatus prints a summary of the credentials in an identity
+// file produced by "tctl auth sign" or "tsh login --out".
+func printIdentityStatus(path string) error {
+	key, err := client.KeyFromIdentityFile(path)
+	if err != nil {
+		return trace.Wrap(err)
+	}
+	sshCert, err := key.SSHCert()
+	if err != nil {
+		return trace.Wrap(err)
+	}
+	username, err := key.CertUsername()
+	if err != nil {
+		return trace.Wrap(err)
+	}
+	roles, err := key.CertRoles()
+	if err != nil {
+		return trace.Wrap(err)
+	}
+	fmt.Printf("Identity file: %v\n", path)
+	fmt.Printf("  Logged in as: %v\n", username)
+	fmt.Printf("  Roles:        %v\n", strings.Join(roles, ", "))
+	fmt.Printf("  Principals:   %v\n", strings.Join(sshCert.ValidPrincipals, ", "))
+	fmt.Printf("  Valid until:  %v\n", time.Unix(int64(sshCert.ValidBefore), 0))
+	return nil
+}